@@ -0,0 +1,209 @@
+package config
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+)
+
+func Parse(prefix string) (*Config, error) {
+	c := new(Config)
+	if err := envconfig.Process(prefix, c); err != nil {
+		return nil, errors.Wrap(err, "failed to parse env")
+	}
+	if c.MaxHouseholdMembers < 1 {
+		return nil, errors.New("household_max_members must be at least 1")
+	}
+	return c, nil
+}
+
+type Config struct {
+	ServeRESTAddress string `envconfig:"serve_rest_address" default:":8001"`
+	ServeGRPCAddress string `envconfig:"serve_grpc_address" default:":8002"`
+
+	CORSAllowedOrigins []string `envconfig:"cors_allowed_origins"`
+	// TrustedProxies must include address gateway connects to grpc server from, it runs in the same process
+	TrustedProxies []string `envconfig:"trusted_proxies" default:"127.0.0.1/32,::1/128"`
+
+	SessionCookieName   string        `envconfig:"session_cookie_name" default:"user_token"`
+	SessionCookieMaxAge time.Duration `envconfig:"session_cookie_max_age" default:"720h"`
+	SessionCookieSecure bool          `envconfig:"session_cookie_secure" default:"true"`
+
+	DatabaseUser     string `envconfig:"db_user" default:"root"`
+	DatabasePassword string `envconfig:"db_password" default:"1234"`
+	DatabaseHost     string `envconfig:"db_host" default:"userservice-db"`
+	DatabaseName     string `envconfig:"db_name" default:"userservice"`
+
+	AMQPHost            string        `envconfig:"amqp_host"`
+	AMQPUser            string        `envconfig:"amqp_user" default:"guest"`
+	AMQPPassword        string        `envconfig:"amqp_password" default:"guest"`
+	AMQPExchange        string        `envconfig:"amqp_exchange" default:"userservice.events"`
+	OutboxRelayInterval time.Duration `envconfig:"outbox_relay_interval" default:"1s"`
+
+	MaxDatabaseConnections int  `envconfig:"max_connections" default:"10"`
+	AutoMigrate            bool `envconfig:"auto_migrate" default:"true"`
+
+	HealthCheckInterval time.Duration `envconfig:"health_check_interval" default:"10s"`
+	HealthCheckTimeout  time.Duration `envconfig:"health_check_timeout" default:"2s"`
+
+	GRPCDefaultTimeout time.Duration            `envconfig:"grpc_default_timeout" default:"10s"`
+	GRPCMethodTimeouts map[string]time.Duration `envconfig:"grpc_method_timeouts"`
+
+	Salt string `envconfig:"hasher_salt"`
+
+	MaxFailedLoginAttempts int           `envconfig:"login_max_failed_attempts" default:"0"`
+	LockoutDuration        time.Duration `envconfig:"login_lockout_duration" default:"15m"`
+
+	DeletedUserGracePeriod   time.Duration `envconfig:"deleted_user_grace_period" default:"720h"`
+	DeletedUserPurgeInterval time.Duration `envconfig:"deleted_user_purge_interval" default:"1h"`
+
+	DataExportDir          string        `envconfig:"data_export_dir" default:"data-exports"`
+	DataExportArchiveTTL   time.Duration `envconfig:"data_export_ttl" default:"72h"`
+	DataExportPollInterval time.Duration `envconfig:"data_export_poll_interval" default:"10s"`
+
+	AuditSealInterval time.Duration `envconfig:"audit_seal_interval" default:"1s"`
+
+	TermsVersion   string `envconfig:"terms_of_service_version"`
+	PrivacyVersion string `envconfig:"privacy_policy_version"`
+	RequireTerms   bool   `envconfig:"require_terms_on_sign_up"`
+
+	MaxHouseholdMembers int `envconfig:"household_max_members" default:"6"`
+	// HouseholdAddressSecret keys hash of household address, changing it makes addresses of existing households mismatch
+	HouseholdAddressSecret string `envconfig:"household_address_key" required:"true"`
+
+	ChildAgeLimit          int            `envconfig:"child_age" default:"13"`
+	AdultAgeLimit          int            `envconfig:"adult_age" default:"18"`
+	AdultAgeLimitByCountry map[string]int `envconfig:"adult_age_by_country"`
+
+	// providers are keyed by name used in api, e.g. google:https://accounts.google.com,apple:https://appleid.apple.com
+	OIDCIssuers       map[string]string `envconfig:"oidc_issuers"`
+	OIDCClientIDs     map[string]string `envconfig:"oidc_client_ids"`
+	OIDCClientSecrets map[string]string `envconfig:"oidc_client_secrets"`
+	OIDCRedirectURL   string            `envconfig:"oidc_redirect_url"`
+
+	OAuth2IssuerURL     string        `envconfig:"oauth2_issuer" required:"true"`
+	OAuth2SigningKeyPEM string        `envconfig:"oauth2_signing_key" required:"true"`
+	OAuth2AccessTTL     time.Duration `envconfig:"oauth2_access_token_ttl" default:"1h"`
+	OAuth2RefreshTTL    time.Duration `envconfig:"oauth2_refresh_token_ttl" default:"720h"`
+	OAuth2LoginURL      string        `envconfig:"oauth2_login_url"`
+	OAuth2ConsentURL    string        `envconfig:"oauth2_consent_url"`
+	OAuth2DeviceURL     string        `envconfig:"oauth2_device_verification_url"`
+
+	CacheSize        int           `envconfig:"user_cache_size" default:"10000"`
+	CacheTTL         time.Duration `envconfig:"user_cache_ttl" default:"1m"`
+	CacheLoadTimeout time.Duration `envconfig:"user_cache_load_timeout" default:"5s"`
+
+	TracingExporter     string  `envconfig:"tracing_exporter" default:"none"`
+	TracingOTLPEndpoint string  `envconfig:"tracing_otlp_endpoint" default:"localhost:4317"`
+	TracingOTLPInsecure bool    `envconfig:"tracing_otlp_insecure" default:"true"`
+	TracingFilePath     string  `envconfig:"tracing_file_path" default:"traces.jsonl"`
+	TracingSampleRatio  float64 `envconfig:"tracing_sample_ratio" default:"1"`
+}
+
+func (c *Config) HasherSalt() string {
+	return c.Salt
+}
+
+func (c *Config) UserCacheSize() int {
+	return c.CacheSize
+}
+
+func (c *Config) UserCacheTTL() time.Duration {
+	return c.CacheTTL
+}
+
+func (c *Config) UserCacheLoadTimeout() time.Duration {
+	return c.CacheLoadTimeout
+}
+
+func (c *Config) LoginMaxFailedAttempts() int {
+	return c.MaxFailedLoginAttempts
+}
+
+func (c *Config) LoginLockoutDuration() time.Duration {
+	return c.LockoutDuration
+}
+
+func (c *Config) DataExportStorageDir() string {
+	return c.DataExportDir
+}
+
+func (c *Config) DataExportTTL() time.Duration {
+	return c.DataExportArchiveTTL
+}
+
+func (c *Config) TermsOfServiceVersion() string {
+	return c.TermsVersion
+}
+
+func (c *Config) PrivacyPolicyVersion() string {
+	return c.PrivacyVersion
+}
+
+func (c *Config) RequireTermsOnSignUp() bool {
+	return c.RequireTerms
+}
+
+func (c *Config) HouseholdMaxMembers() int {
+	return c.MaxHouseholdMembers
+}
+
+func (c *Config) HouseholdAddressKey() string {
+	return c.HouseholdAddressSecret
+}
+
+func (c *Config) ChildAge() int {
+	return c.ChildAgeLimit
+}
+
+func (c *Config) AdultAge() int {
+	return c.AdultAgeLimit
+}
+
+func (c *Config) AdultAgeByCountry() map[string]int {
+	return c.AdultAgeLimitByCountry
+}
+
+func (c *Config) ExternalIdentityIssuers() map[string]string {
+	return c.OIDCIssuers
+}
+
+func (c *Config) ExternalIdentityClientIDs() map[string]string {
+	return c.OIDCClientIDs
+}
+
+func (c *Config) ExternalIdentityClientSecrets() map[string]string {
+	return c.OIDCClientSecrets
+}
+
+func (c *Config) ExternalIdentityRedirectURL() string {
+	return c.OIDCRedirectURL
+}
+
+func (c *Config) OAuth2Issuer() string {
+	return c.OAuth2IssuerURL
+}
+
+func (c *Config) OAuth2SigningKey() string {
+	return c.OAuth2SigningKeyPEM
+}
+
+func (c *Config) OAuth2AccessTokenTTL() time.Duration {
+	return c.OAuth2AccessTTL
+}
+
+func (c *Config) OAuth2RefreshTokenTTL() time.Duration {
+	return c.OAuth2RefreshTTL
+}
+
+func (c *Config) OAuth2DeviceVerificationURL() string {
+	if c.OAuth2DeviceURL == "" {
+		return c.OAuth2IssuerURL + "/device"
+	}
+	return c.OAuth2DeviceURL
+}
+
+func (c *Config) SessionTTL() time.Duration {
+	return c.SessionCookieMaxAge
+}
@@ -0,0 +1,37 @@
+package integration
+
+import (
+	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
+	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/amqp"
+
+	"userservice/cmd/internal/config"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/infrastructure/integration"
+)
+
+func EventHandlers(c *config.Config) []service.EventHandler {
+	if c.AMQPHost == "" {
+		return nil
+	}
+	return []service.EventHandler{integration.NewOutboxEventHandler()}
+}
+
+func Connect(c *config.Config, logger log.Logger) (publisher service.OutboxPublisher, stop func() error, err error) {
+	if c.AMQPHost == "" {
+		return nil, func() error { return nil }, nil
+	}
+
+	connection := amqp.NewAMQPConnection(&amqp.Config{
+		User:     c.AMQPUser,
+		Password: c.AMQPPassword,
+		Host:     c.AMQPHost,
+	}, logger)
+	amqpPublisher := integration.NewAMQPPublisher(c.AMQPExchange)
+	connection.AddChannel(amqpPublisher)
+
+	err = connection.Start()
+	if err != nil {
+		return nil, nil, err
+	}
+	return amqpPublisher, connection.Stop, nil
+}
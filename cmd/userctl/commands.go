@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/userexchange"
+)
+
+var (
+	errMissingFlag  = errors.New("missing required flag")
+	errUnknownRole  = errors.New("unknown role, expected listener, creator or admin")
+	errAmbiguousKey = errors.New("only one of -id and -email must be set")
+	errInvalidDate  = errors.New("invalid date, expected YYYY-MM-DD")
+)
+
+func createUser(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	email := flags.String("email", "", "email of user")
+	password := flags.String("password", "", "password of user")
+	roleName := flags.String("role", "listener", "role of user: listener, creator or admin")
+	termsVersion := flags.String("terms-version", "", "version of terms of service user accepted, required when service requires terms on sign up")
+	birthDate := flags.String("date-of-birth", "", "date of birth of user in YYYY-MM-DD format")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *email == "" || *password == "" {
+		return errors.Wrap(errMissingFlag, "-email and -password")
+	}
+	role, err := parseRole(*roleName)
+	if err != nil {
+		return err
+	}
+
+	var terms *service.TermsAcceptance
+	if *termsVersion != "" {
+		terms = &service.TermsAcceptance{Version: *termsVersion}
+	}
+
+	var dateOfBirth *time.Time
+	if *birthDate != "" {
+		date, err2 := time.Parse(service.DateLayout, *birthDate)
+		if err2 != nil {
+			return errInvalidDate
+		}
+		dateOfBirth = &date
+	}
+
+	userID, err := env.container.UserService().AddUser(ctx, *email, *password, role, terms, dateOfBirth)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.Wrap(err, "invalid id of added user")
+	}
+
+	user, err := env.container.UserQueryService().GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	return env.printer.PrintUsers([]query.UserView{user})
+}
+
+func getUser(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	id := flags.String("id", "", "id of user")
+	email := flags.String("email", "", "email of user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var user query.UserView
+	var err error
+
+	switch {
+	case *id != "" && *email != "":
+		return errAmbiguousKey
+	case *id != "":
+		var userID uuid.UUID
+		userID, err = uuid.Parse(*id)
+		if err != nil {
+			return errors.Wrap(err, "invalid -id")
+		}
+		user, err = env.container.UserQueryService().GetUser(ctx, userID)
+	case *email != "":
+		user, err = env.container.UserQueryService().GetByEmail(ctx, *email)
+	default:
+		return errors.Wrap(errMissingFlag, "-id or -email")
+	}
+	if err != nil {
+		return err
+	}
+
+	return env.printer.PrintUsers([]query.UserView{user})
+}
+
+func listUsers(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	roleName := flags.String("role", "", "show only users with role: listener, creator or admin")
+	limit := flags.Int("limit", 50, "max number of users to show")
+	offset := flags.Int("offset", 0, "number of users to skip")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := makeListUsersSpec(*roleName)
+	if err != nil {
+		return err
+	}
+	spec.Limit = *limit
+	spec.Offset = *offset
+
+	users, err := env.container.UserQueryService().ListUsers(ctx, spec)
+	if err != nil {
+		return err
+	}
+	return env.printer.PrintUsers(users)
+}
+
+func setRole(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("set-role", flag.ContinueOnError)
+	id := flags.String("id", "", "id of user")
+	roleName := flags.String("role", "", "new role: listener, creator or admin")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	userID, err := parseUserID(*id)
+	if err != nil {
+		return err
+	}
+	role, err := parseRole(*roleName)
+	if err != nil {
+		return err
+	}
+
+	return env.container.UserService().ChangeRole(ctx, userID, role)
+}
+
+func resetPassword(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	id := flags.String("id", "", "id of user")
+	password := flags.String("password", "", "new password, random one is generated and printed when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	userID, err := parseUserID(*id)
+	if err != nil {
+		return err
+	}
+
+	generated := *password == ""
+	if generated {
+		*password, err = generatePassword()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = env.container.UserService().ChangePassword(ctx, userID, *password)
+	if err != nil {
+		return err
+	}
+
+	if generated {
+		return env.printer.PrintValue("password", *password)
+	}
+	return nil
+}
+
+func deleteUser(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("delete", flag.ContinueOnError)
+	id := flags.String("id", "", "id of user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	userID, err := parseUserID(*id)
+	if err != nil {
+		return err
+	}
+
+	return env.container.UserService().RemoveUser(ctx, userID)
+}
+
+func restoreUser(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	id := flags.String("id", "", "id of user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	userID, err := parseUserID(*id)
+	if err != nil {
+		return err
+	}
+
+	return env.container.UserService().RestoreUser(ctx, userID)
+}
+
+func eraseUser(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("erase", flag.ContinueOnError)
+	id := flags.String("id", "", "id of user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	userID, err := parseUserID(*id)
+	if err != nil {
+		return err
+	}
+
+	certificate, err := env.container.ErasureService().EraseUser(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+	return env.printer.PrintValue("certificate_id", certificate.ID.String())
+}
+
+func unlockUser(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("unlock", flag.ContinueOnError)
+	id := flags.String("id", "", "id of user")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	userID, err := parseUserID(*id)
+	if err != nil {
+		return err
+	}
+
+	return env.container.UserService().UnlockUser(ctx, userID)
+}
+
+func exportUsers(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	roleName := flags.String("role", "", "export only users with role: listener, creator or admin")
+	format := flags.String("format", string(userexchange.NDJSON), "file format: csv or ndjson")
+	includePasswordHashes := flags.Bool("include-password-hashes", false, "export password hashes with their algorithm")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	spec, err := makeListUsersSpec(*roleName)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(env.out)
+	writer, err := userexchange.NewWriter(userexchange.Format(*format), out, *includePasswordHashes)
+	if err != nil {
+		return err
+	}
+
+	err = userexchange.Export(ctx, env.container.UserQueryService(), spec, writer)
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(out.Flush())
+}
+
+func importUsers(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := flags.String("file", "-", "path to file, - reads stdin")
+	format := flags.String("format", "", "file format: csv or ndjson, detected by file extension when empty")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	in := env.in
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	reader, err := userexchange.NewReader(userexchange.Format(*format), bufio.NewReader(in))
+	if err != nil {
+		return err
+	}
+
+	report, err := env.container.UserService().ImportUsers(ctx, reader)
+	if err != nil {
+		return err
+	}
+	return env.printer.PrintImportReport(report)
+}
+
+func makeListUsersSpec(roleName string) (query.ListUsersSpec, error) {
+	if roleName == "" {
+		return query.ListUsersSpec{}, nil
+	}
+
+	role, err := parseRole(roleName)
+	if err != nil {
+		return query.ListUsersSpec{}, err
+	}
+	queryRole := query.Role(role)
+	return query.ListUsersSpec{Role: &queryRole}, nil
+}
+
+func parseUserID(id string) (uuid.UUID, error) {
+	if id == "" {
+		return uuid.UUID{}, errors.Wrap(errMissingFlag, "-id")
+	}
+	userID, err := uuid.Parse(id)
+	return userID, errors.Wrap(err, "invalid -id")
+}
+
+func parseRole(name string) (service.Role, error) {
+	role, ok := domain.RoleByName(strings.ToLower(name))
+	if !ok {
+		return 0, errors.Wrap(errUnknownRole, name)
+	}
+	return service.Role(role), nil
+}
+
+func generatePassword() (string, error) {
+	const passwordBytes = 12
+	b := make([]byte, passwordBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func verifyAuditLog(ctx context.Context, env environment, args []string) error {
+	flags := flag.NewFlagSet("verify-audit", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// recent entries are sealed first, otherwise they would be left out of verification
+	_, err := env.container.AuditService().SealPending(ctx)
+	if err != nil {
+		return err
+	}
+	verified, err := env.container.AuditService().VerifyChain(ctx)
+	if err != nil {
+		return err
+	}
+	return env.printer.PrintValue("verified_entries", strconv.Itoa(verified))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	"userservice/cmd/internal/config"
+	"userservice/cmd/internal/integration"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/infrastructure"
+	infrastructuremysql "userservice/pkg/userservice/infrastructure/mysql"
+)
+
+const configPrefix = "userservice"
+
+var errUnknownCommand = errors.New("unknown command")
+
+type environment struct {
+	container infrastructure.DependencyContainer
+	printer   printer
+	in        io.Reader
+	out       io.Writer
+}
+
+type command struct {
+	description string
+	run         func(ctx context.Context, env environment, args []string) error
+}
+
+var commands = map[string]command{
+	"create-user":    {description: "create user with given email, password and role", run: createUser},
+	"get":            {description: "show user by id or email", run: getUser},
+	"list":           {description: "list users page by page", run: listUsers},
+	"set-role":       {description: "change role of user", run: setRole},
+	"reset-password": {description: "set new password, random one is generated when omitted", run: resetPassword},
+	"delete":         {description: "delete user, it can be restored until grace period ends", run: deleteUser},
+	"restore":        {description: "restore deleted user before it is purged", run: restoreUser},
+	"erase":          {description: "irreversibly scrub personal data of user and print erasure certificate", run: eraseUser},
+	"unlock":         {description: "unlock user locked after failed logins", run: unlockUser},
+	"export":         {description: "export users as csv or ndjson, password hashes only on request", run: exportUsers},
+	"import":         {description: "import users from csv or ndjson with plaintext or hashed passwords", run: importUsers},
+	"verify-audit":   {description: "verify hash chain of audit log", run: verifyAuditLog},
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, in io.Reader, out io.Writer) error {
+	flags := flag.NewFlagSet("userctl", flag.ContinueOnError)
+	output := flags.String("output", outputTable, "output format: table or json")
+	timeout := flags.Duration("timeout", time.Minute, "timeout of whole command")
+	flags.Usage = func() {
+		usage(flags)
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUnknownCommand
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		flags.Usage()
+		return errors.Wrap(errUnknownCommand, flags.Arg(0))
+	}
+
+	printer, err := newPrinter(*output, out)
+	if err != nil {
+		return err
+	}
+
+	c, err := config.Parse(configPrefix)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx = service.WithAuditContext(ctx, service.AuditContext{UserAgent: "userctl"})
+
+	return withContainer(c, func(container infrastructure.DependencyContainer) error {
+		return cmd.run(ctx, environment{
+			container: container,
+			printer:   printer,
+			in:        in,
+			out:       out,
+		}, flags.Args()[1:])
+	})
+}
+
+func withContainer(c *config.Config, f func(container infrastructure.DependencyContainer) error) error {
+	connector := mysql.NewConnector()
+	err := connector.Open(mysql.DSN{
+		User:     c.DatabaseUser,
+		Password: c.DatabasePassword,
+		Host:     c.DatabaseHost,
+		Database: c.DatabaseName,
+	}, c.MaxDatabaseConnections)
+	if err != nil {
+		return err
+	}
+	defer connector.Close()
+
+	client, err := infrastructuremysql.NewTransactionalClient(connector.TransactionalClient())
+	if err != nil {
+		return err
+	}
+
+	// erasure and other commands dispatch events that downstream services must receive as from service itself,
+	// they are written to outbox and published by running service
+	return f(infrastructure.NewDependencyContainer(client, c, integration.EventHandlers(c)...))
+}
+
+func usage(flags *flag.FlagSet) {
+	out := flags.Output()
+	_, _ = fmt.Fprintln(out, "Usage: userctl [flags] <command> [command flags]")
+	_, _ = fmt.Fprintln(out, "\nCommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(out, "  %-16s %s\n", name, commands[name].description)
+	}
+
+	_, _ = fmt.Fprintln(out, "\nFlags:")
+	flags.PrintDefaults()
+	_, _ = fmt.Fprintln(out, "\nRun 'userctl <command> -h' to see command flags")
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var errUnknownOutput = errors.New("unknown output format, expected table or json")
+
+type printer interface {
+	PrintUsers(users []query.UserView) error
+	PrintValue(name, value string) error
+	PrintImportReport(report service.ImportReport) error
+}
+
+func newPrinter(output string, out io.Writer) (printer, error) {
+	switch output {
+	case outputTable:
+		return &tablePrinter{out: out}, nil
+	case outputJSON:
+		return &jsonPrinter{encoder: json.NewEncoder(out)}, nil
+	default:
+		return nil, errors.Wrap(errUnknownOutput, output)
+	}
+}
+
+// userRecord deliberately has no password hash, output of userctl may end up in terminals and tickets
+type userRecord struct {
+	ID                  string     `json:"id"`
+	Email               string     `json:"email"`
+	Role                string     `json:"role"`
+	FailedLoginAttempts int        `json:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+}
+
+func makeUserRecord(user query.UserView) userRecord {
+	return userRecord{
+		ID:                  user.ID.String(),
+		Email:               user.Email,
+		Role:                domain.Role(user.Role).Name(),
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LockedUntil:         user.LockedUntil,
+	}
+}
+
+type jsonPrinter struct {
+	encoder *json.Encoder
+}
+
+func (p *jsonPrinter) PrintUsers(users []query.UserView) error {
+	for _, user := range users {
+		err := p.encoder.Encode(makeUserRecord(user))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+type importReportRecord struct {
+	Imported int                    `json:"imported"`
+	Errors   []importRowErrorRecord `json:"errors"`
+}
+
+type importRowErrorRecord struct {
+	Row   int    `json:"row"`
+	Email string `json:"email"`
+	Error string `json:"error"`
+}
+
+func (p *jsonPrinter) PrintImportReport(report service.ImportReport) error {
+	record := importReportRecord{
+		Imported: report.Imported,
+		Errors:   make([]importRowErrorRecord, 0, len(report.Errors)),
+	}
+	for _, rowErr := range report.Errors {
+		record.Errors = append(record.Errors, importRowErrorRecord{Row: rowErr.Row, Email: rowErr.Email, Error: rowErr.Err.Error()})
+	}
+	return errors.WithStack(p.encoder.Encode(record))
+}
+
+func (p *jsonPrinter) PrintValue(name, value string) error {
+	return errors.WithStack(p.encoder.Encode(map[string]string{name: value}))
+}
+
+type tablePrinter struct {
+	out           io.Writer
+	headerPrinted bool
+}
+
+func (p *tablePrinter) PrintUsers(users []query.UserView) error {
+	writer := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+
+	if !p.headerPrinted {
+		_, _ = fmt.Fprintln(writer, "ID\tEMAIL\tROLE\tFAILED LOGINS\tLOCKED UNTIL")
+		p.headerPrinted = true
+	}
+
+	for _, user := range users {
+		record := makeUserRecord(user)
+		lockedUntil := "-"
+		if record.LockedUntil != nil {
+			lockedUntil = record.LockedUntil.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\n", record.ID, record.Email, record.Role, record.FailedLoginAttempts, lockedUntil)
+	}
+
+	return errors.WithStack(writer.Flush())
+}
+
+func (p *tablePrinter) PrintValue(name, value string) error {
+	_, err := fmt.Fprintf(p.out, "%s: %s\n", name, value)
+	return errors.WithStack(err)
+}
+
+func (p *tablePrinter) PrintImportReport(report service.ImportReport) error {
+	writer := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(writer, "imported: %d, failed: %d\n", report.Imported, len(report.Errors))
+
+	if len(report.Errors) > 0 {
+		_, _ = fmt.Fprintln(writer, "\nROW\tEMAIL\tERROR")
+		for _, rowErr := range report.Errors {
+			_, _ = fmt.Fprintf(writer, "%d\t%s\t%s\n", rowErr.Row, rowErr.Email, rowErr.Err)
+		}
+	}
+
+	return errors.WithStack(writer.Flush())
+}
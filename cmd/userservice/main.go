@@ -2,32 +2,53 @@ package main
 
 import (
 	"context"
-	"io"
+	"fmt"
 	stdlog "log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	commonauth "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/auth"
 	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
 	jsonlog "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/logger"
 	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
 	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/server"
 	"github.com/gorilla/mux"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"userservice/api/authenticationservice"
 	"userservice/api/authorizationservice"
 	"userservice/api/userservice"
+	"userservice/cmd/internal/config"
+	"userservice/cmd/internal/integration"
 	migrationsembedder "userservice/data/mysql"
+	"userservice/pkg/userservice/app/service"
 	"userservice/pkg/userservice/infrastructure"
+	"userservice/pkg/userservice/infrastructure/background"
+	"userservice/pkg/userservice/infrastructure/health"
+	"userservice/pkg/userservice/infrastructure/metrics"
+	infrastructuremysql "userservice/pkg/userservice/infrastructure/mysql"
+	"userservice/pkg/userservice/infrastructure/tracing"
 	"userservice/pkg/userservice/infrastructure/transport"
 )
 
 var appID = "UNKNOWN"
 
+const (
+	databaseHealthCheck   = "database"
+	migrationsHealthCheck = "migrations"
+	amqpHealthCheck       = "amqp"
+)
+
 func main() {
 	logger, err := initLogger()
 	if err != nil {
@@ -39,6 +60,14 @@ func main() {
 		logger.FatalError(err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == migrateCommand {
+		err = runMigrate(config, os.Args[2:], os.Stdout)
+		if err != nil {
+			logger.FatalError(err)
+		}
+		return
+	}
+
 	err = runService(config, logger)
 	if err == server.ErrStopped {
 		logger.Info("service is successfully stopped")
@@ -47,38 +76,152 @@ func main() {
 	}
 }
 
-func runService(config *config, logger log.MainLogger) error {
-	dsn := mysql.DSN{
-		User:     config.DatabaseUser,
-		Password: config.DatabasePassword,
-		Host:     config.DatabaseHost,
-		Database: config.DatabaseName,
+func runService(config *config.Config, logger log.MainLogger) error {
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName:  appID,
+		Exporter:     config.TracingExporter,
+		OTLPEndpoint: config.TracingOTLPEndpoint,
+		OTLPInsecure: config.TracingOTLPInsecure,
+		FilePath:     config.TracingFilePath,
+		SampleRatio:  config.TracingSampleRatio,
+	})
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+			logger.Error(shutdownErr, "failed to flush traces")
+		}
+	}()
+
 	connector := mysql.NewConnector()
-	err := connector.MigrateUp(dsn, migrationsembedder.MigrationsEmbedder)
+	err = connector.Open(makeDSN(config), config.MaxDatabaseConnections)
+	if err != nil {
+		return err
+	}
+	defer connector.Close()
+
+	migrator, err := infrastructuremysql.NewMigrator(connector.Client(), migrationsembedder.MigrationsEmbedder)
 	if err != nil {
-		logger.Error(err, "failed to migrate")
+		return err
 	}
-	err = connector.Open(dsn, config.MaxDatabaseConnections)
+	if config.AutoMigrate {
+		n, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		logger.Info(fmt.Sprintf("applied %d migrations", n))
+	}
+	newerMigrations, err := migrator.CheckSchemaVersion()
 	if err != nil {
 		return err
 	}
-	defer connector.Close()
+	if len(newerMigrations) != 0 {
+		logger.WithFields(log.Fields{
+			"severity":           "warning",
+			"unknown_migrations": newerMigrations,
+		}).Info(fmt.Sprintf("WARNING: database schema is ahead of service, unknown migrations: %s", strings.Join(newerMigrations, ", ")))
+	}
+
+	if statsProvider, ok := connector.Client().(metrics.DBStatsProvider); ok {
+		err = metrics.RegisterDBStats(statsProvider)
+		if err != nil {
+			return err
+		}
+	}
 
 	stopChan := make(chan struct{})
 	listenForKillSignal(stopChan)
 
-	container := infrastructure.NewDependencyContainer(connector.TransactionalClient(), config)
+	client, err := infrastructuremysql.NewTransactionalClient(connector.TransactionalClient())
+	if err != nil {
+		return err
+	}
+
+	outboxPublisher, stopIntegration, err := integration.Connect(config, logger)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if stopErr := stopIntegration(); stopErr != nil {
+			logger.Error(stopErr, "failed to close amqp connection")
+		}
+	}()
+
+	container := infrastructure.NewDependencyContainer(client, config, integration.EventHandlers(config)...)
+	_, err = container.OAuth2Service().KeySet()
+	if err != nil {
+		return errors.Wrap(err, "invalid oauth2 signing key")
+	}
 
 	userServiceServer := transport.NewUserServiceServer(container)
 	authServiceServer := transport.NewAuthServer(container)
 	serverHub := server.NewHub(stopChan)
 
-	baseServer := grpc.NewServer(grpc.UnaryInterceptor(makeGRPCUnaryInterceptor(logger)))
+	trustedProxies, err := transport.ParseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		return err
+	}
+
+	grpcMetrics := metrics.NewGRPCMetrics()
+	baseServer := grpc.NewServer(
+		grpc.UnaryInterceptor(makeGRPCUnaryInterceptor(logger, grpcMetrics, transport.DeadlineConfig{
+			DefaultTimeout: config.GRPCDefaultTimeout,
+			MethodTimeouts: config.GRPCMethodTimeouts,
+		}, trustedProxies, container.UserDescriptorSerializer())),
+		grpc.StreamInterceptor(makeGRPCStreamInterceptor(logger, grpcMetrics, trustedProxies)),
+	)
 	userservice.RegisterUserServiceServer(baseServer, userServiceServer)
 	authenticationservice.RegisterAuthenticationServiceServer(baseServer, authServiceServer)
 	authorizationservice.RegisterAuthorizationServiceServer(baseServer, authServiceServer)
 
+	checker := makeHealthChecker(config, client, migrator)
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(baseServer, healthServer)
+	healthStatusUpdater := transport.NewHealthStatusUpdater(
+		checker,
+		healthServer,
+		grpcServiceHealthDependencies(baseServer),
+		config.HealthCheckInterval,
+		logger,
+	)
+	serverHub.AddServer(&server.FuncServer{
+		ServeImpl: healthStatusUpdater.Run,
+		StopImpl:  healthStatusUpdater.Stop,
+	})
+
+	userPurger := background.NewUserPurger(container.UserService(), []service.ExpiredDataPurger{
+		container.SessionService(),
+		container.OAuth2Service(),
+	}, background.UserPurgerConfig{
+		GracePeriod: config.DeletedUserGracePeriod,
+		Interval:    config.DeletedUserPurgeInterval,
+	}, logger)
+	serverHub.AddServer(&server.FuncServer{
+		ServeImpl: userPurger.Run,
+		StopImpl:  userPurger.Stop,
+	})
+
+	dataExportRunner := background.NewDataExportRunner(container.DataExportService(), config.DataExportPollInterval, logger)
+	serverHub.AddServer(&server.FuncServer{
+		ServeImpl: dataExportRunner.Run,
+		StopImpl:  dataExportRunner.Stop,
+	})
+
+	auditLogSealer := background.NewAuditLogSealer(container.AuditService(), config.AuditSealInterval, logger)
+	serverHub.AddServer(&server.FuncServer{
+		ServeImpl: auditLogSealer.Run,
+		StopImpl:  auditLogSealer.Stop,
+	})
+
+	if outboxPublisher != nil {
+		outboxRelay := background.NewOutboxRelay(container.OutboxService(), outboxPublisher, config.OutboxRelayInterval, logger)
+		serverHub.AddServer(&server.FuncServer{
+			ServeImpl: outboxRelay.Run,
+			StopImpl:  outboxRelay.Stop,
+		})
+	}
+
 	serverHub.AddServer(server.NewGrpcServer(
 		baseServer,
 		server.GrpcServerConfig{ServeAddress: config.ServeGRPCAddress},
@@ -91,23 +234,41 @@ func runService(config *config, logger log.MainLogger) error {
 
 	serverHub.AddServer(&server.FuncServer{
 		ServeImpl: func() error {
-			grpcGatewayMux := runtime.NewServeMux()
-			opts := []grpc.DialOption{grpc.WithInsecure()}
-			err := userservice.RegisterUserServiceHandlerFromEndpoint(ctx, grpcGatewayMux, config.ServeGRPCAddress, opts)
+			grpcGatewayMux := transport.NewGatewayMux(container.SessionService(), container.UserDescriptorSerializer(), transport.GatewayConfig{
+				SessionCookieName:   config.SessionCookieName,
+				SessionCookieMaxAge: config.SessionCookieMaxAge,
+				SessionCookieSecure: config.SessionCookieSecure,
+			})
+			err := registerGatewayHandlers(ctx, grpcGatewayMux, config.ServeGRPCAddress)
 			if err != nil {
 				return err
 			}
 
 			router := mux.NewRouter()
-			router.PathPrefix("/api/").Handler(grpcGatewayMux)
+			router.PathPrefix("/api/").Handler(otelhttp.NewHandler(grpcGatewayMux, "grpc-gateway"))
+			router.HandleFunc("/data-exports/download", transport.NewDataExportDownloadHandler(container.DataExportService())).Methods(http.MethodGet)
+
+			oauth2Config := transport.OAuth2Config{
+				SessionCookieName: config.SessionCookieName,
+				LoginURL:          config.OAuth2LoginURL,
+				ConsentURL:        config.OAuth2ConsentURL,
+			}
+			router.HandleFunc(transport.OAuth2AuthorizePath, transport.NewOAuth2AuthorizeHandler(container.OAuth2Service(), container.SessionService(), oauth2Config)).
+				Methods(http.MethodGet, http.MethodPost)
+			router.HandleFunc(transport.OAuth2TokenPath, transport.NewOAuth2TokenHandler(container.OAuth2Service())).Methods(http.MethodPost)
+			router.HandleFunc(transport.OAuth2DeviceAuthorizationPath, transport.NewOAuth2DeviceAuthorizationHandler(container.OAuth2Service())).Methods(http.MethodPost)
+			router.HandleFunc(transport.OAuth2UserInfoPath, transport.NewOAuth2UserInfoHandler(container.OAuth2Service(), container.UserQueryService())).
+				Methods(http.MethodGet, http.MethodPost)
+			router.HandleFunc(transport.OAuth2JWKSPath, transport.NewOAuth2JWKSHandler(container.OAuth2Service())).Methods(http.MethodGet)
+			router.HandleFunc(transport.OAuth2DiscoveryPath, transport.NewOAuth2DiscoveryHandler(container.OAuth2Service())).Methods(http.MethodGet)
+			router.HandleFunc(transport.OAuth2MetadataPath, transport.NewOAuth2DiscoveryHandler(container.OAuth2Service())).Methods(http.MethodGet)
 
-			router.HandleFunc("/resilience/ready", func(w http.ResponseWriter, _ *http.Request) {
-				w.WriteHeader(http.StatusOK)
-				_, _ = io.WriteString(w, http.StatusText(http.StatusOK))
-			}).Methods(http.MethodGet)
+			router.Handle(metrics.Path, metrics.NewHandler()).Methods(http.MethodGet)
+			router.HandleFunc("/resilience/live", transport.NewLivenessHandler()).Methods(http.MethodGet)
+			router.HandleFunc("/resilience/ready", transport.NewReadinessHandler(checker)).Methods(http.MethodGet)
 
 			httpServer = &http.Server{
-				Handler:      transport.NewLoggingMiddleware(router, logger),
+				Handler:      transport.NewLoggingMiddleware(transport.NewCORSMiddleware(router, config.CORSAllowedOrigins), logger, metrics.NewHTTPMetrics()),
 				Addr:         config.ServeRESTAddress,
 				WriteTimeout: 15 * time.Second,
 				ReadTimeout:  15 * time.Second,
@@ -125,6 +286,64 @@ func runService(config *config, logger log.MainLogger) error {
 	return serverHub.Run()
 }
 
+func registerGatewayHandlers(ctx context.Context, grpcGatewayMux *runtime.ServeMux, grpcAddress string) error {
+	opts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+	}
+
+	registrators := []func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error{
+		userservice.RegisterUserServiceHandlerFromEndpoint,
+		authenticationservice.RegisterAuthenticationServiceHandlerFromEndpoint,
+		authorizationservice.RegisterAuthorizationServiceHandlerFromEndpoint,
+	}
+	for _, register := range registrators {
+		err := register(ctx, grpcGatewayMux, grpcAddress, opts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func makeDSN(config *config.Config) mysql.DSN {
+	return mysql.DSN{
+		User:     config.DatabaseUser,
+		Password: config.DatabasePassword,
+		Host:     config.DatabaseHost,
+		Database: config.DatabaseName,
+	}
+}
+
+func makeHealthChecker(config *config.Config, client infrastructuremysql.TransactionalClient, migrator infrastructuremysql.Migrator) health.Checker {
+	checker := health.NewChecker(config.HealthCheckTimeout)
+	checker.AddCheck(databaseHealthCheck, health.NewDatabaseCheck(client))
+	checker.AddCheck(migrationsHealthCheck, func(context.Context) error {
+		_, err := migrator.CheckSchemaVersion()
+		return err
+	})
+	if config.AMQPHost != "" {
+		checker.AddCheck(amqpHealthCheck, health.NewAMQPCheck(health.AMQPConfig{
+			User:     config.AMQPUser,
+			Password: config.AMQPPassword,
+			Host:     config.AMQPHost,
+		}))
+	}
+	return checker
+}
+
+func grpcServiceHealthDependencies(baseServer *grpc.Server) map[string][]string {
+	dependencies := map[string][]string{}
+	for service := range baseServer.GetServiceInfo() {
+		if service == healthpb.Health_ServiceDesc.ServiceName {
+			continue
+		}
+		dependencies[service] = []string{databaseHealthCheck, migrationsHealthCheck}
+	}
+	return dependencies
+}
+
 func initLogger() (log.MainLogger, error) {
 	return jsonlog.NewLogger(&jsonlog.Config{AppName: appID}), nil
 }
@@ -138,10 +357,51 @@ func listenForKillSignal(stopChan chan<- struct{}) {
 	}()
 }
 
-func makeGRPCUnaryInterceptor(logger log.Logger) grpc.UnaryServerInterceptor {
+func makeGRPCUnaryInterceptor(
+	logger log.Logger,
+	grpcMetrics metrics.GRPCMetrics,
+	deadlineConfig transport.DeadlineConfig,
+	trustedProxies transport.TrustedProxies,
+	serializer commonauth.UserDescriptorSerializer,
+) grpc.UnaryServerInterceptor {
+	tracingInterceptor := otelgrpc.UnaryServerInterceptor()
 	loggerInterceptor := transport.NewLoggerServerInterceptor(logger)
+	metricsInterceptor := transport.NewMetricsServerInterceptor(grpcMetrics)
+	deadlineInterceptor := transport.NewDeadlineServerInterceptor(deadlineConfig)
+	clientIPInterceptor := transport.NewClientIPServerInterceptor(trustedProxies)
+	auditContextInterceptor := transport.NewAuditContextServerInterceptor(serializer)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
-		resp, err = loggerInterceptor(ctx, req, info, handler)
+		// metrics interceptor goes before logger interceptor to observe status codes already translated by it
+		resp, err = tracingInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return metricsInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return loggerInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return deadlineInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+						return clientIPInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+							return auditContextInterceptor(ctx, req, info, handler)
+						})
+					})
+				})
+			})
+		})
 		return resp, err
 	}
 }
+
+func makeGRPCStreamInterceptor(logger log.Logger, grpcMetrics metrics.GRPCMetrics, trustedProxies transport.TrustedProxies) grpc.StreamServerInterceptor {
+	tracingInterceptor := otelgrpc.StreamServerInterceptor()
+	loggerInterceptor := transport.NewLoggerStreamServerInterceptor(logger)
+	metricsInterceptor := transport.NewMetricsStreamServerInterceptor(grpcMetrics)
+	clientIPInterceptor := transport.NewClientIPStreamServerInterceptor(trustedProxies)
+	auditContextInterceptor := transport.NewAuditContextStreamServerInterceptor()
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return tracingInterceptor(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+			return metricsInterceptor(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+				return loggerInterceptor(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+					return clientIPInterceptor(srv, stream, info, func(srv interface{}, stream grpc.ServerStream) error {
+						return auditContextInterceptor(srv, stream, info, handler)
+					})
+				})
+			})
+		})
+	}
+}
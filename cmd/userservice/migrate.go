@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
+	"github.com/pkg/errors"
+
+	"userservice/cmd/internal/config"
+	migrationsembedder "userservice/data/mysql"
+	infrastructuremysql "userservice/pkg/userservice/infrastructure/mysql"
+)
+
+const migrateCommand = "migrate"
+
+var errInvalidMigrateArgs = errors.New("usage: migrate up | down [N] | status | redo")
+
+func runMigrate(config *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet(migrateCommand, flag.ContinueOnError)
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return errInvalidMigrateArgs
+	}
+
+	connector := mysql.NewConnector()
+	err = connector.Open(makeDSN(config), 1)
+	if err != nil {
+		return err
+	}
+	defer connector.Close()
+
+	migrator, err := infrastructuremysql.NewMigrator(connector.Client(), migrationsembedder.MigrationsEmbedder)
+	if err != nil {
+		return err
+	}
+
+	switch flags.Arg(0) {
+	case "up":
+		n, err := migrator.Up()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "applied %d migrations\n", n)
+		return errors.WithStack(err)
+	case "down":
+		max := 1
+		if flags.NArg() > 1 {
+			max, err = strconv.Atoi(flags.Arg(1))
+			if err != nil || max < 1 {
+				return errInvalidMigrateArgs
+			}
+		}
+		n, err := migrator.Down(max)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "rolled back %d migrations\n", n)
+		return errors.WithStack(err)
+	case "redo":
+		id, err := migrator.Redo()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "reapplied migration %s\n", id)
+		return errors.WithStack(err)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		return printMigrationStatuses(statuses, out)
+	default:
+		return errInvalidMigrateArgs
+	}
+}
+
+func printMigrationStatuses(statuses []infrastructuremysql.MigrationStatus, out io.Writer) error {
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "MIGRATION\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Unknown {
+			appliedAt += " (unknown to this binary)"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\n", status.ID, appliedAt)
+	}
+	return errors.WithStack(writer.Flush())
+}
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE `user`
+    ADD COLUMN `failed_login_attempts` int NOT NULL DEFAULT 0,
+    ADD COLUMN `locked_until` datetime NULL;
+
+-- +migrate Down
+ALTER TABLE `user`
+    DROP COLUMN `failed_login_attempts`,
+    DROP COLUMN `locked_until`;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `user`
+    ADD COLUMN `password_algorithm` varchar(32) NOT NULL DEFAULT 'salted-sha1' AFTER `password`;
+
+-- +migrate Down
+ALTER TABLE `user`
+    DROP COLUMN `password_algorithm`;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE `user`
+    ADD COLUMN `deleted_at` datetime NULL,
+    ADD INDEX `user_deleted_at_index` (`deleted_at`);
+
+-- +migrate Down
+ALTER TABLE `user`
+    DROP INDEX `user_deleted_at_index`,
+    DROP COLUMN `deleted_at`;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE `data_export`
+(
+    `data_export_id` binary(16) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `status` smallint(2) NOT NULL,
+    `download_token_hash` char(64) NOT NULL,
+    `requested_at` datetime NOT NULL,
+    `started_at` datetime NULL,
+    `completed_at` datetime NULL,
+    `expires_at` datetime NULL,
+    `error` text NOT NULL,
+    PRIMARY KEY (`data_export_id`),
+    UNIQUE INDEX `data_export_download_token_hash_index` (`download_token_hash`),
+    INDEX `data_export_status_index` (`status`, `requested_at`),
+    INDEX `data_export_user_id_index` (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `data_export`;
@@ -0,0 +1,20 @@
+-- +migrate Up
+ALTER TABLE `user`
+    ADD COLUMN `erased_at` datetime NULL;
+
+CREATE TABLE `erasure_certificate`
+(
+    `erasure_certificate_id` binary(16) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `requested_by` binary(16) NULL,
+    `erased_at` datetime NOT NULL,
+    `scopes` varchar(1024) NOT NULL,
+    PRIMARY KEY (`erasure_certificate_id`),
+    INDEX `erasure_certificate_user_id_index` (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `erasure_certificate`;
+
+ALTER TABLE `user`
+    DROP COLUMN `erased_at`;
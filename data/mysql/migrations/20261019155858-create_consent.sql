@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE `consent`
+(
+    `consent_id` binary(16) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `kind` smallint(2) NOT NULL,
+    `version` varchar(64) NOT NULL,
+    `channel` varchar(32) NOT NULL,
+    `granted` tinyint(1) NOT NULL,
+    `source_ip` varchar(45) NOT NULL,
+    `recorded_at` datetime(6) NOT NULL,
+    PRIMARY KEY (`consent_id`),
+    INDEX `consent_user_id_index` (`user_id`, `recorded_at`)
+);
+
+-- +migrate Down
+DROP TABLE `consent`;
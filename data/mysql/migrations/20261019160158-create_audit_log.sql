@@ -0,0 +1,71 @@
+-- +migrate Up
+CREATE TABLE `audit_log`
+(
+    `audit_log_id` bigint NOT NULL AUTO_INCREMENT,
+    `action` varchar(64) NOT NULL,
+    `actor_id` binary(16) NULL,
+    `target_id` binary(16) NULL,
+    `request_id` varchar(64) NOT NULL,
+    `details` text NOT NULL,
+    `occurred_at` datetime(6) NOT NULL,
+    PRIMARY KEY (`audit_log_id`),
+    INDEX `audit_log_actor_id_index` (`actor_id`, `audit_log_id`),
+    INDEX `audit_log_target_id_index` (`target_id`, `audit_log_id`),
+    INDEX `audit_log_occurred_at_index` (`occurred_at`)
+);
+
+-- ip and user agent are personal data, they are kept outside of hash chain so erasure can remove them
+CREATE TABLE `audit_log_client`
+(
+    `audit_log_id` bigint NOT NULL,
+    `ip` varchar(45) NOT NULL,
+    `user_agent` varchar(255) NOT NULL,
+    PRIMARY KEY (`audit_log_id`)
+);
+
+-- entries are chained after commit by sealer, so audited transactions do not wait for each other
+CREATE TABLE `audit_log_seal`
+(
+    `seal_seq` bigint NOT NULL,
+    `audit_log_id` bigint NOT NULL,
+    `prev_hash` char(64) NOT NULL,
+    `hash` char(64) NOT NULL,
+    PRIMARY KEY (`seal_seq`),
+    UNIQUE INDEX `audit_log_seal_audit_log_id_index` (`audit_log_id`)
+);
+
+CREATE TABLE `audit_log_pending`
+(
+    `audit_log_id` bigint NOT NULL,
+    PRIMARY KEY (`audit_log_id`)
+);
+
+-- single row holds end of hash chain, it is locked only by sealer
+CREATE TABLE `audit_log_head`
+(
+    `audit_log_head_id` tinyint NOT NULL,
+    `last_seal_seq` bigint NOT NULL,
+    `last_hash` char(64) NOT NULL,
+    PRIMARY KEY (`audit_log_head_id`)
+);
+
+INSERT INTO `audit_log_head` (`audit_log_head_id`, `last_seal_seq`, `last_hash`) VALUES (1, 0, '');
+
+CREATE TRIGGER `audit_log_forbid_update` BEFORE UPDATE ON `audit_log`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
+CREATE TRIGGER `audit_log_forbid_delete` BEFORE DELETE ON `audit_log`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
+CREATE TRIGGER `audit_log_seal_forbid_update` BEFORE UPDATE ON `audit_log_seal`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log_seal is append-only';
+
+CREATE TRIGGER `audit_log_seal_forbid_delete` BEFORE DELETE ON `audit_log_seal`
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log_seal is append-only';
+
+-- +migrate Down
+DROP TABLE `audit_log_head`;
+DROP TABLE `audit_log_pending`;
+DROP TABLE `audit_log_seal`;
+DROP TABLE `audit_log_client`;
+DROP TABLE `audit_log`;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `profile`
+(
+    `user_id` binary(16) NOT NULL,
+    `display_name` varchar(64) NOT NULL,
+    `avatar_url` varchar(512) NOT NULL,
+    `country` char(2) NOT NULL,
+    `language` varchar(35) NOT NULL,
+    `date_of_birth` date NULL,
+    `bio` text NOT NULL,
+    `updated_at` datetime NOT NULL,
+    PRIMARY KEY (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `profile`;
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE `creator_application`
+(
+    `creator_application_id` binary(16) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `status` smallint(2) NOT NULL,
+    `statement` text NOT NULL,
+    `reviewer_id` binary(16) NULL,
+    `reviewer_notes` text NOT NULL,
+    `applied_at` datetime NOT NULL,
+    `review_started_at` datetime NULL,
+    `decided_at` datetime NULL,
+    PRIMARY KEY (`creator_application_id`),
+    INDEX `creator_application_status_index` (`status`, `applied_at`),
+    INDEX `creator_application_user_id_index` (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `creator_application`;
@@ -0,0 +1,41 @@
+-- +migrate Up
+CREATE TABLE `artist`
+(
+    `artist_id` binary(16) NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`artist_id`)
+);
+
+CREATE TABLE `artist_member`
+(
+    `artist_id` binary(16) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `role` smallint(2) NOT NULL,
+    `joined_at` datetime NOT NULL,
+    PRIMARY KEY (`artist_id`, `user_id`),
+    INDEX `artist_member_user_id_index` (`user_id`)
+);
+
+CREATE TABLE `artist_invitation`
+(
+    `artist_invitation_id` binary(16) NOT NULL,
+    `artist_id` binary(16) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `role` smallint(2) NOT NULL,
+    `status` smallint(2) NOT NULL,
+    `invited_by` binary(16) NOT NULL,
+    `invitee_id` binary(16) NULL,
+    `created_at` datetime NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `responded_at` datetime NULL,
+    PRIMARY KEY (`artist_invitation_id`),
+    INDEX `artist_invitation_artist_id_status_index` (`artist_id`, `status`),
+    INDEX `artist_invitation_email_status_index` (`email`, `status`),
+    INDEX `artist_invitation_invitee_id_index` (`invitee_id`)
+);
+
+-- +migrate Down
+DROP TABLE `artist_invitation`;
+DROP TABLE `artist_member`;
+DROP TABLE `artist`;
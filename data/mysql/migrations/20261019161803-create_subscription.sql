@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `subscription`
+(
+    `user_id` binary(16) NOT NULL,
+    `plan` smallint(2) NOT NULL,
+    `status` smallint(2) NOT NULL,
+    `period_start` datetime NULL,
+    `period_end` datetime NULL,
+    `trial` tinyint(1) NOT NULL,
+    `trial_used` tinyint(1) NOT NULL,
+    `updated_at` datetime NOT NULL,
+    PRIMARY KEY (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `subscription`;
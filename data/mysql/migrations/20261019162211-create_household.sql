@@ -0,0 +1,40 @@
+-- +migrate Up
+CREATE TABLE `household`
+(
+    `household_id` binary(16) NOT NULL,
+    `manager_id` binary(16) NOT NULL,
+    `address_hash` char(64) NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`household_id`),
+    UNIQUE INDEX `household_manager_id_index` (`manager_id`)
+);
+
+CREATE TABLE `household_member`
+(
+    `household_id` binary(16) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `joined_at` datetime NOT NULL,
+    PRIMARY KEY (`household_id`, `user_id`),
+    UNIQUE INDEX `household_member_user_id_index` (`user_id`)
+);
+
+CREATE TABLE `household_invitation`
+(
+    `household_invitation_id` binary(16) NOT NULL,
+    `household_id` binary(16) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `status` smallint(2) NOT NULL,
+    `invitee_id` binary(16) NULL,
+    `created_at` datetime NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `responded_at` datetime NULL,
+    PRIMARY KEY (`household_invitation_id`),
+    INDEX `household_invitation_household_id_status_index` (`household_id`, `status`),
+    INDEX `household_invitation_email_status_index` (`email`, `status`),
+    INDEX `household_invitation_invitee_id_index` (`invitee_id`)
+);
+
+-- +migrate Down
+DROP TABLE `household_invitation`;
+DROP TABLE `household_member`;
+DROP TABLE `household`;
@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE `parental_controls`
+(
+    `user_id` binary(16) NOT NULL,
+    `guardian_id` binary(16) NULL,
+    `explicit_content_filter` tinyint(1) NULL,
+    `pin_hash` varchar(255) NOT NULL DEFAULT '',
+    `failed_pin_attempts` int NOT NULL DEFAULT 0,
+    `pin_locked_until` datetime NULL,
+    `updated_at` datetime NOT NULL,
+    PRIMARY KEY (`user_id`),
+    INDEX `parental_controls_guardian_id_index` (`guardian_id`)
+);
+
+-- +migrate Down
+DROP TABLE `parental_controls`;
@@ -0,0 +1,34 @@
+-- +migrate Up
+CREATE TABLE `follow`
+(
+    `follower_id` binary(16) NOT NULL,
+    `target_kind` smallint(2) NOT NULL,
+    `target_id` binary(16) NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`follower_id`, `target_kind`, `target_id`),
+    INDEX `follow_target_index` (`target_kind`, `target_id`, `created_at`),
+    INDEX `follow_follower_id_created_at_index` (`follower_id`, `created_at`)
+);
+
+CREATE TABLE `follow_count`
+(
+    `target_kind` smallint(2) NOT NULL,
+    `target_id` binary(16) NOT NULL,
+    `followers` int NOT NULL DEFAULT 0,
+    `following` int NOT NULL DEFAULT 0,
+    PRIMARY KEY (`target_kind`, `target_id`)
+);
+
+CREATE TABLE `user_block`
+(
+    `blocker_id` binary(16) NOT NULL,
+    `blocked_id` binary(16) NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`blocker_id`, `blocked_id`),
+    INDEX `user_block_blocked_id_index` (`blocked_id`)
+);
+
+-- +migrate Down
+DROP TABLE `user_block`;
+DROP TABLE `follow_count`;
+DROP TABLE `follow`;
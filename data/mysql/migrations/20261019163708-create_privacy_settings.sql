@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE `privacy_settings`
+(
+    `user_id` binary(16) NOT NULL,
+    `profile_visibility` smallint(2) NOT NULL,
+    `who_can_follow` smallint(2) NOT NULL,
+    `listening_activity_public` tinyint(1) NOT NULL,
+    `updated_at` datetime NOT NULL,
+    PRIMARY KEY (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `privacy_settings`;
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE `external_identity`
+(
+    `issuer` varchar(255) NOT NULL,
+    `subject` varchar(255) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `provider` varchar(64) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `linked_at` datetime NOT NULL,
+    PRIMARY KEY (`issuer`, `subject`),
+    UNIQUE INDEX `external_identity_user_id_provider_index` (`user_id`, `provider`)
+);
+
+CREATE TABLE `external_login_request`
+(
+    `state` varchar(64) NOT NULL,
+    `provider` varchar(64) NOT NULL,
+    `code_verifier` varchar(128) NOT NULL,
+    `nonce` varchar(64) NOT NULL,
+    `link_user_id` binary(16),
+    `expires_at` datetime NOT NULL,
+    PRIMARY KEY (`state`),
+    INDEX `external_login_request_expires_at_index` (`expires_at`),
+    INDEX `external_login_request_link_user_id_index` (`link_user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `external_login_request`;
+DROP TABLE `external_identity`;
@@ -0,0 +1,49 @@
+-- +migrate Up
+CREATE TABLE `oauth2_client`
+(
+    `client_id` varchar(64) NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `secret_hash` varchar(64) NOT NULL,
+    `redirect_uris` text NOT NULL,
+    `scopes` text NOT NULL,
+    `grant_types` varchar(255) NOT NULL,
+    `first_party` tinyint(1) NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`client_id`)
+);
+
+CREATE TABLE `oauth2_authorization_code`
+(
+    `code_hash` varchar(64) NOT NULL,
+    `client_id` varchar(64) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `redirect_uri` text NOT NULL,
+    `scopes` text NOT NULL,
+    `code_challenge` varchar(128) NOT NULL,
+    `nonce` varchar(255) NOT NULL,
+    `expires_at` datetime NOT NULL,
+    PRIMARY KEY (`code_hash`),
+    INDEX `oauth2_authorization_code_expires_at_index` (`expires_at`),
+    INDEX `oauth2_authorization_code_user_id_index` (`user_id`),
+    INDEX `oauth2_authorization_code_client_id_index` (`client_id`)
+);
+
+CREATE TABLE `oauth2_token`
+(
+    `token_hash` varchar(64) NOT NULL,
+    `kind` tinyint NOT NULL,
+    `client_id` varchar(64) NOT NULL,
+    `user_id` binary(16),
+    `scopes` text NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`token_hash`),
+    INDEX `oauth2_token_expires_at_index` (`expires_at`),
+    INDEX `oauth2_token_user_id_index` (`user_id`),
+    INDEX `oauth2_token_client_id_index` (`client_id`)
+);
+
+-- +migrate Down
+DROP TABLE `oauth2_token`;
+DROP TABLE `oauth2_authorization_code`;
+DROP TABLE `oauth2_client`;
@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE `oauth2_device_authorization`
+(
+    `device_code_hash` varchar(64) NOT NULL,
+    `user_code` varchar(16) NOT NULL,
+    `client_id` varchar(64) NOT NULL,
+    `scopes` text NOT NULL,
+    `status` tinyint NOT NULL,
+    `user_id` binary(16),
+    `poll_interval` int NOT NULL,
+    `last_polled_at` datetime,
+    `expires_at` datetime NOT NULL,
+    PRIMARY KEY (`device_code_hash`),
+    UNIQUE INDEX `oauth2_device_authorization_user_code_index` (`user_code`),
+    INDEX `oauth2_device_authorization_expires_at_index` (`expires_at`),
+    INDEX `oauth2_device_authorization_user_id_index` (`user_id`),
+    INDEX `oauth2_device_authorization_client_id_index` (`client_id`)
+);
+
+-- +migrate Down
+DROP TABLE `oauth2_device_authorization`;
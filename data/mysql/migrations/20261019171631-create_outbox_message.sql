@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE `outbox_message`
+(
+    `outbox_message_id` bigint NOT NULL AUTO_INCREMENT,
+    `routing_key` varchar(255) NOT NULL,
+    `body` mediumblob NOT NULL,
+    `created_at` datetime(6) NOT NULL,
+    PRIMARY KEY (`outbox_message_id`)
+);
+
+-- +migrate Down
+DROP TABLE `outbox_message`;
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- user has at most one application waiting for decision, applied (0) or under review (1)
+ALTER TABLE `creator_application`
+    ADD COLUMN `open_user_id` binary(16) AS (IF(`status` IN (0, 1), `user_id`, NULL)) STORED,
+    ADD UNIQUE INDEX `creator_application_open_user_id_index` (`open_user_id`);
+
+-- +migrate Down
+ALTER TABLE `creator_application`
+    DROP INDEX `creator_application_open_user_id_index`,
+    DROP COLUMN `open_user_id`;
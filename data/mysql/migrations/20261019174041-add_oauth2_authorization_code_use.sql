@@ -0,0 +1,16 @@
+-- +migrate Up
+-- exchanged codes are kept until expiry, so replay is detected and revokes tokens issued for code
+ALTER TABLE `oauth2_authorization_code`
+    ADD COLUMN `used_at` datetime;
+
+ALTER TABLE `oauth2_token`
+    ADD COLUMN `authorization_code_hash` varchar(64) NOT NULL DEFAULT '',
+    ADD INDEX `oauth2_token_authorization_code_hash_index` (`authorization_code_hash`);
+
+-- +migrate Down
+ALTER TABLE `oauth2_token`
+    DROP INDEX `oauth2_token_authorization_code_hash_index`,
+    DROP COLUMN `authorization_code_hash`;
+
+ALTER TABLE `oauth2_authorization_code`
+    DROP COLUMN `used_at`;
@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE `oauth2_user_code_attempt`
+(
+    `user_id` binary(16) NOT NULL,
+    `failed_attempts` int NOT NULL,
+    `window_started_at` datetime NOT NULL,
+    `locked_until` datetime,
+    PRIMARY KEY (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `oauth2_user_code_attempt`;
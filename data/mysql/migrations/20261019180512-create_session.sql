@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE `session`
+(
+    `token_hash` varchar(64) NOT NULL,
+    `user_id` binary(16) NOT NULL,
+    `expires_at` datetime NOT NULL,
+    `created_at` datetime NOT NULL,
+    PRIMARY KEY (`token_hash`),
+    INDEX `session_expires_at_index` (`expires_at`),
+    INDEX `session_user_id_index` (`user_id`)
+);
+
+-- +migrate Down
+DROP TABLE `session`;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- email is compared case insensitively by collation, so binary comparison finds rows stored before normalization
+UPDATE `user` SET `email` = LOWER(TRIM(`email`)) WHERE BINARY `email` <> BINARY LOWER(TRIM(`email`));
+
+-- +migrate Down
+-- original spelling of emails is not kept
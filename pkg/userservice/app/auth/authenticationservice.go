@@ -1,51 +1,193 @@
 package auth
 
 import (
+	"context"
+	"time"
+
 	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/auth"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"userservice/pkg/userservice/app/hash"
 	"userservice/pkg/userservice/app/query"
 	appservice "userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
 )
 
 var (
 	ErrIncorrectAuthData         = errors.New("incorrect auth data")
 	ErrOnlyCreatorsCanAddContent = errors.New("only creators can add content")
+	ErrUserLocked                = errors.New("user is locked")
+	ErrAdminRequired             = errors.New("only admins can perform this action")
+	ErrNotArtistUploader         = errors.New("user can not add content on behalf of artist")
 )
 
+type AuthenticatedUser struct {
+	UserID                    string
+	Role                      appservice.Role
+	TermsReacceptanceRequired bool
+	Plan                      appservice.SubscriptionPlan
+	Entitlements              []string
+}
+
 type AuthenticationService interface {
-	AuthenticateUser(email, password string) (string, appservice.Role, error)
-	CanAddContent(descriptor auth.UserDescriptor) (bool, error)
+	AuthenticateUser(ctx context.Context, email, password string) (AuthenticatedUser, error)
+	AuthenticateExternalUser(ctx context.Context, state, code string, signUp appservice.ExternalSignUp) (AuthenticatedUser, error)
+	AuthenticateDevice(ctx context.Context, clientID, deviceCode string) (AuthenticatedUser, error)
+	CanAddContent(ctx context.Context, descriptor auth.UserDescriptor) (bool, error)
+	CanAddContentForArtist(ctx context.Context, descriptor auth.UserDescriptor, artistID uuid.UUID) (bool, error)
+	AssertAdmin(ctx context.Context, descriptor auth.UserDescriptor) error
 }
 
-func NewAuthenticationService(queryService query.UserQueryService, hasher hash.Hasher) AuthenticationService {
+// NewAuthenticationService reads credentials and lock state through credentialsQueryService, it must not be cached,
+// so lock or password change on other replica takes effect at once
+func NewAuthenticationService(
+	queryService query.UserQueryService,
+	credentialsQueryService query.UserQueryService,
+	userService appservice.UserService,
+	consentService appservice.ConsentService,
+	auditService appservice.AuditService,
+	artistQueryService query.ArtistQueryService,
+	subscriptionQueryService query.SubscriptionQueryService,
+	externalIdentityService appservice.ExternalIdentityService,
+	oauth2Service appservice.OAuth2Service,
+	verifier hash.Verifier,
+) AuthenticationService {
 	return &authenticationService{
-		queryService: queryService,
-		hasher:       hasher,
+		queryService:             queryService,
+		credentialsQueryService:  credentialsQueryService,
+		userService:              userService,
+		consentService:           consentService,
+		auditService:             auditService,
+		artistQueryService:       artistQueryService,
+		subscriptionQueryService: subscriptionQueryService,
+		externalIdentityService:  externalIdentityService,
+		oauth2Service:            oauth2Service,
+		verifier:                 verifier,
 	}
 }
 
 type authenticationService struct {
-	queryService query.UserQueryService
-	hasher       hash.Hasher
+	queryService             query.UserQueryService
+	credentialsQueryService  query.UserQueryService
+	userService              appservice.UserService
+	consentService           appservice.ConsentService
+	auditService             appservice.AuditService
+	artistQueryService       query.ArtistQueryService
+	subscriptionQueryService query.SubscriptionQueryService
+	externalIdentityService  appservice.ExternalIdentityService
+	oauth2Service            appservice.OAuth2Service
+	verifier                 hash.Verifier
+}
+
+func (service *authenticationService) AuthenticateUser(ctx context.Context, email, password string) (AuthenticatedUser, error) {
+	// unknown, deleted, erased and locked users get same error as wrong password, so emails can not be enumerated
+	user, err := service.credentialsQueryService.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrUserNotFound {
+			// attempted email is not recorded, it may be personal data of someone else
+			return AuthenticatedUser{}, service.recordLoginFailure(ctx, nil, "user_not_found", ErrIncorrectAuthData)
+		}
+		return AuthenticatedUser{}, err
+	}
+
+	if user.IsLocked(time.Now()) {
+		return AuthenticatedUser{}, service.recordLoginFailure(ctx, &user.ID, "user_locked", ErrIncorrectAuthData)
+	}
+
+	algorithm := hash.Algorithm(user.PasswordAlgorithm)
+	ok, err := service.verifier.Verify(algorithm, user.Password, password)
+	if err != nil {
+		return AuthenticatedUser{}, err
+	}
+	if !ok {
+		err = service.userService.RecordLoginFailure(ctx, user.ID)
+		if err != nil {
+			return AuthenticatedUser{}, err
+		}
+		return AuthenticatedUser{}, ErrIncorrectAuthData
+	}
+
+	if algorithm != hash.Native {
+		err = service.userService.ChangePassword(ctx, user.ID, password)
+		if err != nil {
+			return AuthenticatedUser{}, err
+		}
+	}
+
+	return service.completeLogin(ctx, user)
+}
+
+func (service *authenticationService) AuthenticateExternalUser(ctx context.Context, state, code string, signUp appservice.ExternalSignUp) (AuthenticatedUser, error) {
+	signIn, err := service.externalIdentityService.CompleteSignIn(ctx, state, code, signUp)
+	if err != nil {
+		return AuthenticatedUser{}, err
+	}
+
+	user, err := service.credentialsQueryService.GetUser(ctx, signIn.UserID)
+	if err != nil {
+		return AuthenticatedUser{}, err
+	}
+	// lock is not lifted by signing in through provider, it would bypass lockout of password login
+	if user.IsLocked(time.Now()) {
+		return AuthenticatedUser{}, service.recordLoginFailure(ctx, &user.ID, "user_locked", ErrUserLocked)
+	}
+
+	return service.completeLogin(ctx, user)
+}
+
+func (service *authenticationService) AuthenticateDevice(ctx context.Context, clientID, deviceCode string) (AuthenticatedUser, error) {
+	userID, err := service.oauth2Service.AuthenticateDevice(ctx, appservice.OAuth2ClientCredentials{ClientID: clientID}, deviceCode)
+	if err != nil {
+		return AuthenticatedUser{}, err
+	}
+
+	user, err := service.credentialsQueryService.GetUser(ctx, userID)
+	if err != nil {
+		return AuthenticatedUser{}, err
+	}
+	if user.IsLocked(time.Now()) {
+		return AuthenticatedUser{}, service.recordLoginFailure(ctx, &user.ID, "user_locked", ErrUserLocked)
+	}
+
+	return service.completeLogin(ctx, user)
 }
 
-func (service *authenticationService) AuthenticateUser(email, password string) (string, appservice.Role, error) {
-	user, err := service.queryService.GetByEmail(email)
+func (service *authenticationService) completeLogin(ctx context.Context, user query.UserView) (AuthenticatedUser, error) {
+	err := service.userService.RecordLoginSuccess(ctx, user.ID)
 	if err != nil {
-		return "", 0, err
+		return AuthenticatedUser{}, err
 	}
 
-	if service.hasher.Hash(password) != user.Password {
-		return "", 0, ErrIncorrectAuthData
+	termsReacceptanceRequired, err := service.consentService.TermsReacceptanceRequired(ctx, user.ID)
+	if err != nil {
+		return AuthenticatedUser{}, err
+	}
+
+	entitlements, err := service.subscriptionQueryService.GetEntitlements(ctx, user.ID)
+	if err != nil {
+		return AuthenticatedUser{}, err
 	}
 
-	return user.ID.String(), appservice.Role(user.Role), err
+	return AuthenticatedUser{
+		UserID:                    user.ID.String(),
+		Role:                      appservice.Role(user.Role),
+		TermsReacceptanceRequired: termsReacceptanceRequired,
+		Plan:                      appservice.SubscriptionPlan(entitlements.Plan),
+		Entitlements:              entitlements.Entitlements,
+	}, nil
 }
 
-func (service *authenticationService) CanAddContent(userDescriptor auth.UserDescriptor) (bool, error) {
-	user, err := service.queryService.GetUser(userDescriptor.UserID)
+func (service *authenticationService) recordLoginFailure(ctx context.Context, userID *uuid.UUID, reason string, err error) error {
+	auditErr := service.auditService.Record(ctx, appservice.AuditLoginFailed, userID, map[string]string{"reason": reason})
+	if auditErr != nil {
+		return auditErr
+	}
+	return err
+}
+
+func (service *authenticationService) CanAddContent(ctx context.Context, userDescriptor auth.UserDescriptor) (bool, error) {
+	user, err := service.queryService.GetUser(ctx, userDescriptor.UserID)
 	if err != nil {
 		return false, err
 	}
@@ -57,3 +199,35 @@ func (service *authenticationService) CanAddContent(userDescriptor auth.UserDesc
 
 	return true, nil
 }
+
+func (service *authenticationService) CanAddContentForArtist(ctx context.Context, userDescriptor auth.UserDescriptor, artistID uuid.UUID) (bool, error) {
+	_, err := service.queryService.GetUser(ctx, userDescriptor.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	role, err := service.artistQueryService.GetMemberRole(ctx, artistID, userDescriptor.UserID)
+	if errors.Cause(err) == domain.ErrArtistMemberNotFound {
+		return false, ErrNotArtistUploader
+	}
+	if err != nil {
+		return false, err
+	}
+	if role < query.ArtistUploader {
+		return false, ErrNotArtistUploader
+	}
+
+	return true, nil
+}
+
+func (service *authenticationService) AssertAdmin(ctx context.Context, userDescriptor auth.UserDescriptor) error {
+	user, err := service.queryService.GetUser(ctx, userDescriptor.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role != query.Admin {
+		return ErrAdminRequired
+	}
+	return nil
+}
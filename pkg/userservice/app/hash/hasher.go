@@ -2,8 +2,14 @@
 package hash
 
 import (
+	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type Hasher interface {
@@ -24,3 +30,35 @@ func (h *sha1Hasher) Hash(value string) string {
 
 	return fmt.Sprintf("%x", hash.Sum([]byte(h.salt)))
 }
+
+func NewHMACHasher(key string) Hasher {
+	return &hmacHasher{key: []byte(key)}
+}
+
+type hmacHasher struct {
+	key []byte
+}
+
+func (h *hmacHasher) Hash(value string) string {
+	mac := hmac.New(sha256.New, h.key)
+	_, _ = mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+}
+
+func NewBCryptHasher() PasswordHasher {
+	return &bcryptHasher{}
+}
+
+type bcryptHasher struct{}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to hash password")
+	}
+	return string(hash), nil
+}
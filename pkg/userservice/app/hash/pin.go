@@ -0,0 +1,26 @@
+package hash
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"userservice/pkg/userservice/domain"
+)
+
+func NewPINHasher() domain.PINHasher {
+	return &pinHasher{}
+}
+
+type pinHasher struct{}
+
+func (h *pinHasher) Hash(pin string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to hash pin")
+	}
+	return string(hash), nil
+}
+
+func (h *pinHasher) Matches(hash, pin string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(pin)) == nil
+}
@@ -0,0 +1,59 @@
+package hash
+
+import (
+	"crypto/subtle"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type Algorithm string
+
+const (
+	SaltedSHA1 Algorithm = "salted-sha1"
+	BCrypt     Algorithm = "bcrypt"
+	NoPassword           = Algorithm(domain.NoPasswordAlgorithm)
+	Native               = BCrypt
+)
+
+var ErrUnsupportedAlgorithm = errors.New("unsupported password hash algorithm")
+
+func IsSupported(algorithm Algorithm) bool {
+	switch algorithm {
+	case SaltedSHA1, BCrypt:
+		return true
+	default:
+		return false
+	}
+}
+
+type Verifier interface {
+	Verify(algorithm Algorithm, hash, password string) (bool, error)
+}
+
+func NewVerifier(hasher Hasher) Verifier {
+	return &verifier{hasher: hasher}
+}
+
+type verifier struct {
+	hasher Hasher
+}
+
+func (v *verifier) Verify(algorithm Algorithm, hash, password string) (bool, error) {
+	switch algorithm {
+	case SaltedSHA1:
+		return subtle.ConstantTimeCompare([]byte(v.hasher.Hash(password)), []byte(hash)) == 1, nil
+	case BCrypt:
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, nil
+		}
+		return err == nil, errors.Wrap(err, "failed to verify bcrypt hash")
+	case NoPassword:
+		return false, nil
+	default:
+		return false, errors.Wrap(ErrUnsupportedAlgorithm, string(algorithm))
+	}
+}
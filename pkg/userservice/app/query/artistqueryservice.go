@@ -0,0 +1,65 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type ArtistRole int
+
+const (
+	ArtistViewer   = ArtistRole(domain.ArtistViewer)
+	ArtistUploader = ArtistRole(domain.ArtistUploader)
+	ArtistManager  = ArtistRole(domain.ArtistManager)
+	ArtistOwner    = ArtistRole(domain.ArtistOwner)
+)
+
+type ArtistMemberView struct {
+	UserID   uuid.UUID
+	Role     ArtistRole
+	JoinedAt time.Time
+}
+
+type ArtistView struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	Members   []ArtistMemberView
+}
+
+func (view ArtistView) HasMember(userID uuid.UUID) bool {
+	for _, member := range view.Members {
+		if member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+type ArtistMembershipView struct {
+	ArtistID   uuid.UUID
+	ArtistName string
+	Role       ArtistRole
+	JoinedAt   time.Time
+}
+
+type ArtistInvitationView struct {
+	ID         uuid.UUID
+	ArtistID   uuid.UUID
+	ArtistName string
+	Role       ArtistRole
+	InvitedBy  uuid.UUID
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+type ArtistQueryService interface {
+	GetArtist(ctx context.Context, artistID uuid.UUID) (ArtistView, error)
+	ListUserArtists(ctx context.Context, userID uuid.UUID) ([]ArtistMembershipView, error)
+	ListInvitations(ctx context.Context, userID uuid.UUID) ([]ArtistInvitationView, error)
+	GetMemberRole(ctx context.Context, artistID, userID uuid.UUID) (ArtistRole, error)
+}
@@ -0,0 +1,34 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditEntryView struct {
+	Seq        int64
+	Action     string
+	ActorID    *uuid.UUID
+	TargetID   *uuid.UUID
+	IP         string
+	UserAgent  string
+	RequestID  string
+	Details    map[string]string
+	OccurredAt time.Time
+	Hash       string
+}
+
+type AuditLogSpec struct {
+	UserID    *uuid.UUID
+	Action    string
+	From      *time.Time
+	To        *time.Time
+	BeforeSeq int64
+	Limit     int
+}
+
+type AuditLogQueryService interface {
+	ListEntries(ctx context.Context, spec AuditLogSpec) ([]AuditEntryView, error)
+}
@@ -0,0 +1,36 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type CreatorApplicationStatus int
+
+const (
+	CreatorApplicationApplied     = CreatorApplicationStatus(domain.CreatorApplicationStatusApplied)
+	CreatorApplicationUnderReview = CreatorApplicationStatus(domain.CreatorApplicationStatusUnderReview)
+	CreatorApplicationApproved    = CreatorApplicationStatus(domain.CreatorApplicationStatusApproved)
+	CreatorApplicationRejected    = CreatorApplicationStatus(domain.CreatorApplicationStatusRejected)
+)
+
+type CreatorApplicationView struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Status          CreatorApplicationStatus
+	Statement       string
+	ReviewerID      *uuid.UUID
+	ReviewerNotes   string
+	AppliedAt       time.Time
+	ReviewStartedAt *time.Time
+	DecidedAt       *time.Time
+}
+
+type CreatorApplicationQueryService interface {
+	ListPendingApplications(ctx context.Context, offset, limit int) ([]CreatorApplicationView, error)
+	ListUserApplications(ctx context.Context, userID uuid.UUID) ([]CreatorApplicationView, error)
+}
@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type OAuth2TokenKind int
+
+const (
+	OAuth2AccessToken  = OAuth2TokenKind(domain.OAuth2AccessToken)
+	OAuth2RefreshToken = OAuth2TokenKind(domain.OAuth2RefreshToken)
+)
+
+type OAuth2ClientView struct {
+	ID           string
+	Name         string
+	RedirectURIs []string
+	Scopes       []string
+	GrantTypes   []string
+	Public       bool
+	FirstParty   bool
+	CreatedAt    time.Time
+}
+
+type OAuth2TokenView struct {
+	Kind      OAuth2TokenKind
+	ClientID  string
+	UserID    *uuid.UUID
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+type OAuth2QueryService interface {
+	GetClient(ctx context.Context, clientID string) (OAuth2ClientView, error)
+	// GetToken fails with domain.ErrOAuth2TokenNotFound also when user of token is deleted or erased
+	GetToken(ctx context.Context, tokenHash string) (OAuth2TokenView, error)
+}
@@ -0,0 +1,18 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionView struct {
+	UserID    uuid.UUID
+	ExpiresAt time.Time
+}
+
+type SessionQueryService interface {
+	// GetSession fails with domain.ErrSessionNotFound also when user of session is deleted or erased
+	GetSession(ctx context.Context, tokenHash string) (SessionView, error)
+}
@@ -0,0 +1,31 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type SubscriptionPlan int
+
+const (
+	FreePlan    = SubscriptionPlan(domain.FreePlan)
+	PremiumPlan = SubscriptionPlan(domain.PremiumPlan)
+	FamilyPlan  = SubscriptionPlan(domain.FamilyPlan)
+	StudentPlan = SubscriptionPlan(domain.StudentPlan)
+)
+
+type EntitlementsView struct {
+	Plan              SubscriptionPlan
+	Entitlements      []string
+	ValidUntil        *time.Time
+	Trial             bool
+	SharedByHousehold bool
+}
+
+type SubscriptionQueryService interface {
+	GetEntitlements(ctx context.Context, userID uuid.UUID) (EntitlementsView, error)
+}
@@ -1,6 +1,9 @@
 package query
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
@@ -12,6 +15,7 @@ type Role int
 const (
 	Listener = Role(domain.Listener)
 	Creator  = Role(domain.Creator)
+	Admin    = Role(domain.Admin)
 )
 
 var (
@@ -23,9 +27,25 @@ type UserView struct {
 	Email    string
 	Password string
 	Role     Role
+
+	PasswordAlgorithm string
+
+	FailedLoginAttempts int
+	LockedUntil         *time.Time
+}
+
+func (view UserView) IsLocked(at time.Time) bool {
+	return view.LockedUntil != nil && at.Before(*view.LockedUntil)
+}
+
+type ListUsersSpec struct {
+	Role   *Role
+	Offset int
+	Limit  int
 }
 
 type UserQueryService interface {
-	GetUser(id uuid.UUID) (UserView, error)
-	GetByEmail(email string) (UserView, error)
+	GetUser(ctx context.Context, id uuid.UUID) (UserView, error)
+	GetByEmail(ctx context.Context, email string) (UserView, error)
+	ListUsers(ctx context.Context, spec ListUsersSpec) ([]UserView, error)
 }
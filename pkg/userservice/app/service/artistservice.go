@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type ArtistRole int
+
+const (
+	ArtistViewer   = ArtistRole(domain.ArtistViewer)
+	ArtistUploader = ArtistRole(domain.ArtistUploader)
+	ArtistManager  = ArtistRole(domain.ArtistManager)
+	ArtistOwner    = ArtistRole(domain.ArtistOwner)
+)
+
+type ArtistService interface {
+	CreateArtist(ctx context.Context, ownerID uuid.UUID, name string) (uuid.UUID, error)
+	InviteMember(ctx context.Context, artistID, actorID uuid.UUID, email string, role ArtistRole) (uuid.UUID, error)
+	RespondToInvitation(ctx context.Context, invitationID, userID uuid.UUID, accept bool) error
+	ChangeMemberRole(ctx context.Context, artistID, actorID, userID uuid.UUID, role ArtistRole) error
+	RemoveMember(ctx context.Context, artistID, actorID, userID uuid.UUID) error
+	TransferOwnership(ctx context.Context, artistID, actorID, newOwnerID uuid.UUID) error
+}
+
+func NewArtistService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler) ArtistService {
+	return &artistService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+	}
+}
+
+type artistService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+}
+
+func (service *artistService) CreateArtist(ctx context.Context, ownerID uuid.UUID, name string) (uuid.UUID, error) {
+	var artistID domain.ArtistID
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		artistID, err = domainArtistService(provider, dispatcher).CreateArtist(ctx, domain.UserID(ownerID), name)
+		return err
+	})
+	return uuid.UUID(artistID), err
+}
+
+func (service *artistService) InviteMember(ctx context.Context, artistID, actorID uuid.UUID, email string, role ArtistRole) (uuid.UUID, error) {
+	var invitationID domain.ArtistInvitationID
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		invitationID, err = domainArtistService(provider, dispatcher).InviteMember(
+			ctx,
+			domain.ArtistID(artistID),
+			domain.UserID(actorID),
+			domain.NormalizeEmail(email),
+			domain.ArtistRole(role),
+		)
+		return err
+	})
+	return uuid.UUID(invitationID), err
+}
+
+func (service *artistService) RespondToInvitation(ctx context.Context, invitationID, userID uuid.UUID, accept bool) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainArtistService(provider, dispatcher).RespondToInvitation(ctx, domain.ArtistInvitationID(invitationID), domain.UserID(userID), accept)
+	})
+}
+
+func (service *artistService) ChangeMemberRole(ctx context.Context, artistID, actorID, userID uuid.UUID, role ArtistRole) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainArtistService(provider, dispatcher).ChangeMemberRole(
+			ctx,
+			domain.ArtistID(artistID),
+			domain.UserID(actorID),
+			domain.UserID(userID),
+			domain.ArtistRole(role),
+		)
+	})
+}
+
+func (service *artistService) RemoveMember(ctx context.Context, artistID, actorID, userID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainArtistService(provider, dispatcher).RemoveMember(ctx, domain.ArtistID(artistID), domain.UserID(actorID), domain.UserID(userID))
+	})
+}
+
+func (service *artistService) TransferOwnership(ctx context.Context, artistID, actorID, newOwnerID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainArtistService(provider, dispatcher).TransferOwnership(ctx, domain.ArtistID(artistID), domain.UserID(actorID), domain.UserID(newOwnerID))
+	})
+}
+
+func (service *artistService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func domainArtistService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.ArtistService {
+	return domain.NewArtistService(provider.UserRepository(), provider.ArtistRepository(), provider.ArtistInvitationRepository(), dispatcher)
+}
+
+func NewArtistMembershipEraser() PersonalDataEraser {
+	return &artistMembershipEraser{}
+}
+
+type artistMembershipEraser struct{}
+
+func (eraser *artistMembershipEraser) Scope() string {
+	return "artist_memberships"
+}
+
+func (eraser *artistMembershipEraser) Erase(ctx context.Context, provider RepositoryProvider, dispatcher domain.EventDispatcher, userID domain.UserID) error {
+	err := domainArtistService(provider, dispatcher).ReleaseMemberships(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return provider.ArtistInvitationRepository().RemoveByInvitee(ctx, userID)
+}
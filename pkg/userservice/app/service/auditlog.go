@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+const (
+	auditLogVerifyBatchSize = 1000
+	auditLogSealBatchSize   = 500
+)
+
+type AuditAction string
+
+const (
+	AuditUserRegistered      AuditAction = "user_registered"
+	AuditLoginSucceeded      AuditAction = "login_succeeded"
+	AuditLoginFailed         AuditAction = "login_failed"
+	AuditRoleChanged         AuditAction = "role_changed"
+	AuditEmailChanged        AuditAction = "email_changed"
+	AuditPasswordChanged     AuditAction = "password_changed"
+	AuditUserDeleted         AuditAction = "user_deleted"
+	AuditUserRestored        AuditAction = "user_restored"
+	AuditUserPurged          AuditAction = "user_purged"
+	AuditUserErased          AuditAction = "user_erased"
+	AuditUserUnlocked        AuditAction = "user_unlocked"
+	AuditDataExportRequested AuditAction = "data_export_requested"
+	AuditUsersExported       AuditAction = "users_exported"
+
+	AuditCreatorApplicationSubmitted     AuditAction = "creator_application_submitted"
+	AuditCreatorApplicationReviewStarted AuditAction = "creator_application_review_started"
+	AuditCreatorApplicationApproved      AuditAction = "creator_application_approved"
+	AuditCreatorApplicationRejected      AuditAction = "creator_application_rejected"
+
+	AuditArtistCreated            AuditAction = "artist_created"
+	AuditArtistMemberInvited      AuditAction = "artist_member_invited"
+	AuditArtistInvitationDeclined AuditAction = "artist_invitation_declined"
+	AuditArtistMemberAdded        AuditAction = "artist_member_added"
+	AuditArtistMemberRoleChanged  AuditAction = "artist_member_role_changed"
+	AuditArtistMemberRemoved      AuditAction = "artist_member_removed"
+	AuditArtistOwnershipChanged   AuditAction = "artist_ownership_transferred"
+
+	AuditSubscriptionChanged AuditAction = "subscription_changed"
+
+	AuditHouseholdCreated            AuditAction = "household_created"
+	AuditHouseholdMemberInvited      AuditAction = "household_member_invited"
+	AuditHouseholdInvitationDeclined AuditAction = "household_invitation_declined"
+	AuditHouseholdMemberJoined       AuditAction = "household_member_joined"
+	AuditHouseholdMemberRemoved      AuditAction = "household_member_removed"
+
+	AuditParentalControlsChanged AuditAction = "parental_controls_changed"
+	AuditParentalPINChanged      AuditAction = "parental_pin_changed"
+	AuditParentalPINFailed       AuditAction = "parental_pin_failed"
+
+	AuditUserBlocked   AuditAction = "user_blocked"
+	AuditUserUnblocked AuditAction = "user_unblocked"
+
+	AuditExternalIdentityLinked   AuditAction = "external_identity_linked"
+	AuditExternalIdentityUnlinked AuditAction = "external_identity_unlinked"
+
+	AuditOAuth2AuthorizationGranted AuditAction = "oauth2_authorization_granted"
+)
+
+type AuditContext struct {
+	ActorID   *uuid.UUID
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+type auditContextKey struct{}
+
+func WithAuditContext(ctx context.Context, auditContext AuditContext) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, auditContext)
+}
+
+func WithAuditActor(ctx context.Context, actorID uuid.UUID) context.Context {
+	auditContext := auditContextFromContext(ctx)
+	auditContext.ActorID = &actorID
+	return WithAuditContext(ctx, auditContext)
+}
+
+func auditContextFromContext(ctx context.Context) AuditContext {
+	auditContext, _ := ctx.Value(auditContextKey{}).(AuditContext)
+	return auditContext
+}
+
+type AuditService interface {
+	// Record writes entry for action that changes nothing, mutations are audited by their own unit of work
+	Record(ctx context.Context, action AuditAction, targetID *uuid.UUID, details map[string]string) error
+	// SealPending chains entries appended since last call, they are tamper evident only after that
+	SealPending(ctx context.Context) (int, error)
+	VerifyChain(ctx context.Context) (int, error)
+}
+
+func NewAuditService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler) AuditService {
+	return &auditService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+	}
+}
+
+type auditService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+}
+
+func (service *auditService) Record(ctx context.Context, action AuditAction, targetID *uuid.UUID, details map[string]string) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var target *domain.UserID
+		if targetID != nil {
+			id := domain.UserID(*targetID)
+			target = &id
+		}
+		return provider.AuditLogRepository().Append(ctx, newAuditEntry(ctx, action, target, details))
+	})
+}
+
+func (service *auditService) SealPending(ctx context.Context) (int, error) {
+	sealed := 0
+	for {
+		var n int
+		err := executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+			var err error
+			n, err = provider.AuditLogRepository().SealPending(ctx, auditLogSealBatchSize)
+			return err
+		})
+		sealed += n
+		if err != nil || n < auditLogSealBatchSize {
+			return sealed, err
+		}
+	}
+}
+
+func (service *auditService) VerifyChain(ctx context.Context) (int, error) {
+	verified := 0
+	var lastSeq int64
+	lastHash := ""
+	for {
+		var entries []domain.AuditEntry
+		err := executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+			var err error
+			entries, err = provider.AuditLogRepository().FindSealedAfter(ctx, lastSeq, auditLogVerifyBatchSize)
+			return err
+		})
+		if err != nil {
+			return verified, err
+		}
+		if len(entries) == 0 {
+			return verified, nil
+		}
+
+		for _, entry := range entries {
+			hash, err := entry.ComputeHash()
+			if err != nil {
+				return verified, err
+			}
+			if entry.ChainSeq != lastSeq+1 || entry.PrevHash != lastHash || entry.Hash != hash {
+				return verified, errors.Wrapf(domain.ErrAuditLogTampered, "entry %d", entry.Seq)
+			}
+			lastSeq, lastHash = entry.ChainSeq, entry.Hash
+			verified++
+		}
+	}
+}
+
+func appendAuditEntries(ctx context.Context, repo domain.AuditLogRepository, events []domain.Event) error {
+	for _, event := range events {
+		action, targetID, details, ok := auditEventEntry(event)
+		if !ok {
+			continue
+		}
+		err := repo.Append(ctx, newAuditEntry(ctx, action, &targetID, details))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newAuditEntry(ctx context.Context, action AuditAction, targetID *domain.UserID, details map[string]string) domain.AuditEntry {
+	auditContext := auditContextFromContext(ctx)
+
+	var actorID *domain.UserID
+	if auditContext.ActorID != nil {
+		id := domain.UserID(*auditContext.ActorID)
+		actorID = &id
+	}
+
+	return domain.AuditEntry{
+		Action:     string(action),
+		ActorID:    actorID,
+		TargetID:   targetID,
+		IP:         auditContext.IP,
+		UserAgent:  auditContext.UserAgent,
+		RequestID:  auditContext.RequestID,
+		Details:    details,
+		OccurredAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+}
+
+// auditEventEntry maps domain events to audit entries, details never contain personal data like emails
+func auditEventEntry(event domain.Event) (action AuditAction, targetID domain.UserID, details map[string]string, ok bool) {
+	switch e := event.(type) {
+	case domain.UserCreated:
+		return AuditUserRegistered, e.UserID, map[string]string{"role": e.Role.Name()}, true
+	case domain.UserLoggedIn:
+		return AuditLoginSucceeded, e.UserID, nil, true
+	case domain.UserLoginFailed:
+		details = map[string]string{"failed_attempts": strconv.Itoa(e.FailedAttempts)}
+		if e.LockedUntil != nil {
+			details["locked_until"] = e.LockedUntil.UTC().Format(time.RFC3339)
+		}
+		return AuditLoginFailed, e.UserID, details, true
+	case domain.UserRoleChanged:
+		return AuditRoleChanged, e.UserID, map[string]string{"role": e.Role.Name()}, true
+	case domain.UserEmailChanged:
+		return AuditEmailChanged, e.UserID, nil, true
+	case domain.UserPasswordChanged:
+		return AuditPasswordChanged, e.UserID, nil, true
+	case domain.UserRemoved:
+		return AuditUserDeleted, e.UserID, nil, true
+	case domain.UserRestored:
+		return AuditUserRestored, e.UserID, nil, true
+	case domain.UserPurged:
+		return AuditUserPurged, e.UserID, nil, true
+	case domain.UserErased:
+		return AuditUserErased, e.UserID, nil, true
+	case domain.UserUnlocked:
+		return AuditUserUnlocked, e.UserID, nil, true
+	case domain.DataExportRequested:
+		return AuditDataExportRequested, e.UserID, map[string]string{"export_id": uuid.UUID(e.ExportID).String()}, true
+	case domain.CreatorApplicationSubmitted:
+		return AuditCreatorApplicationSubmitted, e.UserID, map[string]string{"application_id": uuid.UUID(e.ApplicationID).String()}, true
+	case domain.CreatorApplicationReviewStarted:
+		return AuditCreatorApplicationReviewStarted, e.UserID, map[string]string{"application_id": uuid.UUID(e.ApplicationID).String()}, true
+	case domain.CreatorApplicationApproved:
+		return AuditCreatorApplicationApproved, e.UserID, map[string]string{"application_id": uuid.UUID(e.ApplicationID).String()}, true
+	case domain.CreatorApplicationRejected:
+		return AuditCreatorApplicationRejected, e.UserID, map[string]string{"application_id": uuid.UUID(e.ApplicationID).String()}, true
+	case domain.ArtistCreated:
+		return AuditArtistCreated, e.OwnerID, map[string]string{"artist_id": uuid.UUID(e.ArtistID).String()}, true
+	case domain.ArtistMemberInvited:
+		return AuditArtistMemberInvited, e.InvitedBy, map[string]string{
+			"artist_id":     uuid.UUID(e.ArtistID).String(),
+			"invitation_id": uuid.UUID(e.InvitationID).String(),
+			"role":          e.Role.Name(),
+		}, true
+	case domain.ArtistInvitationDeclined:
+		return AuditArtistInvitationDeclined, e.UserID, map[string]string{
+			"artist_id":     uuid.UUID(e.ArtistID).String(),
+			"invitation_id": uuid.UUID(e.InvitationID).String(),
+		}, true
+	case domain.ArtistMemberAdded:
+		return AuditArtistMemberAdded, e.UserID, map[string]string{"artist_id": uuid.UUID(e.ArtistID).String(), "role": e.Role.Name()}, true
+	case domain.ArtistMemberRoleChanged:
+		return AuditArtistMemberRoleChanged, e.UserID, map[string]string{"artist_id": uuid.UUID(e.ArtistID).String(), "role": e.Role.Name()}, true
+	case domain.ArtistMemberRemoved:
+		return AuditArtistMemberRemoved, e.UserID, map[string]string{"artist_id": uuid.UUID(e.ArtistID).String()}, true
+	case domain.SubscriptionChanged:
+		return AuditSubscriptionChanged, e.UserID, map[string]string{
+			"plan":   e.Plan.Name(),
+			"status": e.Status.Name(),
+			"trial":  strconv.FormatBool(e.Trial),
+		}, true
+	case domain.ArtistOwnershipTransferred:
+		return AuditArtistOwnershipChanged, e.NewOwner, map[string]string{
+			"artist_id":      uuid.UUID(e.ArtistID).String(),
+			"previous_owner": uuid.UUID(e.PreviousOwner).String(),
+		}, true
+	case domain.HouseholdCreated:
+		return AuditHouseholdCreated, e.ManagerID, map[string]string{"household_id": uuid.UUID(e.HouseholdID).String()}, true
+	case domain.HouseholdMemberInvited:
+		return AuditHouseholdMemberInvited, e.ManagerID, map[string]string{
+			"household_id":  uuid.UUID(e.HouseholdID).String(),
+			"invitation_id": uuid.UUID(e.InvitationID).String(),
+		}, true
+	case domain.HouseholdInvitationDeclined:
+		return AuditHouseholdInvitationDeclined, e.UserID, map[string]string{
+			"household_id":  uuid.UUID(e.HouseholdID).String(),
+			"invitation_id": uuid.UUID(e.InvitationID).String(),
+		}, true
+	case domain.HouseholdMemberJoined:
+		return AuditHouseholdMemberJoined, e.UserID, map[string]string{"household_id": uuid.UUID(e.HouseholdID).String()}, true
+	case domain.HouseholdMemberRemoved:
+		return AuditHouseholdMemberRemoved, e.UserID, map[string]string{"household_id": uuid.UUID(e.HouseholdID).String()}, true
+	case domain.ParentalControlsChanged:
+		details = map[string]string{"actor_id": uuid.UUID(e.ActorID).String()}
+		if e.ExplicitContentFilter != nil {
+			details["explicit_content_filter"] = strconv.FormatBool(*e.ExplicitContentFilter)
+		}
+		if e.GuardianID != nil {
+			details["guardian_id"] = uuid.UUID(*e.GuardianID).String()
+		}
+		return AuditParentalControlsChanged, e.UserID, details, true
+	case domain.ParentalPINChanged:
+		return AuditParentalPINChanged, e.UserID, map[string]string{"actor_id": uuid.UUID(e.ActorID).String()}, true
+	case domain.ParentalPINFailed:
+		details = map[string]string{"failed_attempts": strconv.Itoa(e.FailedAttempts)}
+		if e.LockedUntil != nil {
+			details["locked_until"] = e.LockedUntil.UTC().Format(time.RFC3339)
+		}
+		return AuditParentalPINFailed, e.UserID, details, true
+	case domain.UserBlocked:
+		return AuditUserBlocked, e.BlockerID, map[string]string{"blocked_id": uuid.UUID(e.BlockedID).String()}, true
+	case domain.UserUnblocked:
+		return AuditUserUnblocked, e.BlockerID, map[string]string{"blocked_id": uuid.UUID(e.BlockedID).String()}, true
+	case domain.ExternalIdentityLinked:
+		return AuditExternalIdentityLinked, e.UserID, map[string]string{"provider": e.Provider, "provisioned": strconv.FormatBool(e.Provisioned)}, true
+	case domain.ExternalIdentityUnlinked:
+		return AuditExternalIdentityUnlinked, e.UserID, map[string]string{"provider": e.Provider}, true
+	case domain.OAuth2AuthorizationGranted:
+		return AuditOAuth2AuthorizationGranted, e.UserID, map[string]string{"client_id": e.ClientID, "scopes": strings.Join(e.Scopes, " ")}, true
+	}
+	return "", domain.UserID{}, nil, false
+}
+
+func NewAuditClientDataEraser() PersonalDataEraser {
+	return &auditClientDataEraser{}
+}
+
+type auditClientDataEraser struct{}
+
+func (eraser *auditClientDataEraser) Scope() string {
+	return "audit_client_data"
+}
+
+func (eraser *auditClientDataEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.AuditLogRepository().RemoveClientData(ctx, userID)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type mockAuditLogRepository struct {
+	entries []domain.AuditEntry
+	pending []int
+	lastSeq int64
+}
+
+func (repo *mockAuditLogRepository) Append(_ context.Context, entry domain.AuditEntry) error {
+	repo.lastSeq++
+	entry.Seq = repo.lastSeq
+	repo.entries = append(repo.entries, entry)
+	repo.pending = append(repo.pending, len(repo.entries)-1)
+	return nil
+}
+
+func (repo *mockAuditLogRepository) SealPending(_ context.Context, limit int) (int, error) {
+	var lastChainSeq int64
+	lastHash := ""
+	for _, entry := range repo.entries {
+		if entry.ChainSeq > lastChainSeq {
+			lastChainSeq, lastHash = entry.ChainSeq, entry.Hash
+		}
+	}
+
+	n := 0
+	for len(repo.pending) > 0 && n < limit {
+		i := repo.pending[0]
+		sealed, err := repo.entries[i].Seal(lastChainSeq, lastHash)
+		if err != nil {
+			return n, err
+		}
+		repo.entries[i] = sealed
+		lastChainSeq, lastHash = sealed.ChainSeq, sealed.Hash
+		repo.pending = repo.pending[1:]
+		n++
+	}
+	return n, nil
+}
+
+func (repo *mockAuditLogRepository) FindSealedAfter(_ context.Context, afterChainSeq int64, limit int) ([]domain.AuditEntry, error) {
+	var result []domain.AuditEntry
+	for _, entry := range repo.entries {
+		if entry.ChainSeq > afterChainSeq {
+			result = append(result, entry)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ChainSeq < result[j].ChainSeq })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (repo *mockAuditLogRepository) RemoveClientData(_ context.Context, userID domain.UserID) error {
+	for i, entry := range repo.entries {
+		if (entry.ActorID != nil && *entry.ActorID == userID) || (entry.TargetID != nil && *entry.TargetID == userID) {
+			repo.entries[i].IP = ""
+			repo.entries[i].UserAgent = ""
+		}
+	}
+	return nil
+}
+
+func newTestAuditService(provider *mockRepositoryProvider) AuditService {
+	return NewAuditService(&mockUnitOfWorkFactory{provider: provider}, NewCompositeEventHandler())
+}
+
+func recordAuditEntries(t *testing.T, ctx context.Context, service AuditService, userID uuid.UUID, n int) {
+	for i := 0; i < n; i++ {
+		err := service.Record(ctx, AuditLoginFailed, &userID, map[string]string{"reason": "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAuditLogVerifyChain(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name             string
+		entries          int
+		tamper           func(repo *mockAuditLogRepository)
+		expectedVerified int
+		expectedErr      error
+	}{
+		{
+			name:             "untouched chain is verified",
+			entries:          5,
+			expectedVerified: 5,
+		},
+		{
+			name:    "modified details are detected",
+			entries: 5,
+			tamper: func(repo *mockAuditLogRepository) {
+				repo.entries[2].Details = map[string]string{"reason": "other"}
+			},
+			expectedVerified: 2,
+			expectedErr:      domain.ErrAuditLogTampered,
+		},
+		{
+			name:    "modified action is detected",
+			entries: 5,
+			tamper: func(repo *mockAuditLogRepository) {
+				repo.entries[0].Action = string(AuditLoginSucceeded)
+			},
+			expectedVerified: 0,
+			expectedErr:      domain.ErrAuditLogTampered,
+		},
+		{
+			name:    "rehashed modified entry breaks next entry",
+			entries: 5,
+			tamper: func(repo *mockAuditLogRepository) {
+				entry := repo.entries[1]
+				entry.TargetID = nil
+				entry.Hash, _ = entry.ComputeHash()
+				repo.entries[1] = entry
+			},
+			expectedVerified: 2,
+			expectedErr:      domain.ErrAuditLogTampered,
+		},
+		{
+			name:    "deleted entry is detected",
+			entries: 5,
+			tamper: func(repo *mockAuditLogRepository) {
+				repo.entries = append(repo.entries[:3], repo.entries[4:]...)
+			},
+			expectedVerified: 3,
+			expectedErr:      domain.ErrAuditLogTampered,
+		},
+		{
+			name:    "erased client data keeps chain valid",
+			entries: 5,
+			tamper: func(repo *mockAuditLogRepository) {
+				for i := range repo.entries {
+					repo.entries[i].IP = "127.0.0.1"
+				}
+				_ = repo.RemoveClientData(context.Background(), domain.UserID(userID))
+			},
+			expectedVerified: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			provider := newMockRepositoryProvider()
+			service := newTestAuditService(provider)
+
+			recordAuditEntries(t, ctx, service, userID, test.entries)
+			_, err := service.SealPending(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.tamper != nil {
+				test.tamper(provider.auditLog)
+			}
+
+			verified, err := service.VerifyChain(ctx)
+			if errors.Cause(err) != test.expectedErr {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if verified != test.expectedVerified {
+				t.Errorf("expected %d verified entries, got %d", test.expectedVerified, verified)
+			}
+		})
+	}
+}
+
+func TestAuditLogSealContinuity(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockRepositoryProvider()
+	service := newTestAuditService(provider)
+	userID := uuid.New()
+
+	batches := []int{3, auditLogSealBatchSize + 1, 0, 2}
+	total := 0
+	for _, n := range batches {
+		recordAuditEntries(t, ctx, service, userID, n)
+		sealed, err := service.SealPending(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sealed != n {
+			t.Fatalf("expected %d sealed entries, got %d", n, sealed)
+		}
+		total += n
+	}
+
+	entries := provider.auditLog.entries
+	for i, entry := range entries {
+		if entry.ChainSeq != int64(i+1) {
+			t.Fatalf("entry %d has chain seq %d", entry.Seq, entry.ChainSeq)
+		}
+		if i > 0 && entry.PrevHash != entries[i-1].Hash {
+			t.Fatalf("entry %d is not chained to previous entry", entry.Seq)
+		}
+	}
+	if entries[0].PrevHash != "" {
+		t.Errorf("first entry is chained to %q", entries[0].PrevHash)
+	}
+
+	verified, err := service.VerifyChain(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified != total {
+		t.Errorf("expected %d verified entries, got %d", total, verified)
+	}
+}
+
+func TestAuditLogUnsealedEntriesAreNotVerified(t *testing.T) {
+	ctx := context.Background()
+	provider := newMockRepositoryProvider()
+	service := newTestAuditService(provider)
+	userID := uuid.New()
+
+	recordAuditEntries(t, ctx, service, userID, 2)
+	_, err := service.SealPending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recordAuditEntries(t, ctx, service, userID, 3)
+
+	verified, err := service.VerifyChain(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified != 2 {
+		t.Errorf("expected 2 verified entries, got %d", verified)
+	}
+}
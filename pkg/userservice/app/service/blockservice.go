@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type BlockView struct {
+	BlockedID uuid.UUID
+	CreatedAt time.Time
+}
+
+type UserPair struct {
+	UserID  uuid.UUID
+	OtherID uuid.UUID
+}
+
+type BlockService interface {
+	BlockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	UnblockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	ListBlocked(ctx context.Context, blockerID uuid.UUID, offset, limit int) ([]BlockView, error)
+	IsBlocked(ctx context.Context, pairs []UserPair) ([]bool, error)
+}
+
+func NewBlockService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler) BlockService {
+	return &blockService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+	}
+}
+
+type blockService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+}
+
+func (service *blockService) BlockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainBlockService(provider, dispatcher).Block(ctx, domain.UserID(blockerID), domain.UserID(blockedID))
+	})
+}
+
+func (service *blockService) UnblockUser(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainBlockService(provider, dispatcher).Unblock(ctx, domain.UserID(blockerID), domain.UserID(blockedID))
+	})
+}
+
+func (service *blockService) ListBlocked(ctx context.Context, blockerID uuid.UUID, offset, limit int) ([]BlockView, error) {
+	var blocks []domain.Block
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		blocks, err = provider.BlockRepository().FindByBlocker(ctx, domain.UserID(blockerID), offset, limit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BlockView, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, BlockView{BlockedID: uuid.UUID(block.BlockedID), CreatedAt: block.CreatedAt})
+	}
+	return result, nil
+}
+
+func (service *blockService) IsBlocked(ctx context.Context, pairs []UserPair) ([]bool, error) {
+	domainPairs := make([]domain.UserPair, 0, len(pairs))
+	for _, pair := range pairs {
+		domainPairs = append(domainPairs, domain.UserPair{UserID: domain.UserID(pair.UserID), OtherID: domain.UserID(pair.OtherID)})
+	}
+
+	var blocks []domain.Block
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		blocks, err = provider.BlockRepository().FindBetween(ctx, domainPairs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	blocked := make(map[domain.UserPair]bool, len(blocks))
+	for _, block := range blocks {
+		blocked[domain.UserPair{UserID: block.BlockerID, OtherID: block.BlockedID}] = true
+	}
+	result := make([]bool, 0, len(pairs))
+	for _, pair := range domainPairs {
+		result = append(result, blocked[pair] || blocked[domain.UserPair{UserID: pair.OtherID, OtherID: pair.UserID}])
+	}
+	return result, nil
+}
+
+func (service *blockService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func domainBlockService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.BlockService {
+	return domain.NewBlockService(
+		provider.UserRepository(),
+		provider.ArtistRepository(),
+		provider.FollowRepository(),
+		provider.BlockRepository(),
+		dispatcher,
+	)
+}
+
+func NewBlockEraser() PersonalDataEraser {
+	return &blockEraser{}
+}
+
+type blockEraser struct{}
+
+func (eraser *blockEraser) Scope() string {
+	return "blocks"
+}
+
+func (eraser *blockEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.BlockRepository().RemoveByUser(ctx, userID)
+}
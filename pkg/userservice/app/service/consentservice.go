@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+var (
+	ErrTermsAcceptanceRequired   = errors.New("acceptance of terms of service is required")
+	ErrOutdatedDocumentVersion   = errors.New("accepted document version is not current one")
+	ErrDocumentVersionRequired   = errors.New("document version is required")
+	ErrDocumentConsentNotGranted = errors.New("document can only be accepted, withdrawal is done by account deletion")
+	ErrUnknownMarketingChannel   = errors.New("unknown marketing channel")
+	ErrUnknownConsentKind        = errors.New("unknown consent kind")
+	ErrChannelNotAllowedForKind  = errors.New("channel is allowed only for marketing consent")
+	ErrVersionNotAllowedForKind  = errors.New("version is not allowed for marketing consent")
+)
+
+type ConsentKind int
+
+const (
+	TermsOfService = ConsentKind(domain.TermsOfService)
+	PrivacyPolicy  = ConsentKind(domain.PrivacyPolicy)
+	Marketing      = ConsentKind(domain.Marketing)
+)
+
+var marketingChannels = map[string]bool{
+	"email": true,
+	"sms":   true,
+	"push":  true,
+}
+
+type ConsentPolicy struct {
+	TermsOfServiceVersion string
+	PrivacyPolicyVersion  string
+	RequireTermsOnSignUp  bool
+}
+
+type ConsentRecord struct {
+	Kind     ConsentKind
+	Version  string
+	Channel  string
+	Granted  bool
+	SourceIP string
+}
+
+type TermsAcceptance struct {
+	Version  string
+	SourceIP string
+}
+
+type ConsentView struct {
+	Kind       ConsentKind
+	Version    string
+	Channel    string
+	Granted    bool
+	SourceIP   string
+	RecordedAt time.Time
+}
+
+type ConsentService interface {
+	RecordConsent(ctx context.Context, userID uuid.UUID, record ConsentRecord) error
+	GetConsents(ctx context.Context, userID uuid.UUID) ([]ConsentView, error)
+	GetConsentHistory(ctx context.Context, userID uuid.UUID) ([]ConsentView, error)
+	TermsReacceptanceRequired(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+func NewConsentService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler, policy ConsentPolicy) ConsentService {
+	return &consentService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		policy:            policy,
+	}
+}
+
+type consentService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	policy            ConsentPolicy
+}
+
+func (service *consentService) RecordConsent(ctx context.Context, userID uuid.UUID, record ConsentRecord) error {
+	err := service.policy.validate(record)
+	if err != nil {
+		return err
+	}
+
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		user, err := provider.UserRepository().Find(ctx, domain.UserID(userID))
+		if err != nil {
+			return err
+		}
+		if user.IsDeleted() || user.IsErased() {
+			return domain.ErrUserNotFound
+		}
+		return storeConsent(ctx, provider.ConsentRepository(), domain.UserID(userID), record)
+	})
+}
+
+func (service *consentService) GetConsents(ctx context.Context, userID uuid.UUID) ([]ConsentView, error) {
+	history, err := service.GetConsentHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	type consentKey struct {
+		kind    ConsentKind
+		channel string
+	}
+	var keys []consentKey
+	latest := map[consentKey]ConsentView{}
+	for _, consent := range history {
+		key := consentKey{kind: consent.Kind, channel: consent.Channel}
+		if _, ok := latest[key]; !ok {
+			keys = append(keys, key)
+		}
+		latest[key] = consent
+	}
+
+	result := make([]ConsentView, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, latest[key])
+	}
+	return result, nil
+}
+
+func (service *consentService) GetConsentHistory(ctx context.Context, userID uuid.UUID) ([]ConsentView, error) {
+	var consents []domain.Consent
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		consents, err = provider.ConsentRepository().FindByUser(ctx, domain.UserID(userID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ConsentView, 0, len(consents))
+	for _, consent := range consents {
+		result = append(result, ConsentView{
+			Kind:       ConsentKind(consent.Kind),
+			Version:    consent.Version,
+			Channel:    consent.Channel,
+			Granted:    consent.Granted,
+			SourceIP:   consent.SourceIP,
+			RecordedAt: consent.RecordedAt,
+		})
+	}
+	return result, nil
+}
+
+func (service *consentService) TermsReacceptanceRequired(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if service.policy.TermsOfServiceVersion == "" {
+		return false, nil
+	}
+
+	consents, err := service.GetConsents(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, consent := range consents {
+		if consent.Kind == TermsOfService {
+			return consent.Version != service.policy.TermsOfServiceVersion, nil
+		}
+	}
+	return true, nil
+}
+
+func (service *consentService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func (policy ConsentPolicy) validate(record ConsentRecord) error {
+	switch record.Kind {
+	case TermsOfService:
+		return validateDocumentConsent(record, policy.TermsOfServiceVersion)
+	case PrivacyPolicy:
+		return validateDocumentConsent(record, policy.PrivacyPolicyVersion)
+	case Marketing:
+		if record.Version != "" {
+			return ErrVersionNotAllowedForKind
+		}
+		if !marketingChannels[record.Channel] {
+			return errors.Wrap(ErrUnknownMarketingChannel, record.Channel)
+		}
+		return nil
+	default:
+		return ErrUnknownConsentKind
+	}
+}
+
+func (policy ConsentPolicy) validateSignUp(terms *TermsAcceptance) error {
+	if terms == nil {
+		if policy.RequireTermsOnSignUp {
+			return ErrTermsAcceptanceRequired
+		}
+		return nil
+	}
+	return policy.validate(terms.record())
+}
+
+func (terms TermsAcceptance) record() ConsentRecord {
+	return ConsentRecord{
+		Kind:     TermsOfService,
+		Version:  terms.Version,
+		Granted:  true,
+		SourceIP: terms.SourceIP,
+	}
+}
+
+func validateDocumentConsent(record ConsentRecord, currentVersion string) error {
+	switch {
+	case record.Channel != "":
+		return ErrChannelNotAllowedForKind
+	case !record.Granted:
+		return ErrDocumentConsentNotGranted
+	case record.Version == "":
+		return ErrDocumentVersionRequired
+	case currentVersion != "" && record.Version != currentVersion:
+		return errors.Wrapf(ErrOutdatedDocumentVersion, "current version is %s", currentVersion)
+	}
+	return nil
+}
+
+func storeConsent(ctx context.Context, repo domain.ConsentRepository, userID domain.UserID, record ConsentRecord) error {
+	return repo.Store(ctx, domain.Consent{
+		ID:         repo.NewID(),
+		UserID:     userID,
+		Kind:       domain.ConsentKind(record.Kind),
+		Version:    record.Version,
+		Channel:    record.Channel,
+		Granted:    record.Granted,
+		SourceIP:   record.SourceIP,
+		RecordedAt: time.Now(),
+	})
+}
+
+func NewConsentEraser() PersonalDataEraser {
+	return &consentEraser{}
+}
+
+type consentEraser struct{}
+
+func (eraser *consentEraser) Scope() string {
+	return "consents"
+}
+
+func (eraser *consentEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.ConsentRepository().AnonymizeByUser(ctx, userID)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+)
+
+type CreatorApplicationDecision int
+
+const (
+	StartCreatorApplicationReview = CreatorApplicationDecision(domain.StartCreatorApplicationReview)
+	ApproveCreatorApplication     = CreatorApplicationDecision(domain.ApproveCreatorApplication)
+	RejectCreatorApplication      = CreatorApplicationDecision(domain.RejectCreatorApplication)
+)
+
+type CreatorApplicationService interface {
+	ApplyForCreator(ctx context.Context, userID uuid.UUID, statement string) (uuid.UUID, error)
+	ReviewCreatorApplication(ctx context.Context, applicationID, reviewerID uuid.UUID, decision CreatorApplicationDecision, notes string) (query.CreatorApplicationView, error)
+}
+
+func NewCreatorApplicationService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler) CreatorApplicationService {
+	return &creatorApplicationService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+	}
+}
+
+type creatorApplicationService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+}
+
+func (service *creatorApplicationService) ApplyForCreator(ctx context.Context, userID uuid.UUID, statement string) (uuid.UUID, error) {
+	var applicationID domain.CreatorApplicationID
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		applicationID, err = domainCreatorApplicationService(provider, dispatcher).Apply(ctx, domain.UserID(userID), statement)
+		return err
+	})
+	return uuid.UUID(applicationID), err
+}
+
+func (service *creatorApplicationService) ReviewCreatorApplication(
+	ctx context.Context,
+	applicationID, reviewerID uuid.UUID,
+	decision CreatorApplicationDecision,
+	notes string,
+) (query.CreatorApplicationView, error) {
+	var application domain.CreatorApplication
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		application, err = domainCreatorApplicationService(provider, dispatcher).Review(
+			ctx,
+			domain.CreatorApplicationID(applicationID),
+			domain.UserID(reviewerID),
+			domain.CreatorApplicationDecision(decision),
+			notes,
+		)
+		return err
+	})
+	if err != nil {
+		return query.CreatorApplicationView{}, err
+	}
+	return makeCreatorApplicationView(application), nil
+}
+
+func (service *creatorApplicationService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func domainCreatorApplicationService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.CreatorApplicationService {
+	return domain.NewCreatorApplicationService(provider.UserRepository(), provider.CreatorApplicationRepository(), dispatcher)
+}
+
+func makeCreatorApplicationView(application domain.CreatorApplication) query.CreatorApplicationView {
+	view := query.CreatorApplicationView{
+		ID:              uuid.UUID(application.ID),
+		UserID:          uuid.UUID(application.UserID),
+		Status:          query.CreatorApplicationStatus(application.Status),
+		Statement:       application.Statement,
+		ReviewerNotes:   application.ReviewerNotes,
+		AppliedAt:       application.AppliedAt,
+		ReviewStartedAt: application.ReviewStartedAt,
+		DecidedAt:       application.DecidedAt,
+	}
+	if application.ReviewerID != nil {
+		reviewerID := uuid.UUID(*application.ReviewerID)
+		view.ReviewerID = &reviewerID
+	}
+	return view
+}
+
+func NewCreatorApplicationEraser() PersonalDataEraser {
+	return &creatorApplicationEraser{}
+}
+
+type creatorApplicationEraser struct{}
+
+func (eraser *creatorApplicationEraser) Scope() string {
+	return "creator_applications"
+}
+
+func (eraser *creatorApplicationEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.CreatorApplicationRepository().RemoveByUser(ctx, userID)
+}
@@ -0,0 +1,650 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+)
+
+func NewProfileDataExportSection(queryService query.UserQueryService, profileService ProfileService) DataExportSection {
+	return &profileDataExportSection{queryService: queryService, profileService: profileService}
+}
+
+type profileDataExportSection struct {
+	queryService   query.UserQueryService
+	profileService ProfileService
+}
+
+type profileData struct {
+	UserID              string     `json:"user_id"`
+	Email               string     `json:"email"`
+	Role                string     `json:"role"`
+	FailedLoginAttempts int        `json:"failed_login_attempts"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+	DisplayName         string     `json:"display_name,omitempty"`
+	AvatarURL           string     `json:"avatar_url,omitempty"`
+	Country             string     `json:"country,omitempty"`
+	Language            string     `json:"language,omitempty"`
+	DateOfBirth         string     `json:"date_of_birth,omitempty"`
+	Bio                 string     `json:"bio,omitempty"`
+}
+
+func (section *profileDataExportSection) Name() string {
+	return "profile"
+}
+
+func (section *profileDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	user, err := section.queryService.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := section.profileService.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	data := profileData{
+		UserID:              user.ID.String(),
+		Email:               user.Email,
+		Role:                domain.Role(user.Role).Name(),
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LockedUntil:         user.LockedUntil,
+		DisplayName:         profile.DisplayName,
+		AvatarURL:           profile.AvatarURL,
+		Country:             profile.Country,
+		Language:            profile.Language,
+		Bio:                 profile.Bio,
+	}
+	if profile.DateOfBirth != nil {
+		data.DateOfBirth = profile.DateOfBirth.Format(DateLayout)
+	}
+	return data, nil
+}
+
+var dataExportConsentKindNames = map[ConsentKind]string{
+	TermsOfService: "terms_of_service",
+	PrivacyPolicy:  "privacy_policy",
+	Marketing:      "marketing",
+}
+
+func NewConsentDataExportSection(consentService ConsentService) DataExportSection {
+	return &consentDataExportSection{consentService: consentService}
+}
+
+type consentDataExportSection struct {
+	consentService ConsentService
+}
+
+type consentData struct {
+	Kind       string    `json:"kind"`
+	Version    string    `json:"version,omitempty"`
+	Channel    string    `json:"channel,omitempty"`
+	Granted    bool      `json:"granted"`
+	SourceIP   string    `json:"source_ip,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func (section *consentDataExportSection) Name() string {
+	return "consents"
+}
+
+func (section *consentDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	consents, err := section.consentService.GetConsentHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]consentData, 0, len(consents))
+	for _, consent := range consents {
+		result = append(result, consentData{
+			Kind:       dataExportConsentKindNames[consent.Kind],
+			Version:    consent.Version,
+			Channel:    consent.Channel,
+			Granted:    consent.Granted,
+			SourceIP:   consent.SourceIP,
+			RecordedAt: consent.RecordedAt,
+		})
+	}
+	return result, nil
+}
+
+const auditDataExportBatchSize = 500
+
+func NewAuditDataExportSection(queryService query.AuditLogQueryService) DataExportSection {
+	return &auditDataExportSection{queryService: queryService}
+}
+
+type auditDataExportSection struct {
+	queryService query.AuditLogQueryService
+}
+
+type auditEntryData struct {
+	Action     string            `json:"action"`
+	ByUser     bool              `json:"by_user"`
+	IP         string            `json:"ip,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+func (section *auditDataExportSection) Name() string {
+	return "audit"
+}
+
+func (section *auditDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	entries, err := listAuditEntries(ctx, section.queryService, userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]auditEntryData, 0, len(entries))
+	for _, entry := range entries {
+		data := auditEntryData{
+			Action:     entry.Action,
+			ByUser:     entry.ActorID != nil && *entry.ActorID == userID,
+			Details:    entry.Details,
+			OccurredAt: entry.OccurredAt,
+		}
+		if data.ByUser {
+			data.IP = entry.IP
+			data.UserAgent = entry.UserAgent
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+func NewRoleHistoryDataExportSection(queryService query.AuditLogQueryService) DataExportSection {
+	return &roleHistoryDataExportSection{queryService: queryService}
+}
+
+type roleHistoryDataExportSection struct {
+	queryService query.AuditLogQueryService
+}
+
+type roleChangeData struct {
+	Role      string    `json:"role"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (section *roleHistoryDataExportSection) Name() string {
+	return "role_history"
+}
+
+func (section *roleHistoryDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	result := []roleChangeData{}
+	for _, action := range []AuditAction{AuditRoleChanged, AuditUserRegistered} {
+		entries, err := listAuditEntries(ctx, section.queryService, userID, action)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.TargetID == nil || *entry.TargetID != userID {
+				continue
+			}
+			result = append(result, roleChangeData{Role: entry.Details["role"], ChangedAt: entry.OccurredAt})
+		}
+	}
+	return result, nil
+}
+
+func NewSessionsDataExportSection(queryService query.AuditLogQueryService) DataExportSection {
+	return &sessionsDataExportSection{queryService: queryService}
+}
+
+type sessionsDataExportSection struct {
+	queryService query.AuditLogQueryService
+}
+
+type sessionData struct {
+	SignedInAt time.Time `json:"signed_in_at"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+func (section *sessionsDataExportSection) Name() string {
+	return "sessions"
+}
+
+func (section *sessionsDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	entries, err := listAuditEntries(ctx, section.queryService, userID, AuditLoginSucceeded)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]sessionData, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, sessionData{SignedInAt: entry.OccurredAt, IP: entry.IP, UserAgent: entry.UserAgent})
+	}
+	return result, nil
+}
+
+func listAuditEntries(ctx context.Context, queryService query.AuditLogQueryService, userID uuid.UUID, action AuditAction) ([]query.AuditEntryView, error) {
+	var result []query.AuditEntryView
+	spec := query.AuditLogSpec{UserID: &userID, Action: string(action), Limit: auditDataExportBatchSize}
+	for {
+		entries, err := queryService.ListEntries(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
+
+		if len(entries) < spec.Limit {
+			return result, nil
+		}
+		spec.BeforeSeq = entries[len(entries)-1].Seq
+	}
+}
+
+var dataExportCreatorApplicationStatusNames = map[query.CreatorApplicationStatus]string{
+	query.CreatorApplicationApplied:     "applied",
+	query.CreatorApplicationUnderReview: "under_review",
+	query.CreatorApplicationApproved:    "approved",
+	query.CreatorApplicationRejected:    "rejected",
+}
+
+func NewCreatorApplicationDataExportSection(queryService query.CreatorApplicationQueryService) DataExportSection {
+	return &creatorApplicationDataExportSection{queryService: queryService}
+}
+
+type creatorApplicationDataExportSection struct {
+	queryService query.CreatorApplicationQueryService
+}
+
+type creatorApplicationData struct {
+	Status        string     `json:"status"`
+	Statement     string     `json:"statement,omitempty"`
+	ReviewerNotes string     `json:"reviewer_notes,omitempty"`
+	AppliedAt     time.Time  `json:"applied_at"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+}
+
+func (section *creatorApplicationDataExportSection) Name() string {
+	return "creator_applications"
+}
+
+func (section *creatorApplicationDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	applications, err := section.queryService.ListUserApplications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]creatorApplicationData, 0, len(applications))
+	for _, application := range applications {
+		result = append(result, creatorApplicationData{
+			Status:        dataExportCreatorApplicationStatusNames[application.Status],
+			Statement:     application.Statement,
+			ReviewerNotes: application.ReviewerNotes,
+			AppliedAt:     application.AppliedAt,
+			DecidedAt:     application.DecidedAt,
+		})
+	}
+	return result, nil
+}
+
+func NewArtistDataExportSection(queryService query.ArtistQueryService) DataExportSection {
+	return &artistDataExportSection{queryService: queryService}
+}
+
+type artistDataExportSection struct {
+	queryService query.ArtistQueryService
+}
+
+type artistMembershipData struct {
+	ArtistID   string    `json:"artist_id"`
+	ArtistName string    `json:"artist_name"`
+	Role       string    `json:"role"`
+	JoinedAt   time.Time `json:"joined_at"`
+}
+
+func (section *artistDataExportSection) Name() string {
+	return "artists"
+}
+
+func (section *artistDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	memberships, err := section.queryService.ListUserArtists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]artistMembershipData, 0, len(memberships))
+	for _, membership := range memberships {
+		result = append(result, artistMembershipData{
+			ArtistID:   membership.ArtistID.String(),
+			ArtistName: membership.ArtistName,
+			Role:       domain.ArtistRole(membership.Role).Name(),
+			JoinedAt:   membership.JoinedAt,
+		})
+	}
+	return result, nil
+}
+
+func NewSubscriptionDataExportSection(subscriptionService SubscriptionService) DataExportSection {
+	return &subscriptionDataExportSection{subscriptionService: subscriptionService}
+}
+
+type subscriptionDataExportSection struct {
+	subscriptionService SubscriptionService
+}
+
+type subscriptionData struct {
+	Plan        string     `json:"plan"`
+	Status      string     `json:"status"`
+	PeriodStart *time.Time `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time `json:"period_end,omitempty"`
+	Trial       bool       `json:"trial"`
+}
+
+func (section *subscriptionDataExportSection) Name() string {
+	return "subscription"
+}
+
+func (section *subscriptionDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	subscription, err := section.subscriptionService.GetSubscription(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptionData{
+		Plan:        domain.SubscriptionPlan(subscription.Plan).Name(),
+		Status:      domain.SubscriptionStatus(subscription.Status).Name(),
+		PeriodStart: subscription.PeriodStart,
+		PeriodEnd:   subscription.PeriodEnd,
+		Trial:       subscription.Trial,
+	}, nil
+}
+
+func NewHouseholdDataExportSection(householdService HouseholdService) DataExportSection {
+	return &householdDataExportSection{householdService: householdService}
+}
+
+type householdDataExportSection struct {
+	householdService HouseholdService
+}
+
+type householdData struct {
+	HouseholdID string    `json:"household_id"`
+	Manager     bool      `json:"manager"`
+	JoinedAt    time.Time `json:"joined_at"`
+	MemberIDs   []string  `json:"member_ids"`
+}
+
+func (section *householdDataExportSection) Name() string {
+	return "household"
+}
+
+func (section *householdDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	household, err := section.householdService.GetHousehold(ctx, userID)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrHouseholdNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := householdData{HouseholdID: household.ID.String(), MemberIDs: []string{}}
+	for _, member := range household.Members {
+		if member.UserID == userID {
+			result.Manager = member.Manager
+			result.JoinedAt = member.JoinedAt
+			continue
+		}
+		result.MemberIDs = append(result.MemberIDs, member.UserID.String())
+	}
+	return result, nil
+}
+
+func NewParentalControlsDataExportSection(parentalControlsService ParentalControlsService) DataExportSection {
+	return &parentalControlsDataExportSection{parentalControlsService: parentalControlsService}
+}
+
+type parentalControlsDataExportSection struct {
+	parentalControlsService ParentalControlsService
+}
+
+type parentalControlsData struct {
+	AgeCategory           string  `json:"age_category"`
+	ExplicitContentFilter bool    `json:"explicit_content_filter"`
+	GuardianID            *string `json:"guardian_id,omitempty"`
+	PINSet                bool    `json:"pin_set"`
+}
+
+func (section *parentalControlsDataExportSection) Name() string {
+	return "parental_controls"
+}
+
+func (section *parentalControlsDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	restrictions, err := section.parentalControlsService.GetContentRestrictions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := parentalControlsData{
+		AgeCategory:           domain.AgeCategory(restrictions.Category).Name(),
+		ExplicitContentFilter: restrictions.ExplicitContentFilter,
+		PINSet:                restrictions.PINSet,
+	}
+	if restrictions.GuardianID != nil {
+		guardianID := restrictions.GuardianID.String()
+		result.GuardianID = &guardianID
+	}
+	return result, nil
+}
+
+const followsDataExportPageSize = 500
+
+func NewFollowsDataExportSection(followService FollowService) DataExportSection {
+	return &followsDataExportSection{followService: followService}
+}
+
+type followsDataExportSection struct {
+	followService FollowService
+}
+
+type followData struct {
+	TargetKind string    `json:"target_kind"`
+	TargetID   string    `json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (section *followsDataExportSection) Name() string {
+	return "follows"
+}
+
+func (section *followsDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	result := []followData{}
+	for offset := 0; ; offset += followsDataExportPageSize {
+		follows, err := section.followService.ListFollowing(ctx, userID, userID, offset, followsDataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, follow := range follows {
+			result = append(result, followData{
+				TargetKind: domain.FollowTargetKind(follow.Target.Kind).Name(),
+				TargetID:   follow.Target.ID.String(),
+				CreatedAt:  follow.CreatedAt,
+			})
+		}
+		if len(follows) < followsDataExportPageSize {
+			return result, nil
+		}
+	}
+}
+
+const blocksDataExportPageSize = 500
+
+func NewBlocksDataExportSection(blockService BlockService) DataExportSection {
+	return &blocksDataExportSection{blockService: blockService}
+}
+
+type blocksDataExportSection struct {
+	blockService BlockService
+}
+
+type blockData struct {
+	UserID    string    `json:"user_id"`
+	BlockedAt time.Time `json:"blocked_at"`
+}
+
+func (section *blocksDataExportSection) Name() string {
+	return "blocked_users"
+}
+
+func (section *blocksDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	result := []blockData{}
+	for offset := 0; ; offset += blocksDataExportPageSize {
+		blocks, err := section.blockService.ListBlocked(ctx, userID, offset, blocksDataExportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range blocks {
+			result = append(result, blockData{UserID: block.BlockedID.String(), BlockedAt: block.CreatedAt})
+		}
+		if len(blocks) < blocksDataExportPageSize {
+			return result, nil
+		}
+	}
+}
+
+func NewPrivacyDataExportSection(privacyService PrivacyService) DataExportSection {
+	return &privacyDataExportSection{privacyService: privacyService}
+}
+
+type privacyDataExportSection struct {
+	privacyService PrivacyService
+}
+
+type privacyData struct {
+	ProfileVisibility       string `json:"profile_visibility"`
+	WhoCanFollow            string `json:"who_can_follow"`
+	ListeningActivityPublic bool   `json:"listening_activity_public"`
+}
+
+func (section *privacyDataExportSection) Name() string {
+	return "privacy_settings"
+}
+
+func (section *privacyDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	settings, err := section.privacyService.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return privacyData{
+		ProfileVisibility:       domain.ProfileVisibility(settings.ProfileVisibility).Name(),
+		WhoCanFollow:            domain.FollowPolicy(settings.WhoCanFollow).Name(),
+		ListeningActivityPublic: settings.ListeningActivityPublic,
+	}, nil
+}
+
+func NewExternalIdentitiesDataExportSection(externalIdentityService ExternalIdentityService) DataExportSection {
+	return &externalIdentitiesDataExportSection{externalIdentityService: externalIdentityService}
+}
+
+type externalIdentitiesDataExportSection struct {
+	externalIdentityService ExternalIdentityService
+}
+
+type externalIdentityData struct {
+	Provider string    `json:"provider"`
+	Email    string    `json:"email,omitempty"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+func (section *externalIdentitiesDataExportSection) Name() string {
+	return "external_identities"
+}
+
+func (section *externalIdentitiesDataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	identities, err := section.externalIdentityService.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]externalIdentityData, 0, len(identities))
+	for _, identity := range identities {
+		result = append(result, externalIdentityData{
+			Provider: identity.Provider,
+			Email:    identity.Email,
+			LinkedAt: identity.LinkedAt,
+		})
+	}
+	return result, nil
+}
+
+func NewOAuth2DataExportSection(oauth2Service OAuth2Service, queryService query.AuditLogQueryService) DataExportSection {
+	return &oauth2DataExportSection{oauth2Service: oauth2Service, queryService: queryService}
+}
+
+type oauth2DataExportSection struct {
+	oauth2Service OAuth2Service
+	queryService  query.AuditLogQueryService
+}
+
+type oauth2Data struct {
+	Tokens []oauth2TokenData `json:"tokens"`
+	Grants []oauth2GrantData `json:"grants"`
+}
+
+type oauth2TokenData struct {
+	ClientID   string    `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Kind       string    `json:"kind"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type oauth2GrantData struct {
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+func (section *oauth2DataExportSection) Name() string {
+	return "oauth2"
+}
+
+func (section *oauth2DataExportSection) Collect(ctx context.Context, userID uuid.UUID) (interface{}, error) {
+	tokens, err := section.oauth2Service.ListUserTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := listAuditEntries(ctx, section.queryService, userID, AuditOAuth2AuthorizationGranted)
+	if err != nil {
+		return nil, err
+	}
+
+	result := oauth2Data{
+		Tokens: make([]oauth2TokenData, 0, len(tokens)),
+		Grants: make([]oauth2GrantData, 0, len(entries)),
+	}
+	for _, token := range tokens {
+		kind := "access"
+		if token.Refresh {
+			kind = "refresh"
+		}
+		result.Tokens = append(result.Tokens, oauth2TokenData{
+			ClientID:   token.ClientID,
+			ClientName: token.ClientName,
+			Kind:       kind,
+			Scopes:     token.Scopes,
+			CreatedAt:  token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+		})
+	}
+	for _, entry := range entries {
+		result.Grants = append(result.Grants, oauth2GrantData{
+			ClientID:  entry.Details["client_id"],
+			Scopes:    strings.Fields(entry.Details["scopes"]),
+			GrantedAt: entry.OccurredAt,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+const (
+	downloadTokenBytes          = 32
+	dataExportProcessingTimeout = 15 * time.Minute
+	manifestFileName            = "manifest.json"
+)
+
+var (
+	ErrDataExportNotReady = errors.New("data export is not ready")
+	ErrDataExportExpired  = errors.New("data export is expired")
+)
+
+type DataExportStatus int
+
+const (
+	DataExportPending    = DataExportStatus(domain.DataExportPending)
+	DataExportProcessing = DataExportStatus(domain.DataExportProcessing)
+	DataExportReady      = DataExportStatus(domain.DataExportReady)
+	DataExportFailed     = DataExportStatus(domain.DataExportFailed)
+	DataExportExpired    = DataExportStatus(domain.DataExportExpired)
+)
+
+type DataExportSection interface {
+	Name() string
+	Collect(ctx context.Context, userID uuid.UUID) (interface{}, error)
+}
+
+type ArchiveStorage interface {
+	Save(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type DataExportView struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Status      DataExportStatus
+	RequestedAt time.Time
+	ExpiresAt   *time.Time
+}
+
+type DataExportService interface {
+	RequestDataExport(ctx context.Context, userID uuid.UUID) (exportID uuid.UUID, downloadToken string, err error)
+	GetDataExport(ctx context.Context, exportID uuid.UUID) (DataExportView, error)
+	OpenArchive(ctx context.Context, downloadToken string) (io.ReadCloser, error)
+	ProcessPending(ctx context.Context) (bool, error)
+	RemoveExpired(ctx context.Context, limit int) (int, error)
+}
+
+func NewDataExportService(
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	storage ArchiveStorage,
+	sections []DataExportSection,
+	ttl time.Duration,
+) DataExportService {
+	return &dataExportService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		storage:           storage,
+		sections:          sections,
+		ttl:               ttl,
+	}
+}
+
+type dataExportService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	storage           ArchiveStorage
+	sections          []DataExportSection
+	ttl               time.Duration
+}
+
+func (service *dataExportService) RequestDataExport(ctx context.Context, userID uuid.UUID) (uuid.UUID, string, error) {
+	token, err := generateDownloadToken()
+	if err != nil {
+		return uuid.UUID{}, "", err
+	}
+
+	var exportID domain.DataExportID
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		_, err2 := provider.UserRepository().Find(ctx, domain.UserID(userID))
+		if err2 != nil {
+			return err2
+		}
+
+		repo := provider.DataExportRepository()
+		exportID = repo.NewID()
+		err2 = repo.Store(ctx, domain.DataExport{
+			ID:                exportID,
+			UserID:            domain.UserID(userID),
+			Status:            domain.DataExportPending,
+			DownloadTokenHash: hashDownloadToken(token),
+			RequestedAt:       time.Now(),
+		})
+		if err2 != nil {
+			return err2
+		}
+
+		return dispatcher.Dispatch(domain.DataExportRequested{ExportID: exportID, UserID: domain.UserID(userID)})
+	})
+	if err != nil {
+		return uuid.UUID{}, "", err
+	}
+
+	return uuid.UUID(exportID), token, nil
+}
+
+func (service *dataExportService) GetDataExport(ctx context.Context, exportID uuid.UUID) (DataExportView, error) {
+	var view DataExportView
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		export, err := provider.DataExportRepository().Find(ctx, domain.DataExportID(exportID))
+		if err != nil {
+			return err
+		}
+
+		view = DataExportView{
+			ID:          uuid.UUID(export.ID),
+			UserID:      uuid.UUID(export.UserID),
+			Status:      DataExportStatus(export.Status),
+			RequestedAt: export.RequestedAt,
+			ExpiresAt:   export.ExpiresAt,
+		}
+		return nil
+	})
+	return view, err
+}
+
+func (service *dataExportService) OpenArchive(ctx context.Context, downloadToken string) (io.ReadCloser, error) {
+	var export domain.DataExport
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		export, err = provider.DataExportRepository().FindByDownloadTokenHash(ctx, hashDownloadToken(downloadToken))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !export.IsDownloadable(time.Now()) {
+		if export.Status == domain.DataExportReady || export.Status == domain.DataExportExpired {
+			return nil, ErrDataExportExpired
+		}
+		return nil, ErrDataExportNotReady
+	}
+
+	return service.storage.Open(ctx, archiveKey(export.ID))
+}
+
+func (service *dataExportService) ProcessPending(ctx context.Context) (bool, error) {
+	var export domain.DataExport
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		repo := provider.DataExportRepository()
+
+		var err error
+		now := time.Now()
+		export, err = repo.ClaimPending(ctx, now.Add(-dataExportProcessingTimeout))
+		if err != nil {
+			return err
+		}
+
+		export.Start(now)
+		return repo.Store(ctx, export)
+	})
+	if errors.Cause(err) == domain.ErrNoPendingDataExport {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	buildErr := service.buildArchive(ctx, export)
+
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		repo := provider.DataExportRepository()
+
+		claimed, err := repo.Find(ctx, export.ID)
+		if err != nil {
+			return err
+		}
+
+		// export was expired while archive was assembled, e.g. user was erased
+		if claimed.Status != domain.DataExportProcessing {
+			return service.storage.Delete(ctx, archiveKey(claimed.ID))
+		}
+
+		if buildErr != nil {
+			claimed.Fail(time.Now(), buildErr.Error())
+		} else {
+			claimed.Complete(time.Now(), service.ttl)
+		}
+		return repo.Store(ctx, claimed)
+	})
+	if err != nil {
+		return true, err
+	}
+	return true, buildErr
+}
+
+func (service *dataExportService) RemoveExpired(ctx context.Context, limit int) (int, error) {
+	removed := 0
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		repo := provider.DataExportRepository()
+
+		exports, err := repo.FindExpired(ctx, time.Now(), limit)
+		if err != nil {
+			return err
+		}
+
+		for _, export := range exports {
+			err = service.storage.Delete(ctx, archiveKey(export.ID))
+			if err != nil {
+				return err
+			}
+
+			export.Expire()
+			err = repo.Store(ctx, export)
+			if err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+func (service *dataExportService) buildArchive(ctx context.Context, export domain.DataExport) error {
+	userID := uuid.UUID(export.UserID)
+
+	buffer := &bytes.Buffer{}
+	archive := zip.NewWriter(buffer)
+
+	manifest := dataExportManifest{
+		UserID:      userID.String(),
+		GeneratedAt: time.Now(),
+	}
+	for _, section := range service.sections {
+		data, err := section.Collect(ctx, userID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to collect %s", section.Name())
+		}
+
+		fileName := section.Name() + ".json"
+		err = writeJSONFile(archive, fileName, data)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, fileName)
+	}
+
+	err := writeJSONFile(archive, manifestFileName, manifest)
+	if err != nil {
+		return err
+	}
+	err = archive.Close()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return service.storage.Save(ctx, archiveKey(export.ID), buffer)
+}
+
+func (service *dataExportService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+type dataExportManifest struct {
+	UserID      string    `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+func writeJSONFile(archive *zip.Writer, name string, data interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return errors.WithStack(encoder.Encode(data))
+}
+
+func archiveKey(id domain.DataExportID) string {
+	return uuid.UUID(id).String() + ".zip"
+}
+
+func generateDownloadToken() (string, error) {
+	b := make([]byte, downloadTokenBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashDownloadToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
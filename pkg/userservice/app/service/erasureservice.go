@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+const accountErasureScope = "account"
+
+type PersonalDataEraser interface {
+	Scope() string
+	Erase(ctx context.Context, provider RepositoryProvider, dispatcher domain.EventDispatcher, userID domain.UserID) error
+}
+
+type ErasureCertificateView struct {
+	ID       uuid.UUID
+	UserID   uuid.UUID
+	ErasedAt time.Time
+	Scopes   []string
+}
+
+type ErasureService interface {
+	EraseUser(ctx context.Context, userID uuid.UUID, requestedBy *uuid.UUID) (ErasureCertificateView, error)
+}
+
+func NewErasureService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler, erasers []PersonalDataEraser) ErasureService {
+	return &erasureService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		erasers:           erasers,
+	}
+}
+
+type erasureService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	erasers           []PersonalDataEraser
+}
+
+func (service *erasureService) EraseUser(ctx context.Context, userID uuid.UUID, requestedBy *uuid.UUID) (ErasureCertificateView, error) {
+	var certificate domain.ErasureCertificate
+
+	err := executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		err := domainService.EraseUser(ctx, domain.UserID(userID))
+		if err != nil {
+			return err
+		}
+
+		scopes := []string{accountErasureScope}
+		for _, eraser := range service.erasers {
+			err = eraser.Erase(ctx, provider, dispatcher, domain.UserID(userID))
+			if err != nil {
+				return err
+			}
+			scopes = append(scopes, eraser.Scope())
+		}
+
+		repo := provider.ErasureCertificateRepository()
+		certificate = domain.ErasureCertificate{
+			ID:       repo.NewID(),
+			UserID:   domain.UserID(userID),
+			ErasedAt: time.Now(),
+			Scopes:   scopes,
+		}
+		if requestedBy != nil {
+			requester := domain.UserID(*requestedBy)
+			certificate.RequestedBy = &requester
+		}
+		return repo.Store(ctx, certificate)
+	})
+	if err != nil {
+		return ErasureCertificateView{}, err
+	}
+
+	return ErasureCertificateView{
+		ID:       uuid.UUID(certificate.ID),
+		UserID:   uuid.UUID(certificate.UserID),
+		ErasedAt: certificate.ErasedAt,
+		Scopes:   certificate.Scopes,
+	}, nil
+}
+
+func NewDataExportEraser(storage ArchiveStorage) PersonalDataEraser {
+	return &dataExportEraser{storage: storage}
+}
+
+type dataExportEraser struct {
+	storage ArchiveStorage
+}
+
+func (eraser *dataExportEraser) Scope() string {
+	return "data_exports"
+}
+
+func (eraser *dataExportEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	repo := provider.DataExportRepository()
+
+	exports, err := repo.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		err = eraser.storage.Delete(ctx, archiveKey(export.ID))
+		if err != nil {
+			return err
+		}
+
+		if export.Status == domain.DataExportExpired {
+			continue
+		}
+		export.Expire()
+		err = repo.Store(ctx, export)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type EventHandler interface {
+	Handle(event domain.Event)
+}
+
+// TransactionalEventHandler also handles events before commit in unit of work that dispatched them,
+// its failure rolls change back, e.g. writing event to outbox
+type TransactionalEventHandler interface {
+	EventHandler
+	HandleInUnitOfWork(ctx context.Context, provider RepositoryProvider, event domain.Event) error
+}
+
+func NewCompositeEventHandler(handlers ...EventHandler) EventHandler {
+	return compositeEventHandler(handlers)
+}
+
+type compositeEventHandler []EventHandler
+
+func (handlers compositeEventHandler) Handle(event domain.Event) {
+	for _, handler := range handlers {
+		handler.Handle(event)
+	}
+}
+
+func (handlers compositeEventHandler) HandleInUnitOfWork(ctx context.Context, provider RepositoryProvider, event domain.Event) error {
+	for _, handler := range handlers {
+		if transactionalHandler, ok := handler.(TransactionalEventHandler); ok {
+			err := transactionalHandler.HandleInUnitOfWork(ctx, provider, event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type eventCollector struct {
+	events []domain.Event
+}
+
+func (collector *eventCollector) Dispatch(event domain.Event) error {
+	collector.events = append(collector.events, event)
+	return nil
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/domain"
+)
+
+var (
+	ErrUnknownExternalProvider      = errors.New("unknown external identity provider")
+	ErrExternalAuthenticationFailed = errors.New("external identity provider did not authenticate user")
+	ErrExternalLoginRequestMismatch = errors.New("external login request was started for another purpose")
+)
+
+type ExternalClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+type ExternalIdentityProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (ExternalClaims, error)
+}
+
+type ExternalAuthorization struct {
+	URL   string
+	State string
+}
+
+type ExternalSignUp struct {
+	Terms       *TermsAcceptance
+	DateOfBirth *time.Time
+}
+
+type ExternalSignIn struct {
+	UserID      uuid.UUID
+	Provisioned bool
+}
+
+type ExternalIdentityView struct {
+	Provider string
+	Email    string
+	LinkedAt time.Time
+}
+
+type ExternalIdentityService interface {
+	StartSignIn(ctx context.Context, provider string) (ExternalAuthorization, error)
+	CompleteSignIn(ctx context.Context, state, code string, signUp ExternalSignUp) (ExternalSignIn, error)
+	StartLink(ctx context.Context, userID uuid.UUID, provider string) (ExternalAuthorization, error)
+	CompleteLink(ctx context.Context, userID uuid.UUID, state, code string) error
+	Unlink(ctx context.Context, userID uuid.UUID, provider string) error
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]ExternalIdentityView, error)
+}
+
+func NewExternalIdentityService(
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	consentPolicy ConsentPolicy,
+	providers map[string]ExternalIdentityProvider,
+) ExternalIdentityService {
+	return &externalIdentityService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		consentPolicy:     consentPolicy,
+		providers:         providers,
+	}
+}
+
+type externalIdentityService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	consentPolicy     ConsentPolicy
+	providers         map[string]ExternalIdentityProvider
+}
+
+func (service *externalIdentityService) StartSignIn(ctx context.Context, provider string) (ExternalAuthorization, error) {
+	return service.start(ctx, provider, nil)
+}
+
+func (service *externalIdentityService) CompleteSignIn(ctx context.Context, state, code string, signUp ExternalSignUp) (ExternalSignIn, error) {
+	request, err := service.consumeRequest(ctx, state)
+	if err != nil {
+		return ExternalSignIn{}, err
+	}
+	if request.LinkUserID != nil {
+		return ExternalSignIn{}, ErrExternalLoginRequestMismatch
+	}
+	identity, err := service.exchange(ctx, request, code)
+	if err != nil {
+		return ExternalSignIn{}, err
+	}
+
+	var result ExternalSignIn
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domainExternalIdentityService(provider, dispatcher)
+		userID, err2 := domainService.SignIn(ctx, identity)
+		if errors.Cause(err2) != domain.ErrExternalIdentityNotFound {
+			result = ExternalSignIn{UserID: uuid.UUID(userID)}
+			return err2
+		}
+
+		userID, err2 = service.provision(ctx, provider, dispatcher, identity, signUp)
+		if err2 != nil {
+			return err2
+		}
+		result = ExternalSignIn{UserID: uuid.UUID(userID), Provisioned: true}
+		return domainService.LinkProvisioned(ctx, userID, identity)
+	})
+	return result, err
+}
+
+// provision signs up user of identity the same way as AddUser does, existing account is never taken over by email match,
+// owner links provider while signed in
+func (service *externalIdentityService) provision(
+	ctx context.Context,
+	provider RepositoryProvider,
+	dispatcher domain.EventDispatcher,
+	identity domain.ExternalIdentity,
+	request ExternalSignUp,
+) (domain.UserID, error) {
+	if identity.Email == "" {
+		return domain.UserID{}, domain.ErrExternalEmailNotVerified
+	}
+	err := service.consentPolicy.validateSignUp(request.Terms)
+	if err != nil {
+		return domain.UserID{}, err
+	}
+
+	userID, err := signUp(ctx, provider, dispatcher, signUpRequest{
+		Email:             identity.Email,
+		PasswordAlgorithm: domain.NoPasswordAlgorithm,
+		Role:              Listener,
+		Terms:             request.Terms,
+		DateOfBirth:       request.DateOfBirth,
+	})
+	if errors.Cause(err) == domain.ErrUserWithEmailAlreadyExists {
+		return domain.UserID{}, domain.ErrExternalEmailBelongsToOtherUser
+	}
+	return userID, err
+}
+
+func (service *externalIdentityService) StartLink(ctx context.Context, userID uuid.UUID, provider string) (ExternalAuthorization, error) {
+	linkUserID := domain.UserID(userID)
+	return service.start(ctx, provider, &linkUserID)
+}
+
+func (service *externalIdentityService) CompleteLink(ctx context.Context, userID uuid.UUID, state, code string) error {
+	request, err := service.consumeRequest(ctx, state)
+	if err != nil {
+		return err
+	}
+	if request.LinkUserID == nil || *request.LinkUserID != domain.UserID(userID) {
+		return ErrExternalLoginRequestMismatch
+	}
+	identity, err := service.exchange(ctx, request, code)
+	if err != nil {
+		return err
+	}
+
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainExternalIdentityService(provider, dispatcher).Link(ctx, domain.UserID(userID), identity)
+	})
+}
+
+func (service *externalIdentityService) Unlink(ctx context.Context, userID uuid.UUID, provider string) error {
+	return service.executeInUnitOfWork(ctx, func(repositoryProvider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainExternalIdentityService(repositoryProvider, dispatcher).Unlink(ctx, domain.UserID(userID), provider)
+	})
+}
+
+func (service *externalIdentityService) ListIdentities(ctx context.Context, userID uuid.UUID) ([]ExternalIdentityView, error) {
+	var identities []domain.ExternalIdentity
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		identities, err = provider.ExternalIdentityRepository().FindByUser(ctx, domain.UserID(userID))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ExternalIdentityView, 0, len(identities))
+	for _, identity := range identities {
+		result = append(result, ExternalIdentityView{Provider: identity.Provider, Email: identity.Email, LinkedAt: identity.LinkedAt})
+	}
+	return result, nil
+}
+
+func (service *externalIdentityService) start(ctx context.Context, providerName string, linkUserID *domain.UserID) (ExternalAuthorization, error) {
+	provider, ok := service.providers[providerName]
+	if !ok {
+		return ExternalAuthorization{}, ErrUnknownExternalProvider
+	}
+
+	request := domain.ExternalLoginRequest{
+		Provider:   providerName,
+		LinkUserID: linkUserID,
+		ExpiresAt:  time.Now().Add(domain.ExternalLoginRequestTTL),
+	}
+	for _, token := range []*string{&request.State, &request.Nonce, &request.CodeVerifier} {
+		var err error
+		*token, err = hash.RandomToken()
+		if err != nil {
+			return ExternalAuthorization{}, err
+		}
+	}
+
+	url, err := provider.AuthCodeURL(ctx, request.State, request.Nonce, hash.PKCEChallenge(request.CodeVerifier))
+	if err != nil {
+		return ExternalAuthorization{}, err
+	}
+
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		if linkUserID != nil {
+			user, err2 := provider.UserRepository().Find(ctx, *linkUserID)
+			if err2 != nil {
+				return err2
+			}
+			if user.IsDeleted() || user.IsErased() {
+				return domain.ErrUserNotFound
+			}
+		}
+		err2 := provider.ExternalLoginRequestRepository().RemoveExpired(ctx, time.Now())
+		if err2 != nil {
+			return err2
+		}
+		return provider.ExternalLoginRequestRepository().Store(ctx, request)
+	})
+	if err != nil {
+		return ExternalAuthorization{}, err
+	}
+	return ExternalAuthorization{URL: url, State: request.State}, nil
+}
+
+// consumeRequest removes request in own unit of work, so state can not be replayed even if exchange fails
+func (service *externalIdentityService) consumeRequest(ctx context.Context, state string) (domain.ExternalLoginRequest, error) {
+	var request domain.ExternalLoginRequest
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		request, err = provider.ExternalLoginRequestRepository().Find(ctx, state)
+		if err != nil {
+			return err
+		}
+		return provider.ExternalLoginRequestRepository().Remove(ctx, state)
+	})
+	if err != nil {
+		return domain.ExternalLoginRequest{}, err
+	}
+	if time.Now().After(request.ExpiresAt) {
+		return domain.ExternalLoginRequest{}, domain.ErrExternalLoginRequestExpired
+	}
+	return request, nil
+}
+
+func (service *externalIdentityService) exchange(ctx context.Context, request domain.ExternalLoginRequest, code string) (domain.ExternalIdentity, error) {
+	provider, ok := service.providers[request.Provider]
+	if !ok {
+		return domain.ExternalIdentity{}, ErrUnknownExternalProvider
+	}
+
+	claims, err := provider.Exchange(ctx, code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		return domain.ExternalIdentity{}, err
+	}
+
+	identity := domain.ExternalIdentity{Provider: request.Provider, Issuer: claims.Issuer, Subject: claims.Subject}
+	// unverified email is not trusted, since anyone may claim it at provider
+	if claims.EmailVerified {
+		identity.Email = claims.Email
+	}
+	return identity, nil
+}
+
+func (service *externalIdentityService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func domainExternalIdentityService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.ExternalIdentityService {
+	return domain.NewExternalIdentityService(provider.UserRepository(), provider.ExternalIdentityRepository(), dispatcher)
+}
+
+func NewExternalIdentityEraser() PersonalDataEraser {
+	return &externalIdentityEraser{}
+}
+
+type externalIdentityEraser struct{}
+
+func (eraser *externalIdentityEraser) Scope() string {
+	return "external_identities"
+}
+
+func (eraser *externalIdentityEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	err := provider.ExternalIdentityRepository().RemoveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return provider.ExternalLoginRequestRepository().RemoveByUser(ctx, userID)
+}
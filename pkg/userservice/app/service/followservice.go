@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type FollowTargetKind int
+
+const (
+	FollowTargetUser   = FollowTargetKind(domain.FollowTargetUser)
+	FollowTargetArtist = FollowTargetKind(domain.FollowTargetArtist)
+)
+
+type FollowTarget struct {
+	Kind FollowTargetKind
+	ID   uuid.UUID
+}
+
+type FollowView struct {
+	FollowerID uuid.UUID
+	Target     FollowTarget
+	CreatedAt  time.Time
+}
+
+type FollowCountsView struct {
+	Followers int
+	Following int
+}
+
+type FollowService interface {
+	Follow(ctx context.Context, followerID uuid.UUID, target FollowTarget) error
+	Unfollow(ctx context.Context, followerID uuid.UUID, target FollowTarget) error
+	ListFollowers(ctx context.Context, viewerID uuid.UUID, target FollowTarget, offset, limit int) ([]FollowView, error)
+	ListFollowing(ctx context.Context, viewerID, userID uuid.UUID, offset, limit int) ([]FollowView, error)
+	GetCounts(ctx context.Context, viewerID uuid.UUID, target FollowTarget) (FollowCountsView, error)
+}
+
+func NewFollowService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler) FollowService {
+	return &followService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+	}
+}
+
+type followService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+}
+
+func (service *followService) Follow(ctx context.Context, followerID uuid.UUID, target FollowTarget) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainFollowService(provider, dispatcher).Follow(ctx, domain.UserID(followerID), domainFollowTarget(target))
+	})
+}
+
+func (service *followService) Unfollow(ctx context.Context, followerID uuid.UUID, target FollowTarget) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainFollowService(provider, dispatcher).Unfollow(ctx, domain.UserID(followerID), domainFollowTarget(target))
+	})
+}
+
+func (service *followService) ListFollowers(ctx context.Context, viewerID uuid.UUID, target FollowTarget, offset, limit int) ([]FollowView, error) {
+	var follows []domain.Follow
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		if target.Kind == FollowTargetUser {
+			err := assertCanViewProfile(ctx, provider, dispatcher, viewerID, target.ID)
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		follows, err = provider.FollowRepository().FindFollowers(ctx, domainFollowTarget(target), offset, limit)
+		return err
+	})
+	return makeFollowViews(follows), err
+}
+
+func (service *followService) ListFollowing(ctx context.Context, viewerID, userID uuid.UUID, offset, limit int) ([]FollowView, error) {
+	var follows []domain.Follow
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		err := assertCanViewProfile(ctx, provider, dispatcher, viewerID, userID)
+		if err != nil {
+			return err
+		}
+		follows, err = provider.FollowRepository().FindFollowing(ctx, domain.UserID(userID), offset, limit)
+		return err
+	})
+	return makeFollowViews(follows), err
+}
+
+func (service *followService) GetCounts(ctx context.Context, viewerID uuid.UUID, target FollowTarget) (FollowCountsView, error) {
+	var counts domain.FollowCounts
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		if target.Kind == FollowTargetUser {
+			err := assertCanViewProfile(ctx, provider, dispatcher, viewerID, target.ID)
+			if err != nil {
+				return err
+			}
+		}
+		var err error
+		counts, err = provider.FollowRepository().Counts(ctx, domainFollowTarget(target))
+		return err
+	})
+	return FollowCountsView{Followers: counts.Followers, Following: counts.Following}, err
+}
+
+func (service *followService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func domainFollowService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.FollowService {
+	return domain.NewFollowService(
+		provider.UserRepository(),
+		provider.ArtistRepository(),
+		provider.FollowRepository(),
+		provider.BlockRepository(),
+		provider.PrivacySettingsRepository(),
+		dispatcher,
+	)
+}
+
+func assertCanViewProfile(ctx context.Context, provider RepositoryProvider, dispatcher domain.EventDispatcher, viewerID, userID uuid.UUID) error {
+	canView, err := domainPrivacyService(provider, dispatcher).CanViewProfile(ctx, domain.UserID(viewerID), domain.UserID(userID))
+	if err != nil {
+		return err
+	}
+	if !canView {
+		return domain.ErrProfileNotVisible
+	}
+	return nil
+}
+
+func domainFollowTarget(target FollowTarget) domain.FollowTarget {
+	return domain.FollowTarget{Kind: domain.FollowTargetKind(target.Kind), ID: target.ID}
+}
+
+func makeFollowViews(follows []domain.Follow) []FollowView {
+	result := make([]FollowView, 0, len(follows))
+	for _, follow := range follows {
+		result = append(result, FollowView{
+			FollowerID: uuid.UUID(follow.FollowerID),
+			Target:     FollowTarget{Kind: FollowTargetKind(follow.Target.Kind), ID: follow.Target.ID},
+			CreatedAt:  follow.CreatedAt,
+		})
+	}
+	return result
+}
+
+func NewFollowEraser() PersonalDataEraser {
+	return &followEraser{}
+}
+
+type followEraser struct{}
+
+func (eraser *followEraser) Scope() string {
+	return "follows"
+}
+
+func (eraser *followEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.FollowRepository().RemoveByUser(ctx, userID)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type HouseholdMemberView struct {
+	UserID   uuid.UUID
+	Manager  bool
+	JoinedAt time.Time
+}
+
+type HouseholdView struct {
+	ID         uuid.UUID
+	ManagerID  uuid.UUID
+	CreatedAt  time.Time
+	MaxMembers int
+	Members    []HouseholdMemberView
+}
+
+type HouseholdInvitationView struct {
+	ID          uuid.UUID
+	HouseholdID uuid.UUID
+	ManagerID   uuid.UUID
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+type HouseholdService interface {
+	CreateHousehold(ctx context.Context, managerID uuid.UUID, address string) (uuid.UUID, error)
+	GetHousehold(ctx context.Context, userID uuid.UUID) (HouseholdView, error)
+	InviteMember(ctx context.Context, managerID uuid.UUID, email string) (uuid.UUID, error)
+	ListInvitations(ctx context.Context, userID uuid.UUID) ([]HouseholdInvitationView, error)
+	RespondToInvitation(ctx context.Context, invitationID, userID uuid.UUID, accept bool, address string) error
+	RemoveMember(ctx context.Context, actorID, userID uuid.UUID) error
+}
+
+func NewHouseholdService(
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	addressHasher domain.HouseholdAddressHasher,
+	maxMembers int,
+) HouseholdService {
+	return &householdService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		addressHasher:     addressHasher,
+		maxMembers:        maxMembers,
+	}
+}
+
+type householdService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	addressHasher     domain.HouseholdAddressHasher
+	maxMembers        int
+}
+
+func (service *householdService) CreateHousehold(ctx context.Context, managerID uuid.UUID, address string) (uuid.UUID, error) {
+	var householdID domain.HouseholdID
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		householdID, err = service.domainHouseholdService(provider, dispatcher).CreateHousehold(ctx, domain.UserID(managerID), address)
+		return err
+	})
+	return uuid.UUID(householdID), err
+}
+
+func (service *householdService) GetHousehold(ctx context.Context, userID uuid.UUID) (HouseholdView, error) {
+	var view HouseholdView
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		repo := provider.HouseholdRepository()
+		household, err := repo.FindByMember(ctx, domain.UserID(userID))
+		if err != nil {
+			return err
+		}
+		members, err := repo.FindMembers(ctx, household.ID)
+		if err != nil {
+			return err
+		}
+
+		view = HouseholdView{
+			ID:         uuid.UUID(household.ID),
+			ManagerID:  uuid.UUID(household.ManagerID),
+			CreatedAt:  household.CreatedAt,
+			MaxMembers: service.maxMembers,
+		}
+		for _, member := range members {
+			view.Members = append(view.Members, HouseholdMemberView{
+				UserID:   uuid.UUID(member.UserID),
+				Manager:  member.UserID == household.ManagerID,
+				JoinedAt: member.JoinedAt,
+			})
+		}
+		return nil
+	})
+	return view, err
+}
+
+func (service *householdService) InviteMember(ctx context.Context, managerID uuid.UUID, email string) (uuid.UUID, error) {
+	var invitationID domain.HouseholdInvitationID
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		invitationID, err = service.domainHouseholdService(provider, dispatcher).InviteMember(ctx, domain.UserID(managerID), domain.NormalizeEmail(email))
+		return err
+	})
+	return uuid.UUID(invitationID), err
+}
+
+func (service *householdService) ListInvitations(ctx context.Context, userID uuid.UUID) ([]HouseholdInvitationView, error) {
+	var result []HouseholdInvitationView
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		user, err := provider.UserRepository().Find(ctx, domain.UserID(userID))
+		if err != nil {
+			return err
+		}
+		invitations, err := provider.HouseholdInvitationRepository().FindPendingByEmail(ctx, user.Email)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result = make([]HouseholdInvitationView, 0, len(invitations))
+		for _, invitation := range invitations {
+			if !now.Before(invitation.ExpiresAt) {
+				continue
+			}
+			household, err := provider.HouseholdRepository().Find(ctx, invitation.HouseholdID)
+			if err != nil {
+				return err
+			}
+			result = append(result, HouseholdInvitationView{
+				ID:          uuid.UUID(invitation.ID),
+				HouseholdID: uuid.UUID(invitation.HouseholdID),
+				ManagerID:   uuid.UUID(household.ManagerID),
+				CreatedAt:   invitation.CreatedAt,
+				ExpiresAt:   invitation.ExpiresAt,
+			})
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (service *householdService) RespondToInvitation(ctx context.Context, invitationID, userID uuid.UUID, accept bool, address string) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return service.domainHouseholdService(provider, dispatcher).RespondToInvitation(
+			ctx,
+			domain.HouseholdInvitationID(invitationID),
+			domain.UserID(userID),
+			accept,
+			address,
+		)
+	})
+}
+
+func (service *householdService) RemoveMember(ctx context.Context, actorID, userID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return service.domainHouseholdService(provider, dispatcher).RemoveMember(ctx, domain.UserID(actorID), domain.UserID(userID))
+	})
+}
+
+func (service *householdService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func (service *householdService) domainHouseholdService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.HouseholdService {
+	return domain.NewHouseholdService(
+		provider.UserRepository(),
+		provider.HouseholdRepository(),
+		provider.HouseholdInvitationRepository(),
+		provider.SubscriptionRepository(),
+		dispatcher,
+		service.addressHasher,
+		service.maxMembers,
+	)
+}
+
+func NewHouseholdEraser() PersonalDataEraser {
+	return &householdEraser{}
+}
+
+type householdEraser struct{}
+
+func (eraser *householdEraser) Scope() string {
+	return "household"
+}
+
+func (eraser *householdEraser) Erase(ctx context.Context, provider RepositoryProvider, dispatcher domain.EventDispatcher, userID domain.UserID) error {
+	err := removeHouseholdMembership(ctx, provider, dispatcher, userID)
+	if err != nil {
+		return err
+	}
+	return provider.HouseholdInvitationRepository().RemoveByInvitee(ctx, userID)
+}
+
+func removeHouseholdMembership(ctx context.Context, provider RepositoryProvider, dispatcher domain.EventDispatcher, userID domain.UserID) error {
+	repo := provider.HouseholdRepository()
+	household, err := repo.FindByMember(ctx, userID)
+	if err != nil {
+		if errors.Cause(err) == domain.ErrHouseholdNotFound {
+			return nil
+		}
+		return err
+	}
+	if household.ManagerID != userID {
+		err = repo.RemoveMember(ctx, household.ID, userID)
+		if err != nil {
+			return err
+		}
+		return dispatcher.Dispatch(domain.HouseholdMemberRemoved{HouseholdID: household.ID, UserID: userID})
+	}
+
+	members, err := repo.FindMembers(ctx, household.ID)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		err = repo.RemoveMember(ctx, household.ID, member.UserID)
+		if err != nil {
+			return err
+		}
+		err = dispatcher.Dispatch(domain.HouseholdMemberRemoved{HouseholdID: household.ID, UserID: member.UserID})
+		if err != nil {
+			return err
+		}
+	}
+	err = provider.HouseholdInvitationRepository().RemoveByHousehold(ctx, household.ID)
+	if err != nil {
+		return err
+	}
+	return repo.Remove(ctx, household.ID)
+}
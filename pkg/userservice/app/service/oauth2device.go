@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/domain"
+)
+
+var (
+	ErrOAuth2AuthorizationPending = errors.New("user has not approved device yet")
+	ErrOAuth2SlowDown             = errors.New("device polls too often")
+	ErrOAuth2DeviceCodeExpired    = errors.New("device code expired")
+	ErrOAuth2AccessDenied         = errors.New("user denied device authorization")
+
+	errOAuth2UserCodeExhausted = errors.New("failed to generate unique device user code")
+)
+
+const (
+	oauth2DeviceCodeTTL      = 10 * time.Minute
+	oauth2DevicePollInterval = 5 * time.Second
+	// user code has no vowels, so it can not spell words, and no similar looking letters
+	oauth2UserCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	oauth2UserCodeLength   = 8
+	oauth2UserCodeAttempts = 5
+)
+
+type OAuth2DeviceAuthorizationView struct {
+	DeviceCode              string
+	UserCode                string
+	VerificationURI         string
+	VerificationURIComplete string
+	ExpiresIn               time.Duration
+	Interval                time.Duration
+}
+
+type OAuth2DeviceApprovalView struct {
+	Client    OAuth2ClientView
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+func (service *oauth2Service) StartDeviceAuthorization(
+	ctx context.Context,
+	credentials OAuth2ClientCredentials,
+	scopes []string,
+) (OAuth2DeviceAuthorizationView, error) {
+	deviceCode, err := hash.RandomToken()
+	if err != nil {
+		return OAuth2DeviceAuthorizationView{}, err
+	}
+
+	var userCode string
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		client, err2 := authenticateOAuth2Client(ctx, provider, credentials, domain.OAuth2DeviceCodeGrant)
+		if err2 != nil {
+			return err2
+		}
+		if !client.AllowsScopes(scopes) {
+			return domain.ErrInvalidOAuth2Scope
+		}
+
+		userCode, err2 = newUniqueOAuth2UserCode(ctx, provider)
+		if err2 != nil {
+			return err2
+		}
+
+		return provider.OAuth2DeviceAuthorizationRepository().Store(ctx, domain.OAuth2DeviceAuthorization{
+			DeviceCodeHash: hash.HashToken(deviceCode),
+			UserCode:       userCode,
+			ClientID:       client.ID,
+			Scopes:         scopes,
+			Status:         domain.OAuth2DeviceAuthorizationPending,
+			PollInterval:   oauth2DevicePollInterval,
+			ExpiresAt:      time.Now().Add(oauth2DeviceCodeTTL),
+		})
+	})
+	if err != nil {
+		return OAuth2DeviceAuthorizationView{}, err
+	}
+
+	displayCode := userCode[:oauth2UserCodeLength/2] + "-" + userCode[oauth2UserCodeLength/2:]
+	return OAuth2DeviceAuthorizationView{
+		DeviceCode:              deviceCode,
+		UserCode:                displayCode,
+		VerificationURI:         service.policy.DeviceVerificationURI,
+		VerificationURIComplete: service.policy.DeviceVerificationURI + "?" + url.Values{"user_code": {displayCode}}.Encode(),
+		ExpiresIn:               oauth2DeviceCodeTTL,
+		Interval:                oauth2DevicePollInterval,
+	}, nil
+}
+
+func (service *oauth2Service) PollDeviceToken(ctx context.Context, credentials OAuth2ClientCredentials, deviceCode string) (OAuth2Tokens, error) {
+	client, authorization, err := service.pollDeviceAuthorization(ctx, credentials, deviceCode, false)
+	if err != nil {
+		return OAuth2Tokens{}, err
+	}
+
+	var tokens OAuth2Tokens
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err2 error
+		tokens, err2 = service.issueTokens(ctx, provider, client, authorization.UserID, authorization.Scopes, "", "")
+		return err2
+	})
+	return tokens, err
+}
+
+func (service *oauth2Service) AuthenticateDevice(ctx context.Context, credentials OAuth2ClientCredentials, deviceCode string) (uuid.UUID, error) {
+	_, authorization, err := service.pollDeviceAuthorization(ctx, credentials, deviceCode, true)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	return uuid.UUID(*authorization.UserID), nil
+}
+
+// pollDeviceAuthorization records poll and returns authorization once user approved it,
+// approved authorization is removed in own unit of work, so device code is single use even if exchange fails
+func (service *oauth2Service) pollDeviceAuthorization(
+	ctx context.Context,
+	credentials OAuth2ClientCredentials,
+	deviceCode string,
+	firstPartyOnly bool,
+) (domain.OAuth2Client, domain.OAuth2DeviceAuthorization, error) {
+	var client domain.OAuth2Client
+	var authorization domain.OAuth2DeviceAuthorization
+	// pending outcomes are returned after commit, otherwise poll time would be rolled back and polling could not be slowed down
+	var pollErr error
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		client, err = authenticateOAuth2Client(ctx, provider, credentials, domain.OAuth2DeviceCodeGrant)
+		if err != nil {
+			return err
+		}
+		if firstPartyOnly && !client.FirstParty {
+			return domain.ErrOAuth2GrantNotAllowed
+		}
+		repo := provider.OAuth2DeviceAuthorizationRepository()
+		authorization, err = repo.FindByDeviceCode(ctx, hash.HashToken(deviceCode))
+		if errors.Cause(err) == domain.ErrOAuth2DeviceAuthorizationNotFound {
+			return ErrInvalidOAuth2Grant
+		}
+		if err != nil {
+			return err
+		}
+		if authorization.ClientID != client.ID {
+			return ErrInvalidOAuth2Grant
+		}
+
+		now := time.Now()
+		if now.After(authorization.ExpiresAt) {
+			pollErr = ErrOAuth2DeviceCodeExpired
+			return repo.Remove(ctx, authorization.DeviceCodeHash)
+		}
+
+		switch authorization.Status {
+		case domain.OAuth2DeviceAuthorizationDenied:
+			pollErr = ErrOAuth2AccessDenied
+			return repo.Remove(ctx, authorization.DeviceCodeHash)
+		case domain.OAuth2DeviceAuthorizationApproved:
+			return repo.Remove(ctx, authorization.DeviceCodeHash)
+		}
+
+		pollErr = ErrOAuth2AuthorizationPending
+		if authorization.LastPolledAt != nil && now.Sub(*authorization.LastPolledAt) < authorization.PollInterval {
+			pollErr = ErrOAuth2SlowDown
+			authorization.PollInterval += oauth2DevicePollInterval
+		}
+		authorization.LastPolledAt = &now
+		return repo.Store(ctx, authorization)
+	})
+	if err == nil {
+		err = pollErr
+	}
+	return client, authorization, err
+}
+
+func (service *oauth2Service) LookupDevice(ctx context.Context, userID uuid.UUID, userCode string) (OAuth2DeviceApprovalView, error) {
+	var view OAuth2DeviceApprovalView
+	// wrong code is returned after commit, otherwise failure would be rolled back and codes could be guessed
+	var lookupErr error
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		authorization, found, err := findPendingDeviceAuthorization(ctx, provider, domain.UserID(userID), userCode)
+		if err != nil {
+			return err
+		}
+		if !found {
+			lookupErr = domain.ErrOAuth2DeviceAuthorizationNotFound
+			return nil
+		}
+		client, err := provider.OAuth2ClientRepository().Find(ctx, authorization.ClientID)
+		if err != nil {
+			return err
+		}
+		view = OAuth2DeviceApprovalView{
+			Client:    makeOAuth2ClientView(client),
+			Scopes:    authorization.Scopes,
+			ExpiresAt: authorization.ExpiresAt,
+		}
+		return nil
+	})
+	if err == nil {
+		err = lookupErr
+	}
+	return view, err
+}
+
+func (service *oauth2Service) ApproveDevice(ctx context.Context, userID uuid.UUID, userCode string, approve bool) (OAuth2ClientView, error) {
+	var client domain.OAuth2Client
+	var lookupErr error
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		authorization, found, err := findPendingDeviceAuthorization(ctx, provider, domain.UserID(userID), userCode)
+		if err != nil {
+			return err
+		}
+		if !found {
+			lookupErr = domain.ErrOAuth2DeviceAuthorizationNotFound
+			return nil
+		}
+		client, err = provider.OAuth2ClientRepository().Find(ctx, authorization.ClientID)
+		if err != nil {
+			return err
+		}
+
+		repo := provider.OAuth2DeviceAuthorizationRepository()
+
+		approverID := domain.UserID(userID)
+		authorization.UserID = &approverID
+		authorization.Status = domain.OAuth2DeviceAuthorizationDenied
+		if approve {
+			authorization.Status = domain.OAuth2DeviceAuthorizationApproved
+		}
+		err = repo.Store(ctx, authorization)
+		if err != nil || !approve {
+			return err
+		}
+		return dispatcher.Dispatch(domain.OAuth2AuthorizationGranted{UserID: approverID, ClientID: client.ID, Scopes: authorization.Scopes})
+	})
+	if err == nil {
+		err = lookupErr
+	}
+	if err != nil {
+		return OAuth2ClientView{}, err
+	}
+	return makeOAuth2ClientView(client), nil
+}
+
+// findPendingDeviceAuthorization counts wrong codes of user and locks user out after too many of them,
+// found is false when code is wrong, caller must commit recorded failure
+func findPendingDeviceAuthorization(
+	ctx context.Context,
+	provider RepositoryProvider,
+	userID domain.UserID,
+	userCode string,
+) (authorization domain.OAuth2DeviceAuthorization, found bool, err error) {
+	_, err = findActiveUser(ctx, provider, userID)
+	if err != nil {
+		return domain.OAuth2DeviceAuthorization{}, false, err
+	}
+	attemptsRepo := provider.OAuth2UserCodeAttemptsRepository()
+	attempts, err := attemptsRepo.Find(ctx, userID)
+	if err != nil {
+		return domain.OAuth2DeviceAuthorization{}, false, err
+	}
+	now := time.Now()
+	if attempts.IsLocked(now) {
+		return domain.OAuth2DeviceAuthorization{}, false, domain.ErrOAuth2UserCodeLocked
+	}
+
+	authorization, err = provider.OAuth2DeviceAuthorizationRepository().FindByUserCode(ctx, normalizeOAuth2UserCode(userCode))
+	if err != nil && errors.Cause(err) != domain.ErrOAuth2DeviceAuthorizationNotFound {
+		return domain.OAuth2DeviceAuthorization{}, false, err
+	}
+	if err == nil && authorization.Status == domain.OAuth2DeviceAuthorizationPending && !now.After(authorization.ExpiresAt) {
+		return authorization, true, nil
+	}
+
+	attempts.RecordFailure(now)
+	return domain.OAuth2DeviceAuthorization{}, false, attemptsRepo.Store(ctx, attempts)
+}
+
+// newUniqueOAuth2UserCode generates user code not used by unexpired authorization,
+// otherwise store would merge new authorization into authorization of other device by unique user code
+func newUniqueOAuth2UserCode(ctx context.Context, provider RepositoryProvider) (string, error) {
+	repo := provider.OAuth2DeviceAuthorizationRepository()
+	for i := 0; i < oauth2UserCodeAttempts; i++ {
+		userCode, err := newOAuth2UserCode()
+		if err != nil {
+			return "", err
+		}
+
+		existing, err := repo.FindByUserCode(ctx, userCode)
+		if errors.Cause(err) == domain.ErrOAuth2DeviceAuthorizationNotFound {
+			return userCode, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if time.Now().After(existing.ExpiresAt) {
+			return userCode, repo.Remove(ctx, existing.DeviceCodeHash)
+		}
+	}
+	return "", errOAuth2UserCodeExhausted
+}
+
+func newOAuth2UserCode() (string, error) {
+	max := big.NewInt(int64(len(oauth2UserCodeAlphabet)))
+	code := make([]byte, oauth2UserCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		code[i] = oauth2UserCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+func normalizeOAuth2UserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(userCode))
+}
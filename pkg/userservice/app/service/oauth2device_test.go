@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+const (
+	testDeviceClientID = "tv"
+	testDeviceScope    = "profile"
+)
+
+var errDuplicateUserCode = errors.New("duplicate user code")
+
+type mockOAuth2ClientRepository struct {
+	domain.OAuth2ClientRepository
+	clients map[string]domain.OAuth2Client
+}
+
+func (repo *mockOAuth2ClientRepository) Find(_ context.Context, clientID string) (domain.OAuth2Client, error) {
+	client, ok := repo.clients[clientID]
+	if !ok {
+		return domain.OAuth2Client{}, domain.ErrOAuth2ClientNotFound
+	}
+	return client, nil
+}
+
+type mockOAuth2TokenRepository struct {
+	domain.OAuth2TokenRepository
+	tokens map[string]domain.OAuth2Token
+}
+
+func (repo *mockOAuth2TokenRepository) Store(_ context.Context, token domain.OAuth2Token) error {
+	repo.tokens[token.TokenHash] = token
+	return nil
+}
+
+type mockOAuth2DeviceAuthorizationRepository struct {
+	domain.OAuth2DeviceAuthorizationRepository
+	authorizations map[string]domain.OAuth2DeviceAuthorization
+	occupied       int
+	occupiedUntil  time.Time
+}
+
+func (repo *mockOAuth2DeviceAuthorizationRepository) FindByDeviceCode(_ context.Context, deviceCodeHash string) (domain.OAuth2DeviceAuthorization, error) {
+	authorization, ok := repo.authorizations[deviceCodeHash]
+	if !ok {
+		return domain.OAuth2DeviceAuthorization{}, domain.ErrOAuth2DeviceAuthorizationNotFound
+	}
+	return authorization, nil
+}
+
+func (repo *mockOAuth2DeviceAuthorizationRepository) FindByUserCode(_ context.Context, userCode string) (domain.OAuth2DeviceAuthorization, error) {
+	if repo.occupied > 0 {
+		repo.occupied--
+		repo.authorizations[uuid.New().String()] = domain.OAuth2DeviceAuthorization{
+			DeviceCodeHash: uuid.New().String(),
+			UserCode:       userCode,
+			ClientID:       testDeviceClientID,
+			ExpiresAt:      repo.occupiedUntil,
+		}
+	}
+	for _, authorization := range repo.authorizations {
+		if authorization.UserCode == userCode {
+			return authorization, nil
+		}
+	}
+	return domain.OAuth2DeviceAuthorization{}, domain.ErrOAuth2DeviceAuthorizationNotFound
+}
+
+func (repo *mockOAuth2DeviceAuthorizationRepository) Store(_ context.Context, authorization domain.OAuth2DeviceAuthorization) error {
+	for key, existing := range repo.authorizations {
+		if existing.UserCode == authorization.UserCode && key != authorization.DeviceCodeHash {
+			return errDuplicateUserCode
+		}
+	}
+	repo.authorizations[authorization.DeviceCodeHash] = authorization
+	return nil
+}
+
+func (repo *mockOAuth2DeviceAuthorizationRepository) Remove(_ context.Context, deviceCodeHash string) error {
+	for key, authorization := range repo.authorizations {
+		if authorization.DeviceCodeHash == deviceCodeHash {
+			delete(repo.authorizations, key)
+		}
+	}
+	return nil
+}
+
+func (repo *mockOAuth2DeviceAuthorizationRepository) only(t *testing.T) domain.OAuth2DeviceAuthorization {
+	t.Helper()
+	if len(repo.authorizations) != 1 {
+		t.Fatalf("expected single device authorization, got %d", len(repo.authorizations))
+	}
+	for _, authorization := range repo.authorizations {
+		return authorization
+	}
+	return domain.OAuth2DeviceAuthorization{}
+}
+
+type mockOAuth2UserCodeAttemptsRepository struct {
+	domain.OAuth2UserCodeAttemptsRepository
+	attempts map[domain.UserID]domain.OAuth2UserCodeAttempts
+}
+
+func (repo *mockOAuth2UserCodeAttemptsRepository) Find(_ context.Context, userID domain.UserID) (domain.OAuth2UserCodeAttempts, error) {
+	attempts, ok := repo.attempts[userID]
+	if !ok {
+		return domain.OAuth2UserCodeAttempts{UserID: userID}, nil
+	}
+	return attempts, nil
+}
+
+func (repo *mockOAuth2UserCodeAttemptsRepository) Store(_ context.Context, attempts domain.OAuth2UserCodeAttempts) error {
+	repo.attempts[attempts.UserID] = attempts
+	return nil
+}
+
+func newTestOAuth2Service() (OAuth2Service, *mockRepositoryProvider, uuid.UUID) {
+	provider := newMockRepositoryProvider()
+	provider.oauth2Clients.clients[testDeviceClientID] = domain.OAuth2Client{
+		ID:         testDeviceClientID,
+		Scopes:     []string{testDeviceScope},
+		GrantTypes: []domain.OAuth2GrantType{domain.OAuth2DeviceCodeGrant},
+		FirstParty: true,
+	}
+	userID := uuid.New()
+	provider.users.users[domain.UserID(userID)] = domain.User{ID: domain.UserID(userID), Email: "user@example.com"}
+
+	service := NewOAuth2Service(
+		&mockUnitOfWorkFactory{provider: provider},
+		NewCompositeEventHandler(),
+		nil,
+		nil,
+		nil,
+		OAuth2Policy{AccessTokenTTL: time.Hour, DeviceVerificationURI: "https://userservice.test/device"},
+	)
+	return service, provider, userID
+}
+
+func startTestDeviceAuthorization(t *testing.T, service OAuth2Service) OAuth2DeviceAuthorizationView {
+	t.Helper()
+	view, err := service.StartDeviceAuthorization(context.Background(), OAuth2ClientCredentials{ClientID: testDeviceClientID}, []string{testDeviceScope})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return view
+}
+
+func TestStartDeviceAuthorizationUserCodeIsUnique(t *testing.T) {
+	tests := []struct {
+		name                   string
+		occupied               int
+		occupiedFor            time.Duration
+		expectedErr            error
+		expectedAuthorizations int
+	}{
+		{
+			name:                   "free code is used",
+			expectedAuthorizations: 1,
+		},
+		{
+			name:                   "code of pending authorization is generated again",
+			occupied:               2,
+			occupiedFor:            time.Minute,
+			expectedAuthorizations: 3,
+		},
+		{
+			name:                   "code of expired authorization is taken over",
+			occupied:               1,
+			occupiedFor:            -time.Minute,
+			expectedAuthorizations: 1,
+		},
+		{
+			name:                   "generation gives up after bounded attempts",
+			occupied:               oauth2UserCodeAttempts,
+			occupiedFor:            time.Minute,
+			expectedErr:            errOAuth2UserCodeExhausted,
+			expectedAuthorizations: oauth2UserCodeAttempts,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, provider, _ := newTestOAuth2Service()
+			provider.deviceAuthorizations.occupied = test.occupied
+			provider.deviceAuthorizations.occupiedUntil = time.Now().Add(test.occupiedFor)
+
+			view, err := service.StartDeviceAuthorization(context.Background(), OAuth2ClientCredentials{ClientID: testDeviceClientID}, []string{testDeviceScope})
+			if errors.Cause(err) != test.expectedErr {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if len(provider.deviceAuthorizations.authorizations) != test.expectedAuthorizations {
+				t.Errorf("expected %d device authorizations, got %d", test.expectedAuthorizations, len(provider.deviceAuthorizations.authorizations))
+			}
+			if err != nil {
+				return
+			}
+
+			stored, err := provider.deviceAuthorizations.FindByUserCode(context.Background(), normalizeOAuth2UserCode(view.UserCode))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status != domain.OAuth2DeviceAuthorizationPending || !stored.ExpiresAt.After(time.Now()) {
+				t.Errorf("user code does not belong to new authorization: %+v", stored)
+			}
+		})
+	}
+}
+
+func TestDeviceUserCodeLockout(t *testing.T) {
+	ctx := context.Background()
+	service, provider, userID := newTestOAuth2Service()
+	view := startTestDeviceAuthorization(t, service)
+
+	for i := 0; i < domain.MaxFailedOAuth2UserCodeAttempts; i++ {
+		_, err := service.LookupDevice(ctx, userID, "WRONG-CODE")
+		if errors.Cause(err) != domain.ErrOAuth2DeviceAuthorizationNotFound {
+			t.Fatalf("attempt %d: expected %v, got %v", i+1, domain.ErrOAuth2DeviceAuthorizationNotFound, err)
+		}
+	}
+
+	_, err := service.LookupDevice(ctx, userID, view.UserCode)
+	if errors.Cause(err) != domain.ErrOAuth2UserCodeLocked {
+		t.Fatalf("expected locked user to get %v for correct code, got %v", domain.ErrOAuth2UserCodeLocked, err)
+	}
+	_, err = service.ApproveDevice(ctx, userID, view.UserCode, true)
+	if errors.Cause(err) != domain.ErrOAuth2UserCodeLocked {
+		t.Fatalf("expected locked user to get %v on approval, got %v", domain.ErrOAuth2UserCodeLocked, err)
+	}
+
+	otherUserID := uuid.New()
+	provider.users.users[domain.UserID(otherUserID)] = domain.User{ID: domain.UserID(otherUserID)}
+	approval, err := service.LookupDevice(ctx, otherUserID, view.UserCode)
+	if err != nil {
+		t.Fatalf("expected lock of one user to keep code usable for others, got %v", err)
+	}
+	if approval.Client.ID != testDeviceClientID {
+		t.Errorf("expected client %q, got %q", testDeviceClientID, approval.Client.ID)
+	}
+}
+
+func TestPollDeviceTokenSlowDown(t *testing.T) {
+	ctx := context.Background()
+	service, provider, _ := newTestOAuth2Service()
+	view := startTestDeviceAuthorization(t, service)
+	credentials := OAuth2ClientCredentials{ClientID: testDeviceClientID}
+
+	steps := []struct {
+		name             string
+		polledAgo        time.Duration
+		expectedErr      error
+		expectedInterval time.Duration
+	}{
+		{name: "first poll is pending", expectedErr: ErrOAuth2AuthorizationPending, expectedInterval: oauth2DevicePollInterval},
+		{name: "immediate poll slows device down", expectedErr: ErrOAuth2SlowDown, expectedInterval: 2 * oauth2DevicePollInterval},
+		{name: "poll within grown interval slows down again", polledAgo: oauth2DevicePollInterval + time.Second, expectedErr: ErrOAuth2SlowDown, expectedInterval: 3 * oauth2DevicePollInterval},
+		{name: "poll after interval is pending", polledAgo: 3*oauth2DevicePollInterval + time.Second, expectedErr: ErrOAuth2AuthorizationPending, expectedInterval: 3 * oauth2DevicePollInterval},
+	}
+
+	for _, step := range steps {
+		if step.polledAgo != 0 {
+			authorization := provider.deviceAuthorizations.only(t)
+			polledAt := time.Now().Add(-step.polledAgo)
+			authorization.LastPolledAt = &polledAt
+			provider.deviceAuthorizations.authorizations[authorization.DeviceCodeHash] = authorization
+		}
+
+		_, err := service.PollDeviceToken(ctx, credentials, view.DeviceCode)
+		if errors.Cause(err) != step.expectedErr {
+			t.Fatalf("%s: expected %v, got %v", step.name, step.expectedErr, err)
+		}
+		if interval := provider.deviceAuthorizations.only(t).PollInterval; interval != step.expectedInterval {
+			t.Fatalf("%s: expected interval %v, got %v", step.name, step.expectedInterval, interval)
+		}
+	}
+}
+
+func TestDeviceApprovalIsRedeemedOnce(t *testing.T) {
+	tests := []struct {
+		name   string
+		redeem func(ctx context.Context, service OAuth2Service, deviceCode string) error
+	}{
+		{
+			name: "token poll",
+			redeem: func(ctx context.Context, service OAuth2Service, deviceCode string) error {
+				_, err := service.PollDeviceToken(ctx, OAuth2ClientCredentials{ClientID: testDeviceClientID}, deviceCode)
+				return err
+			},
+		},
+		{
+			name: "device sign in",
+			redeem: func(ctx context.Context, service OAuth2Service, deviceCode string) error {
+				_, err := service.AuthenticateDevice(ctx, OAuth2ClientCredentials{ClientID: testDeviceClientID}, deviceCode)
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			service, provider, userID := newTestOAuth2Service()
+			view := startTestDeviceAuthorization(t, service)
+
+			_, err := service.ApproveDevice(ctx, userID, view.UserCode, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = service.ApproveDevice(ctx, userID, view.UserCode, true)
+			if errors.Cause(err) != domain.ErrOAuth2DeviceAuthorizationNotFound {
+				t.Fatalf("expected decided code to be unusable, got %v", err)
+			}
+
+			err = test.redeem(ctx, service, view.DeviceCode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = test.redeem(ctx, service, view.DeviceCode)
+			if errors.Cause(err) != ErrInvalidOAuth2Grant {
+				t.Fatalf("expected second redemption to fail with %v, got %v", ErrInvalidOAuth2Grant, err)
+			}
+			if len(provider.deviceAuthorizations.authorizations) != 0 {
+				t.Errorf("redeemed device authorization was kept")
+			}
+		})
+	}
+}
+
+func TestDeniedDeviceIsNotRedeemed(t *testing.T) {
+	ctx := context.Background()
+	service, provider, userID := newTestOAuth2Service()
+	view := startTestDeviceAuthorization(t, service)
+
+	_, err := service.ApproveDevice(ctx, userID, view.UserCode, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = service.PollDeviceToken(ctx, OAuth2ClientCredentials{ClientID: testDeviceClientID}, view.DeviceCode)
+	if errors.Cause(err) != ErrOAuth2AccessDenied {
+		t.Fatalf("expected %v, got %v", ErrOAuth2AccessDenied, err)
+	}
+	if len(provider.oauth2Tokens.tokens) != 0 {
+		t.Errorf("tokens were issued for denied device")
+	}
+}
@@ -0,0 +1,760 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+)
+
+var (
+	ErrInvalidOAuth2ClientRegistration = errors.New("invalid oauth2 client registration")
+	ErrInvalidOAuth2Client             = errors.New("oauth2 client authentication failed")
+	ErrInvalidOAuth2Grant              = errors.New("oauth2 grant is invalid, expired or issued to another client")
+	ErrOAuth2PKCERequired              = errors.New("authorization code flow requires S256 code challenge")
+	ErrInvalidOAuth2AccessToken        = errors.New("oauth2 access token is invalid or expired")
+	ErrInvalidOAuth2ConsentChallenge   = errors.New("oauth2 consent challenge is invalid, expired or issued for other request")
+)
+
+const (
+	oauth2AuthorizationCodeTTL = 5 * time.Minute
+	oauth2PKCEMethod           = "S256"
+	oauth2ConsentChallengeTTL  = 10 * time.Minute
+	oauth2ConsentPurpose       = "oauth2_consent"
+)
+
+type OAuth2GrantType string
+
+const (
+	OAuth2AuthorizationCodeGrant = OAuth2GrantType(domain.OAuth2AuthorizationCodeGrant)
+	OAuth2ClientCredentialsGrant = OAuth2GrantType(domain.OAuth2ClientCredentialsGrant)
+	OAuth2RefreshTokenGrant      = OAuth2GrantType(domain.OAuth2RefreshTokenGrant)
+	OAuth2DeviceCodeGrant        = OAuth2GrantType(domain.OAuth2DeviceCodeGrant)
+)
+
+type OAuth2Policy struct {
+	Issuer                string
+	AccessTokenTTL        time.Duration
+	RefreshTokenTTL       time.Duration
+	DeviceVerificationURI string
+}
+
+type IDTokenSigner interface {
+	Sign(claims map[string]interface{}) (string, error)
+	Verify(token string) (map[string]interface{}, error)
+	KeySet() ([]byte, error)
+}
+
+type OAuth2ClientRegistration struct {
+	Name         string
+	RedirectURIs []string
+	Scopes       []string
+	GrantTypes   []OAuth2GrantType
+	Public       bool
+	FirstParty   bool
+}
+
+type OAuth2ClientView struct {
+	ID           string
+	Name         string
+	RedirectURIs []string
+	Scopes       []string
+	GrantTypes   []OAuth2GrantType
+	Public       bool
+	FirstParty   bool
+	CreatedAt    time.Time
+}
+
+type OAuth2TokenView struct {
+	ClientID   string
+	ClientName string
+	Refresh    bool
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+type OAuth2AuthorizationRequest struct {
+	ClientID            string
+	RedirectURI         string
+	Scopes              []string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+type OAuth2ClientCredentials struct {
+	ClientID     string
+	ClientSecret string
+}
+
+type OAuth2Tokens struct {
+	AccessToken  string
+	ExpiresIn    time.Duration
+	RefreshToken string
+	IDToken      string
+	Scopes       []string
+}
+
+type OAuth2AccessInfo struct {
+	ClientID string
+	UserID   *uuid.UUID
+	Scopes   []string
+}
+
+type OAuth2Service interface {
+	ExpiredDataPurger
+
+	RegisterClient(ctx context.Context, registration OAuth2ClientRegistration) (OAuth2ClientView, string, error)
+	ListClients(ctx context.Context) ([]OAuth2ClientView, error)
+	RemoveClient(ctx context.Context, clientID string) error
+
+	ValidateAuthorizationRequest(ctx context.Context, request OAuth2AuthorizationRequest) (OAuth2ClientView, error)
+	IssueConsentChallenge(userID uuid.UUID, session string, request OAuth2AuthorizationRequest) (string, error)
+	VerifyConsentChallenge(challenge string, userID uuid.UUID, session string, request OAuth2AuthorizationRequest) error
+	Authorize(ctx context.Context, userID uuid.UUID, request OAuth2AuthorizationRequest) (string, error)
+
+	ExchangeAuthorizationCode(ctx context.Context, credentials OAuth2ClientCredentials, code, redirectURI, codeVerifier string) (OAuth2Tokens, error)
+	ExchangeClientCredentials(ctx context.Context, credentials OAuth2ClientCredentials, scopes []string) (OAuth2Tokens, error)
+	ExchangeRefreshToken(ctx context.Context, credentials OAuth2ClientCredentials, refreshToken string, scopes []string) (OAuth2Tokens, error)
+	ResolveAccessToken(ctx context.Context, accessToken string) (OAuth2AccessInfo, error)
+	ListUserTokens(ctx context.Context, userID uuid.UUID) ([]OAuth2TokenView, error)
+
+	StartDeviceAuthorization(ctx context.Context, credentials OAuth2ClientCredentials, scopes []string) (OAuth2DeviceAuthorizationView, error)
+	PollDeviceToken(ctx context.Context, credentials OAuth2ClientCredentials, deviceCode string) (OAuth2Tokens, error)
+	// AuthenticateDevice polls as PollDeviceToken, but returns user who approved device instead of oauth2 tokens,
+	// it is allowed only for first party clients, so third party devices never get session of user
+	AuthenticateDevice(ctx context.Context, credentials OAuth2ClientCredentials, deviceCode string) (uuid.UUID, error)
+	LookupDevice(ctx context.Context, userID uuid.UUID, userCode string) (OAuth2DeviceApprovalView, error)
+	ApproveDevice(ctx context.Context, userID uuid.UUID, userCode string, approve bool) (OAuth2ClientView, error)
+
+	Issuer() string
+	KeySet() ([]byte, error)
+}
+
+func NewOAuth2Service(
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	queryService query.OAuth2QueryService,
+	subscriptionQueryService query.SubscriptionQueryService,
+	signer IDTokenSigner,
+	policy OAuth2Policy,
+) OAuth2Service {
+	return &oauth2Service{
+		unitOfWorkFactory:        unitOfWorkFactory,
+		eventHandler:             eventHandler,
+		queryService:             queryService,
+		subscriptionQueryService: subscriptionQueryService,
+		signer:                   signer,
+		policy:                   policy,
+	}
+}
+
+type oauth2Service struct {
+	unitOfWorkFactory        UnitOfWorkFactory
+	eventHandler             EventHandler
+	queryService             query.OAuth2QueryService
+	subscriptionQueryService query.SubscriptionQueryService
+	signer                   IDTokenSigner
+	policy                   OAuth2Policy
+}
+
+func (service *oauth2Service) RegisterClient(ctx context.Context, registration OAuth2ClientRegistration) (OAuth2ClientView, string, error) {
+	err := validateOAuth2ClientRegistration(registration)
+	if err != nil {
+		return OAuth2ClientView{}, "", err
+	}
+
+	client := domain.OAuth2Client{
+		ID:           uuid.New().String(),
+		Name:         registration.Name,
+		RedirectURIs: registration.RedirectURIs,
+		Scopes:       registration.Scopes,
+		FirstParty:   registration.FirstParty,
+		CreatedAt:    time.Now(),
+	}
+	for _, grantType := range registration.GrantTypes {
+		client.GrantTypes = append(client.GrantTypes, domain.OAuth2GrantType(grantType))
+	}
+	var secret string
+	if !registration.Public {
+		secret, err = hash.RandomToken()
+		if err != nil {
+			return OAuth2ClientView{}, "", err
+		}
+		client.SecretHash = hash.HashToken(secret)
+	}
+
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		return provider.OAuth2ClientRepository().Store(ctx, client)
+	})
+	if err != nil {
+		return OAuth2ClientView{}, "", err
+	}
+	return makeOAuth2ClientView(client), secret, nil
+}
+
+func (service *oauth2Service) ListClients(ctx context.Context) ([]OAuth2ClientView, error) {
+	var clients []domain.OAuth2Client
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		clients, err = provider.OAuth2ClientRepository().FindAll(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]OAuth2ClientView, 0, len(clients))
+	for _, client := range clients {
+		result = append(result, makeOAuth2ClientView(client))
+	}
+	return result, nil
+}
+
+func (service *oauth2Service) ListUserTokens(ctx context.Context, userID uuid.UUID) ([]OAuth2TokenView, error) {
+	var tokens []domain.OAuth2Token
+	var clients []domain.OAuth2Client
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err error
+		tokens, err = provider.OAuth2TokenRepository().FindByUser(ctx, domain.UserID(userID))
+		if err != nil {
+			return err
+		}
+		clients, err = provider.OAuth2ClientRepository().FindAll(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	clientNames := make(map[string]string, len(clients))
+	for _, client := range clients {
+		clientNames[client.ID] = client.Name
+	}
+	now := time.Now()
+	result := make([]OAuth2TokenView, 0, len(tokens))
+	for _, token := range tokens {
+		if now.After(token.ExpiresAt) {
+			continue
+		}
+		result = append(result, OAuth2TokenView{
+			ClientID:   token.ClientID,
+			ClientName: clientNames[token.ClientID],
+			Refresh:    token.Kind == domain.OAuth2RefreshToken,
+			Scopes:     token.Scopes,
+			CreatedAt:  token.CreatedAt,
+			ExpiresAt:  token.ExpiresAt,
+		})
+	}
+	return result, nil
+}
+
+func (service *oauth2Service) RemoveClient(ctx context.Context, clientID string) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		_, err := provider.OAuth2ClientRepository().Find(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		err = provider.OAuth2TokenRepository().RemoveByClient(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		err = provider.OAuth2AuthorizationCodeRepository().RemoveByClient(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		err = provider.OAuth2DeviceAuthorizationRepository().RemoveByClient(ctx, clientID)
+		if err != nil {
+			return err
+		}
+		return provider.OAuth2ClientRepository().Remove(ctx, clientID)
+	})
+}
+
+func (service *oauth2Service) ValidateAuthorizationRequest(ctx context.Context, request OAuth2AuthorizationRequest) (OAuth2ClientView, error) {
+	view, err := service.queryService.GetClient(ctx, request.ClientID)
+	if err != nil {
+		return OAuth2ClientView{}, err
+	}
+
+	client := domain.OAuth2Client{
+		ID:           view.ID,
+		Name:         view.Name,
+		RedirectURIs: view.RedirectURIs,
+		Scopes:       view.Scopes,
+		FirstParty:   view.FirstParty,
+		CreatedAt:    view.CreatedAt,
+	}
+	for _, grantType := range view.GrantTypes {
+		client.GrantTypes = append(client.GrantTypes, domain.OAuth2GrantType(grantType))
+	}
+	err = validateOAuth2AuthorizationRequest(client, request)
+	if err != nil {
+		return OAuth2ClientView{}, err
+	}
+
+	result := makeOAuth2ClientView(client)
+	result.Public = view.Public
+	return result, nil
+}
+
+func (service *oauth2Service) Authorize(ctx context.Context, userID uuid.UUID, request OAuth2AuthorizationRequest) (string, error) {
+	code, err := hash.RandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		client, err2 := provider.OAuth2ClientRepository().Find(ctx, request.ClientID)
+		if err2 != nil {
+			return err2
+		}
+		err2 = validateOAuth2AuthorizationRequest(client, request)
+		if err2 != nil {
+			return err2
+		}
+		_, err2 = findActiveUser(ctx, provider, domain.UserID(userID))
+		if err2 != nil {
+			return err2
+		}
+
+		err2 = provider.OAuth2AuthorizationCodeRepository().Store(ctx, domain.OAuth2AuthorizationCode{
+			CodeHash:      hash.HashToken(code),
+			ClientID:      request.ClientID,
+			UserID:        domain.UserID(userID),
+			RedirectURI:   request.RedirectURI,
+			Scopes:        request.Scopes,
+			CodeChallenge: request.CodeChallenge,
+			Nonce:         request.Nonce,
+			ExpiresAt:     time.Now().Add(oauth2AuthorizationCodeTTL),
+		})
+		if err2 != nil {
+			return err2
+		}
+		return dispatcher.Dispatch(domain.OAuth2AuthorizationGranted{UserID: domain.UserID(userID), ClientID: request.ClientID, Scopes: request.Scopes})
+	})
+	return code, err
+}
+
+func (service *oauth2Service) IssueConsentChallenge(userID uuid.UUID, session string, request OAuth2AuthorizationRequest) (string, error) {
+	return service.signer.Sign(map[string]interface{}{
+		"iss":     service.policy.Issuer,
+		"aud":     service.policy.Issuer,
+		"purpose": oauth2ConsentPurpose,
+		"sub":     userID.String(),
+		"sid":     hash.HashToken(session),
+		"req":     oauth2RequestDigest(request),
+		"exp":     time.Now().Add(oauth2ConsentChallengeTTL).Unix(),
+	})
+}
+
+func (service *oauth2Service) VerifyConsentChallenge(challenge string, userID uuid.UUID, session string, request OAuth2AuthorizationRequest) error {
+	claims, err := service.signer.Verify(challenge)
+	if err != nil {
+		return ErrInvalidOAuth2ConsentChallenge
+	}
+	exp, _ := claims["exp"].(float64)
+	if claims["purpose"] != oauth2ConsentPurpose ||
+		claims["aud"] != service.policy.Issuer ||
+		claims["sub"] != userID.String() ||
+		claims["sid"] != hash.HashToken(session) ||
+		claims["req"] != oauth2RequestDigest(request) ||
+		time.Now().Unix() > int64(exp) {
+		return ErrInvalidOAuth2ConsentChallenge
+	}
+	return nil
+}
+
+func (service *oauth2Service) ExchangeAuthorizationCode(
+	ctx context.Context,
+	credentials OAuth2ClientCredentials,
+	code, redirectURI, codeVerifier string,
+) (OAuth2Tokens, error) {
+	var tokens OAuth2Tokens
+	var replayed bool
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		client, err := authenticateOAuth2Client(ctx, provider, credentials, domain.OAuth2AuthorizationCodeGrant)
+		if err != nil {
+			return err
+		}
+		repo := provider.OAuth2AuthorizationCodeRepository()
+		authorizationCode, err := repo.Find(ctx, hash.HashToken(code))
+		if errors.Cause(err) == domain.ErrOAuth2AuthorizationCodeNotFound {
+			return ErrInvalidOAuth2Grant
+		}
+		if err != nil {
+			return err
+		}
+
+		// request is verified before code is used, so request of other client or with wrong verifier does not burn code
+		if authorizationCode.ClientID != client.ID ||
+			authorizationCode.RedirectURI != redirectURI ||
+			time.Now().After(authorizationCode.ExpiresAt) ||
+			subtle.ConstantTimeCompare([]byte(hash.PKCEChallenge(codeVerifier)), []byte(authorizationCode.CodeChallenge)) != 1 {
+			return ErrInvalidOAuth2Grant
+		}
+		if authorizationCode.UsedAt != nil {
+			// code was intercepted or replayed, tokens issued for it are revoked, see RFC 6749 section 4.1.2
+			replayed = true
+			return provider.OAuth2TokenRepository().RemoveByAuthorizationCode(ctx, authorizationCode.CodeHash)
+		}
+
+		now := time.Now()
+		authorizationCode.UsedAt = &now
+		err = repo.Store(ctx, authorizationCode)
+		if err != nil {
+			return err
+		}
+		tokens, err = service.issueTokens(ctx, provider, client, &authorizationCode.UserID, authorizationCode.Scopes, authorizationCode.Nonce, authorizationCode.CodeHash)
+		return err
+	})
+	if err != nil {
+		return OAuth2Tokens{}, err
+	}
+	if replayed {
+		return OAuth2Tokens{}, ErrInvalidOAuth2Grant
+	}
+	return tokens, nil
+}
+
+func (service *oauth2Service) ExchangeClientCredentials(ctx context.Context, credentials OAuth2ClientCredentials, scopes []string) (OAuth2Tokens, error) {
+	var tokens OAuth2Tokens
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		client, err := authenticateOAuth2Client(ctx, provider, credentials, domain.OAuth2ClientCredentialsGrant)
+		if err != nil {
+			return err
+		}
+		if !client.AllowsScopes(scopes) {
+			return domain.ErrInvalidOAuth2Scope
+		}
+		tokens, err = service.issueTokens(ctx, provider, client, nil, scopes, "", "")
+		return err
+	})
+	return tokens, err
+}
+
+func (service *oauth2Service) ExchangeRefreshToken(
+	ctx context.Context,
+	credentials OAuth2ClientCredentials,
+	refreshToken string,
+	scopes []string,
+) (OAuth2Tokens, error) {
+	var tokens OAuth2Tokens
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		client, err := authenticateOAuth2Client(ctx, provider, credentials, domain.OAuth2RefreshTokenGrant)
+		if err != nil {
+			return err
+		}
+		token, err := provider.OAuth2TokenRepository().Find(ctx, hash.HashToken(refreshToken))
+		if errors.Cause(err) == domain.ErrOAuth2TokenNotFound {
+			return ErrInvalidOAuth2Grant
+		}
+		if err != nil {
+			return err
+		}
+
+		// request is verified before token is used, so token of other client or request for wider scopes does not burn it
+		if token.Kind != domain.OAuth2RefreshToken || token.ClientID != client.ID || token.UserID == nil || time.Now().After(token.ExpiresAt) {
+			return ErrInvalidOAuth2Grant
+		}
+		// client may narrow scopes of refreshed tokens, but never widen them
+		if len(scopes) == 0 {
+			scopes = token.Scopes
+		}
+		for _, scope := range scopes {
+			if !domain.ContainsOAuth2Scope(token.Scopes, scope) {
+				return domain.ErrInvalidOAuth2Scope
+			}
+		}
+
+		// used token is removed together with issue of new ones, failed issue keeps it valid
+		err = provider.OAuth2TokenRepository().Remove(ctx, token.TokenHash)
+		if err != nil {
+			return err
+		}
+		tokens, err = service.issueTokens(ctx, provider, client, token.UserID, scopes, "", token.AuthorizationCodeHash)
+		return err
+	})
+	return tokens, err
+}
+
+func (service *oauth2Service) ResolveAccessToken(ctx context.Context, accessToken string) (OAuth2AccessInfo, error) {
+	token, err := service.queryService.GetToken(ctx, hash.HashToken(accessToken))
+	if errors.Cause(err) == domain.ErrOAuth2TokenNotFound {
+		return OAuth2AccessInfo{}, ErrInvalidOAuth2AccessToken
+	}
+	if err != nil {
+		return OAuth2AccessInfo{}, err
+	}
+	if token.Kind != query.OAuth2AccessToken || time.Now().After(token.ExpiresAt) {
+		return OAuth2AccessInfo{}, ErrInvalidOAuth2AccessToken
+	}
+	return OAuth2AccessInfo{ClientID: token.ClientID, UserID: token.UserID, Scopes: token.Scopes}, nil
+}
+
+func (service *oauth2Service) PurgeExpired(ctx context.Context, before time.Time) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		err := provider.OAuth2TokenRepository().RemoveExpired(ctx, before)
+		if err != nil {
+			return err
+		}
+		err = provider.OAuth2AuthorizationCodeRepository().RemoveExpired(ctx, before)
+		if err != nil {
+			return err
+		}
+		return provider.OAuth2DeviceAuthorizationRepository().RemoveExpired(ctx, before)
+	})
+}
+
+func (service *oauth2Service) Issuer() string {
+	return service.policy.Issuer
+}
+
+func (service *oauth2Service) KeySet() ([]byte, error) {
+	return service.signer.KeySet()
+}
+
+// issueTokens issues access token, refresh token to users of clients allowed to refresh and id token for openid scope,
+// codeHash links tokens to authorization code, so they are revoked when code is replayed
+func (service *oauth2Service) issueTokens(
+	ctx context.Context,
+	provider RepositoryProvider,
+	client domain.OAuth2Client,
+	userID *domain.UserID,
+	scopes []string,
+	nonce string,
+	codeHash string,
+) (OAuth2Tokens, error) {
+	var user domain.User
+	if userID != nil {
+		var err error
+		user, err = findActiveUser(ctx, provider, *userID)
+		if errors.Cause(err) == domain.ErrUserNotFound {
+			return OAuth2Tokens{}, ErrInvalidOAuth2Grant
+		}
+		if err != nil {
+			return OAuth2Tokens{}, err
+		}
+	}
+
+	now := time.Now()
+	tokens := OAuth2Tokens{ExpiresIn: service.policy.AccessTokenTTL, Scopes: scopes}
+	var err error
+	tokens.AccessToken, err = service.storeToken(ctx, provider, domain.OAuth2Token{
+		Kind:                  domain.OAuth2AccessToken,
+		ClientID:              client.ID,
+		UserID:                userID,
+		Scopes:                scopes,
+		AuthorizationCodeHash: codeHash,
+		ExpiresAt:             now.Add(service.policy.AccessTokenTTL),
+		CreatedAt:             now,
+	})
+	if err != nil {
+		return OAuth2Tokens{}, err
+	}
+
+	if userID == nil {
+		return tokens, nil
+	}
+
+	if client.AllowsGrant(domain.OAuth2RefreshTokenGrant) {
+		tokens.RefreshToken, err = service.storeToken(ctx, provider, domain.OAuth2Token{
+			Kind:                  domain.OAuth2RefreshToken,
+			ClientID:              client.ID,
+			UserID:                userID,
+			Scopes:                scopes,
+			AuthorizationCodeHash: codeHash,
+			ExpiresAt:             now.Add(service.policy.RefreshTokenTTL),
+			CreatedAt:             now,
+		})
+		if err != nil {
+			return OAuth2Tokens{}, err
+		}
+	}
+
+	if domain.ContainsOAuth2Scope(scopes, domain.OAuth2OpenIDScope) {
+		entitlements, err := service.subscriptionQueryService.GetEntitlements(ctx, uuid.UUID(user.ID))
+		if err != nil {
+			return OAuth2Tokens{}, err
+		}
+		claims := map[string]interface{}{
+			"iss": service.policy.Issuer,
+			"sub": uuid.UUID(user.ID).String(),
+			"aud": client.ID,
+			"iat": now.Unix(),
+			"exp": now.Add(service.policy.AccessTokenTTL).Unix(),
+		}
+		if nonce != "" {
+			claims["nonce"] = nonce
+		}
+		claims["plan"] = domain.SubscriptionPlan(entitlements.Plan).Name()
+		claims["entitlements"] = entitlements.Entitlements
+		if domain.ContainsOAuth2Scope(scopes, domain.OAuth2EmailScope) {
+			claims["email"] = user.Email
+		}
+		tokens.IDToken, err = service.signer.Sign(claims)
+		if err != nil {
+			return OAuth2Tokens{}, err
+		}
+	}
+	return tokens, nil
+}
+
+func (service *oauth2Service) storeToken(ctx context.Context, provider RepositoryProvider, token domain.OAuth2Token) (string, error) {
+	value, err := hash.RandomToken()
+	if err != nil {
+		return "", err
+	}
+	token.TokenHash = hash.HashToken(value)
+	return value, provider.OAuth2TokenRepository().Store(ctx, token)
+}
+
+func (service *oauth2Service) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func validateOAuth2ClientRegistration(registration OAuth2ClientRegistration) error {
+	if registration.Name == "" || len(registration.GrantTypes) == 0 {
+		return ErrInvalidOAuth2ClientRegistration
+	}
+	for _, grantType := range registration.GrantTypes {
+		switch grantType {
+		case OAuth2AuthorizationCodeGrant:
+			if len(registration.RedirectURIs) == 0 {
+				return ErrInvalidOAuth2ClientRegistration
+			}
+		case OAuth2ClientCredentialsGrant:
+			// public client can not prove own identity
+			if registration.Public {
+				return ErrInvalidOAuth2ClientRegistration
+			}
+		case OAuth2RefreshTokenGrant, OAuth2DeviceCodeGrant:
+		default:
+			return ErrInvalidOAuth2ClientRegistration
+		}
+	}
+	for _, redirectURI := range registration.RedirectURIs {
+		u, err := url.Parse(redirectURI)
+		if err != nil || !u.IsAbs() || u.Fragment != "" {
+			return ErrInvalidOAuth2ClientRegistration
+		}
+	}
+	return nil
+}
+
+func validateOAuth2AuthorizationRequest(client domain.OAuth2Client, request OAuth2AuthorizationRequest) error {
+	if !client.AllowsRedirectURI(request.RedirectURI) {
+		return domain.ErrInvalidOAuth2RedirectURI
+	}
+	if !client.AllowsGrant(domain.OAuth2AuthorizationCodeGrant) {
+		return domain.ErrOAuth2GrantNotAllowed
+	}
+	if !client.AllowsScopes(request.Scopes) {
+		return domain.ErrInvalidOAuth2Scope
+	}
+	if request.CodeChallenge == "" || request.CodeChallengeMethod != oauth2PKCEMethod {
+		return ErrOAuth2PKCERequired
+	}
+	return nil
+}
+
+func oauth2RequestDigest(request OAuth2AuthorizationRequest) string {
+	return hash.HashToken(strings.Join([]string{
+		request.ClientID,
+		request.RedirectURI,
+		strings.Join(request.Scopes, " "),
+		request.Nonce,
+		request.CodeChallenge,
+		request.CodeChallengeMethod,
+	}, "\n"))
+}
+
+func authenticateOAuth2Client(
+	ctx context.Context,
+	provider RepositoryProvider,
+	credentials OAuth2ClientCredentials,
+	grantType domain.OAuth2GrantType,
+) (domain.OAuth2Client, error) {
+	client, err := provider.OAuth2ClientRepository().Find(ctx, credentials.ClientID)
+	if errors.Cause(err) == domain.ErrOAuth2ClientNotFound {
+		return domain.OAuth2Client{}, ErrInvalidOAuth2Client
+	}
+	if err != nil {
+		return domain.OAuth2Client{}, err
+	}
+
+	if client.IsPublic() {
+		if credentials.ClientSecret != "" {
+			return domain.OAuth2Client{}, ErrInvalidOAuth2Client
+		}
+	} else if subtle.ConstantTimeCompare([]byte(hash.HashToken(credentials.ClientSecret)), []byte(client.SecretHash)) != 1 {
+		return domain.OAuth2Client{}, ErrInvalidOAuth2Client
+	}
+
+	if !client.AllowsGrant(grantType) {
+		return domain.OAuth2Client{}, domain.ErrOAuth2GrantNotAllowed
+	}
+	return client, nil
+}
+
+func findActiveUser(ctx context.Context, provider RepositoryProvider, userID domain.UserID) (domain.User, error) {
+	user, err := provider.UserRepository().Find(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func makeOAuth2ClientView(client domain.OAuth2Client) OAuth2ClientView {
+	view := OAuth2ClientView{
+		ID:           client.ID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectURIs,
+		Scopes:       client.Scopes,
+		Public:       client.IsPublic(),
+		FirstParty:   client.FirstParty,
+		CreatedAt:    client.CreatedAt,
+	}
+	for _, grantType := range client.GrantTypes {
+		view.GrantTypes = append(view.GrantTypes, OAuth2GrantType(grantType))
+	}
+	return view
+}
+
+func NewOAuth2TokenEraser() PersonalDataEraser {
+	return &oauth2TokenEraser{}
+}
+
+type oauth2TokenEraser struct{}
+
+func (eraser *oauth2TokenEraser) Scope() string {
+	return "oauth2_tokens"
+}
+
+func (eraser *oauth2TokenEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	err := provider.OAuth2TokenRepository().RemoveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = provider.OAuth2AuthorizationCodeRepository().RemoveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = provider.OAuth2DeviceAuthorizationRepository().RemoveByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return provider.OAuth2UserCodeAttemptsRepository().RemoveByUser(ctx, userID)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type OutboxPublisher interface {
+	Publish(routingKey string, body []byte) error
+}
+
+type OutboxService interface {
+	// PublishPending publishes oldest messages in order and removes published ones,
+	// message is published again if removal is not committed, so consumers must deduplicate by message id
+	PublishPending(ctx context.Context, publisher OutboxPublisher, limit int) (int, error)
+}
+
+func NewOutboxService(unitOfWorkFactory UnitOfWorkFactory) OutboxService {
+	return &outboxService{unitOfWorkFactory: unitOfWorkFactory}
+}
+
+type outboxService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+}
+
+func (service *outboxService) PublishPending(ctx context.Context, publisher OutboxPublisher, limit int) (int, error) {
+	published := 0
+	var publishErr error
+	err := executeInUnitOfWork(ctx, service.unitOfWorkFactory, nil, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		repo := provider.OutboxRepository()
+		messages, err := repo.FindOldest(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			// failed message holds back later ones, so order of events is kept
+			publishErr = publisher.Publish(message.RoutingKey, message.Body)
+			if publishErr != nil {
+				return nil
+			}
+			err = repo.Remove(ctx, message.Seq)
+			if err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type AgeCategory int
+
+const (
+	AgeUnknown = AgeCategory(domain.AgeUnknown)
+	AgeChild   = AgeCategory(domain.AgeChild)
+	AgeMinor   = AgeCategory(domain.AgeMinor)
+	AgeAdult   = AgeCategory(domain.AgeAdult)
+)
+
+type AgePolicy struct {
+	ChildAge          int
+	AdultAge          int
+	AdultAgeByCountry map[string]int
+}
+
+func (policy AgePolicy) domainPolicy() domain.AgePolicy {
+	return domain.AgePolicy{
+		Default:           domain.AgeRule{ChildAge: policy.ChildAge, AdultAge: policy.AdultAge},
+		AdultAgeByCountry: policy.AdultAgeByCountry,
+	}
+}
+
+type ContentRestrictionsView struct {
+	Category              AgeCategory
+	Restricted            bool
+	ExplicitContentFilter bool
+	GuardianID            *uuid.UUID
+	PINSet                bool
+}
+
+type ParentalControlsUpdate struct {
+	ExplicitContentFilter *bool
+	GuardianID            *uuid.UUID
+	RemoveGuardian        bool
+}
+
+type ParentalControlsService interface {
+	GetContentRestrictions(ctx context.Context, userID uuid.UUID) (ContentRestrictionsView, error)
+	SetPIN(ctx context.Context, userID, actorID uuid.UUID, currentPIN, newPIN string) error
+	UpdateControls(ctx context.Context, userID, actorID uuid.UUID, pin string, update ParentalControlsUpdate) (ContentRestrictionsView, error)
+}
+
+func NewParentalControlsService(
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	pinHasher domain.PINHasher,
+	agePolicy AgePolicy,
+) ParentalControlsService {
+	return &parentalControlsService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		pinHasher:         pinHasher,
+		agePolicy:         agePolicy,
+	}
+}
+
+type parentalControlsService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	pinHasher         domain.PINHasher
+	agePolicy         AgePolicy
+}
+
+func (service *parentalControlsService) GetContentRestrictions(ctx context.Context, userID uuid.UUID) (ContentRestrictionsView, error) {
+	var restrictions domain.ContentRestrictions
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		restrictions, err = service.domainParentalControlsService(provider, dispatcher).GetRestrictions(ctx, domain.UserID(userID))
+		return err
+	})
+	if err != nil {
+		return ContentRestrictionsView{}, err
+	}
+	return makeContentRestrictionsView(restrictions), nil
+}
+
+func (service *parentalControlsService) SetPIN(ctx context.Context, userID, actorID uuid.UUID, currentPIN, newPIN string) error {
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return service.domainParentalControlsService(provider, dispatcher).SetPIN(ctx, domain.UserID(userID), domain.UserID(actorID), currentPIN, newPIN)
+	})
+	return recordParentalPINFailure(ctx, service.unitOfWorkFactory, service.eventHandler, service.pinHasher, service.agePolicy, userID, err)
+}
+
+func (service *parentalControlsService) UpdateControls(
+	ctx context.Context,
+	userID, actorID uuid.UUID,
+	pin string,
+	update ParentalControlsUpdate,
+) (ContentRestrictionsView, error) {
+	domainUpdate := domain.ParentalControlsUpdate{
+		ExplicitContentFilter: update.ExplicitContentFilter,
+		RemoveGuardian:        update.RemoveGuardian,
+	}
+	if update.GuardianID != nil {
+		guardianID := domain.UserID(*update.GuardianID)
+		domainUpdate.GuardianID = &guardianID
+	}
+
+	var restrictions domain.ContentRestrictions
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := service.domainParentalControlsService(provider, dispatcher)
+		_, err := domainService.Update(ctx, domain.UserID(userID), domain.UserID(actorID), pin, domainUpdate)
+		if err != nil {
+			return err
+		}
+		restrictions, err = domainService.GetRestrictions(ctx, domain.UserID(userID))
+		return err
+	})
+	err = recordParentalPINFailure(ctx, service.unitOfWorkFactory, service.eventHandler, service.pinHasher, service.agePolicy, userID, err)
+	if err != nil {
+		return ContentRestrictionsView{}, err
+	}
+	return makeContentRestrictionsView(restrictions), nil
+}
+
+func (service *parentalControlsService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func (service *parentalControlsService) domainParentalControlsService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.ParentalControlsService {
+	return domainParentalControlsService(provider, dispatcher, service.pinHasher, service.agePolicy)
+}
+
+func domainParentalControlsService(
+	provider RepositoryProvider,
+	dispatcher domain.EventDispatcher,
+	pinHasher domain.PINHasher,
+	agePolicy AgePolicy,
+) domain.ParentalControlsService {
+	return domain.NewParentalControlsService(
+		provider.UserRepository(),
+		provider.ProfileRepository(),
+		provider.ParentalControlsRepository(),
+		pinHasher,
+		agePolicy.domainPolicy(),
+		dispatcher,
+	)
+}
+
+// recordParentalPINFailure counts wrong pin in own unit of work, since unit of work that checked pin is rolled back, err is returned as is
+func recordParentalPINFailure(
+	ctx context.Context,
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	pinHasher domain.PINHasher,
+	agePolicy AgePolicy,
+	userID uuid.UUID,
+	err error,
+) error {
+	if errors.Cause(err) != domain.ErrParentalPINMismatch {
+		return err
+	}
+	err2 := executeInUnitOfWork(ctx, unitOfWorkFactory, eventHandler, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		return domainParentalControlsService(provider, dispatcher, pinHasher, agePolicy).RecordPINFailure(ctx, domain.UserID(userID))
+	})
+	if err2 != nil {
+		return err2
+	}
+	return err
+}
+
+func makeContentRestrictionsView(restrictions domain.ContentRestrictions) ContentRestrictionsView {
+	view := ContentRestrictionsView{
+		Category:              AgeCategory(restrictions.Category),
+		Restricted:            restrictions.Restricted,
+		ExplicitContentFilter: restrictions.ExplicitContentFilter,
+		PINSet:                restrictions.PINSet,
+	}
+	if restrictions.GuardianID != nil {
+		guardianID := uuid.UUID(*restrictions.GuardianID)
+		view.GuardianID = &guardianID
+	}
+	return view
+}
+
+func NewParentalControlsEraser() PersonalDataEraser {
+	return &parentalControlsEraser{}
+}
+
+type parentalControlsEraser struct{}
+
+func (eraser *parentalControlsEraser) Scope() string {
+	return "parental_controls"
+}
+
+func (eraser *parentalControlsEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	err := provider.ParentalControlsRepository().Remove(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return provider.ParentalControlsRepository().RemoveGuardian(ctx, userID)
+}
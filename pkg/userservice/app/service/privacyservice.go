@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type ProfileVisibility int
+
+const (
+	ProfileVisibilityPublic    = ProfileVisibility(domain.ProfileVisibilityPublic)
+	ProfileVisibilityFollowers = ProfileVisibility(domain.ProfileVisibilityFollowers)
+	ProfileVisibilityPrivate   = ProfileVisibility(domain.ProfileVisibilityPrivate)
+)
+
+type FollowPolicy int
+
+const (
+	FollowPolicyEveryone = FollowPolicy(domain.FollowPolicyEveryone)
+	FollowPolicyNobody   = FollowPolicy(domain.FollowPolicyNobody)
+)
+
+type PrivacySettingsView struct {
+	ProfileVisibility       ProfileVisibility
+	WhoCanFollow            FollowPolicy
+	ListeningActivityPublic bool
+}
+
+type PrivacySettingsUpdate struct {
+	ProfileVisibility       *ProfileVisibility
+	WhoCanFollow            *FollowPolicy
+	ListeningActivityPublic *bool
+}
+
+type PrivacyService interface {
+	GetSettings(ctx context.Context, userID uuid.UUID) (PrivacySettingsView, error)
+	UpdateSettings(ctx context.Context, userID uuid.UUID, update PrivacySettingsUpdate) (PrivacySettingsView, error)
+}
+
+func NewPrivacyService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler) PrivacyService {
+	return &privacyService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+	}
+}
+
+type privacyService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+}
+
+func (service *privacyService) GetSettings(ctx context.Context, userID uuid.UUID) (PrivacySettingsView, error) {
+	var settings domain.PrivacySettings
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		settings, err = domainPrivacyService(provider, dispatcher).GetSettings(ctx, domain.UserID(userID))
+		return err
+	})
+	if err != nil {
+		return PrivacySettingsView{}, err
+	}
+	return makePrivacySettingsView(settings), nil
+}
+
+func (service *privacyService) UpdateSettings(ctx context.Context, userID uuid.UUID, update PrivacySettingsUpdate) (PrivacySettingsView, error) {
+	domainUpdate := domain.PrivacySettingsUpdate{ListeningActivityPublic: update.ListeningActivityPublic}
+	if update.ProfileVisibility != nil {
+		visibility := domain.ProfileVisibility(*update.ProfileVisibility)
+		domainUpdate.ProfileVisibility = &visibility
+	}
+	if update.WhoCanFollow != nil {
+		policy := domain.FollowPolicy(*update.WhoCanFollow)
+		domainUpdate.WhoCanFollow = &policy
+	}
+
+	var settings domain.PrivacySettings
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		settings, err = domainPrivacyService(provider, dispatcher).Update(ctx, domain.UserID(userID), domainUpdate)
+		return err
+	})
+	if err != nil {
+		return PrivacySettingsView{}, err
+	}
+	return makePrivacySettingsView(settings), nil
+}
+
+func (service *privacyService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func domainPrivacyService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.PrivacyService {
+	return domain.NewPrivacyService(
+		provider.UserRepository(),
+		provider.PrivacySettingsRepository(),
+		provider.FollowRepository(),
+		provider.BlockRepository(),
+		dispatcher,
+	)
+}
+
+func makePrivacySettingsView(settings domain.PrivacySettings) PrivacySettingsView {
+	return PrivacySettingsView{
+		ProfileVisibility:       ProfileVisibility(settings.ProfileVisibility),
+		WhoCanFollow:            FollowPolicy(settings.WhoCanFollow),
+		ListeningActivityPublic: settings.ListeningActivityPublic,
+	}
+}
+
+func NewPrivacySettingsEraser() PersonalDataEraser {
+	return &privacySettingsEraser{}
+}
+
+type privacySettingsEraser struct{}
+
+func (eraser *privacySettingsEraser) Scope() string {
+	return "privacy_settings"
+}
+
+func (eraser *privacySettingsEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.PrivacySettingsRepository().Remove(ctx, userID)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+)
+
+var ErrUnknownProfileField = errors.New("unknown profile field")
+
+const DateLayout = "2006-01-02"
+
+type ProfileField string
+
+const (
+	ProfileDisplayName = ProfileField(domain.ProfileDisplayName)
+	ProfileAvatarURL   = ProfileField(domain.ProfileAvatarURL)
+	ProfileCountry     = ProfileField(domain.ProfileCountry)
+	ProfileLanguage    = ProfileField(domain.ProfileLanguage)
+	ProfileDateOfBirth = ProfileField(domain.ProfileDateOfBirth)
+	ProfileBio         = ProfileField(domain.ProfileBio)
+)
+
+var profileFields = map[ProfileField]bool{
+	ProfileDisplayName: true,
+	ProfileAvatarURL:   true,
+	ProfileCountry:     true,
+	ProfileLanguage:    true,
+	ProfileDateOfBirth: true,
+	ProfileBio:         true,
+}
+
+type ProfileView struct {
+	UserID      uuid.UUID
+	DisplayName string
+	AvatarURL   string
+	Country     string
+	Language    string
+	DateOfBirth *time.Time
+	Bio         string
+	UpdatedAt   time.Time
+}
+
+type ProfileUpdate struct {
+	Fields      []ProfileField
+	DisplayName string
+	AvatarURL   string
+	Country     string
+	Language    string
+	DateOfBirth *time.Time
+	Bio         string
+	ParentalPIN string
+}
+
+type ProfileService interface {
+	GetProfile(ctx context.Context, userID uuid.UUID) (ProfileView, error)
+	GetPublicProfile(ctx context.Context, viewerID, userID uuid.UUID) (ProfileView, error)
+	UpdateProfile(ctx context.Context, userID, actorID uuid.UUID, update ProfileUpdate) (ProfileView, error)
+}
+
+func NewProfileService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler, pinHasher domain.PINHasher, agePolicy AgePolicy) ProfileService {
+	return &profileService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		pinHasher:         pinHasher,
+		agePolicy:         agePolicy,
+	}
+}
+
+type profileService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	pinHasher         domain.PINHasher
+	agePolicy         AgePolicy
+}
+
+func (service *profileService) GetProfile(ctx context.Context, userID uuid.UUID) (ProfileView, error) {
+	var profile domain.Profile
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		profile, err = domain.NewProfileService(provider.UserRepository(), provider.ProfileRepository(), dispatcher).GetProfile(ctx, domain.UserID(userID))
+		return err
+	})
+	if err != nil {
+		return ProfileView{}, err
+	}
+	return makeProfileView(profile), nil
+}
+
+func (service *profileService) GetPublicProfile(ctx context.Context, viewerID, userID uuid.UUID) (ProfileView, error) {
+	var profile domain.Profile
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		err := assertCanViewProfile(ctx, provider, dispatcher, viewerID, userID)
+		if err != nil {
+			return err
+		}
+		profile, err = domain.NewProfileService(provider.UserRepository(), provider.ProfileRepository(), dispatcher).GetProfile(ctx, domain.UserID(userID))
+		return err
+	})
+	if err != nil {
+		return ProfileView{}, err
+	}
+	return ProfileView{
+		UserID:      uuid.UUID(profile.UserID),
+		DisplayName: profile.DisplayName,
+		AvatarURL:   profile.AvatarURL,
+		Bio:         profile.Bio,
+		UpdatedAt:   profile.UpdatedAt,
+	}, nil
+}
+
+func (service *profileService) UpdateProfile(ctx context.Context, userID, actorID uuid.UUID, update ProfileUpdate) (ProfileView, error) {
+	fields := make([]domain.ProfileField, 0, len(update.Fields))
+	changesAge := false
+	for _, field := range update.Fields {
+		if !profileFields[field] {
+			return ProfileView{}, errors.Wrap(ErrUnknownProfileField, string(field))
+		}
+		fields = append(fields, domain.ProfileField(field))
+		changesAge = changesAge || field == ProfileDateOfBirth || field == ProfileCountry
+	}
+
+	var profile domain.Profile
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		err := domainParentalControlsService(provider, dispatcher, service.pinHasher, service.agePolicy).AuthorizeProfileChange(
+			ctx,
+			domain.UserID(userID),
+			domain.UserID(actorID),
+			update.ParentalPIN,
+			changesAge,
+		)
+		if err != nil {
+			return err
+		}
+
+		profile, err = domain.NewProfileService(provider.UserRepository(), provider.ProfileRepository(), dispatcher).UpdateProfile(ctx, domain.UserID(userID), domain.ProfileUpdate{
+			Fields:      fields,
+			DisplayName: update.DisplayName,
+			AvatarURL:   update.AvatarURL,
+			Country:     update.Country,
+			Language:    update.Language,
+			DateOfBirth: update.DateOfBirth,
+			Bio:         update.Bio,
+		})
+		return err
+	})
+	err = recordParentalPINFailure(ctx, service.unitOfWorkFactory, service.eventHandler, service.pinHasher, service.agePolicy, userID, err)
+	if err != nil {
+		return ProfileView{}, err
+	}
+	return makeProfileView(profile), nil
+}
+
+func (service *profileService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func makeProfileView(profile domain.Profile) ProfileView {
+	return ProfileView{
+		UserID:      uuid.UUID(profile.UserID),
+		DisplayName: profile.DisplayName,
+		AvatarURL:   profile.AvatarURL,
+		Country:     profile.Country,
+		Language:    profile.Language,
+		DateOfBirth: profile.DateOfBirth,
+		Bio:         profile.Bio,
+		UpdatedAt:   profile.UpdatedAt,
+	}
+}
+
+func NewProfileEraser() PersonalDataEraser {
+	return &profileEraser{}
+}
+
+type profileEraser struct{}
+
+func (eraser *profileEraser) Scope() string {
+	return "profile"
+}
+
+func (eraser *profileEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.ProfileRepository().Remove(ctx, userID)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+)
+
+var ErrInvalidSession = errors.New("session is invalid or expired")
+
+// ExpiredDataPurger removes data that is of no use after it expired, background purger runs it periodically,
+// so requests never pay for table-wide deletes
+type ExpiredDataPurger interface {
+	PurgeExpired(ctx context.Context, before time.Time) error
+}
+
+type SessionService interface {
+	ExpiredDataPurger
+	StartSession(ctx context.Context, userID uuid.UUID) (string, error)
+	ResolveSession(ctx context.Context, token string) (uuid.UUID, error)
+}
+
+func NewSessionService(
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	queryService query.SessionQueryService,
+	ttl time.Duration,
+) SessionService {
+	return &sessionService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+		queryService:      queryService,
+		ttl:               ttl,
+	}
+}
+
+type sessionService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+	queryService      query.SessionQueryService
+	ttl               time.Duration
+}
+
+func (service *sessionService) StartSession(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := hash.RandomToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		_, err2 := findActiveUser(ctx, provider, domain.UserID(userID))
+		if err2 != nil {
+			return err2
+		}
+		return provider.SessionRepository().Store(ctx, domain.Session{
+			TokenHash: hash.HashToken(token),
+			UserID:    domain.UserID(userID),
+			ExpiresAt: now.Add(service.ttl),
+			CreatedAt: now,
+		})
+	})
+	return token, err
+}
+
+func (service *sessionService) ResolveSession(ctx context.Context, token string) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.UUID{}, ErrInvalidSession
+	}
+
+	session, err := service.queryService.GetSession(ctx, hash.HashToken(token))
+	if errors.Cause(err) == domain.ErrSessionNotFound {
+		return uuid.UUID{}, ErrInvalidSession
+	}
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return uuid.UUID{}, ErrInvalidSession
+	}
+	return session.UserID, nil
+}
+
+func (service *sessionService) PurgeExpired(ctx context.Context, before time.Time) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		return provider.SessionRepository().RemoveExpired(ctx, before)
+	})
+}
+
+func (service *sessionService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func NewSessionEraser() PersonalDataEraser {
+	return &sessionEraser{}
+}
+
+type sessionEraser struct{}
+
+func (eraser *sessionEraser) Scope() string {
+	return "sessions"
+}
+
+func (eraser *sessionEraser) Erase(ctx context.Context, provider RepositoryProvider, _ domain.EventDispatcher, userID domain.UserID) error {
+	return provider.SessionRepository().RemoveByUser(ctx, userID)
+}
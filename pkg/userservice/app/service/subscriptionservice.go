@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type SubscriptionPlan int
+
+const (
+	FreePlan    = SubscriptionPlan(domain.FreePlan)
+	PremiumPlan = SubscriptionPlan(domain.PremiumPlan)
+	FamilyPlan  = SubscriptionPlan(domain.FamilyPlan)
+	StudentPlan = SubscriptionPlan(domain.StudentPlan)
+)
+
+type SubscriptionStatus int
+
+const (
+	SubscriptionActive   = SubscriptionStatus(domain.SubscriptionActive)
+	SubscriptionPastDue  = SubscriptionStatus(domain.SubscriptionPastDue)
+	SubscriptionCanceled = SubscriptionStatus(domain.SubscriptionCanceled)
+	SubscriptionExpired  = SubscriptionStatus(domain.SubscriptionExpired)
+)
+
+type SubscriptionView struct {
+	UserID      uuid.UUID
+	Plan        SubscriptionPlan
+	Status      SubscriptionStatus
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	Trial       bool
+	UpdatedAt   time.Time
+}
+
+type SubscriptionUpdate struct {
+	Plan        SubscriptionPlan
+	Status      SubscriptionStatus
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	Trial       bool
+}
+
+type SubscriptionService interface {
+	GetSubscription(ctx context.Context, userID uuid.UUID) (SubscriptionView, error)
+	SetSubscription(ctx context.Context, userID uuid.UUID, update SubscriptionUpdate) (SubscriptionView, error)
+}
+
+func NewSubscriptionService(unitOfWorkFactory UnitOfWorkFactory, eventHandler EventHandler) SubscriptionService {
+	return &subscriptionService{
+		unitOfWorkFactory: unitOfWorkFactory,
+		eventHandler:      eventHandler,
+	}
+}
+
+type subscriptionService struct {
+	unitOfWorkFactory UnitOfWorkFactory
+	eventHandler      EventHandler
+}
+
+func (service *subscriptionService) GetSubscription(ctx context.Context, userID uuid.UUID) (SubscriptionView, error) {
+	var subscription domain.Subscription
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		subscription, err = domainSubscriptionService(provider, dispatcher).GetSubscription(ctx, domain.UserID(userID))
+		return err
+	})
+	if err != nil {
+		return SubscriptionView{}, err
+	}
+	return makeSubscriptionView(subscription), nil
+}
+
+func (service *subscriptionService) SetSubscription(ctx context.Context, userID uuid.UUID, update SubscriptionUpdate) (SubscriptionView, error) {
+	var subscription domain.Subscription
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err error
+		subscription, err = domainSubscriptionService(provider, dispatcher).SetSubscription(ctx, domain.UserID(userID), domain.SubscriptionUpdate{
+			Plan:        domain.SubscriptionPlan(update.Plan),
+			Status:      domain.SubscriptionStatus(update.Status),
+			PeriodStart: update.PeriodStart,
+			PeriodEnd:   update.PeriodEnd,
+			Trial:       update.Trial,
+		})
+		return err
+	})
+	if err != nil {
+		return SubscriptionView{}, err
+	}
+	return makeSubscriptionView(subscription), nil
+}
+
+func (service *subscriptionService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
+}
+
+func domainSubscriptionService(provider RepositoryProvider, dispatcher domain.EventDispatcher) domain.SubscriptionService {
+	return domain.NewSubscriptionService(provider.UserRepository(), provider.SubscriptionRepository(), dispatcher)
+}
+
+func makeSubscriptionView(subscription domain.Subscription) SubscriptionView {
+	return SubscriptionView{
+		UserID:      uuid.UUID(subscription.UserID),
+		Plan:        SubscriptionPlan(subscription.Plan),
+		Status:      SubscriptionStatus(subscription.Status),
+		PeriodStart: subscription.PeriodStart,
+		PeriodEnd:   subscription.PeriodEnd,
+		Trial:       subscription.Trial,
+		UpdatedAt:   subscription.UpdatedAt,
+	}
+}
@@ -1,16 +1,88 @@
 package service
 
-import "userservice/pkg/userservice/domain"
+import (
+	"context"
+
+	"userservice/pkg/userservice/domain"
+)
 
 type UnitOfWorkFactory interface {
-	NewUnitOfWork(lockName string) (UnitOfWork, error)
+	NewUnitOfWork(ctx context.Context, lockName string) (UnitOfWork, error)
 }
 
 type RepositoryProvider interface {
 	UserRepository() domain.UserRepository
+	DataExportRepository() domain.DataExportRepository
+	ErasureCertificateRepository() domain.ErasureCertificateRepository
+	ConsentRepository() domain.ConsentRepository
+	AuditLogRepository() domain.AuditLogRepository
+	ProfileRepository() domain.ProfileRepository
+	CreatorApplicationRepository() domain.CreatorApplicationRepository
+	ArtistRepository() domain.ArtistRepository
+	ArtistInvitationRepository() domain.ArtistInvitationRepository
+	SubscriptionRepository() domain.SubscriptionRepository
+	HouseholdRepository() domain.HouseholdRepository
+	HouseholdInvitationRepository() domain.HouseholdInvitationRepository
+	ParentalControlsRepository() domain.ParentalControlsRepository
+	FollowRepository() domain.FollowRepository
+	BlockRepository() domain.BlockRepository
+	PrivacySettingsRepository() domain.PrivacySettingsRepository
+	ExternalIdentityRepository() domain.ExternalIdentityRepository
+	ExternalLoginRequestRepository() domain.ExternalLoginRequestRepository
+	OAuth2ClientRepository() domain.OAuth2ClientRepository
+	OAuth2AuthorizationCodeRepository() domain.OAuth2AuthorizationCodeRepository
+	OAuth2TokenRepository() domain.OAuth2TokenRepository
+	OAuth2DeviceAuthorizationRepository() domain.OAuth2DeviceAuthorizationRepository
+	OAuth2UserCodeAttemptsRepository() domain.OAuth2UserCodeAttemptsRepository
+	SessionRepository() domain.SessionRepository
+	OutboxRepository() domain.OutboxRepository
 }
 
 type UnitOfWork interface {
 	RepositoryProvider
 	Complete(err error) error
 }
+
+func executeInUnitOfWork(
+	ctx context.Context,
+	unitOfWorkFactory UnitOfWorkFactory,
+	eventHandler EventHandler,
+	f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error,
+) (err error) {
+	unitOfWork, err := unitOfWorkFactory.NewUnitOfWork(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	collector := &eventCollector{}
+	defer func() {
+		err = unitOfWork.Complete(err)
+		if err != nil {
+			return
+		}
+		// events are handled only after commit, so subscribers never observe rolled back changes
+		for _, event := range collector.events {
+			eventHandler.Handle(event)
+		}
+	}()
+
+	err = f(unitOfWork, collector)
+	if err != nil {
+		return err
+	}
+	// audit entries are written in the same transaction, so committed change is never missing in audit log
+	err = appendAuditEntries(ctx, unitOfWork.AuditLogRepository(), collector.events)
+	if err != nil {
+		return err
+	}
+
+	if handler, ok := eventHandler.(TransactionalEventHandler); ok {
+		for _, event := range collector.events {
+			err = handler.HandleInUnitOfWork(ctx, unitOfWork, event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+
+	"userservice/pkg/userservice/domain"
+)
+
+type mockRepositoryProvider struct {
+	RepositoryProvider
+	auditLog             *mockAuditLogRepository
+	users                *mockUserRepository
+	oauth2Clients        *mockOAuth2ClientRepository
+	oauth2Tokens         *mockOAuth2TokenRepository
+	deviceAuthorizations *mockOAuth2DeviceAuthorizationRepository
+	userCodeAttempts     *mockOAuth2UserCodeAttemptsRepository
+}
+
+func newMockRepositoryProvider() *mockRepositoryProvider {
+	return &mockRepositoryProvider{
+		auditLog:             &mockAuditLogRepository{},
+		users:                &mockUserRepository{users: map[domain.UserID]domain.User{}},
+		oauth2Clients:        &mockOAuth2ClientRepository{clients: map[string]domain.OAuth2Client{}},
+		oauth2Tokens:         &mockOAuth2TokenRepository{tokens: map[string]domain.OAuth2Token{}},
+		deviceAuthorizations: &mockOAuth2DeviceAuthorizationRepository{authorizations: map[string]domain.OAuth2DeviceAuthorization{}},
+		userCodeAttempts:     &mockOAuth2UserCodeAttemptsRepository{attempts: map[domain.UserID]domain.OAuth2UserCodeAttempts{}},
+	}
+}
+
+func (provider *mockRepositoryProvider) AuditLogRepository() domain.AuditLogRepository {
+	return provider.auditLog
+}
+
+func (provider *mockRepositoryProvider) UserRepository() domain.UserRepository {
+	return provider.users
+}
+
+func (provider *mockRepositoryProvider) OAuth2ClientRepository() domain.OAuth2ClientRepository {
+	return provider.oauth2Clients
+}
+
+func (provider *mockRepositoryProvider) OAuth2TokenRepository() domain.OAuth2TokenRepository {
+	return provider.oauth2Tokens
+}
+
+func (provider *mockRepositoryProvider) OAuth2DeviceAuthorizationRepository() domain.OAuth2DeviceAuthorizationRepository {
+	return provider.deviceAuthorizations
+}
+
+func (provider *mockRepositoryProvider) OAuth2UserCodeAttemptsRepository() domain.OAuth2UserCodeAttemptsRepository {
+	return provider.userCodeAttempts
+}
+
+type mockUnitOfWorkFactory struct {
+	provider RepositoryProvider
+}
+
+func (factory *mockUnitOfWorkFactory) NewUnitOfWork(context.Context, string) (UnitOfWork, error) {
+	return &mockUnitOfWork{RepositoryProvider: factory.provider}, nil
+}
+
+type mockUnitOfWork struct {
+	RepositoryProvider
+}
+
+func (unitOfWork *mockUnitOfWork) Complete(err error) error {
+	return err
+}
+
+type mockUserRepository struct {
+	domain.UserRepository
+	users map[domain.UserID]domain.User
+}
+
+func (repo *mockUserRepository) Find(_ context.Context, id domain.UserID) (domain.User, error) {
+	user, ok := repo.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return user, nil
+}
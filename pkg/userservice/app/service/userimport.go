@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/mail"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/domain"
+)
+
+var (
+	ErrInvalidEmail        = errors.New("invalid email")
+	ErrPasswordRequired    = errors.New("either password or password hash is required")
+	ErrAmbiguousPassword   = errors.New("only one of password and password hash must be set")
+	ErrDuplicateImportRow  = errors.New("email is duplicated in import")
+	ErrUnknownImportedRole = errors.New("unknown role")
+)
+
+type ImportedUser struct {
+	Email             string
+	Role              Role
+	Password          string
+	PasswordHash      string
+	PasswordAlgorithm hash.Algorithm
+}
+
+type ImportRow struct {
+	Number int
+	User   ImportedUser
+	Err    error
+}
+
+type ImportRowReader interface {
+	Read() (ImportRow, error)
+}
+
+type ImportRowError struct {
+	Row   int
+	Email string
+	Err   error
+}
+
+type ImportReport struct {
+	Imported int
+	Errors   []ImportRowError
+}
+
+func (service *userService) ImportUsers(ctx context.Context, reader ImportRowReader) (ImportReport, error) {
+	var report ImportReport
+	rowsByEmail := map[string]int{}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return report, nil
+		}
+		if err != nil {
+			return report, err
+		}
+
+		email := domain.NormalizeEmail(row.User.Email)
+		err = row.Err
+		if err == nil {
+			err = validateImportedUser(email, row.User)
+		}
+		if err == nil {
+			if firstRow, ok := rowsByEmail[email]; ok {
+				err = errors.Wrap(ErrDuplicateImportRow, fmt.Sprintf("first seen in row %d", firstRow))
+			}
+		}
+		if err == nil {
+			rowsByEmail[email] = row.Number
+			err = service.importUser(ctx, email, row.User)
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			report.Errors = append(report.Errors, ImportRowError{Row: row.Number, Email: row.User.Email, Err: err})
+			continue
+		}
+		report.Imported++
+	}
+}
+
+func (service *userService) importUser(ctx context.Context, email string, user ImportedUser) error {
+	password, algorithm := user.PasswordHash, user.PasswordAlgorithm
+	if user.Password != "" {
+		var err error
+		password, err = service.hasher.Hash(user.Password)
+		if err != nil {
+			return err
+		}
+		algorithm = hash.Native
+	}
+
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		_, err := domainService.AddUser(ctx, email, password, string(algorithm), domain.Role(user.Role))
+		return err
+	})
+}
+
+func validateImportedUser(email string, user ImportedUser) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return errors.Wrap(ErrInvalidEmail, user.Email)
+	}
+
+	switch user.Role {
+	case Listener, Creator, Admin:
+	default:
+		return ErrUnknownImportedRole
+	}
+
+	switch {
+	case user.Password == "" && user.PasswordHash == "":
+		return ErrPasswordRequired
+	case user.Password != "" && user.PasswordHash != "":
+		return ErrAmbiguousPassword
+	case user.PasswordHash != "" && !hash.IsSupported(user.PasswordAlgorithm):
+		return errors.Wrap(hash.ErrUnsupportedAlgorithm, string(user.PasswordAlgorithm))
+	}
+	return nil
+}
@@ -1,7 +1,11 @@
 package service
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 
 	"userservice/pkg/userservice/app/hash"
 	"userservice/pkg/userservice/domain"
@@ -12,34 +16,78 @@ type Role int
 const (
 	Listener = Role(domain.Listener)
 	Creator  = Role(domain.Creator)
+	Admin    = Role(domain.Admin)
 )
 
 type UserService interface {
-	AddUser(email, password string, role Role) (string, error)
+	AddUser(ctx context.Context, email, password string, role Role, terms *TermsAcceptance, dateOfBirth *time.Time) (string, error)
+	ChangeRole(ctx context.Context, userID uuid.UUID, role Role) error
+	ChangeEmail(ctx context.Context, userID uuid.UUID, email string) error
+	RemoveUser(ctx context.Context, userID uuid.UUID) error
+	RestoreUser(ctx context.Context, userID uuid.UUID) error
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, password string) error
+	RecordLoginFailure(ctx context.Context, userID uuid.UUID) error
+	RecordLoginSuccess(ctx context.Context, userID uuid.UUID) error
+	UnlockUser(ctx context.Context, userID uuid.UUID) error
+	ImportUsers(ctx context.Context, reader ImportRowReader) (ImportReport, error)
+}
+
+type LockoutPolicy struct {
+	MaxFailedAttempts int
+	Duration          time.Duration
 }
 
-func NewUserService(unitOfWorkFactory UnitOfWorkFactory, hasher hash.Hasher) UserService {
+func NewUserService(
+	unitOfWorkFactory UnitOfWorkFactory,
+	hasher hash.PasswordHasher,
+	eventHandler EventHandler,
+	erasers []PersonalDataEraser,
+	lockoutPolicy LockoutPolicy,
+	consentPolicy ConsentPolicy,
+) UserService {
 	return &userService{
 		unitOfWorkFactory: unitOfWorkFactory,
 		hasher:            hasher,
+		eventHandler:      eventHandler,
+		erasers:           erasers,
+		lockoutPolicy:     lockoutPolicy,
+		consentPolicy:     consentPolicy,
 	}
 }
 
 type userService struct {
 	unitOfWorkFactory UnitOfWorkFactory
-	hasher            hash.Hasher
+	hasher            hash.PasswordHasher
+	eventHandler      EventHandler
+	erasers           []PersonalDataEraser
+	lockoutPolicy     LockoutPolicy
+	consentPolicy     ConsentPolicy
 }
 
-func (service *userService) AddUser(email, password string, role Role) (string, error) {
-	var userID domain.UserID
-
-	err := service.executeInUnitOfWork(func(provider RepositoryProvider) error {
-		domainService := domain.NewUserService(provider.UserRepository())
+func (service *userService) AddUser(ctx context.Context, email, password string, role Role, terms *TermsAcceptance, dateOfBirth *time.Time) (string, error) {
+	err := service.consentPolicy.validateSignUp(terms)
+	if err != nil {
+		return "", err
+	}
 
-		var err2 error
+	passwordHash, err := service.hasher.Hash(password)
+	if err != nil {
+		return "", err
+	}
 
-		userID, err2 = domainService.AddUser(email, service.hasher.Hash(password), domain.Role(role))
+	var userID domain.UserID
 
+	err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		var err2 error
+		userID, err2 = signUp(ctx, provider, dispatcher, signUpRequest{
+			Email:             email,
+			PasswordHash:      passwordHash,
+			PasswordAlgorithm: string(hash.Native),
+			Role:              role,
+			Terms:             terms,
+			DateOfBirth:       dateOfBirth,
+		})
 		return err2
 	})
 
@@ -50,14 +98,143 @@ func (service *userService) AddUser(email, password string, role Role) (string,
 	return uuid.UUID(userID).String(), nil
 }
 
-func (service *userService) executeInUnitOfWork(f func(provider RepositoryProvider) error) error {
-	unitOfWork, err := service.unitOfWorkFactory.NewUnitOfWork("")
+type signUpRequest struct {
+	Email             string
+	PasswordHash      string
+	PasswordAlgorithm string
+	Role              Role
+	Terms             *TermsAcceptance
+	DateOfBirth       *time.Time
+}
+
+func signUp(ctx context.Context, provider RepositoryProvider, dispatcher domain.EventDispatcher, request signUpRequest) (domain.UserID, error) {
+	domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+	userID, err := domainService.AddUser(ctx, request.Email, request.PasswordHash, request.PasswordAlgorithm, domain.Role(request.Role))
+	if err != nil {
+		return domain.UserID{}, err
+	}
+
+	if request.DateOfBirth != nil {
+		profileService := domain.NewProfileService(provider.UserRepository(), provider.ProfileRepository(), dispatcher)
+		_, err = profileService.UpdateProfile(ctx, userID, domain.ProfileUpdate{
+			Fields:      []domain.ProfileField{domain.ProfileDateOfBirth},
+			DateOfBirth: request.DateOfBirth,
+		})
+		if err != nil {
+			return domain.UserID{}, err
+		}
+	}
+
+	if request.Terms == nil {
+		return userID, nil
+	}
+	return userID, storeConsent(ctx, provider.ConsentRepository(), userID, request.Terms.record())
+}
+
+func (service *userService) ChangeRole(ctx context.Context, userID uuid.UUID, role Role) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.ChangeRole(ctx, domain.UserID(userID), domain.Role(role))
+	})
+}
+
+func (service *userService) ChangeEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.ChangeEmail(ctx, domain.UserID(userID), email)
+	})
+}
+
+func (service *userService) RemoveUser(ctx context.Context, userID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.RemoveUser(ctx, domain.UserID(userID))
+	})
+}
+
+func (service *userService) RestoreUser(ctx context.Context, userID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.RestoreUser(ctx, domain.UserID(userID))
+	})
+}
+
+// PurgeDeletedUsers purges each user in its own unit of work, so one failure does not hold back the rest
+func (service *userService) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	var userIDs []domain.UserID
+	err := service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, _ domain.EventDispatcher) error {
+		var err2 error
+		userIDs, err2 = provider.UserRepository().FindDeletedBefore(ctx, deletedBefore, limit)
+		return err2
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		err = service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+			domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+			err2 := domainService.PurgeUser(ctx, userID, deletedBefore)
+			if err2 != nil {
+				return err2
+			}
+			for _, eraser := range service.erasers {
+				err2 = eraser.Erase(ctx, provider, dispatcher, userID)
+				if err2 != nil {
+					return err2
+				}
+			}
+			return provider.SubscriptionRepository().Remove(ctx, userID)
+		})
+		switch errors.Cause(err) {
+		case nil:
+			purged++
+		case domain.ErrUserNotFound, domain.ErrUserNotDeleted:
+			// user was purged by another instance or restored meanwhile
+		default:
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+func (service *userService) ChangePassword(ctx context.Context, userID uuid.UUID, password string) error {
+	passwordHash, err := service.hasher.Hash(password)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		err = unitOfWork.Complete(err)
-	}()
-	err = f(unitOfWork)
-	return err
+
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.ChangePassword(ctx, domain.UserID(userID), passwordHash, string(hash.Native))
+	})
+}
+
+func (service *userService) RecordLoginFailure(ctx context.Context, userID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.RegisterLoginFailure(ctx, domain.UserID(userID), domain.LockoutPolicy{
+			MaxFailedAttempts: service.lockoutPolicy.MaxFailedAttempts,
+			Duration:          service.lockoutPolicy.Duration,
+		})
+	})
+}
+
+func (service *userService) RecordLoginSuccess(ctx context.Context, userID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.RegisterLoginSuccess(ctx, domain.UserID(userID))
+	})
+}
+
+func (service *userService) UnlockUser(ctx context.Context, userID uuid.UUID) error {
+	return service.executeInUnitOfWork(ctx, func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error {
+		domainService := domain.NewUserService(provider.UserRepository(), dispatcher)
+		return domainService.Unlock(ctx, domain.UserID(userID))
+	})
+}
+
+func (service *userService) executeInUnitOfWork(ctx context.Context, f func(provider RepositoryProvider, dispatcher domain.EventDispatcher) error) error {
+	return executeInUnitOfWork(ctx, service.unitOfWorkFactory, service.eventHandler, f)
 }
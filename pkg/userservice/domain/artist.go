@@ -0,0 +1,571 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	MaxArtistNameLength = 100
+	ArtistInvitationTTL = 7 * 24 * time.Hour
+)
+
+var (
+	ErrArtistNotFound                = errors.New("artist not found")
+	ErrArtistMemberNotFound          = errors.New("user is not member of artist")
+	ErrArtistInvitationNotFound      = errors.New("artist invitation not found")
+	ErrArtistInvitationExpired       = errors.New("artist invitation is expired")
+	ErrArtistInvitationNotPending    = errors.New("artist invitation is already answered")
+	ErrInvalidArtistName             = errors.New("artist name must be from 1 to 100 characters")
+	ErrInvalidArtistRole             = errors.New("invalid artist role")
+	ErrAlreadyArtistMember           = errors.New("user is already member of artist")
+	ErrArtistPermissionDenied        = errors.New("not enough rights in artist team")
+	ErrOnlyCreatorsCanCreateArtist   = errors.New("only creators can create artist")
+	ErrArtistOwnerCannotLeave        = errors.New("owner can not leave artist, ownership must be transferred first")
+	ErrArtistOwnershipTransferToSelf = errors.New("user already owns artist")
+)
+
+type ArtistID uuid.UUID
+
+// ArtistRole values are ordered, every role has all rights of roles below it
+type ArtistRole int
+
+const (
+	ArtistViewer ArtistRole = iota
+	ArtistUploader
+	ArtistManager
+	ArtistOwner
+)
+
+var artistRoleNames = map[ArtistRole]string{
+	ArtistViewer:   "viewer",
+	ArtistUploader: "uploader",
+	ArtistManager:  "manager",
+	ArtistOwner:    "owner",
+}
+
+func (role ArtistRole) Name() string {
+	return artistRoleNames[role]
+}
+
+func (role ArtistRole) IsValid() bool {
+	return role >= ArtistViewer && role <= ArtistOwner
+}
+
+type Artist struct {
+	ID        ArtistID
+	Name      string
+	CreatedAt time.Time
+}
+
+type ArtistMember struct {
+	ArtistID ArtistID
+	UserID   UserID
+	Role     ArtistRole
+	JoinedAt time.Time
+}
+
+type ArtistInvitationID uuid.UUID
+
+type ArtistInvitationStatus int
+
+const (
+	ArtistInvitationStatusPending ArtistInvitationStatus = iota
+	ArtistInvitationStatusAccepted
+	ArtistInvitationStatusDeclined
+	ArtistInvitationStatusRevoked
+)
+
+type ArtistInvitation struct {
+	ID          ArtistInvitationID
+	ArtistID    ArtistID
+	Email       string
+	Role        ArtistRole
+	Status      ArtistInvitationStatus
+	InvitedBy   UserID
+	InviteeID   *UserID
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	RespondedAt *time.Time
+}
+
+type ArtistRepository interface {
+	NewID() ArtistID
+	Find(ctx context.Context, id ArtistID) (Artist, error)
+	Store(ctx context.Context, artist Artist) error
+	FindMember(ctx context.Context, id ArtistID, userID UserID) (ArtistMember, error)
+	FindMembers(ctx context.Context, id ArtistID) ([]ArtistMember, error)
+	FindMembershipsByUser(ctx context.Context, userID UserID) ([]ArtistMember, error)
+	StoreMember(ctx context.Context, member ArtistMember) error
+	RemoveMember(ctx context.Context, id ArtistID, userID UserID) error
+}
+
+type ArtistInvitationRepository interface {
+	NewID() ArtistInvitationID
+	Find(ctx context.Context, id ArtistInvitationID) (ArtistInvitation, error)
+	FindPendingByEmail(ctx context.Context, email string) ([]ArtistInvitation, error)
+	FindPendingByArtist(ctx context.Context, artistID ArtistID) ([]ArtistInvitation, error)
+	Store(ctx context.Context, invitation ArtistInvitation) error
+	RemoveByInvitee(ctx context.Context, userID UserID) error
+}
+
+type ArtistCreated struct {
+	ArtistID ArtistID
+	OwnerID  UserID
+}
+
+func (e ArtistCreated) ID() string {
+	return "artist_created"
+}
+
+// ArtistMemberInvited carries email for notification, it must not get to audit log
+type ArtistMemberInvited struct {
+	InvitationID ArtistInvitationID
+	ArtistID     ArtistID
+	InvitedBy    UserID
+	Email        string
+	Role         ArtistRole
+}
+
+func (e ArtistMemberInvited) ID() string {
+	return "artist_member_invited"
+}
+
+type ArtistInvitationDeclined struct {
+	InvitationID ArtistInvitationID
+	ArtistID     ArtistID
+	UserID       UserID
+}
+
+func (e ArtistInvitationDeclined) ID() string {
+	return "artist_invitation_declined"
+}
+
+type ArtistMemberAdded struct {
+	ArtistID ArtistID
+	UserID   UserID
+	Role     ArtistRole
+}
+
+func (e ArtistMemberAdded) ID() string {
+	return "artist_member_added"
+}
+
+type ArtistMemberRoleChanged struct {
+	ArtistID ArtistID
+	UserID   UserID
+	Role     ArtistRole
+}
+
+func (e ArtistMemberRoleChanged) ID() string {
+	return "artist_member_role_changed"
+}
+
+type ArtistMemberRemoved struct {
+	ArtistID ArtistID
+	UserID   UserID
+}
+
+func (e ArtistMemberRemoved) ID() string {
+	return "artist_member_removed"
+}
+
+type ArtistOwnershipTransferred struct {
+	ArtistID      ArtistID
+	PreviousOwner UserID
+	NewOwner      UserID
+}
+
+func (e ArtistOwnershipTransferred) ID() string {
+	return "artist_ownership_transferred"
+}
+
+type ArtistService interface {
+	CreateArtist(ctx context.Context, ownerID UserID, name string) (ArtistID, error)
+	InviteMember(ctx context.Context, id ArtistID, actorID UserID, email string, role ArtistRole) (ArtistInvitationID, error)
+	RespondToInvitation(ctx context.Context, invitationID ArtistInvitationID, userID UserID, accept bool) error
+	ChangeMemberRole(ctx context.Context, id ArtistID, actorID, userID UserID, role ArtistRole) error
+	RemoveMember(ctx context.Context, id ArtistID, actorID, userID UserID) error
+	TransferOwnership(ctx context.Context, id ArtistID, actorID, newOwnerID UserID) error
+	ReleaseMemberships(ctx context.Context, userID UserID) error
+}
+
+func NewArtistService(
+	userRepository UserRepository,
+	artistRepository ArtistRepository,
+	invitationRepository ArtistInvitationRepository,
+	dispatcher EventDispatcher,
+) ArtistService {
+	return &artistService{
+		userRepo:       userRepository,
+		artistRepo:     artistRepository,
+		invitationRepo: invitationRepository,
+		dispatcher:     dispatcher,
+	}
+}
+
+type artistService struct {
+	userRepo       UserRepository
+	artistRepo     ArtistRepository
+	invitationRepo ArtistInvitationRepository
+	dispatcher     EventDispatcher
+}
+
+func (service *artistService) CreateArtist(ctx context.Context, ownerID UserID, name string) (ArtistID, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxArtistNameLength {
+		return ArtistID{}, ErrInvalidArtistName
+	}
+
+	owner, err := service.findActiveUser(ctx, ownerID)
+	if err != nil {
+		return ArtistID{}, err
+	}
+	if owner.Role != Creator && owner.Role != Admin {
+		return ArtistID{}, ErrOnlyCreatorsCanCreateArtist
+	}
+
+	now := time.Now()
+	artist := Artist{
+		ID:        service.artistRepo.NewID(),
+		Name:      name,
+		CreatedAt: now,
+	}
+	err = service.artistRepo.Store(ctx, artist)
+	if err != nil {
+		return ArtistID{}, err
+	}
+	err = service.artistRepo.StoreMember(ctx, ArtistMember{ArtistID: artist.ID, UserID: ownerID, Role: ArtistOwner, JoinedAt: now})
+	if err != nil {
+		return ArtistID{}, err
+	}
+
+	return artist.ID, service.dispatcher.Dispatch(ArtistCreated{ArtistID: artist.ID, OwnerID: ownerID})
+}
+
+func (service *artistService) InviteMember(ctx context.Context, id ArtistID, actorID UserID, email string, role ArtistRole) (ArtistInvitationID, error) {
+	if !role.IsValid() || role == ArtistOwner {
+		return ArtistInvitationID{}, ErrInvalidArtistRole
+	}
+
+	actorRole, err := service.actorRole(ctx, id, actorID)
+	if err != nil {
+		return ArtistInvitationID{}, err
+	}
+	if !canManage(actorRole, role) {
+		return ArtistInvitationID{}, ErrArtistPermissionDenied
+	}
+
+	invitee, err := service.userRepo.FindByEmail(ctx, email)
+	switch errors.Cause(err) {
+	case nil:
+		_, err = service.artistRepo.FindMember(ctx, id, invitee.ID)
+		if err == nil {
+			return ArtistInvitationID{}, ErrAlreadyArtistMember
+		}
+		if errors.Cause(err) != ErrArtistMemberNotFound {
+			return ArtistInvitationID{}, err
+		}
+	case ErrUserNotFound:
+	default:
+		return ArtistInvitationID{}, err
+	}
+
+	// new invitation replaces pending one, so role of invitation is always the latest one
+	pending, err := service.invitationRepo.FindPendingByArtist(ctx, id)
+	if err != nil {
+		return ArtistInvitationID{}, err
+	}
+	for _, invitation := range pending {
+		if invitation.Email != email {
+			continue
+		}
+		invitation.Status = ArtistInvitationStatusRevoked
+		err = service.invitationRepo.Store(ctx, invitation)
+		if err != nil {
+			return ArtistInvitationID{}, err
+		}
+	}
+
+	now := time.Now()
+	invitation := ArtistInvitation{
+		ID:        service.invitationRepo.NewID(),
+		ArtistID:  id,
+		Email:     email,
+		Role:      role,
+		Status:    ArtistInvitationStatusPending,
+		InvitedBy: actorID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ArtistInvitationTTL),
+	}
+	err = service.invitationRepo.Store(ctx, invitation)
+	if err != nil {
+		return ArtistInvitationID{}, err
+	}
+
+	return invitation.ID, service.dispatcher.Dispatch(ArtistMemberInvited{
+		InvitationID: invitation.ID,
+		ArtistID:     id,
+		InvitedBy:    actorID,
+		Email:        email,
+		Role:         role,
+	})
+}
+
+func (service *artistService) RespondToInvitation(ctx context.Context, invitationID ArtistInvitationID, userID UserID, accept bool) error {
+	user, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := service.invitationRepo.Find(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	// invitation of someone else is reported as missing, so ids can not be probed
+	if invitation.Email != user.Email {
+		return ErrArtistInvitationNotFound
+	}
+	if invitation.Status != ArtistInvitationStatusPending {
+		return ErrArtistInvitationNotPending
+	}
+	now := time.Now()
+	if !now.Before(invitation.ExpiresAt) {
+		return ErrArtistInvitationExpired
+	}
+
+	invitation.InviteeID = &userID
+	invitation.RespondedAt = &now
+	if !accept {
+		invitation.Status = ArtistInvitationStatusDeclined
+		err = service.invitationRepo.Store(ctx, invitation)
+		if err != nil {
+			return err
+		}
+		return service.dispatcher.Dispatch(ArtistInvitationDeclined{InvitationID: invitationID, ArtistID: invitation.ArtistID, UserID: userID})
+	}
+
+	invitation.Status = ArtistInvitationStatusAccepted
+	err = service.invitationRepo.Store(ctx, invitation)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.artistRepo.FindMember(ctx, invitation.ArtistID, userID)
+	if err == nil {
+		return ErrAlreadyArtistMember
+	}
+	if errors.Cause(err) != ErrArtistMemberNotFound {
+		return err
+	}
+
+	err = service.artistRepo.StoreMember(ctx, ArtistMember{ArtistID: invitation.ArtistID, UserID: userID, Role: invitation.Role, JoinedAt: now})
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ArtistMemberAdded{ArtistID: invitation.ArtistID, UserID: userID, Role: invitation.Role})
+}
+
+func (service *artistService) ChangeMemberRole(ctx context.Context, id ArtistID, actorID, userID UserID, role ArtistRole) error {
+	if !role.IsValid() || role == ArtistOwner {
+		return ErrInvalidArtistRole
+	}
+
+	actorRole, err := service.actorRole(ctx, id, actorID)
+	if err != nil {
+		return err
+	}
+	member, err := service.artistRepo.FindMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !canManage(actorRole, member.Role) || !canManage(actorRole, role) {
+		return ErrArtistPermissionDenied
+	}
+	if member.Role == role {
+		return nil
+	}
+
+	member.Role = role
+	err = service.artistRepo.StoreMember(ctx, member)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ArtistMemberRoleChanged{ArtistID: id, UserID: userID, Role: role})
+}
+
+func (service *artistService) RemoveMember(ctx context.Context, id ArtistID, actorID, userID UserID) error {
+	member, err := service.artistRepo.FindMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == ArtistOwner {
+		return ErrArtistOwnerCannotLeave
+	}
+
+	if actorID != userID {
+		actorRole, err := service.actorRole(ctx, id, actorID)
+		if err != nil {
+			return err
+		}
+		if !canManage(actorRole, member.Role) {
+			return ErrArtistPermissionDenied
+		}
+	}
+
+	err = service.artistRepo.RemoveMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ArtistMemberRemoved{ArtistID: id, UserID: userID})
+}
+
+func (service *artistService) TransferOwnership(ctx context.Context, id ArtistID, actorID, newOwnerID UserID) error {
+	actorRole, err := service.actorRole(ctx, id, actorID)
+	if err != nil {
+		return err
+	}
+	if actorRole != ArtistOwner {
+		return ErrArtistPermissionDenied
+	}
+
+	newOwner, err := service.artistRepo.FindMember(ctx, id, newOwnerID)
+	if err != nil {
+		return err
+	}
+	if newOwner.Role == ArtistOwner {
+		return ErrArtistOwnershipTransferToSelf
+	}
+	_, err = service.findActiveUser(ctx, newOwnerID)
+	if err != nil {
+		return err
+	}
+
+	members, err := service.artistRepo.FindMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+	var previousOwner UserID
+	for _, member := range members {
+		if member.Role != ArtistOwner {
+			continue
+		}
+		previousOwner = member.UserID
+		member.Role = ArtistManager
+		err = service.artistRepo.StoreMember(ctx, member)
+		if err != nil {
+			return err
+		}
+	}
+
+	newOwner.Role = ArtistOwner
+	err = service.artistRepo.StoreMember(ctx, newOwner)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ArtistOwnershipTransferred{ArtistID: id, PreviousOwner: previousOwner, NewOwner: newOwnerID})
+}
+
+func (service *artistService) ReleaseMemberships(ctx context.Context, userID UserID) error {
+	memberships, err := service.artistRepo.FindMembershipsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, membership := range memberships {
+		if membership.Role == ArtistOwner {
+			err = service.handOverOwnership(ctx, membership.ArtistID, userID)
+			if err != nil {
+				return err
+			}
+		}
+		err = service.artistRepo.RemoveMember(ctx, membership.ArtistID, userID)
+		if err != nil {
+			return err
+		}
+		err = service.dispatcher.Dispatch(ArtistMemberRemoved{ArtistID: membership.ArtistID, UserID: userID})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (service *artistService) handOverOwnership(ctx context.Context, id ArtistID, ownerID UserID) error {
+	members, err := service.artistRepo.FindMembers(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var successor *ArtistMember
+	for i, member := range members {
+		if member.UserID == ownerID || (successor != nil && member.Role <= successor.Role) {
+			continue
+		}
+		_, err = service.findActiveUser(ctx, member.UserID)
+		if errors.Cause(err) == ErrUserNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		successor = &members[i]
+	}
+	if successor == nil {
+		return nil
+	}
+
+	successor.Role = ArtistOwner
+	err = service.artistRepo.StoreMember(ctx, *successor)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ArtistOwnershipTransferred{ArtistID: id, PreviousOwner: ownerID, NewOwner: successor.UserID})
+}
+
+func (service *artistService) actorRole(ctx context.Context, id ArtistID, actorID UserID) (ArtistRole, error) {
+	_, err := service.artistRepo.Find(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	actor, err := service.findActiveUser(ctx, actorID)
+	if err != nil {
+		return 0, err
+	}
+	if actor.Role == Admin {
+		return ArtistOwner, nil
+	}
+
+	member, err := service.artistRepo.FindMember(ctx, id, actorID)
+	if err != nil {
+		if errors.Cause(err) == ErrArtistMemberNotFound {
+			return 0, ErrArtistPermissionDenied
+		}
+		return 0, err
+	}
+	return member.Role, nil
+}
+
+func (service *artistService) findActiveUser(ctx context.Context, id UserID) (User, error) {
+	user, err := service.userRepo.Find(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func canManage(actorRole, role ArtistRole) bool {
+	if actorRole == ArtistOwner {
+		return true
+	}
+	return actorRole == ArtistManager && role < ArtistManager
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var ErrAuditLogTampered = errors.New("audit log hash chain is broken")
+
+type AuditEntry struct {
+	Seq      int64
+	Action   string
+	ActorID  *UserID
+	TargetID *UserID
+	// IP and UserAgent are personal data, they are not covered by hash so they can be erased
+	IP        string
+	UserAgent string
+	RequestID string
+	Details   map[string]string
+	// OccurredAt is kept in UTC with microsecond precision of database, so hash can be recomputed from stored entry
+	OccurredAt time.Time
+	ChainSeq   int64
+	PrevHash   string
+	Hash       string
+}
+
+type hashedAuditEntry struct {
+	Seq        int64             `json:"seq"`
+	ChainSeq   int64             `json:"chain_seq"`
+	Action     string            `json:"action"`
+	ActorID    string            `json:"actor_id"`
+	TargetID   string            `json:"target_id"`
+	RequestID  string            `json:"request_id"`
+	Details    map[string]string `json:"details"`
+	OccurredAt string            `json:"occurred_at"`
+	PrevHash   string            `json:"prev_hash"`
+}
+
+// ComputeHash covers every field except Hash itself and client data, encoding/json sorts Details keys so result is deterministic
+func (entry AuditEntry) ComputeHash() (string, error) {
+	data, err := json.Marshal(hashedAuditEntry{
+		Seq:        entry.Seq,
+		ChainSeq:   entry.ChainSeq,
+		Action:     entry.Action,
+		ActorID:    optionalUserIDString(entry.ActorID),
+		TargetID:   optionalUserIDString(entry.TargetID),
+		RequestID:  entry.RequestID,
+		Details:    entry.Details,
+		OccurredAt: entry.OccurredAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:   entry.PrevHash,
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (entry AuditEntry) Seal(lastChainSeq int64, lastHash string) (AuditEntry, error) {
+	entry.ChainSeq = lastChainSeq + 1
+	entry.PrevHash = lastHash
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return AuditEntry{}, err
+	}
+	entry.Hash = hash
+	return entry, nil
+}
+
+func optionalUserIDString(id *UserID) string {
+	if id == nil {
+		return ""
+	}
+	return uuid.UUID(*id).String()
+}
+
+type AuditLogRepository interface {
+	// Append stores entry without waiting for other transactions, it is chained later by SealPending
+	Append(ctx context.Context, entry AuditEntry) error
+	// SealPending chains committed entries to the last sealed one, concurrent calls wait for each other
+	SealPending(ctx context.Context, limit int) (int, error)
+	FindSealedAfter(ctx context.Context, afterChainSeq int64, limit int) ([]AuditEntry, error)
+	RemoveClientData(ctx context.Context, userID UserID) error
+}
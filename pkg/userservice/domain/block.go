@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrBlockNotFound   = errors.New("block not found")
+	ErrAlreadyBlocked  = errors.New("user already blocked")
+	ErrCannotBlockSelf = errors.New("user can not block own account")
+)
+
+type Block struct {
+	BlockerID UserID
+	BlockedID UserID
+	CreatedAt time.Time
+}
+
+type UserPair struct {
+	UserID  UserID
+	OtherID UserID
+}
+
+type BlockRepository interface {
+	Find(ctx context.Context, blockerID, blockedID UserID) (Block, error)
+	FindByBlocker(ctx context.Context, blockerID UserID, offset, limit int) ([]Block, error)
+	FindBetween(ctx context.Context, pairs []UserPair) ([]Block, error)
+	IsBlockedEitherWay(ctx context.Context, userID, otherID UserID) (bool, error)
+	Store(ctx context.Context, block Block) error
+	Remove(ctx context.Context, blockerID, blockedID UserID) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+}
+
+type UserBlocked struct {
+	BlockerID UserID
+	BlockedID UserID
+}
+
+func (e UserBlocked) ID() string {
+	return "user_blocked"
+}
+
+type UserUnblocked struct {
+	BlockerID UserID
+	BlockedID UserID
+}
+
+func (e UserUnblocked) ID() string {
+	return "user_unblocked"
+}
+
+type BlockService interface {
+	Block(ctx context.Context, blockerID, blockedID UserID) error
+	Unblock(ctx context.Context, blockerID, blockedID UserID) error
+}
+
+func NewBlockService(
+	userRepository UserRepository,
+	artistRepository ArtistRepository,
+	followRepository FollowRepository,
+	blockRepository BlockRepository,
+	dispatcher EventDispatcher,
+) BlockService {
+	return &blockService{
+		userRepo:   userRepository,
+		artistRepo: artistRepository,
+		followRepo: followRepository,
+		blockRepo:  blockRepository,
+		dispatcher: dispatcher,
+	}
+}
+
+type blockService struct {
+	userRepo   UserRepository
+	artistRepo ArtistRepository
+	followRepo FollowRepository
+	blockRepo  BlockRepository
+	dispatcher EventDispatcher
+}
+
+func (service *blockService) Block(ctx context.Context, blockerID, blockedID UserID) error {
+	if blockerID == blockedID {
+		return ErrCannotBlockSelf
+	}
+	for _, userID := range []UserID{blockerID, blockedID} {
+		user, err := service.userRepo.Find(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.IsDeleted() || user.IsErased() {
+			return ErrUserNotFound
+		}
+	}
+
+	_, err := service.blockRepo.Find(ctx, blockerID, blockedID)
+	if err == nil {
+		return ErrAlreadyBlocked
+	}
+	if errors.Cause(err) != ErrBlockNotFound {
+		return err
+	}
+
+	err = service.blockRepo.Store(ctx, Block{BlockerID: blockerID, BlockedID: blockedID, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	err = service.removeFollows(ctx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	err = service.removeFollows(ctx, blockedID, blockerID)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(UserBlocked{BlockerID: blockerID, BlockedID: blockedID})
+}
+
+func (service *blockService) Unblock(ctx context.Context, blockerID, blockedID UserID) error {
+	_, err := service.blockRepo.Find(ctx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	err = service.blockRepo.Remove(ctx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(UserUnblocked{BlockerID: blockerID, BlockedID: blockedID})
+}
+
+func (service *blockService) removeFollows(ctx context.Context, followerID, userID UserID) error {
+	targets := []FollowTarget{UserFollowTarget(userID)}
+	memberships, err := service.artistRepo.FindMembershipsByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, membership := range memberships {
+		if membership.Role == ArtistOwner {
+			targets = append(targets, FollowTarget{Kind: FollowTargetArtist, ID: uuid.UUID(membership.ArtistID)})
+		}
+	}
+
+	for _, target := range targets {
+		_, err = service.followRepo.Find(ctx, followerID, target)
+		if errors.Cause(err) == ErrFollowNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		err = service.followRepo.Remove(ctx, followerID, target)
+		if err != nil {
+			return err
+		}
+		err = service.dispatcher.Dispatch(UserUnfollowed{FollowerID: followerID, Target: target})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
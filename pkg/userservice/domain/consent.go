@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ConsentID uuid.UUID
+
+type ConsentKind int
+
+const (
+	TermsOfService ConsentKind = iota
+	PrivacyPolicy
+	Marketing
+)
+
+type Consent struct {
+	ID         ConsentID
+	UserID     UserID
+	Kind       ConsentKind
+	Version    string
+	Channel    string
+	Granted    bool
+	SourceIP   string
+	RecordedAt time.Time
+}
+
+type ConsentRepository interface {
+	NewID() ConsentID
+	Store(ctx context.Context, consent Consent) error
+	FindByUser(ctx context.Context, userID UserID) ([]Consent, error)
+	AnonymizeByUser(ctx context.Context, userID UserID) error
+}
@@ -0,0 +1,225 @@
+package domain
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type CreatorApplicationID uuid.UUID
+
+type CreatorApplicationStatus int
+
+const (
+	CreatorApplicationStatusApplied CreatorApplicationStatus = iota
+	CreatorApplicationStatusUnderReview
+	CreatorApplicationStatusApproved
+	CreatorApplicationStatusRejected
+)
+
+const maxCreatorApplicationTextLength = 4000
+
+type CreatorApplicationDecision int
+
+const (
+	StartCreatorApplicationReview CreatorApplicationDecision = iota
+	ApproveCreatorApplication
+	RejectCreatorApplication
+)
+
+var (
+	ErrCreatorApplicationNotFound        = errors.New("creator application not found")
+	ErrCreatorApplicationAlreadyOpen     = errors.New("user already has open creator application")
+	ErrOnlyListenersCanApplyForCreator   = errors.New("only listeners can apply for creator")
+	ErrInvalidCreatorApplicationDecision = errors.New("decision is not allowed in current status of application")
+	ErrCreatorApplicationTextTooLong     = errors.New("statement or reviewer notes are too long")
+)
+
+type CreatorApplication struct {
+	ID        CreatorApplicationID
+	UserID    UserID
+	Status    CreatorApplicationStatus
+	Statement string
+
+	ReviewerID      *UserID
+	ReviewerNotes   string
+	AppliedAt       time.Time
+	ReviewStartedAt *time.Time
+	DecidedAt       *time.Time
+}
+
+func (application CreatorApplication) IsOpen() bool {
+	return application.Status == CreatorApplicationStatusApplied || application.Status == CreatorApplicationStatusUnderReview
+}
+
+type CreatorApplicationRepository interface {
+	NewID() CreatorApplicationID
+	Find(ctx context.Context, id CreatorApplicationID) (CreatorApplication, error)
+	FindOpenByUser(ctx context.Context, userID UserID) (CreatorApplication, error)
+	Add(ctx context.Context, application CreatorApplication) error
+	Store(ctx context.Context, application CreatorApplication) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+}
+
+type CreatorApplicationSubmitted struct {
+	ApplicationID CreatorApplicationID
+	UserID        UserID
+}
+
+func (e CreatorApplicationSubmitted) ID() string {
+	return "creator_application_submitted"
+}
+
+type CreatorApplicationReviewStarted struct {
+	ApplicationID CreatorApplicationID
+	UserID        UserID
+	ReviewerID    UserID
+}
+
+func (e CreatorApplicationReviewStarted) ID() string {
+	return "creator_application_review_started"
+}
+
+type CreatorApplicationApproved struct {
+	ApplicationID CreatorApplicationID
+	UserID        UserID
+	ReviewerID    UserID
+}
+
+func (e CreatorApplicationApproved) ID() string {
+	return "creator_application_approved"
+}
+
+type CreatorApplicationRejected struct {
+	ApplicationID CreatorApplicationID
+	UserID        UserID
+	ReviewerID    UserID
+}
+
+func (e CreatorApplicationRejected) ID() string {
+	return "creator_application_rejected"
+}
+
+type CreatorApplicationService interface {
+	Apply(ctx context.Context, userID UserID, statement string) (CreatorApplicationID, error)
+	Review(ctx context.Context, id CreatorApplicationID, reviewerID UserID, decision CreatorApplicationDecision, notes string) (CreatorApplication, error)
+}
+
+func NewCreatorApplicationService(
+	userRepository UserRepository,
+	applicationRepository CreatorApplicationRepository,
+	dispatcher EventDispatcher,
+) CreatorApplicationService {
+	return &creatorApplicationService{
+		userRepo:        userRepository,
+		applicationRepo: applicationRepository,
+		dispatcher:      dispatcher,
+		userService:     NewUserService(userRepository, dispatcher),
+	}
+}
+
+type creatorApplicationService struct {
+	userRepo        UserRepository
+	applicationRepo CreatorApplicationRepository
+	dispatcher      EventDispatcher
+	userService     UserService
+}
+
+func (service *creatorApplicationService) Apply(ctx context.Context, userID UserID, statement string) (CreatorApplicationID, error) {
+	if utf8.RuneCountInString(statement) > maxCreatorApplicationTextLength {
+		return CreatorApplicationID{}, ErrCreatorApplicationTextTooLong
+	}
+
+	user, err := service.userRepo.Find(ctx, userID)
+	if err != nil {
+		return CreatorApplicationID{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return CreatorApplicationID{}, ErrUserNotFound
+	}
+	if user.Role != Listener {
+		return CreatorApplicationID{}, ErrOnlyListenersCanApplyForCreator
+	}
+
+	_, err = service.applicationRepo.FindOpenByUser(ctx, userID)
+	if err == nil {
+		return CreatorApplicationID{}, ErrCreatorApplicationAlreadyOpen
+	}
+	if errors.Cause(err) != ErrCreatorApplicationNotFound {
+		return CreatorApplicationID{}, err
+	}
+
+	application := CreatorApplication{
+		ID:        service.applicationRepo.NewID(),
+		UserID:    userID,
+		Status:    CreatorApplicationStatusApplied,
+		Statement: statement,
+		AppliedAt: time.Now(),
+	}
+	err = service.applicationRepo.Add(ctx, application)
+	if err != nil {
+		return CreatorApplicationID{}, err
+	}
+
+	return application.ID, service.dispatcher.Dispatch(CreatorApplicationSubmitted{
+		ApplicationID: application.ID,
+		UserID:        userID,
+	})
+}
+
+func (service *creatorApplicationService) Review(
+	ctx context.Context,
+	id CreatorApplicationID,
+	reviewerID UserID,
+	decision CreatorApplicationDecision,
+	notes string,
+) (CreatorApplication, error) {
+	if utf8.RuneCountInString(notes) > maxCreatorApplicationTextLength {
+		return CreatorApplication{}, ErrCreatorApplicationTextTooLong
+	}
+
+	application, err := service.applicationRepo.Find(ctx, id)
+	if err != nil {
+		return CreatorApplication{}, err
+	}
+
+	now := time.Now()
+	var event Event
+	switch {
+	case decision == StartCreatorApplicationReview && application.Status == CreatorApplicationStatusApplied:
+		application.Status = CreatorApplicationStatusUnderReview
+		application.ReviewStartedAt = &now
+		event = CreatorApplicationReviewStarted{ApplicationID: id, UserID: application.UserID, ReviewerID: reviewerID}
+	case decision == ApproveCreatorApplication && application.Status == CreatorApplicationStatusUnderReview:
+		application.Status = CreatorApplicationStatusApproved
+		application.DecidedAt = &now
+		event = CreatorApplicationApproved{ApplicationID: id, UserID: application.UserID, ReviewerID: reviewerID}
+	case decision == RejectCreatorApplication && application.Status == CreatorApplicationStatusUnderReview:
+		application.Status = CreatorApplicationStatusRejected
+		application.DecidedAt = &now
+		event = CreatorApplicationRejected{ApplicationID: id, UserID: application.UserID, ReviewerID: reviewerID}
+	default:
+		return CreatorApplication{}, ErrInvalidCreatorApplicationDecision
+	}
+
+	application.ReviewerID = &reviewerID
+	if notes != "" {
+		application.ReviewerNotes = notes
+	}
+	err = service.applicationRepo.Store(ctx, application)
+	if err != nil {
+		return CreatorApplication{}, err
+	}
+
+	if application.Status == CreatorApplicationStatusApproved {
+		err = service.userService.ChangeRole(ctx, application.UserID, Creator)
+		if err != nil {
+			return CreatorApplication{}, err
+		}
+	}
+
+	return application, service.dispatcher.Dispatch(event)
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+type DataExportID uuid.UUID
+
+type DataExportStatus int
+
+const (
+	DataExportPending DataExportStatus = iota
+	DataExportProcessing
+	DataExportReady
+	DataExportFailed
+	DataExportExpired
+)
+
+var (
+	ErrDataExportNotFound  = errors.New("data export not found")
+	ErrNoPendingDataExport = errors.New("no pending data export")
+)
+
+type DataExportRequested struct {
+	ExportID DataExportID
+	UserID   UserID
+}
+
+func (e DataExportRequested) ID() string {
+	return "data_export_requested"
+}
+
+type DataExport struct {
+	ID                DataExportID
+	UserID            UserID
+	Status            DataExportStatus
+	DownloadTokenHash string
+
+	RequestedAt time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+	Error       string
+}
+
+func (export *DataExport) Start(at time.Time) {
+	export.Status = DataExportProcessing
+	export.StartedAt = &at
+}
+
+func (export *DataExport) Complete(at time.Time, ttl time.Duration) {
+	expiresAt := at.Add(ttl)
+	export.Status = DataExportReady
+	export.CompletedAt = &at
+	export.ExpiresAt = &expiresAt
+}
+
+func (export *DataExport) Fail(at time.Time, reason string) {
+	export.Status = DataExportFailed
+	export.CompletedAt = &at
+	export.Error = reason
+}
+
+func (export *DataExport) Expire() {
+	export.Status = DataExportExpired
+}
+
+func (export DataExport) IsDownloadable(at time.Time) bool {
+	return export.Status == DataExportReady && export.ExpiresAt != nil && at.Before(*export.ExpiresAt)
+}
+
+type DataExportRepository interface {
+	NewID() DataExportID
+	Find(ctx context.Context, id DataExportID) (DataExport, error)
+	FindByDownloadTokenHash(ctx context.Context, tokenHash string) (DataExport, error)
+	ClaimPending(ctx context.Context, staleBefore time.Time) (DataExport, error)
+	FindExpired(ctx context.Context, at time.Time, limit int) ([]DataExport, error)
+	FindByUser(ctx context.Context, userID UserID) ([]DataExport, error)
+	Store(ctx context.Context, export DataExport) error
+}
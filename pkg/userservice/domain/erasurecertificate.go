@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ErasureCertificateID uuid.UUID
+
+type ErasureCertificate struct {
+	ID          ErasureCertificateID
+	UserID      UserID
+	RequestedBy *UserID
+	ErasedAt    time.Time
+	Scopes      []string
+}
+
+type ErasureCertificateRepository interface {
+	NewID() ErasureCertificateID
+	Store(ctx context.Context, certificate ErasureCertificate) error
+}
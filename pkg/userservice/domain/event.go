@@ -0,0 +1,9 @@
+package domain
+
+type Event interface {
+	ID() string
+}
+
+type EventDispatcher interface {
+	Dispatch(event Event) error
+}
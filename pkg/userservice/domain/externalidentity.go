@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrExternalIdentityNotFound        = errors.New("external identity not found")
+	ErrExternalIdentityLinkedToOther   = errors.New("external identity is linked to another user")
+	ErrExternalProviderAlreadyLinked   = errors.New("user already linked account of provider")
+	ErrExternalLoginRequestNotFound    = errors.New("external login request not found")
+	ErrExternalLoginRequestExpired     = errors.New("external login request expired")
+	ErrLastSignInMethod                = errors.New("user without password can not unlink last external identity")
+	ErrExternalEmailNotVerified        = errors.New("external identity has no verified email")
+	ErrExternalEmailBelongsToOtherUser = errors.New("user with email of external identity exists, sign in and link provider instead")
+)
+
+const NoPasswordAlgorithm = "none"
+
+func (user User) HasPassword() bool {
+	return user.PasswordAlgorithm != NoPasswordAlgorithm
+}
+
+type ExternalIdentity struct {
+	UserID   UserID
+	Provider string
+	Issuer   string
+	Subject  string
+	Email    string
+	LinkedAt time.Time
+}
+
+type ExternalIdentityRepository interface {
+	FindBySubject(ctx context.Context, issuer, subject string) (ExternalIdentity, error)
+	FindByUser(ctx context.Context, userID UserID) ([]ExternalIdentity, error)
+	Store(ctx context.Context, identity ExternalIdentity) error
+	Remove(ctx context.Context, userID UserID, provider string) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+}
+
+const ExternalLoginRequestTTL = 10 * time.Minute
+
+type ExternalLoginRequest struct {
+	State        string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	LinkUserID   *UserID
+	ExpiresAt    time.Time
+}
+
+type ExternalLoginRequestRepository interface {
+	Find(ctx context.Context, state string) (ExternalLoginRequest, error)
+	Store(ctx context.Context, request ExternalLoginRequest) error
+	Remove(ctx context.Context, state string) error
+	RemoveExpired(ctx context.Context, before time.Time) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+}
+
+type ExternalIdentityLinked struct {
+	UserID      UserID
+	Provider    string
+	Provisioned bool
+}
+
+func (e ExternalIdentityLinked) ID() string {
+	return "external_identity_linked"
+}
+
+type ExternalIdentityUnlinked struct {
+	UserID   UserID
+	Provider string
+}
+
+func (e ExternalIdentityUnlinked) ID() string {
+	return "external_identity_unlinked"
+}
+
+type ExternalIdentityService interface {
+	SignIn(ctx context.Context, identity ExternalIdentity) (UserID, error)
+	LinkProvisioned(ctx context.Context, userID UserID, identity ExternalIdentity) error
+	Link(ctx context.Context, userID UserID, identity ExternalIdentity) error
+	Unlink(ctx context.Context, userID UserID, provider string) error
+}
+
+func NewExternalIdentityService(
+	userRepository UserRepository,
+	identityRepository ExternalIdentityRepository,
+	dispatcher EventDispatcher,
+) ExternalIdentityService {
+	return &externalIdentityService{
+		userRepo:     userRepository,
+		identityRepo: identityRepository,
+		dispatcher:   dispatcher,
+	}
+}
+
+type externalIdentityService struct {
+	userRepo     UserRepository
+	identityRepo ExternalIdentityRepository
+	dispatcher   EventDispatcher
+}
+
+func (service *externalIdentityService) SignIn(ctx context.Context, identity ExternalIdentity) (UserID, error) {
+	existing, err := service.identityRepo.FindBySubject(ctx, identity.Issuer, identity.Subject)
+	if err != nil {
+		return UserID{}, err
+	}
+	user, err := service.findActiveUser(ctx, existing.UserID)
+	return user.ID, err
+}
+
+func (service *externalIdentityService) LinkProvisioned(ctx context.Context, userID UserID, identity ExternalIdentity) error {
+	_, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	identity.UserID = userID
+	identity.LinkedAt = time.Now()
+	err = service.identityRepo.Store(ctx, identity)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ExternalIdentityLinked{UserID: userID, Provider: identity.Provider, Provisioned: true})
+}
+
+func (service *externalIdentityService) Link(ctx context.Context, userID UserID, identity ExternalIdentity) error {
+	_, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	existing, err := service.identityRepo.FindBySubject(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return ErrExternalIdentityLinkedToOther
+		}
+		return nil
+	}
+	if errors.Cause(err) != ErrExternalIdentityNotFound {
+		return err
+	}
+
+	identities, err := service.identityRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, linked := range identities {
+		if linked.Provider == identity.Provider {
+			return ErrExternalProviderAlreadyLinked
+		}
+	}
+
+	identity.UserID = userID
+	identity.LinkedAt = time.Now()
+	err = service.identityRepo.Store(ctx, identity)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ExternalIdentityLinked{UserID: userID, Provider: identity.Provider})
+}
+
+func (service *externalIdentityService) Unlink(ctx context.Context, userID UserID, provider string) error {
+	user, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	identities, err := service.identityRepo.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, identity := range identities {
+		if identity.Provider == provider {
+			found = true
+		}
+	}
+	if !found {
+		return ErrExternalIdentityNotFound
+	}
+	if !user.HasPassword() && len(identities) == 1 {
+		return ErrLastSignInMethod
+	}
+
+	err = service.identityRepo.Remove(ctx, userID, provider)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ExternalIdentityUnlinked{UserID: userID, Provider: provider})
+}
+
+func (service *externalIdentityService) findActiveUser(ctx context.Context, id UserID) (User, error) {
+	user, err := service.userRepo.Find(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
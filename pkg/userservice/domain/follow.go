@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrFollowNotFound          = errors.New("follow not found")
+	ErrAlreadyFollowing        = errors.New("user already follows target")
+	ErrCannotFollowSelf        = errors.New("user can not follow own account")
+	ErrFollowBlocked           = errors.New("user can not follow target because of block")
+	ErrInvalidFollowTargetKind = errors.New("invalid follow target kind")
+)
+
+type FollowTargetKind int
+
+const (
+	FollowTargetUser FollowTargetKind = iota
+	FollowTargetArtist
+)
+
+var followTargetKindNames = map[FollowTargetKind]string{
+	FollowTargetUser:   "user",
+	FollowTargetArtist: "artist",
+}
+
+func (kind FollowTargetKind) Name() string {
+	return followTargetKindNames[kind]
+}
+
+type FollowTarget struct {
+	Kind FollowTargetKind
+	ID   uuid.UUID
+}
+
+func UserFollowTarget(userID UserID) FollowTarget {
+	return FollowTarget{Kind: FollowTargetUser, ID: uuid.UUID(userID)}
+}
+
+type Follow struct {
+	FollowerID UserID
+	Target     FollowTarget
+	CreatedAt  time.Time
+}
+
+type FollowCounts struct {
+	Followers int
+	Following int
+}
+
+type FollowRepository interface {
+	Find(ctx context.Context, followerID UserID, target FollowTarget) (Follow, error)
+	// Add inserts follow and updates counts of both sides only when follow is new, existing follow gives ErrAlreadyFollowing,
+	// so concurrent follows neither lock missing row nor count twice
+	Add(ctx context.Context, follow Follow) error
+	Remove(ctx context.Context, followerID UserID, target FollowTarget) error
+	FindFollowers(ctx context.Context, target FollowTarget, offset, limit int) ([]Follow, error)
+	FindFollowing(ctx context.Context, followerID UserID, offset, limit int) ([]Follow, error)
+	Counts(ctx context.Context, target FollowTarget) (FollowCounts, error)
+	RemoveByUser(ctx context.Context, userID UserID) error
+}
+
+type UserFollowed struct {
+	FollowerID UserID
+	Target     FollowTarget
+}
+
+func (e UserFollowed) ID() string {
+	return "user_followed"
+}
+
+type UserUnfollowed struct {
+	FollowerID UserID
+	Target     FollowTarget
+}
+
+func (e UserUnfollowed) ID() string {
+	return "user_unfollowed"
+}
+
+type FollowService interface {
+	Follow(ctx context.Context, followerID UserID, target FollowTarget) error
+	Unfollow(ctx context.Context, followerID UserID, target FollowTarget) error
+}
+
+func NewFollowService(
+	userRepository UserRepository,
+	artistRepository ArtistRepository,
+	followRepository FollowRepository,
+	blockRepository BlockRepository,
+	privacyRepository PrivacySettingsRepository,
+	dispatcher EventDispatcher,
+) FollowService {
+	return &followService{
+		userRepo:    userRepository,
+		artistRepo:  artistRepository,
+		followRepo:  followRepository,
+		blockRepo:   blockRepository,
+		privacyRepo: privacyRepository,
+		dispatcher:  dispatcher,
+	}
+}
+
+type followService struct {
+	userRepo    UserRepository
+	artistRepo  ArtistRepository
+	followRepo  FollowRepository
+	blockRepo   BlockRepository
+	privacyRepo PrivacySettingsRepository
+	dispatcher  EventDispatcher
+}
+
+func (service *followService) Follow(ctx context.Context, followerID UserID, target FollowTarget) error {
+	_, err := service.findActiveUser(ctx, followerID)
+	if err != nil {
+		return err
+	}
+
+	// artist is blocked by its owner, so blocked user can not reach owner through artist either
+	var targetUserIDs []UserID
+	switch target.Kind {
+	case FollowTargetUser:
+		if target.ID == uuid.UUID(followerID) {
+			return ErrCannotFollowSelf
+		}
+		_, err = service.findActiveUser(ctx, UserID(target.ID))
+		if err != nil {
+			return err
+		}
+		settings, err2 := findPrivacySettings(ctx, service.privacyRepo, UserID(target.ID))
+		if err2 != nil {
+			return err2
+		}
+		if settings.WhoCanFollow == FollowPolicyNobody {
+			return ErrFollowNotAllowed
+		}
+		targetUserIDs = []UserID{UserID(target.ID)}
+	case FollowTargetArtist:
+		members, err2 := service.artistRepo.FindMembers(ctx, ArtistID(target.ID))
+		if err2 != nil {
+			return err2
+		}
+		if len(members) == 0 {
+			return ErrArtistNotFound
+		}
+		for _, member := range members {
+			if member.Role == ArtistOwner {
+				targetUserIDs = append(targetUserIDs, member.UserID)
+			}
+		}
+	default:
+		return ErrInvalidFollowTargetKind
+	}
+
+	for _, userID := range targetUserIDs {
+		blocked, err2 := service.blockRepo.IsBlockedEitherWay(ctx, followerID, userID)
+		if err2 != nil {
+			return err2
+		}
+		if blocked {
+			return ErrFollowBlocked
+		}
+	}
+
+	err = service.followRepo.Add(ctx, Follow{FollowerID: followerID, Target: target, CreatedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(UserFollowed{FollowerID: followerID, Target: target})
+}
+
+func (service *followService) Unfollow(ctx context.Context, followerID UserID, target FollowTarget) error {
+	_, err := service.followRepo.Find(ctx, followerID, target)
+	if err != nil {
+		return err
+	}
+
+	err = service.followRepo.Remove(ctx, followerID, target)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(UserUnfollowed{FollowerID: followerID, Target: target})
+}
+
+func (service *followService) findActiveUser(ctx context.Context, id UserID) (User, error) {
+	user, err := service.userRepo.Find(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
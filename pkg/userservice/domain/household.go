@@ -0,0 +1,470 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const HouseholdInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrHouseholdNotFound                = errors.New("household not found")
+	ErrHouseholdMemberNotFound          = errors.New("user is not member of household")
+	ErrHouseholdInvitationNotFound      = errors.New("household invitation not found")
+	ErrHouseholdInvitationExpired       = errors.New("household invitation is expired")
+	ErrHouseholdInvitationNotPending    = errors.New("household invitation is already answered")
+	ErrAlreadyInHousehold               = errors.New("user already belongs to household")
+	ErrHouseholdFull                    = errors.New("household has no free places")
+	ErrFamilyPlanRequired               = errors.New("household requires family plan")
+	ErrHouseholdAddressRequired         = errors.New("household address is required")
+	ErrHouseholdAddressMismatch         = errors.New("address does not match address of household")
+	ErrOnlyHouseholdManagerCanInvite    = errors.New("only manager of household can invite and remove members")
+	ErrHouseholdManagerCannotLeave      = errors.New("manager can not leave household")
+	ErrHouseholdInvitationForManager    = errors.New("manager can not invite own email")
+	ErrHouseholdInvitationEmailRequired = errors.New("invitation must be addressed to email")
+)
+
+type HouseholdID uuid.UUID
+
+type Household struct {
+	ID          HouseholdID
+	ManagerID   UserID
+	AddressHash string
+	CreatedAt   time.Time
+}
+
+type HouseholdMember struct {
+	HouseholdID HouseholdID
+	UserID      UserID
+	JoinedAt    time.Time
+}
+
+type HouseholdInvitationID uuid.UUID
+
+type HouseholdInvitationStatus int
+
+const (
+	HouseholdInvitationStatusPending HouseholdInvitationStatus = iota
+	HouseholdInvitationStatusAccepted
+	HouseholdInvitationStatusDeclined
+)
+
+type HouseholdInvitation struct {
+	ID          HouseholdInvitationID
+	HouseholdID HouseholdID
+	Email       string
+	Status      HouseholdInvitationStatus
+	InviteeID   *UserID
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	RespondedAt *time.Time
+}
+
+type HouseholdRepository interface {
+	NewID() HouseholdID
+	Find(ctx context.Context, id HouseholdID) (Household, error)
+	FindByMember(ctx context.Context, userID UserID) (Household, error)
+	Store(ctx context.Context, household Household) error
+	Remove(ctx context.Context, id HouseholdID) error
+	FindMembers(ctx context.Context, id HouseholdID) ([]HouseholdMember, error)
+	StoreMember(ctx context.Context, member HouseholdMember) error
+	RemoveMember(ctx context.Context, id HouseholdID, userID UserID) error
+}
+
+type HouseholdInvitationRepository interface {
+	NewID() HouseholdInvitationID
+	Find(ctx context.Context, id HouseholdInvitationID) (HouseholdInvitation, error)
+	FindPendingByHousehold(ctx context.Context, id HouseholdID) ([]HouseholdInvitation, error)
+	FindPendingByEmail(ctx context.Context, email string) ([]HouseholdInvitation, error)
+	Store(ctx context.Context, invitation HouseholdInvitation) error
+	RemoveByHousehold(ctx context.Context, id HouseholdID) error
+	RemoveByInvitee(ctx context.Context, userID UserID) error
+}
+
+type HouseholdCreated struct {
+	HouseholdID HouseholdID
+	ManagerID   UserID
+}
+
+func (e HouseholdCreated) ID() string {
+	return "household_created"
+}
+
+// HouseholdMemberInvited carries email for notification, it must not get to audit log
+type HouseholdMemberInvited struct {
+	InvitationID HouseholdInvitationID
+	HouseholdID  HouseholdID
+	ManagerID    UserID
+	Email        string
+}
+
+func (e HouseholdMemberInvited) ID() string {
+	return "household_member_invited"
+}
+
+type HouseholdInvitationDeclined struct {
+	InvitationID HouseholdInvitationID
+	HouseholdID  HouseholdID
+	UserID       UserID
+}
+
+func (e HouseholdInvitationDeclined) ID() string {
+	return "household_invitation_declined"
+}
+
+type HouseholdMemberJoined struct {
+	HouseholdID HouseholdID
+	UserID      UserID
+}
+
+func (e HouseholdMemberJoined) ID() string {
+	return "household_member_joined"
+}
+
+type HouseholdMemberRemoved struct {
+	HouseholdID HouseholdID
+	UserID      UserID
+}
+
+func (e HouseholdMemberRemoved) ID() string {
+	return "household_member_removed"
+}
+
+// HouseholdAddressHasher must be keyed, addresses are guessable and plain hash of them could be reversed by enumeration
+type HouseholdAddressHasher interface {
+	Hash(address string) string
+}
+
+type HouseholdService interface {
+	CreateHousehold(ctx context.Context, managerID UserID, address string) (HouseholdID, error)
+	InviteMember(ctx context.Context, managerID UserID, email string) (HouseholdInvitationID, error)
+	RespondToInvitation(ctx context.Context, invitationID HouseholdInvitationID, userID UserID, accept bool, address string) error
+	RemoveMember(ctx context.Context, actorID, userID UserID) error
+}
+
+func NewHouseholdService(
+	userRepository UserRepository,
+	householdRepository HouseholdRepository,
+	invitationRepository HouseholdInvitationRepository,
+	subscriptionRepository SubscriptionRepository,
+	dispatcher EventDispatcher,
+	addressHasher HouseholdAddressHasher,
+	maxMembers int,
+) HouseholdService {
+	return &householdService{
+		userRepo:         userRepository,
+		householdRepo:    householdRepository,
+		invitationRepo:   invitationRepository,
+		subscriptionRepo: subscriptionRepository,
+		dispatcher:       dispatcher,
+		addressHasher:    addressHasher,
+		maxMembers:       maxMembers,
+	}
+}
+
+type householdService struct {
+	userRepo         UserRepository
+	householdRepo    HouseholdRepository
+	invitationRepo   HouseholdInvitationRepository
+	subscriptionRepo SubscriptionRepository
+	dispatcher       EventDispatcher
+	addressHasher    HouseholdAddressHasher
+	maxMembers       int
+}
+
+func (service *householdService) CreateHousehold(ctx context.Context, managerID UserID, address string) (HouseholdID, error) {
+	normalizedAddress := NormalizeHouseholdAddress(address)
+	if normalizedAddress == "" {
+		return HouseholdID{}, ErrHouseholdAddressRequired
+	}
+
+	_, err := service.findActiveUser(ctx, managerID)
+	if err != nil {
+		return HouseholdID{}, err
+	}
+	err = service.assertNotInHousehold(ctx, managerID)
+	if err != nil {
+		return HouseholdID{}, err
+	}
+	err = service.assertFamilyPlan(ctx, managerID)
+	if err != nil {
+		return HouseholdID{}, err
+	}
+
+	now := time.Now()
+	household := Household{
+		ID:          service.householdRepo.NewID(),
+		ManagerID:   managerID,
+		AddressHash: service.addressHasher.Hash(normalizedAddress),
+		CreatedAt:   now,
+	}
+	err = service.householdRepo.Store(ctx, household)
+	if err != nil {
+		return HouseholdID{}, err
+	}
+	err = service.householdRepo.StoreMember(ctx, HouseholdMember{HouseholdID: household.ID, UserID: managerID, JoinedAt: now})
+	if err != nil {
+		return HouseholdID{}, err
+	}
+
+	return household.ID, service.dispatcher.Dispatch(HouseholdCreated{HouseholdID: household.ID, ManagerID: managerID})
+}
+
+func (service *householdService) InviteMember(ctx context.Context, managerID UserID, email string) (HouseholdInvitationID, error) {
+	if email == "" {
+		return HouseholdInvitationID{}, ErrHouseholdInvitationEmailRequired
+	}
+
+	household, err := service.managedHousehold(ctx, managerID)
+	if err != nil {
+		return HouseholdInvitationID{}, err
+	}
+	err = service.assertFamilyPlan(ctx, managerID)
+	if err != nil {
+		return HouseholdInvitationID{}, err
+	}
+
+	manager, err := service.findActiveUser(ctx, managerID)
+	if err != nil {
+		return HouseholdInvitationID{}, err
+	}
+	if manager.Email == email {
+		return HouseholdInvitationID{}, ErrHouseholdInvitationForManager
+	}
+
+	members, err := service.householdRepo.FindMembers(ctx, household.ID)
+	if err != nil {
+		return HouseholdInvitationID{}, err
+	}
+	pending, err := service.invitationRepo.FindPendingByHousehold(ctx, household.ID)
+	if err != nil {
+		return HouseholdInvitationID{}, err
+	}
+
+	now := time.Now()
+	reserved := len(members)
+	for _, invitation := range pending {
+		if invitation.Email == email || !now.Before(invitation.ExpiresAt) {
+			continue
+		}
+		reserved++
+	}
+	if reserved >= service.maxMembers {
+		return HouseholdInvitationID{}, ErrHouseholdFull
+	}
+
+	for _, invitation := range pending {
+		if invitation.Email != email {
+			continue
+		}
+		invitation.Status = HouseholdInvitationStatusDeclined
+		invitation.RespondedAt = &now
+		err = service.invitationRepo.Store(ctx, invitation)
+		if err != nil {
+			return HouseholdInvitationID{}, err
+		}
+	}
+
+	invitation := HouseholdInvitation{
+		ID:          service.invitationRepo.NewID(),
+		HouseholdID: household.ID,
+		Email:       email,
+		Status:      HouseholdInvitationStatusPending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(HouseholdInvitationTTL),
+	}
+	err = service.invitationRepo.Store(ctx, invitation)
+	if err != nil {
+		return HouseholdInvitationID{}, err
+	}
+
+	return invitation.ID, service.dispatcher.Dispatch(HouseholdMemberInvited{
+		InvitationID: invitation.ID,
+		HouseholdID:  household.ID,
+		ManagerID:    managerID,
+		Email:        email,
+	})
+}
+
+func (service *householdService) RespondToInvitation(
+	ctx context.Context,
+	invitationID HouseholdInvitationID,
+	userID UserID,
+	accept bool,
+	address string,
+) error {
+	user, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := service.invitationRepo.Find(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	// invitation of someone else is reported as missing, so ids can not be probed
+	if invitation.Email != user.Email {
+		return ErrHouseholdInvitationNotFound
+	}
+	if invitation.Status != HouseholdInvitationStatusPending {
+		return ErrHouseholdInvitationNotPending
+	}
+	now := time.Now()
+	if !now.Before(invitation.ExpiresAt) {
+		return ErrHouseholdInvitationExpired
+	}
+
+	invitation.InviteeID = &userID
+	invitation.RespondedAt = &now
+	if !accept {
+		invitation.Status = HouseholdInvitationStatusDeclined
+		err = service.invitationRepo.Store(ctx, invitation)
+		if err != nil {
+			return err
+		}
+		return service.dispatcher.Dispatch(HouseholdInvitationDeclined{InvitationID: invitationID, HouseholdID: invitation.HouseholdID, UserID: userID})
+	}
+
+	household, err := service.householdRepo.Find(ctx, invitation.HouseholdID)
+	if err != nil {
+		return err
+	}
+	err = service.matchAddress(ctx, &household, address)
+	if err != nil {
+		return err
+	}
+	err = service.assertNotInHousehold(ctx, userID)
+	if err != nil {
+		return err
+	}
+	members, err := service.householdRepo.FindMembers(ctx, household.ID)
+	if err != nil {
+		return err
+	}
+	if len(members) >= service.maxMembers {
+		return ErrHouseholdFull
+	}
+
+	invitation.Status = HouseholdInvitationStatusAccepted
+	err = service.invitationRepo.Store(ctx, invitation)
+	if err != nil {
+		return err
+	}
+	err = service.householdRepo.StoreMember(ctx, HouseholdMember{HouseholdID: household.ID, UserID: userID, JoinedAt: now})
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(HouseholdMemberJoined{HouseholdID: household.ID, UserID: userID})
+}
+
+func (service *householdService) RemoveMember(ctx context.Context, actorID, userID UserID) error {
+	household, err := service.householdRepo.FindByMember(ctx, userID)
+	if err != nil {
+		if errors.Cause(err) == ErrHouseholdNotFound {
+			return ErrHouseholdMemberNotFound
+		}
+		return err
+	}
+	if household.ManagerID == userID {
+		return ErrHouseholdManagerCannotLeave
+	}
+	if actorID != userID && actorID != household.ManagerID {
+		return ErrOnlyHouseholdManagerCanInvite
+	}
+
+	err = service.householdRepo.RemoveMember(ctx, household.ID, userID)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(HouseholdMemberRemoved{HouseholdID: household.ID, UserID: userID})
+}
+
+// matchAddress accepts unkeyed hash of households created before address hash was keyed and replaces it with keyed one
+func (service *householdService) matchAddress(ctx context.Context, household *Household, address string) error {
+	normalizedAddress := NormalizeHouseholdAddress(address)
+	if normalizedAddress == "" {
+		return ErrHouseholdAddressMismatch
+	}
+	addressHash := service.addressHasher.Hash(normalizedAddress)
+	if subtle.ConstantTimeCompare([]byte(addressHash), []byte(household.AddressHash)) == 1 {
+		return nil
+	}
+	legacyHash := sha256.Sum256([]byte(normalizedAddress))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(legacyHash[:])), []byte(household.AddressHash)) != 1 {
+		return ErrHouseholdAddressMismatch
+	}
+	household.AddressHash = addressHash
+	return service.householdRepo.Store(ctx, *household)
+}
+
+func (service *householdService) managedHousehold(ctx context.Context, managerID UserID) (Household, error) {
+	household, err := service.householdRepo.FindByMember(ctx, managerID)
+	if err != nil {
+		return Household{}, err
+	}
+	if household.ManagerID != managerID {
+		return Household{}, ErrOnlyHouseholdManagerCanInvite
+	}
+	return household, nil
+}
+
+func (service *householdService) assertNotInHousehold(ctx context.Context, userID UserID) error {
+	_, err := service.householdRepo.FindByMember(ctx, userID)
+	if err == nil {
+		return ErrAlreadyInHousehold
+	}
+	if errors.Cause(err) != ErrHouseholdNotFound {
+		return err
+	}
+	return nil
+}
+
+func (service *householdService) assertFamilyPlan(ctx context.Context, userID UserID) error {
+	subscription, err := service.subscriptionRepo.Find(ctx, userID)
+	if err != nil {
+		if errors.Cause(err) == ErrSubscriptionNotFound {
+			return ErrFamilyPlanRequired
+		}
+		return err
+	}
+	if subscription.EffectivePlan(time.Now()) != FamilyPlan {
+		return ErrFamilyPlanRequired
+	}
+	return nil
+}
+
+func (service *householdService) findActiveUser(ctx context.Context, id UserID) (User, error) {
+	user, err := service.userRepo.Find(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func HouseholdMemberEntitlements() []Entitlement {
+	result := make([]Entitlement, 0, len(planEntitlements[FamilyPlan]))
+	for _, entitlement := range planEntitlements[FamilyPlan] {
+		if entitlement != EntitlementFamilyManagement {
+			result = append(result, entitlement)
+		}
+	}
+	return result
+}
+
+func NormalizeHouseholdAddress(address string) string {
+	words := strings.FieldsFunc(strings.ToLower(address), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrOAuth2ClientNotFound            = errors.New("oauth2 client not found")
+	ErrInvalidOAuth2RedirectURI        = errors.New("redirect uri is not registered for oauth2 client")
+	ErrInvalidOAuth2Scope              = errors.New("scope is not allowed for oauth2 client")
+	ErrOAuth2GrantNotAllowed           = errors.New("grant type is not allowed for oauth2 client")
+	ErrOAuth2AuthorizationCodeNotFound = errors.New("oauth2 authorization code not found")
+	ErrOAuth2TokenNotFound             = errors.New("oauth2 token not found")
+)
+
+type OAuth2GrantType string
+
+const (
+	OAuth2AuthorizationCodeGrant OAuth2GrantType = "authorization_code"
+	OAuth2ClientCredentialsGrant OAuth2GrantType = "client_credentials"
+	OAuth2RefreshTokenGrant      OAuth2GrantType = "refresh_token"
+	OAuth2DeviceCodeGrant        OAuth2GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+const (
+	OAuth2OpenIDScope = "openid"
+	OAuth2EmailScope  = "email"
+)
+
+type OAuth2Client struct {
+	ID   string
+	Name string
+	// SecretHash is empty for public clients, they can not keep secret and rely on PKCE
+	SecretHash   string
+	RedirectURIs []string
+	Scopes       []string
+	GrantTypes   []OAuth2GrantType
+	FirstParty   bool
+	CreatedAt    time.Time
+}
+
+func (client OAuth2Client) IsPublic() bool {
+	return client.SecretHash == ""
+}
+
+func (client OAuth2Client) AllowsGrant(grantType OAuth2GrantType) bool {
+	for _, allowed := range client.GrantTypes {
+		if allowed == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRedirectURI compares uris exactly, prefix matching would let attacker redirect codes to other paths of host
+func (client OAuth2Client) AllowsRedirectURI(redirectURI string) bool {
+	for _, allowed := range client.RedirectURIs {
+		if allowed == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+func (client OAuth2Client) AllowsScopes(scopes []string) bool {
+	return isSubset(scopes, client.Scopes)
+}
+
+type OAuth2ClientRepository interface {
+	Find(ctx context.Context, clientID string) (OAuth2Client, error)
+	FindAll(ctx context.Context) ([]OAuth2Client, error)
+	Store(ctx context.Context, client OAuth2Client) error
+	Remove(ctx context.Context, clientID string) error
+}
+
+type OAuth2AuthorizationCode struct {
+	CodeHash      string
+	ClientID      string
+	UserID        UserID
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	Nonce         string
+	ExpiresAt     time.Time
+	// UsedAt is set on exchange, code is kept until expiry so replay can be detected
+	UsedAt *time.Time
+}
+
+type OAuth2AuthorizationCodeRepository interface {
+	Find(ctx context.Context, codeHash string) (OAuth2AuthorizationCode, error)
+	Store(ctx context.Context, code OAuth2AuthorizationCode) error
+	Remove(ctx context.Context, codeHash string) error
+	RemoveExpired(ctx context.Context, before time.Time) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+	RemoveByClient(ctx context.Context, clientID string) error
+}
+
+type OAuth2TokenKind int
+
+const (
+	OAuth2AccessToken OAuth2TokenKind = iota
+	OAuth2RefreshToken
+)
+
+type OAuth2Token struct {
+	TokenHash string
+	Kind      OAuth2TokenKind
+	ClientID  string
+	UserID    *UserID
+	Scopes    []string
+	// AuthorizationCodeHash links tokens to code they were issued for, also after refresh, empty for other grants
+	AuthorizationCodeHash string
+	ExpiresAt             time.Time
+	CreatedAt             time.Time
+}
+
+type OAuth2TokenRepository interface {
+	Find(ctx context.Context, tokenHash string) (OAuth2Token, error)
+	FindByUser(ctx context.Context, userID UserID) ([]OAuth2Token, error)
+	Store(ctx context.Context, token OAuth2Token) error
+	Remove(ctx context.Context, tokenHash string) error
+	RemoveExpired(ctx context.Context, before time.Time) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+	RemoveByClient(ctx context.Context, clientID string) error
+	RemoveByAuthorizationCode(ctx context.Context, codeHash string) error
+}
+
+type OAuth2AuthorizationGranted struct {
+	UserID   UserID
+	ClientID string
+	Scopes   []string
+}
+
+func (e OAuth2AuthorizationGranted) ID() string {
+	return "oauth2_authorization_granted"
+}
+
+func ContainsOAuth2Scope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func isSubset(values, of []string) bool {
+	allowed := make(map[string]struct{}, len(of))
+	for _, value := range of {
+		allowed[value] = struct{}{}
+	}
+	for _, value := range values {
+		if _, ok := allowed[value]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrOAuth2DeviceAuthorizationNotFound = errors.New("device authorization not found")
+	ErrOAuth2UserCodeLocked              = errors.New("too many wrong user codes, try again later")
+)
+
+const (
+	// MaxFailedOAuth2UserCodeAttempts within OAuth2UserCodeLockoutDuration lock user out of entering codes,
+	// so codes of other devices can not be guessed, see RFC 8628 section 5.1
+	MaxFailedOAuth2UserCodeAttempts = 5
+	OAuth2UserCodeLockoutDuration   = 15 * time.Minute
+)
+
+type OAuth2DeviceAuthorizationStatus int
+
+const (
+	OAuth2DeviceAuthorizationPending OAuth2DeviceAuthorizationStatus = iota
+	OAuth2DeviceAuthorizationApproved
+	OAuth2DeviceAuthorizationDenied
+)
+
+type OAuth2DeviceAuthorization struct {
+	DeviceCodeHash string
+	UserCode       string
+	ClientID       string
+	Scopes         []string
+	Status         OAuth2DeviceAuthorizationStatus
+	UserID         *UserID
+	PollInterval   time.Duration
+	LastPolledAt   *time.Time
+	ExpiresAt      time.Time
+}
+
+type OAuth2DeviceAuthorizationRepository interface {
+	FindByDeviceCode(ctx context.Context, deviceCodeHash string) (OAuth2DeviceAuthorization, error)
+	FindByUserCode(ctx context.Context, userCode string) (OAuth2DeviceAuthorization, error)
+	Store(ctx context.Context, authorization OAuth2DeviceAuthorization) error
+	Remove(ctx context.Context, deviceCodeHash string) error
+	RemoveExpired(ctx context.Context, before time.Time) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+	RemoveByClient(ctx context.Context, clientID string) error
+}
+
+// OAuth2UserCodeAttempts counts wrong user codes entered by user, success does not reset them,
+// otherwise user could reset counter with codes of own devices
+type OAuth2UserCodeAttempts struct {
+	UserID          UserID
+	FailedAttempts  int
+	WindowStartedAt time.Time
+	LockedUntil     *time.Time
+}
+
+func (attempts OAuth2UserCodeAttempts) IsLocked(now time.Time) bool {
+	return attempts.LockedUntil != nil && now.Before(*attempts.LockedUntil)
+}
+
+func (attempts *OAuth2UserCodeAttempts) RecordFailure(now time.Time) {
+	if now.Sub(attempts.WindowStartedAt) > OAuth2UserCodeLockoutDuration {
+		attempts.FailedAttempts = 0
+		attempts.WindowStartedAt = now
+	}
+	attempts.FailedAttempts++
+	if attempts.FailedAttempts >= MaxFailedOAuth2UserCodeAttempts {
+		lockedUntil := now.Add(OAuth2UserCodeLockoutDuration)
+		attempts.LockedUntil = &lockedUntil
+		attempts.FailedAttempts = 0
+	}
+}
+
+type OAuth2UserCodeAttemptsRepository interface {
+	Find(ctx context.Context, userID UserID) (OAuth2UserCodeAttempts, error)
+	Store(ctx context.Context, attempts OAuth2UserCodeAttempts) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+}
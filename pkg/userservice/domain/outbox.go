@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type OutboxMessage struct {
+	Seq        int64
+	RoutingKey string
+	Body       []byte
+	CreatedAt  time.Time
+}
+
+type OutboxRepository interface {
+	Store(ctx context.Context, message OutboxMessage) error
+	// FindOldest locks returned messages, so concurrent relays do not publish them twice
+	FindOldest(ctx context.Context, limit int) ([]OutboxMessage, error)
+	Remove(ctx context.Context, seq int64) error
+}
@@ -0,0 +1,546 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	MaxFailedPINAttempts = 5
+	PINLockoutDuration   = 15 * time.Minute
+	MinPINLength         = 4
+	MaxPINLength         = 8
+)
+
+var (
+	ErrParentalControlsNotFound  = errors.New("parental controls not found")
+	ErrInvalidParentalPIN        = errors.New("pin must be 4 to 8 digits")
+	ErrParentalPINRequired       = errors.New("parental pin must be set first")
+	ErrParentalPINMismatch       = errors.New("parental pin does not match")
+	ErrParentalPINLocked         = errors.New("parental pin is locked after too many failed attempts")
+	ErrParentalControlsForbidden = errors.New("only user, guardian or admin can change parental controls")
+	ErrGuardianMustBeAdult       = errors.New("guardian must be adult by date of birth in profile")
+	ErrGuardianIsSelf            = errors.New("user can not be own guardian")
+	ErrGuardianRequired          = errors.New("explicit content for minor can be allowed only by guardian")
+	ErrExplicitContentForChild   = errors.New("explicit content can not be allowed for child")
+	ErrGuardianChangeForbidden   = errors.New("guardian of child or minor can be linked only by admin or current guardian")
+	ErrPINSetByGuardian          = errors.New("pin of child or minor can be set only by guardian or admin")
+	ErrAgeChangeByGuardian       = errors.New("date of birth and country of child, minor or guarded user can be changed only by guardian or admin")
+)
+
+type AgeCategory int
+
+const (
+	AgeUnknown AgeCategory = iota
+	AgeChild
+	AgeMinor
+	AgeAdult
+)
+
+var ageCategoryNames = map[AgeCategory]string{
+	AgeUnknown: "unknown",
+	AgeChild:   "child",
+	AgeMinor:   "minor",
+	AgeAdult:   "adult",
+}
+
+func (category AgeCategory) Name() string {
+	return ageCategoryNames[category]
+}
+
+func (category AgeCategory) IsRestricted() bool {
+	return category == AgeChild || category == AgeMinor
+}
+
+type AgeRule struct {
+	ChildAge int
+	AdultAge int
+}
+
+var countryAgeRules = map[string]AgeRule{
+	"AT": {ChildAge: 14, AdultAge: 18},
+	"DE": {ChildAge: 16, AdultAge: 18},
+	"ES": {ChildAge: 14, AdultAge: 18},
+	"FR": {ChildAge: 15, AdultAge: 18},
+	"GB": {ChildAge: 13, AdultAge: 18},
+	"IE": {ChildAge: 16, AdultAge: 18},
+	"IT": {ChildAge: 14, AdultAge: 18},
+	"KR": {ChildAge: 14, AdultAge: 19},
+	"NL": {ChildAge: 16, AdultAge: 18},
+	"US": {ChildAge: 13, AdultAge: 18},
+}
+
+type AgePolicy struct {
+	Default           AgeRule
+	AdultAgeByCountry map[string]int
+}
+
+func (policy AgePolicy) Rule(country string) AgeRule {
+	rule, ok := countryAgeRules[country]
+	if !ok {
+		rule = policy.Default
+	}
+	if adultAge, ok := policy.AdultAgeByCountry[country]; ok {
+		rule.AdultAge = adultAge
+	}
+	return rule
+}
+
+func (policy AgePolicy) Category(profile Profile, at time.Time) AgeCategory {
+	if profile.DateOfBirth == nil {
+		return AgeUnknown
+	}
+
+	rule := policy.Rule(profile.Country)
+	age := ageAt(*profile.DateOfBirth, at)
+	switch {
+	case age < rule.ChildAge:
+		return AgeChild
+	case age < rule.AdultAge:
+		return AgeMinor
+	default:
+		return AgeAdult
+	}
+}
+
+func ageAt(dateOfBirth, at time.Time) int {
+	at = at.UTC()
+	age := at.Year() - dateOfBirth.Year()
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+type ParentalControls struct {
+	UserID                UserID
+	GuardianID            *UserID
+	ExplicitContentFilter *bool
+	PINHash               string
+	FailedPINAttempts     int
+	PINLockedUntil        *time.Time
+	UpdatedAt             time.Time
+}
+
+func (controls ParentalControls) HasPIN() bool {
+	return controls.PINHash != ""
+}
+
+type ContentRestrictions struct {
+	Category              AgeCategory
+	Restricted            bool
+	ExplicitContentFilter bool
+	GuardianID            *UserID
+	PINSet                bool
+}
+
+func (policy AgePolicy) Restrictions(profile Profile, controls ParentalControls, at time.Time) ContentRestrictions {
+	category := policy.Category(profile, at)
+	restricted := category.IsRestricted()
+
+	filter := restricted
+	if controls.ExplicitContentFilter != nil {
+		filter = *controls.ExplicitContentFilter
+	}
+	// setting made when user was older, e.g. before date of birth was corrected, never unlocks content for child
+	if category == AgeChild {
+		filter = true
+	}
+
+	return ContentRestrictions{
+		Category:              category,
+		Restricted:            restricted,
+		ExplicitContentFilter: filter,
+		GuardianID:            controls.GuardianID,
+		PINSet:                controls.HasPIN(),
+	}
+}
+
+type ParentalControlsRepository interface {
+	Find(ctx context.Context, userID UserID) (ParentalControls, error)
+	Store(ctx context.Context, controls ParentalControls) error
+	Remove(ctx context.Context, userID UserID) error
+	RemoveGuardian(ctx context.Context, guardianID UserID) error
+}
+
+type PINHasher interface {
+	Hash(pin string) (string, error)
+	Matches(hash, pin string) bool
+}
+
+type ParentalControlsChanged struct {
+	UserID                UserID
+	ActorID               UserID
+	ExplicitContentFilter *bool
+	GuardianID            *UserID
+}
+
+func (e ParentalControlsChanged) ID() string {
+	return "parental_controls_changed"
+}
+
+type ParentalPINChanged struct {
+	UserID  UserID
+	ActorID UserID
+}
+
+func (e ParentalPINChanged) ID() string {
+	return "parental_pin_changed"
+}
+
+type ParentalPINFailed struct {
+	UserID         UserID
+	FailedAttempts int
+	LockedUntil    *time.Time
+}
+
+func (e ParentalPINFailed) ID() string {
+	return "parental_pin_failed"
+}
+
+type ParentalControlsUpdate struct {
+	ExplicitContentFilter *bool
+	GuardianID            *UserID
+	RemoveGuardian        bool
+}
+
+type ParentalControlsService interface {
+	GetControls(ctx context.Context, userID UserID) (ParentalControls, error)
+	GetRestrictions(ctx context.Context, userID UserID) (ContentRestrictions, error)
+	SetPIN(ctx context.Context, userID, actorID UserID, currentPIN, newPIN string) error
+	Update(ctx context.Context, userID, actorID UserID, pin string, update ParentalControlsUpdate) (ParentalControls, error)
+	AuthorizeProfileChange(ctx context.Context, userID, actorID UserID, pin string, changesAge bool) error
+	// RecordPINFailure must be called in own unit of work after ErrParentalPINMismatch, so failure survives rollback
+	RecordPINFailure(ctx context.Context, userID UserID) error
+}
+
+func NewParentalControlsService(
+	userRepository UserRepository,
+	profileRepository ProfileRepository,
+	controlsRepository ParentalControlsRepository,
+	pinHasher PINHasher,
+	policy AgePolicy,
+	dispatcher EventDispatcher,
+) ParentalControlsService {
+	return &parentalControlsService{
+		userRepo:     userRepository,
+		profileRepo:  profileRepository,
+		controlsRepo: controlsRepository,
+		pinHasher:    pinHasher,
+		policy:       policy,
+		dispatcher:   dispatcher,
+	}
+}
+
+type parentalControlsService struct {
+	userRepo     UserRepository
+	profileRepo  ProfileRepository
+	controlsRepo ParentalControlsRepository
+	pinHasher    PINHasher
+	policy       AgePolicy
+	dispatcher   EventDispatcher
+}
+
+func (service *parentalControlsService) GetControls(ctx context.Context, userID UserID) (ParentalControls, error) {
+	_, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return ParentalControls{}, err
+	}
+	return service.findControls(ctx, userID)
+}
+
+func (service *parentalControlsService) GetRestrictions(ctx context.Context, userID UserID) (ContentRestrictions, error) {
+	controls, err := service.GetControls(ctx, userID)
+	if err != nil {
+		return ContentRestrictions{}, err
+	}
+	profile, err := service.findProfile(ctx, userID)
+	if err != nil {
+		return ContentRestrictions{}, err
+	}
+	return service.policy.Restrictions(profile, controls, time.Now()), nil
+}
+
+func (service *parentalControlsService) SetPIN(ctx context.Context, userID, actorID UserID, currentPIN, newPIN string) error {
+	if !isValidPIN(newPIN) {
+		return ErrInvalidParentalPIN
+	}
+
+	_, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	actor, err := service.findActiveUser(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	controls, err := service.findControls(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !canManageParentalControls(controls, actor) {
+		return ErrParentalControlsForbidden
+	}
+	if actorID == userID && actor.Role != Admin {
+		category, err2 := service.category(ctx, userID)
+		if err2 != nil {
+			return err2
+		}
+		if category.IsRestricted() {
+			return ErrPINSetByGuardian
+		}
+	}
+
+	if controls.HasPIN() && actor.Role != Admin {
+		err = service.checkPIN(ctx, &controls, currentPIN)
+		if err != nil {
+			return err
+		}
+	}
+
+	pinHash, err := service.pinHasher.Hash(newPIN)
+	if err != nil {
+		return err
+	}
+	controls.PINHash = pinHash
+	controls.FailedPINAttempts = 0
+	controls.PINLockedUntil = nil
+	controls.UpdatedAt = time.Now()
+	err = service.controlsRepo.Store(ctx, controls)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ParentalPINChanged{UserID: userID, ActorID: actorID})
+}
+
+func (service *parentalControlsService) Update(
+	ctx context.Context,
+	userID, actorID UserID,
+	pin string,
+	update ParentalControlsUpdate,
+) (ParentalControls, error) {
+	_, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return ParentalControls{}, err
+	}
+	actor, err := service.findActiveUser(ctx, actorID)
+	if err != nil {
+		return ParentalControls{}, err
+	}
+	controls, err := service.findControls(ctx, userID)
+	if err != nil {
+		return ParentalControls{}, err
+	}
+
+	if !canManageParentalControls(controls, actor) {
+		return ParentalControls{}, ErrParentalControlsForbidden
+	}
+
+	category, err := service.category(ctx, userID)
+	if err != nil {
+		return ParentalControls{}, err
+	}
+	if actorID == userID {
+		if !controls.HasPIN() && category.IsRestricted() {
+			return ParentalControls{}, ErrParentalPINRequired
+		}
+		err = service.checkPIN(ctx, &controls, pin)
+		if err != nil {
+			return ParentalControls{}, err
+		}
+	}
+
+	// restricted user can not pick or drop own guardian, guardian is linked by admin and handed over by current guardian
+	changesGuardian := update.RemoveGuardian || update.GuardianID != nil
+	if changesGuardian && !canChangeGuardian(controls, actor, category) {
+		return ParentalControls{}, ErrGuardianChangeForbidden
+	}
+
+	if update.RemoveGuardian {
+		controls.GuardianID = nil
+	} else if update.GuardianID != nil {
+		err = service.assertGuardian(ctx, userID, *update.GuardianID)
+		if err != nil {
+			return ParentalControls{}, err
+		}
+		guardianID := *update.GuardianID
+		controls.GuardianID = &guardianID
+	}
+	if update.ExplicitContentFilter != nil {
+		filter := *update.ExplicitContentFilter
+		if !filter && category == AgeChild {
+			return ParentalControls{}, ErrExplicitContentForChild
+		}
+		if !filter && category == AgeMinor && (controls.GuardianID == nil || *controls.GuardianID != actorID) && actor.Role != Admin {
+			return ParentalControls{}, ErrGuardianRequired
+		}
+		controls.ExplicitContentFilter = &filter
+	}
+
+	controls.UpdatedAt = time.Now()
+	err = service.controlsRepo.Store(ctx, controls)
+	if err != nil {
+		return ParentalControls{}, err
+	}
+	return controls, service.dispatcher.Dispatch(ParentalControlsChanged{
+		UserID:                userID,
+		ActorID:               actorID,
+		ExplicitContentFilter: controls.ExplicitContentFilter,
+		GuardianID:            controls.GuardianID,
+	})
+}
+
+func (service *parentalControlsService) AuthorizeProfileChange(ctx context.Context, userID, actorID UserID, pin string, changesAge bool) error {
+	_, err := service.findActiveUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	actor, err := service.findActiveUser(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	controls, err := service.findControls(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !canManageParentalControls(controls, actor) {
+		return ErrParentalControlsForbidden
+	}
+	if actorID != userID || actor.Role == Admin || !changesAge {
+		return nil
+	}
+
+	category, err := service.category(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if category.IsRestricted() || controls.GuardianID != nil {
+		return ErrAgeChangeByGuardian
+	}
+	return service.checkPIN(ctx, &controls, pin)
+}
+
+func (service *parentalControlsService) RecordPINFailure(ctx context.Context, userID UserID) error {
+	controls, err := service.findControls(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	controls.FailedPINAttempts++
+	if controls.FailedPINAttempts >= MaxFailedPINAttempts {
+		lockedUntil := time.Now().Add(PINLockoutDuration)
+		controls.PINLockedUntil = &lockedUntil
+		controls.FailedPINAttempts = 0
+	}
+	err = service.controlsRepo.Store(ctx, controls)
+	if err != nil {
+		return err
+	}
+	return service.dispatcher.Dispatch(ParentalPINFailed{
+		UserID:         userID,
+		FailedAttempts: controls.FailedPINAttempts,
+		LockedUntil:    controls.PINLockedUntil,
+	})
+}
+
+func (service *parentalControlsService) checkPIN(ctx context.Context, controls *ParentalControls, pin string) error {
+	if !controls.HasPIN() {
+		return nil
+	}
+	if controls.PINLockedUntil != nil && time.Now().Before(*controls.PINLockedUntil) {
+		return ErrParentalPINLocked
+	}
+	if !service.pinHasher.Matches(controls.PINHash, pin) {
+		return ErrParentalPINMismatch
+	}
+	if controls.FailedPINAttempts == 0 && controls.PINLockedUntil == nil {
+		return nil
+	}
+	controls.FailedPINAttempts = 0
+	controls.PINLockedUntil = nil
+	return service.controlsRepo.Store(ctx, *controls)
+}
+
+func (service *parentalControlsService) assertGuardian(ctx context.Context, userID, guardianID UserID) error {
+	if guardianID == userID {
+		return ErrGuardianIsSelf
+	}
+	_, err := service.findActiveUser(ctx, guardianID)
+	if err != nil {
+		return err
+	}
+	profile, err := service.findProfile(ctx, guardianID)
+	if err != nil {
+		return err
+	}
+	if service.policy.Category(profile, time.Now()) != AgeAdult {
+		return ErrGuardianMustBeAdult
+	}
+	return nil
+}
+
+func (service *parentalControlsService) category(ctx context.Context, userID UserID) (AgeCategory, error) {
+	profile, err := service.findProfile(ctx, userID)
+	if err != nil {
+		return AgeUnknown, err
+	}
+	return service.policy.Category(profile, time.Now()), nil
+}
+
+func (service *parentalControlsService) findControls(ctx context.Context, userID UserID) (ParentalControls, error) {
+	controls, err := service.controlsRepo.Find(ctx, userID)
+	if errors.Cause(err) == ErrParentalControlsNotFound {
+		return ParentalControls{UserID: userID}, nil
+	}
+	return controls, err
+}
+
+func (service *parentalControlsService) findProfile(ctx context.Context, userID UserID) (Profile, error) {
+	profile, err := service.profileRepo.Find(ctx, userID)
+	if errors.Cause(err) == ErrProfileNotFound {
+		return Profile{UserID: userID}, nil
+	}
+	return profile, err
+}
+
+func (service *parentalControlsService) findActiveUser(ctx context.Context, id UserID) (User, error) {
+	user, err := service.userRepo.Find(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func canManageParentalControls(controls ParentalControls, actor User) bool {
+	if actor.Role == Admin || actor.ID == controls.UserID {
+		return true
+	}
+	return controls.GuardianID != nil && *controls.GuardianID == actor.ID
+}
+
+func canChangeGuardian(controls ParentalControls, actor User, category AgeCategory) bool {
+	if actor.Role == Admin {
+		return true
+	}
+	if controls.GuardianID != nil && *controls.GuardianID == actor.ID {
+		return true
+	}
+	return actor.ID == controls.UserID && !category.IsRestricted()
+}
+
+func isValidPIN(pin string) bool {
+	if len(pin) < MinPINLength || len(pin) > MaxPINLength {
+		return false
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
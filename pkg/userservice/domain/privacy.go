@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrPrivacySettingsNotFound  = errors.New("privacy settings not found")
+	ErrInvalidProfileVisibility = errors.New("invalid profile visibility")
+	ErrInvalidFollowPolicy      = errors.New("invalid follow policy")
+	ErrFollowNotAllowed         = errors.New("user does not accept followers")
+	ErrProfileNotVisible        = errors.New("profile is not visible to user")
+)
+
+type ProfileVisibility int
+
+const (
+	ProfileVisibilityPublic ProfileVisibility = iota
+	ProfileVisibilityFollowers
+	ProfileVisibilityPrivate
+)
+
+var profileVisibilityNames = map[ProfileVisibility]string{
+	ProfileVisibilityPublic:    "public",
+	ProfileVisibilityFollowers: "followers",
+	ProfileVisibilityPrivate:   "private",
+}
+
+func (visibility ProfileVisibility) Name() string {
+	return profileVisibilityNames[visibility]
+}
+
+type FollowPolicy int
+
+const (
+	FollowPolicyEveryone FollowPolicy = iota
+	FollowPolicyNobody
+)
+
+var followPolicyNames = map[FollowPolicy]string{
+	FollowPolicyEveryone: "everyone",
+	FollowPolicyNobody:   "nobody",
+}
+
+func (policy FollowPolicy) Name() string {
+	return followPolicyNames[policy]
+}
+
+type PrivacySettings struct {
+	UserID                  UserID
+	ProfileVisibility       ProfileVisibility
+	WhoCanFollow            FollowPolicy
+	ListeningActivityPublic bool
+	UpdatedAt               time.Time
+}
+
+func DefaultPrivacySettings(userID UserID) PrivacySettings {
+	return PrivacySettings{
+		UserID:                  userID,
+		ProfileVisibility:       ProfileVisibilityPublic,
+		WhoCanFollow:            FollowPolicyEveryone,
+		ListeningActivityPublic: true,
+	}
+}
+
+type PrivacySettingsRepository interface {
+	Find(ctx context.Context, userID UserID) (PrivacySettings, error)
+	Store(ctx context.Context, settings PrivacySettings) error
+	Remove(ctx context.Context, userID UserID) error
+}
+
+type PrivacySettingsChanged struct {
+	UserID                  UserID
+	ProfileVisibility       ProfileVisibility
+	ListeningActivityPublic bool
+}
+
+func (e PrivacySettingsChanged) ID() string {
+	return "privacy_settings_changed"
+}
+
+type PrivacySettingsUpdate struct {
+	ProfileVisibility       *ProfileVisibility
+	WhoCanFollow            *FollowPolicy
+	ListeningActivityPublic *bool
+}
+
+type PrivacyService interface {
+	GetSettings(ctx context.Context, userID UserID) (PrivacySettings, error)
+	Update(ctx context.Context, userID UserID, update PrivacySettingsUpdate) (PrivacySettings, error)
+	CanViewProfile(ctx context.Context, viewerID, userID UserID) (bool, error)
+}
+
+func NewPrivacyService(
+	userRepository UserRepository,
+	privacyRepository PrivacySettingsRepository,
+	followRepository FollowRepository,
+	blockRepository BlockRepository,
+	dispatcher EventDispatcher,
+) PrivacyService {
+	return &privacyService{
+		userRepo:    userRepository,
+		privacyRepo: privacyRepository,
+		followRepo:  followRepository,
+		blockRepo:   blockRepository,
+		dispatcher:  dispatcher,
+	}
+}
+
+type privacyService struct {
+	userRepo    UserRepository
+	privacyRepo PrivacySettingsRepository
+	followRepo  FollowRepository
+	blockRepo   BlockRepository
+	dispatcher  EventDispatcher
+}
+
+func (service *privacyService) GetSettings(ctx context.Context, userID UserID) (PrivacySettings, error) {
+	user, err := service.userRepo.Find(ctx, userID)
+	if err != nil {
+		return PrivacySettings{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return PrivacySettings{}, ErrUserNotFound
+	}
+	return findPrivacySettings(ctx, service.privacyRepo, userID)
+}
+
+func (service *privacyService) Update(ctx context.Context, userID UserID, update PrivacySettingsUpdate) (PrivacySettings, error) {
+	settings, err := service.GetSettings(ctx, userID)
+	if err != nil {
+		return PrivacySettings{}, err
+	}
+
+	if update.ProfileVisibility != nil {
+		switch *update.ProfileVisibility {
+		case ProfileVisibilityPublic, ProfileVisibilityFollowers, ProfileVisibilityPrivate:
+		default:
+			return PrivacySettings{}, ErrInvalidProfileVisibility
+		}
+		settings.ProfileVisibility = *update.ProfileVisibility
+	}
+	if update.WhoCanFollow != nil {
+		switch *update.WhoCanFollow {
+		case FollowPolicyEveryone, FollowPolicyNobody:
+		default:
+			return PrivacySettings{}, ErrInvalidFollowPolicy
+		}
+		settings.WhoCanFollow = *update.WhoCanFollow
+	}
+	if update.ListeningActivityPublic != nil {
+		settings.ListeningActivityPublic = *update.ListeningActivityPublic
+	}
+	settings.UpdatedAt = time.Now()
+
+	err = service.privacyRepo.Store(ctx, settings)
+	if err != nil {
+		return PrivacySettings{}, err
+	}
+	return settings, service.dispatcher.Dispatch(PrivacySettingsChanged{
+		UserID:                  userID,
+		ProfileVisibility:       settings.ProfileVisibility,
+		ListeningActivityPublic: settings.ListeningActivityPublic,
+	})
+}
+
+func (service *privacyService) CanViewProfile(ctx context.Context, viewerID, userID UserID) (bool, error) {
+	if viewerID == userID {
+		return true, nil
+	}
+	blocked, err := service.blockRepo.IsBlockedEitherWay(ctx, viewerID, userID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	settings, err := service.GetSettings(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	switch settings.ProfileVisibility {
+	case ProfileVisibilityPublic:
+		return true, nil
+	case ProfileVisibilityFollowers:
+		_, err = service.followRepo.Find(ctx, viewerID, UserFollowTarget(userID))
+		if errors.Cause(err) == ErrFollowNotFound {
+			return false, nil
+		}
+		return err == nil, err
+	default:
+		return false, nil
+	}
+}
+
+func findPrivacySettings(ctx context.Context, repo PrivacySettingsRepository, userID UserID) (PrivacySettings, error) {
+	settings, err := repo.Find(ctx, userID)
+	if errors.Cause(err) == ErrPrivacySettingsNotFound {
+		return DefaultPrivacySettings(userID), nil
+	}
+	return settings, err
+}
@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+const (
+	MaxDisplayNameLength = 64
+	MaxAvatarURLLength   = 512
+	MaxBioLength         = 1000
+)
+
+var (
+	ErrProfileNotFound    = errors.New("profile not found")
+	ErrInvalidDisplayName = errors.New("display name must be at most 64 printable characters")
+	ErrInvalidAvatarURL   = errors.New("avatar url must be absolute https url")
+	ErrInvalidCountry     = errors.New("country must be ISO 3166-1 alpha-2 code")
+	ErrInvalidLanguage    = errors.New("language must be BCP 47 tag")
+	ErrInvalidDateOfBirth = errors.New("date of birth must be in the past")
+	ErrBioTooLong         = errors.New("bio is too long")
+)
+
+type ProfileField string
+
+const (
+	ProfileDisplayName ProfileField = "display_name"
+	ProfileAvatarURL   ProfileField = "avatar_url"
+	ProfileCountry     ProfileField = "country"
+	ProfileLanguage    ProfileField = "language"
+	ProfileDateOfBirth ProfileField = "date_of_birth"
+	ProfileBio         ProfileField = "bio"
+)
+
+type Profile struct {
+	UserID      UserID
+	DisplayName string
+	AvatarURL   string
+	Country     string
+	Language    string
+	DateOfBirth *time.Time
+	Bio         string
+	UpdatedAt   time.Time
+}
+
+type ProfileUpdated struct {
+	UserID UserID
+	Fields []ProfileField
+}
+
+func (e ProfileUpdated) ID() string {
+	return "profile_updated"
+}
+
+type ProfileRepository interface {
+	Find(ctx context.Context, userID UserID) (Profile, error)
+	Store(ctx context.Context, profile Profile) error
+	Remove(ctx context.Context, userID UserID) error
+}
+
+type ProfileUpdate struct {
+	Fields      []ProfileField
+	DisplayName string
+	AvatarURL   string
+	Country     string
+	Language    string
+	DateOfBirth *time.Time
+	Bio         string
+}
+
+type ProfileService interface {
+	GetProfile(ctx context.Context, userID UserID) (Profile, error)
+	UpdateProfile(ctx context.Context, userID UserID, update ProfileUpdate) (Profile, error)
+}
+
+func NewProfileService(userRepository UserRepository, profileRepository ProfileRepository, dispatcher EventDispatcher) ProfileService {
+	return &profileService{
+		userRepo:    userRepository,
+		profileRepo: profileRepository,
+		dispatcher:  dispatcher,
+	}
+}
+
+type profileService struct {
+	userRepo    UserRepository
+	profileRepo ProfileRepository
+	dispatcher  EventDispatcher
+}
+
+func (service *profileService) GetProfile(ctx context.Context, userID UserID) (Profile, error) {
+	user, err := service.userRepo.Find(ctx, userID)
+	if err != nil {
+		return Profile{}, err
+	}
+	if user.IsDeleted() || user.IsErased() {
+		return Profile{}, ErrUserNotFound
+	}
+
+	profile, err := service.profileRepo.Find(ctx, userID)
+	if errors.Cause(err) == ErrProfileNotFound {
+		return Profile{UserID: userID}, nil
+	}
+	return profile, err
+}
+
+func (service *profileService) UpdateProfile(ctx context.Context, userID UserID, update ProfileUpdate) (Profile, error) {
+	profile, err := service.GetProfile(ctx, userID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	for _, field := range update.Fields {
+		err = applyProfileField(&profile, field, update)
+		if err != nil {
+			return Profile{}, err
+		}
+	}
+
+	profile.UpdatedAt = time.Now()
+	err = service.profileRepo.Store(ctx, profile)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	return profile, service.dispatcher.Dispatch(ProfileUpdated{
+		UserID: userID,
+		Fields: update.Fields,
+	})
+}
+
+func applyProfileField(profile *Profile, field ProfileField, update ProfileUpdate) error {
+	switch field {
+	case ProfileDisplayName:
+		displayName := strings.TrimSpace(update.DisplayName)
+		if displayName != "" && !isValidDisplayName(displayName) {
+			return ErrInvalidDisplayName
+		}
+		profile.DisplayName = displayName
+	case ProfileAvatarURL:
+		if update.AvatarURL != "" && !isValidAvatarURL(update.AvatarURL) {
+			return ErrInvalidAvatarURL
+		}
+		profile.AvatarURL = update.AvatarURL
+	case ProfileCountry:
+		country, err := normalizeCountry(update.Country)
+		if err != nil {
+			return err
+		}
+		profile.Country = country
+	case ProfileLanguage:
+		lang, err := normalizeLanguage(update.Language)
+		if err != nil {
+			return err
+		}
+		profile.Language = lang
+	case ProfileDateOfBirth:
+		if update.DateOfBirth != nil && !update.DateOfBirth.Before(time.Now()) {
+			return ErrInvalidDateOfBirth
+		}
+		profile.DateOfBirth = truncateToDate(update.DateOfBirth)
+	case ProfileBio:
+		if utf8.RuneCountInString(update.Bio) > MaxBioLength {
+			return ErrBioTooLong
+		}
+		profile.Bio = update.Bio
+	default:
+		return errors.Errorf("unknown profile field %s", field)
+	}
+	return nil
+}
+
+func isValidDisplayName(displayName string) bool {
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameLength {
+		return false
+	}
+	for _, r := range displayName {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isValidAvatarURL(avatarURL string) bool {
+	if len(avatarURL) > MaxAvatarURLLength {
+		return false
+	}
+	u, err := url.Parse(avatarURL)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+func normalizeCountry(country string) (string, error) {
+	if country == "" {
+		return "", nil
+	}
+	if len(country) != 2 {
+		return "", ErrInvalidCountry
+	}
+	region, err := language.ParseRegion(country)
+	if err != nil || !region.IsCountry() {
+		return "", ErrInvalidCountry
+	}
+	return region.String(), nil
+}
+
+func normalizeLanguage(lang string) (string, error) {
+	if lang == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return "", ErrInvalidLanguage
+	}
+	return tag.String(), nil
+}
+
+func truncateToDate(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	year, month, day := t.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &date
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type Session struct {
+	TokenHash string
+	UserID    UserID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type SessionRepository interface {
+	Store(ctx context.Context, session Session) error
+	Remove(ctx context.Context, tokenHash string) error
+	RemoveExpired(ctx context.Context, before time.Time) error
+	RemoveByUser(ctx context.Context, userID UserID) error
+}
@@ -0,0 +1,269 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type SubscriptionPlan int
+
+const (
+	FreePlan SubscriptionPlan = iota
+	PremiumPlan
+	FamilyPlan
+	StudentPlan
+)
+
+var planNames = map[SubscriptionPlan]string{
+	FreePlan:    "free",
+	PremiumPlan: "premium",
+	FamilyPlan:  "family",
+	StudentPlan: "student",
+}
+
+func (plan SubscriptionPlan) Name() string {
+	return planNames[plan]
+}
+
+type SubscriptionStatus int
+
+const (
+	SubscriptionActive SubscriptionStatus = iota
+	SubscriptionPastDue
+	SubscriptionCanceled
+	SubscriptionExpired
+)
+
+var subscriptionStatusNames = map[SubscriptionStatus]string{
+	SubscriptionActive:   "active",
+	SubscriptionPastDue:  "past_due",
+	SubscriptionCanceled: "canceled",
+	SubscriptionExpired:  "expired",
+}
+
+func (status SubscriptionStatus) Name() string {
+	return subscriptionStatusNames[status]
+}
+
+type Entitlement string
+
+const (
+	EntitlementStreaming        Entitlement = "streaming"
+	EntitlementAdFree           Entitlement = "ad_free"
+	EntitlementOfflineDownloads Entitlement = "offline_downloads"
+	EntitlementHighBitrate      Entitlement = "high_bitrate"
+	EntitlementUnlimitedSkips   Entitlement = "unlimited_skips"
+	EntitlementFamilyManagement Entitlement = "family_management"
+)
+
+var planEntitlements = map[SubscriptionPlan][]Entitlement{
+	FreePlan: {EntitlementStreaming},
+	PremiumPlan: {
+		EntitlementStreaming,
+		EntitlementAdFree,
+		EntitlementOfflineDownloads,
+		EntitlementHighBitrate,
+		EntitlementUnlimitedSkips,
+	},
+	FamilyPlan: {
+		EntitlementStreaming,
+		EntitlementAdFree,
+		EntitlementOfflineDownloads,
+		EntitlementHighBitrate,
+		EntitlementUnlimitedSkips,
+		EntitlementFamilyManagement,
+	},
+	StudentPlan: {
+		EntitlementStreaming,
+		EntitlementAdFree,
+		EntitlementOfflineDownloads,
+		EntitlementHighBitrate,
+		EntitlementUnlimitedSkips,
+	},
+}
+
+var (
+	ErrSubscriptionNotFound      = errors.New("subscription not found")
+	ErrInvalidSubscriptionPlan   = errors.New("invalid subscription plan")
+	ErrInvalidSubscriptionStatus = errors.New("invalid subscription status")
+	ErrInvalidBillingPeriod      = errors.New("paid plan requires billing period that ends after it starts")
+	ErrTrialAlreadyUsed          = errors.New("user has already used trial")
+	ErrTrialOnFreePlan           = errors.New("free plan can not be trial")
+)
+
+type Subscription struct {
+	UserID      UserID
+	Plan        SubscriptionPlan
+	Status      SubscriptionStatus
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	Trial       bool
+	TrialUsed   bool
+	UpdatedAt   time.Time
+}
+
+func (subscription Subscription) EffectivePlan(at time.Time) SubscriptionPlan {
+	if subscription.Plan == FreePlan || subscription.Status == SubscriptionExpired {
+		return FreePlan
+	}
+	if subscription.PeriodEnd == nil || !at.Before(*subscription.PeriodEnd) {
+		return FreePlan
+	}
+	return subscription.Plan
+}
+
+func (subscription Subscription) Entitlements(at time.Time) []Entitlement {
+	return PlanEntitlements(subscription.EffectivePlan(at))
+}
+
+func PlanEntitlements(plan SubscriptionPlan) []Entitlement {
+	entitlements := planEntitlements[plan]
+	result := make([]Entitlement, len(entitlements))
+	copy(result, entitlements)
+	return result
+}
+
+type EffectiveSubscription struct {
+	Plan              SubscriptionPlan
+	Entitlements      []Entitlement
+	ValidUntil        *time.Time
+	Trial             bool
+	SharedByHousehold bool
+}
+
+func ResolveEffectiveSubscription(own Subscription, householdManager *Subscription, at time.Time) EffectiveSubscription {
+	plan := own.EffectivePlan(at)
+	if plan == FreePlan && householdManager != nil && householdManager.EffectivePlan(at) == FamilyPlan {
+		return EffectiveSubscription{
+			Plan:              FamilyPlan,
+			Entitlements:      HouseholdMemberEntitlements(),
+			ValidUntil:        householdManager.PeriodEnd,
+			Trial:             householdManager.Trial,
+			SharedByHousehold: true,
+		}
+	}
+
+	result := EffectiveSubscription{Plan: plan, Entitlements: PlanEntitlements(plan)}
+	if plan != FreePlan {
+		result.ValidUntil = own.PeriodEnd
+		result.Trial = own.Trial
+	}
+	return result
+}
+
+type SubscriptionRepository interface {
+	Find(ctx context.Context, userID UserID) (Subscription, error)
+	Store(ctx context.Context, subscription Subscription) error
+	Remove(ctx context.Context, userID UserID) error
+}
+
+type SubscriptionChanged struct {
+	UserID UserID
+	Plan   SubscriptionPlan
+	Status SubscriptionStatus
+	Trial  bool
+}
+
+func (e SubscriptionChanged) ID() string {
+	return "subscription_changed"
+}
+
+type SubscriptionUpdate struct {
+	Plan        SubscriptionPlan
+	Status      SubscriptionStatus
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	Trial       bool
+}
+
+type SubscriptionService interface {
+	GetSubscription(ctx context.Context, userID UserID) (Subscription, error)
+	SetSubscription(ctx context.Context, userID UserID, update SubscriptionUpdate) (Subscription, error)
+}
+
+func NewSubscriptionService(userRepository UserRepository, subscriptionRepository SubscriptionRepository, dispatcher EventDispatcher) SubscriptionService {
+	return &subscriptionService{
+		userRepo:         userRepository,
+		subscriptionRepo: subscriptionRepository,
+		dispatcher:       dispatcher,
+	}
+}
+
+type subscriptionService struct {
+	userRepo         UserRepository
+	subscriptionRepo SubscriptionRepository
+	dispatcher       EventDispatcher
+}
+
+func (service *subscriptionService) GetSubscription(ctx context.Context, userID UserID) (Subscription, error) {
+	user, err := service.userRepo.Find(ctx, userID)
+	if err != nil {
+		return Subscription{}, err
+	}
+	if user.IsErased() {
+		return Subscription{}, ErrUserNotFound
+	}
+
+	subscription, err := service.subscriptionRepo.Find(ctx, userID)
+	if errors.Cause(err) == ErrSubscriptionNotFound {
+		return Subscription{UserID: userID, Plan: FreePlan, Status: SubscriptionActive}, nil
+	}
+	return subscription, err
+}
+
+func (service *subscriptionService) SetSubscription(ctx context.Context, userID UserID, update SubscriptionUpdate) (Subscription, error) {
+	err := validateSubscriptionUpdate(update)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	subscription, err := service.GetSubscription(ctx, userID)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if update.Trial && subscription.TrialUsed && !subscription.Trial {
+		return Subscription{}, ErrTrialAlreadyUsed
+	}
+
+	subscription.Plan = update.Plan
+	subscription.Status = update.Status
+	subscription.PeriodStart = update.PeriodStart
+	subscription.PeriodEnd = update.PeriodEnd
+	subscription.Trial = update.Trial
+	subscription.TrialUsed = subscription.TrialUsed || update.Trial
+	subscription.UpdatedAt = time.Now()
+
+	err = service.subscriptionRepo.Store(ctx, subscription)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	return subscription, service.dispatcher.Dispatch(SubscriptionChanged{
+		UserID: userID,
+		Plan:   subscription.Plan,
+		Status: subscription.Status,
+		Trial:  subscription.Trial,
+	})
+}
+
+func validateSubscriptionUpdate(update SubscriptionUpdate) error {
+	if _, ok := planEntitlements[update.Plan]; !ok {
+		return ErrInvalidSubscriptionPlan
+	}
+	if update.Status < SubscriptionActive || update.Status > SubscriptionExpired {
+		return ErrInvalidSubscriptionStatus
+	}
+	if update.Plan == FreePlan {
+		if update.Trial {
+			return ErrTrialOnFreePlan
+		}
+		return nil
+	}
+	if update.PeriodStart == nil || update.PeriodEnd == nil || !update.PeriodEnd.After(*update.PeriodStart) {
+		return ErrInvalidBillingPeriod
+	}
+	return nil
+}
@@ -1,6 +1,11 @@
 package domain
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 )
@@ -10,26 +15,88 @@ type Role int
 const (
 	Listener Role = iota
 	Creator
+	Admin
 )
 
+var roleNames = map[Role]string{
+	Listener: "listener",
+	Creator:  "creator",
+	Admin:    "admin",
+}
+
+func (role Role) Name() string {
+	return roleNames[role]
+}
+
+func RoleByName(name string) (Role, bool) {
+	for role, roleName := range roleNames {
+		if roleName == name {
+			return role, true
+		}
+	}
+	return 0, false
+}
+
 type UserID uuid.UUID
 
 type User struct {
-	ID       UserID
-	Email    string
-	Password string
+	ID                UserID
+	Email             string
+	Password          string
+	PasswordAlgorithm string
 	Role
+
+	FailedLoginAttempts int
+	LockedUntil         *time.Time
+
+	DeletedAt *time.Time
+	// ErasedAt is set when personal data was scrubbed, record itself stays to keep references to user valid
+	ErasedAt *time.Time
+}
+
+func (user User) IsDeleted() bool {
+	return user.DeletedAt != nil
+}
+
+func (user User) IsErased() bool {
+	return user.ErasedAt != nil
+}
+
+// NormalizeEmail is applied to every stored and looked up email, so emails differing in case or surrounding spaces match
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ErasedEmail is pseudonymous tombstone that keeps email unique without revealing original one
+func ErasedEmail(id UserID) string {
+	return fmt.Sprintf("erased-%s@erased.invalid", uuid.UUID(id).String())
+}
+
+func (user User) IsLocked(at time.Time) bool {
+	return user.LockedUntil != nil && at.Before(*user.LockedUntil)
+}
+
+type LockoutPolicy struct {
+	MaxFailedAttempts int
+	Duration          time.Duration
 }
 
 var (
 	ErrUserNotFound               = errors.New("user not found")
 	ErrUserWithEmailAlreadyExists = errors.New("user with email already exists")
+	ErrUserNotDeleted             = errors.New("user is not deleted")
+	ErrUserAlreadyErased          = errors.New("user is already erased")
 )
 
 type UserRepository interface {
 	NewID() UserID
-	Find(id UserID) (User, error)
-	FindByEmail(email string) (User, error)
-	Store(user User) error
-	Remove(id UserID) error
+	Find(ctx context.Context, id UserID) (User, error)
+	FindByEmail(ctx context.Context, email string) (User, error)
+	// Add fails with ErrUserWithEmailAlreadyExists, Store only updates existing user
+	Add(ctx context.Context, user User) error
+	Store(ctx context.Context, user User) error
+	// IncrementFailedLoginAttempts increments counter in place, so concurrent failures are all counted, and returns new value
+	IncrementFailedLoginAttempts(ctx context.Context, id UserID) (int, error)
+	Remove(ctx context.Context, id UserID) error
+	FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]UserID, error)
 }
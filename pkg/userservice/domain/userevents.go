@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+type UserCreated struct {
+	UserID UserID
+	Email  string
+	Role   Role
+}
+
+func (e UserCreated) ID() string {
+	return "user_created"
+}
+
+type UserRoleChanged struct {
+	UserID UserID
+	Role   Role
+}
+
+func (e UserRoleChanged) ID() string {
+	return "user_role_changed"
+}
+
+type UserEmailChanged struct {
+	UserID   UserID
+	OldEmail string
+	NewEmail string
+}
+
+func (e UserEmailChanged) ID() string {
+	return "user_email_changed"
+}
+
+type UserRemoved struct {
+	UserID UserID
+}
+
+func (e UserRemoved) ID() string {
+	return "user_removed"
+}
+
+type UserPasswordChanged struct {
+	UserID UserID
+}
+
+func (e UserPasswordChanged) ID() string {
+	return "user_password_changed"
+}
+
+type UserLoginFailed struct {
+	UserID         UserID
+	FailedAttempts int
+	LockedUntil    *time.Time
+}
+
+func (e UserLoginFailed) ID() string {
+	return "user_login_failed"
+}
+
+type UserLoggedIn struct {
+	UserID UserID
+}
+
+func (e UserLoggedIn) ID() string {
+	return "user_logged_in"
+}
+
+type UserLoginFailuresReset struct {
+	UserID UserID
+}
+
+func (e UserLoginFailuresReset) ID() string {
+	return "user_login_failures_reset"
+}
+
+type UserUnlocked struct {
+	UserID UserID
+}
+
+func (e UserUnlocked) ID() string {
+	return "user_unlocked"
+}
+
+type UserRestored struct {
+	UserID UserID
+}
+
+func (e UserRestored) ID() string {
+	return "user_restored"
+}
+
+type UserPurged struct {
+	UserID UserID
+}
+
+func (e UserPurged) ID() string {
+	return "user_purged"
+}
+
+type UserErased struct {
+	UserID UserID
+}
+
+func (e UserErased) ID() string {
+	return "user_erased"
+}
@@ -1,43 +1,312 @@
 package domain
 
+import (
+	"context"
+	"time"
+)
+
 type UserService interface {
-	AddUser(email, password string, role Role) (UserID, error)
+	AddUser(ctx context.Context, email, password, passwordAlgorithm string, role Role) (UserID, error)
+	ChangeRole(ctx context.Context, id UserID, role Role) error
+	ChangeEmail(ctx context.Context, id UserID, email string) error
+	RemoveUser(ctx context.Context, id UserID) error
+	RestoreUser(ctx context.Context, id UserID) error
+	PurgeUser(ctx context.Context, id UserID, deletedBefore time.Time) error
+	EraseUser(ctx context.Context, id UserID) error
+	ChangePassword(ctx context.Context, id UserID, password, passwordAlgorithm string) error
+	RegisterLoginFailure(ctx context.Context, id UserID, policy LockoutPolicy) error
+	RegisterLoginSuccess(ctx context.Context, id UserID) error
+	Unlock(ctx context.Context, id UserID) error
 }
 
-func NewUserService(repository UserRepository) UserService {
+func NewUserService(repository UserRepository, dispatcher EventDispatcher) UserService {
 	return &userService{
-		repo: repository,
+		repo:       repository,
+		dispatcher: dispatcher,
 	}
 }
 
 type userService struct {
-	repo UserRepository
+	repo       UserRepository
+	dispatcher EventDispatcher
 }
 
-func (service *userService) AddUser(email, password string, role Role) (UserID, error) {
-	user, err := service.repo.FindByEmail(email)
+func (service *userService) AddUser(ctx context.Context, email, password, passwordAlgorithm string, role Role) (UserID, error) {
+	email = NormalizeEmail(email)
+	err := service.assertEmailIsFree(ctx, email)
+	if err != nil {
+		return UserID{}, err
+	}
+
+	user := User{
+		ID:                service.repo.NewID(),
+		Email:             email,
+		Password:          password,
+		PasswordAlgorithm: passwordAlgorithm,
+		Role:              role,
+	}
+	err = service.repo.Add(ctx, user)
 	if err != nil {
-		if err != ErrUserNotFound {
-			return UserID{}, err
+		return UserID{}, err
+	}
+
+	return user.ID, service.dispatcher.Dispatch(UserCreated{
+		UserID: user.ID,
+		Email:  user.Email,
+		Role:   user.Role,
+	})
+}
+
+func (service *userService) ChangeRole(ctx context.Context, id UserID, role Role) error {
+	user, err := service.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == role {
+		return nil
+	}
+
+	user.Role = role
+	err = service.repo.Store(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserRoleChanged{
+		UserID: user.ID,
+		Role:   user.Role,
+	})
+}
+
+func (service *userService) ChangeEmail(ctx context.Context, id UserID, email string) error {
+	email = NormalizeEmail(email)
+	user, err := service.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.Email == email {
+		return nil
+	}
+
+	err = service.assertEmailIsFree(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	oldEmail := user.Email
+	user.Email = email
+	err = service.repo.Store(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserEmailChanged{
+		UserID:   user.ID,
+		OldEmail: oldEmail,
+		NewEmail: user.Email,
+	})
+}
+
+func (service *userService) RemoveUser(ctx context.Context, id UserID) error {
+	user, err := service.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.DeletedAt = &now
+	err = service.repo.Store(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserRemoved{UserID: id})
+}
+
+func (service *userService) RestoreUser(ctx context.Context, id UserID) error {
+	user, err := service.repo.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.IsErased() {
+		return ErrUserAlreadyErased
+	}
+	if !user.IsDeleted() {
+		return ErrUserNotDeleted
+	}
+
+	user.DeletedAt = nil
+	err = service.repo.Store(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserRestored{UserID: id})
+}
+
+func (service *userService) PurgeUser(ctx context.Context, id UserID, deletedBefore time.Time) error {
+	user, err := service.repo.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsDeleted() || user.DeletedAt.After(deletedBefore) {
+		return ErrUserNotDeleted
+	}
+
+	err = service.repo.Remove(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserPurged{UserID: id})
+}
+
+func (service *userService) ChangePassword(ctx context.Context, id UserID, password, passwordAlgorithm string) error {
+	user, err := service.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	user.Password = password
+	user.PasswordAlgorithm = passwordAlgorithm
+	err = service.repo.Store(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserPasswordChanged{UserID: user.ID})
+}
+
+func (service *userService) RegisterLoginFailure(ctx context.Context, id UserID, policy LockoutPolicy) error {
+	user, err := service.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil
+	}
+	if user.LockedUntil != nil {
+		user.LockedUntil = nil
+		user.FailedLoginAttempts = 0
+		err = service.repo.Store(ctx, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	user.FailedLoginAttempts, err = service.repo.IncrementFailedLoginAttempts(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	// lockout is disabled unless policy sets limit, anyone knowing email could lock user out otherwise
+	if policy.MaxFailedAttempts > 0 && user.FailedLoginAttempts >= policy.MaxFailedAttempts {
+		lockedUntil := now.Add(policy.Duration)
+		user.LockedUntil = &lockedUntil
+		err = service.repo.Store(ctx, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	return service.dispatcher.Dispatch(UserLoginFailed{
+		UserID:         user.ID,
+		FailedAttempts: user.FailedLoginAttempts,
+		LockedUntil:    user.LockedUntil,
+	})
+}
+
+func (service *userService) RegisterLoginSuccess(ctx context.Context, id UserID) error {
+	user, err := service.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.FailedLoginAttempts > 0 {
+		user.FailedLoginAttempts = 0
+		err = service.repo.Store(ctx, user)
+		if err != nil {
+			return err
 		}
 
-		// user found
-		if user.Email == email {
-			return UserID{}, ErrUserWithEmailAlreadyExists
+		err = service.dispatcher.Dispatch(UserLoginFailuresReset{UserID: user.ID})
+		if err != nil {
+			return err
 		}
 	}
 
-	user = User{
-		ID:       service.repo.NewID(),
-		Email:    email,
-		Password: password,
-		Role:     role,
+	return service.dispatcher.Dispatch(UserLoggedIn{UserID: user.ID})
+}
+
+func (service *userService) Unlock(ctx context.Context, id UserID) error {
+	user, err := service.findActive(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.LockedUntil == nil && user.FailedLoginAttempts == 0 {
+		return nil
 	}
-	err = service.repo.Store(user)
 
+	user.LockedUntil = nil
+	user.FailedLoginAttempts = 0
+	err = service.repo.Store(ctx, user)
 	if err != nil {
-		return UserID{}, err
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserUnlocked{UserID: user.ID})
+}
+
+func (service *userService) EraseUser(ctx context.Context, id UserID) error {
+	user, err := service.repo.Find(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	return user.ID, nil
+	if user.IsErased() {
+		return ErrUserAlreadyErased
+	}
+
+	now := time.Now()
+	user.Email = ErasedEmail(user.ID)
+	user.Password = ""
+	user.PasswordAlgorithm = ""
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	user.ErasedAt = &now
+	err = service.repo.Store(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return service.dispatcher.Dispatch(UserErased{UserID: user.ID})
+}
+
+// findActive hides deleted users from every operation except restore and purge
+func (service *userService) findActive(ctx context.Context, id UserID) (User, error) {
+	user, err := service.repo.Find(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if user.IsDeleted() {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (service *userService) assertEmailIsFree(ctx context.Context, email string) error {
+	_, err := service.repo.FindByEmail(ctx, email)
+	if err == nil {
+		return ErrUserWithEmailAlreadyExists
+	}
+	if err != ErrUserNotFound {
+		return err
+	}
+	return nil
 }
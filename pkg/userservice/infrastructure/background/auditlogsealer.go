@@ -0,0 +1,57 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+type AuditLogSealer interface {
+	Run() error
+	Stop() error
+}
+
+func NewAuditLogSealer(auditService service.AuditService, interval time.Duration, logger log.Logger) AuditLogSealer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &auditLogSealer{
+		auditService: auditService,
+		interval:     interval,
+		logger:       logger,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+type auditLogSealer struct {
+	auditService service.AuditService
+	interval     time.Duration
+	logger       log.Logger
+	ctx          context.Context
+	cancel       context.CancelFunc
+}
+
+func (sealer *auditLogSealer) Run() error {
+	ticker := time.NewTicker(sealer.interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := sealer.auditService.SealPending(sealer.ctx)
+		if err != nil && sealer.ctx.Err() == nil {
+			sealer.logger.Error(err, "failed to seal audit log entries")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-sealer.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (sealer *auditLogSealer) Stop() error {
+	sealer.cancel()
+	return nil
+}
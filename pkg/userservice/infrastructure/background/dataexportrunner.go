@@ -0,0 +1,82 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+const expiredDataExportsBatchSize = 100
+
+type DataExportRunner interface {
+	Run() error
+	Stop() error
+}
+
+func NewDataExportRunner(dataExportService service.DataExportService, interval time.Duration, logger log.Logger) DataExportRunner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &dataExportRunner{
+		dataExportService: dataExportService,
+		interval:          interval,
+		logger:            logger,
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+}
+
+type dataExportRunner struct {
+	dataExportService service.DataExportService
+	interval          time.Duration
+	logger            log.Logger
+	ctx               context.Context
+	cancel            context.CancelFunc
+}
+
+func (runner *dataExportRunner) Run() error {
+	ticker := time.NewTicker(runner.interval)
+	defer ticker.Stop()
+
+	for {
+		runner.processPending()
+		runner.removeExpired()
+
+		select {
+		case <-ticker.C:
+		case <-runner.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (runner *dataExportRunner) Stop() error {
+	runner.cancel()
+	return nil
+}
+
+func (runner *dataExportRunner) processPending() {
+	for runner.ctx.Err() == nil {
+		processed, err := runner.dataExportService.ProcessPending(runner.ctx)
+		if err != nil && runner.ctx.Err() == nil {
+			runner.logger.Error(err, "failed to process data export")
+		}
+		if !processed {
+			return
+		}
+	}
+}
+
+func (runner *dataExportRunner) removeExpired() {
+	removed, err := runner.dataExportService.RemoveExpired(runner.ctx, expiredDataExportsBatchSize)
+	if err != nil {
+		if runner.ctx.Err() == nil {
+			runner.logger.Error(err, "failed to remove expired data exports")
+		}
+		return
+	}
+	if removed > 0 {
+		runner.logger.WithField("removed", removed).Info("expired data exports removed")
+	}
+}
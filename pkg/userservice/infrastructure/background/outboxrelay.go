@@ -0,0 +1,73 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+const outboxBatchSize = 100
+
+type OutboxRelay interface {
+	Run() error
+	Stop() error
+}
+
+func NewOutboxRelay(outboxService service.OutboxService, publisher service.OutboxPublisher, interval time.Duration, logger log.Logger) OutboxRelay {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &outboxRelay{
+		outboxService: outboxService,
+		publisher:     publisher,
+		interval:      interval,
+		logger:        logger,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+type outboxRelay struct {
+	outboxService service.OutboxService
+	publisher     service.OutboxPublisher
+	interval      time.Duration
+	logger        log.Logger
+	ctx           context.Context
+	cancel        context.CancelFunc
+}
+
+func (relay *outboxRelay) Run() error {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		relay.publishPending()
+
+		select {
+		case <-ticker.C:
+		case <-relay.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (relay *outboxRelay) Stop() error {
+	relay.cancel()
+	return nil
+}
+
+func (relay *outboxRelay) publishPending() {
+	for relay.ctx.Err() == nil {
+		published, err := relay.outboxService.PublishPending(relay.ctx, relay.publisher, outboxBatchSize)
+		if err != nil {
+			if relay.ctx.Err() == nil {
+				relay.logger.Error(err, "failed to publish outbox messages")
+			}
+			return
+		}
+		if published < outboxBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package background
+
+import (
+	"context"
+	"time"
+
+	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+const purgeBatchSize = 100
+
+type UserPurgerConfig struct {
+	GracePeriod time.Duration
+	Interval    time.Duration
+}
+
+type UserPurger interface {
+	Run() error
+	Stop() error
+}
+
+func NewUserPurger(
+	userService service.UserService,
+	expiredDataPurgers []service.ExpiredDataPurger,
+	config UserPurgerConfig,
+	logger log.Logger,
+) UserPurger {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &userPurger{
+		userService:        userService,
+		expiredDataPurgers: expiredDataPurgers,
+		config:             config,
+		logger:             logger,
+		ctx:                ctx,
+		cancel:             cancel,
+	}
+}
+
+type userPurger struct {
+	userService        service.UserService
+	expiredDataPurgers []service.ExpiredDataPurger
+	config             UserPurgerConfig
+	logger             log.Logger
+	ctx                context.Context
+	cancel             context.CancelFunc
+}
+
+func (purger *userPurger) Run() error {
+	ticker := time.NewTicker(purger.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		purger.purge()
+		purger.purgeExpired()
+
+		select {
+		case <-ticker.C:
+		case <-purger.ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (purger *userPurger) Stop() error {
+	purger.cancel()
+	return nil
+}
+
+func (purger *userPurger) purge() {
+	deletedBefore := time.Now().Add(-purger.config.GracePeriod)
+	total := 0
+
+	for purger.ctx.Err() == nil {
+		purged, err := purger.userService.PurgeDeletedUsers(purger.ctx, deletedBefore, purgeBatchSize)
+		total += purged
+		if err != nil {
+			if purger.ctx.Err() == nil {
+				purger.logger.Error(err, "failed to purge deleted users")
+			}
+			break
+		}
+		if purged < purgeBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		purger.logger.WithField("purged", total).Info("deleted users purged")
+	}
+}
+
+func (purger *userPurger) purgeExpired() {
+	now := time.Now()
+	for _, expiredDataPurger := range purger.expiredDataPurgers {
+		err := expiredDataPurger.PurgeExpired(purger.ctx, now)
+		if err != nil {
+			if purger.ctx.Err() == nil {
+				purger.logger.Error(err, "failed to purge expired data")
+			}
+			return
+		}
+	}
+}
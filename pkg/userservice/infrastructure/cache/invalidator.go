@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"github.com/google/uuid"
+
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+func NewUserQueryServiceInvalidator(queryService UserQueryService) service.EventHandler {
+	return &userQueryServiceInvalidator{queryService: queryService}
+}
+
+type userQueryServiceInvalidator struct {
+	queryService UserQueryService
+}
+
+func (invalidator *userQueryServiceInvalidator) Handle(event domain.Event) {
+	switch e := event.(type) {
+	case domain.UserRoleChanged:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserEmailChanged:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserRemoved:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserPasswordChanged:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserLoginFailed:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserLoginFailuresReset:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserUnlocked:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserRestored:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserPurged:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	case domain.UserErased:
+		invalidator.queryService.Invalidate(uuid.UUID(e.UserID))
+	}
+}
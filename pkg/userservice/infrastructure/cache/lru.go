@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"container/list"
+	"time"
+)
+
+// lru is not safe for concurrent use, owner must serialize access
+type lru struct {
+	size     int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+	onRemove func(key string, value interface{})
+}
+
+type lruEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newLRU(size int, ttl time.Duration, onRemove func(key string, value interface{})) *lru {
+	return &lru{
+		size:     size,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element, size),
+		order:    list.New(),
+		now:      time.Now,
+		onRemove: onRemove,
+	}
+}
+
+func (c *lru) Get(key string) (interface{}, bool) {
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if c.now().After(entry.expiresAt) {
+		c.removeElement(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *lru) Add(key string, value interface{}) {
+	expiresAt := c.now().Add(c.ttl)
+
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+
+	for c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *lru) Remove(key string) {
+	if element, ok := c.items[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+func (c *lru) removeElement(element *list.Element) {
+	entry := element.Value.(*lruEntry)
+	c.order.Remove(element)
+	delete(c.items, entry.key)
+	if c.onRemove != nil {
+		c.onRemove(entry.key, entry.value)
+	}
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type lruStep struct {
+	add     string
+	get     string
+	remove  string
+	advance time.Duration
+}
+
+func TestLRU(t *testing.T) {
+	tests := []struct {
+		name            string
+		size            int
+		steps           []lruStep
+		expectedKeys    []string
+		expectedRemoved []string
+	}{
+		{
+			name:            "least recently added entry is evicted",
+			size:            2,
+			steps:           []lruStep{{add: "a"}, {add: "b"}, {add: "c"}},
+			expectedKeys:    []string{"b", "c"},
+			expectedRemoved: []string{"a"},
+		},
+		{
+			name:            "get makes entry recently used",
+			size:            2,
+			steps:           []lruStep{{add: "a"}, {add: "b"}, {get: "a"}, {add: "c"}},
+			expectedKeys:    []string{"a", "c"},
+			expectedRemoved: []string{"b"},
+		},
+		{
+			name:            "add of present key makes entry recently used",
+			size:            2,
+			steps:           []lruStep{{add: "a"}, {add: "b"}, {add: "a"}, {add: "c"}},
+			expectedKeys:    []string{"a", "c"},
+			expectedRemoved: []string{"b"},
+		},
+		{
+			name:            "expired entry is removed on get",
+			size:            2,
+			steps:           []lruStep{{add: "a"}, {advance: 2 * time.Minute}, {get: "a"}},
+			expectedKeys:    []string{},
+			expectedRemoved: []string{"a"},
+		},
+		{
+			name:            "add of present key extends ttl",
+			size:            2,
+			steps:           []lruStep{{add: "a"}, {advance: 50 * time.Second}, {add: "a"}, {advance: 50 * time.Second}, {get: "a"}},
+			expectedKeys:    []string{"a"},
+			expectedRemoved: []string{},
+		},
+		{
+			name:            "removed entry is reported",
+			size:            2,
+			steps:           []lruStep{{add: "a"}, {add: "b"}, {remove: "a"}, {remove: "missing"}},
+			expectedKeys:    []string{"b"},
+			expectedRemoved: []string{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Now()
+			removed := []string{}
+			c := newLRU(test.size, time.Minute, func(key string, _ interface{}) {
+				removed = append(removed, key)
+			})
+			c.now = func() time.Time { return now }
+
+			for _, step := range test.steps {
+				switch {
+				case step.add != "":
+					c.Add(step.add, step.add)
+				case step.get != "":
+					c.Get(step.get)
+				case step.remove != "":
+					c.Remove(step.remove)
+				default:
+					now = now.Add(step.advance)
+				}
+			}
+
+			keys := []string{}
+			for key := range c.items {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			if !reflect.DeepEqual(keys, test.expectedKeys) {
+				t.Errorf("expected keys %v, got %v", test.expectedKeys, keys)
+			}
+			if !reflect.DeepEqual(removed, test.expectedRemoved) {
+				t.Errorf("expected removed %v, got %v", test.expectedRemoved, removed)
+			}
+			if c.order.Len() != len(c.items) {
+				t.Errorf("order has %d entries, items have %d", c.order.Len(), len(c.items))
+			}
+		})
+	}
+}
+
+func TestLRUGet(t *testing.T) {
+	c := newLRU(1, time.Minute, nil)
+	c.Add("a", 1)
+
+	value, ok := c.Get("a")
+	if !ok || value != 1 {
+		t.Errorf("expected cached value 1, got %v, %v", value, ok)
+	}
+	_, ok = c.Get("b")
+	if ok {
+		t.Error("expected miss for unknown key")
+	}
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/metrics"
+)
+
+type Config struct {
+	Size int
+	TTL  time.Duration
+	// LoadTimeout bounds load shared by concurrent callers, it does not depend on context of any of them
+	LoadTimeout time.Duration
+}
+
+type UserQueryService interface {
+	query.UserQueryService
+	Invalidate(userID uuid.UUID)
+}
+
+func NewUserQueryService(queryService query.UserQueryService, config Config, cacheMetrics metrics.CacheMetrics) UserQueryService {
+	service := &userQueryService{
+		queryService: queryService,
+		metrics:      cacheMetrics,
+		loadTimeout:  config.LoadTimeout,
+		keysByUser:   map[uuid.UUID]map[string]struct{}{},
+	}
+	service.views = newLRU(config.Size, config.TTL, service.onRemove)
+	return service
+}
+
+type userQueryService struct {
+	queryService query.UserQueryService
+	metrics      metrics.CacheMetrics
+	views        *lru
+	group        singleflight.Group
+	loadTimeout  time.Duration
+
+	lock       sync.Mutex
+	keysByUser map[uuid.UUID]map[string]struct{}
+	generation uint64
+}
+
+func (service *userQueryService) GetUser(ctx context.Context, id uuid.UUID) (query.UserView, error) {
+	return service.get(ctx, "GetUser", "id:"+id.String(), func(loadCtx context.Context) (query.UserView, error) {
+		return service.queryService.GetUser(loadCtx, id)
+	})
+}
+
+func (service *userQueryService) GetByEmail(ctx context.Context, email string) (query.UserView, error) {
+	return service.get(ctx, "GetByEmail", "email:"+domain.NormalizeEmail(email), func(loadCtx context.Context) (query.UserView, error) {
+		return service.queryService.GetByEmail(loadCtx, email)
+	})
+}
+
+func (service *userQueryService) ListUsers(ctx context.Context, spec query.ListUsersSpec) ([]query.UserView, error) {
+	return service.queryService.ListUsers(ctx, spec)
+}
+
+func (service *userQueryService) Invalidate(userID uuid.UUID) {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	service.generation++
+	for key := range service.keysByUser[userID] {
+		service.views.Remove(key)
+	}
+}
+
+func (service *userQueryService) get(ctx context.Context, method, key string, load func(ctx context.Context) (query.UserView, error)) (query.UserView, error) {
+	service.lock.Lock()
+	value, ok := service.views.Get(key)
+	service.lock.Unlock()
+
+	if ok {
+		service.metrics.Hit(method)
+		return value.(query.UserView), nil
+	}
+	service.metrics.Miss(method)
+
+	// load is shared, so cancellation of first caller must not fail others waiting for it
+	results := service.group.DoChan(key, func() (interface{}, error) {
+		service.lock.Lock()
+		generation := service.generation
+		service.lock.Unlock()
+
+		loadCtx, cancel := context.WithTimeout(detachedContext{ctx}, service.loadTimeout)
+		defer cancel()
+		view, err := load(loadCtx)
+		if err != nil {
+			return query.UserView{}, err
+		}
+
+		service.store(key, view, generation)
+		return view, nil
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return query.UserView{}, result.Err
+		}
+		return result.Val.(query.UserView), nil
+	case <-ctx.Done():
+		return query.UserView{}, ctx.Err()
+	}
+}
+
+// store skips views loaded before any invalidation happened, otherwise a concurrent mutation could be overwritten by stale data
+func (service *userQueryService) store(key string, view query.UserView, loadedAt uint64) {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+
+	if service.generation != loadedAt {
+		return
+	}
+
+	keys, ok := service.keysByUser[view.ID]
+	if !ok {
+		keys = map[string]struct{}{}
+		service.keysByUser[view.ID] = keys
+	}
+	keys[key] = struct{}{}
+
+	service.views.Add(key, view)
+}
+
+// onRemove is called by views with lock already held
+func (service *userQueryService) onRemove(key string, value interface{}) {
+	view := value.(query.UserView)
+	keys := service.keysByUser[view.ID]
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(service.keysByUser, view.ID)
+	}
+}
+
+// detachedContext keeps values of caller, e.g. trace span, but neither its deadline nor cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
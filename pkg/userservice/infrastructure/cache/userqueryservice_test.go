@@ -0,0 +1,289 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+)
+
+var errLoadFailed = errors.New("load failed")
+
+type mockUserQueryService struct {
+	lock    sync.Mutex
+	users   map[uuid.UUID]query.UserView
+	loads   int
+	err     error
+	started chan struct{}
+	release chan struct{}
+	loadCtx context.Context
+}
+
+func newMockUserQueryService(users ...query.UserView) *mockUserQueryService {
+	service := &mockUserQueryService{users: map[uuid.UUID]query.UserView{}}
+	for _, user := range users {
+		service.users[user.ID] = user
+	}
+	return service
+}
+
+func (service *mockUserQueryService) GetUser(ctx context.Context, id uuid.UUID) (query.UserView, error) {
+	return service.load(ctx, func(user query.UserView) bool { return user.ID == id })
+}
+
+func (service *mockUserQueryService) GetByEmail(ctx context.Context, email string) (query.UserView, error) {
+	return service.load(ctx, func(user query.UserView) bool { return user.Email == domain.NormalizeEmail(email) })
+}
+
+func (service *mockUserQueryService) ListUsers(context.Context, query.ListUsersSpec) ([]query.UserView, error) {
+	return nil, nil
+}
+
+func (service *mockUserQueryService) setUser(user query.UserView) {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	service.users[user.ID] = user
+}
+
+func (service *mockUserQueryService) loadCount() int {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	return service.loads
+}
+
+func (service *mockUserQueryService) load(ctx context.Context, match func(user query.UserView) bool) (query.UserView, error) {
+	service.lock.Lock()
+	service.loads++
+	service.loadCtx = ctx
+	started, release := service.started, service.release
+	view, err := service.find(match)
+	service.lock.Unlock()
+
+	if started != nil {
+		close(started)
+		<-release
+	}
+	return view, err
+}
+
+func (service *mockUserQueryService) find(match func(user query.UserView) bool) (query.UserView, error) {
+	if service.err != nil {
+		return query.UserView{}, service.err
+	}
+	for _, user := range service.users {
+		if match(user) {
+			return user, nil
+		}
+	}
+	return query.UserView{}, domain.ErrUserNotFound
+}
+
+func (service *mockUserQueryService) block() (started <-chan struct{}, release func()) {
+	service.lock.Lock()
+	defer service.lock.Unlock()
+	startedChan, releaseChan := make(chan struct{}), make(chan struct{})
+	service.started, service.release = startedChan, releaseChan
+	return startedChan, func() {
+		service.lock.Lock()
+		service.started, service.release = nil, nil
+		service.lock.Unlock()
+		close(releaseChan)
+	}
+}
+
+type mockCacheMetrics struct{}
+
+func (mockCacheMetrics) Hit(string)  {}
+func (mockCacheMetrics) Miss(string) {}
+
+func newTestUserQueryService(queryService query.UserQueryService) UserQueryService {
+	return NewUserQueryService(queryService, Config{Size: 10, TTL: time.Minute, LoadTimeout: time.Second}, mockCacheMetrics{})
+}
+
+func TestUserQueryServiceCaching(t *testing.T) {
+	user := query.UserView{ID: uuid.New(), Email: "user@example.com", Role: query.Listener}
+
+	tests := []struct {
+		name          string
+		run           func(ctx context.Context, cache UserQueryService, source *mockUserQueryService) error
+		expectedLoads int
+		expectedRole  query.Role
+	}{
+		{
+			name: "repeated get is served from cache",
+			run: func(ctx context.Context, cache UserQueryService, _ *mockUserQueryService) error {
+				_, err := cache.GetUser(ctx, user.ID)
+				return err
+			},
+			expectedLoads: 1,
+			expectedRole:  query.Listener,
+		},
+		{
+			name: "email lookup is normalized",
+			run: func(ctx context.Context, cache UserQueryService, _ *mockUserQueryService) error {
+				_, err := cache.GetByEmail(ctx, " User@Example.com ")
+				if err != nil {
+					return err
+				}
+				_, err = cache.GetByEmail(ctx, "user@example.com")
+				return err
+			},
+			expectedLoads: 2,
+			expectedRole:  query.Listener,
+		},
+		{
+			name: "invalidation removes cached views of user",
+			run: func(ctx context.Context, cache UserQueryService, source *mockUserQueryService) error {
+				changed := user
+				changed.Role = query.Creator
+				source.setUser(changed)
+				cache.Invalidate(user.ID)
+				return nil
+			},
+			expectedLoads: 2,
+			expectedRole:  query.Creator,
+		},
+		{
+			name: "invalidation of other user keeps cached view",
+			run: func(ctx context.Context, cache UserQueryService, source *mockUserQueryService) error {
+				changed := user
+				changed.Role = query.Creator
+				source.setUser(changed)
+				cache.Invalidate(uuid.New())
+				return nil
+			},
+			expectedLoads: 1,
+			expectedRole:  query.Listener,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			source := newMockUserQueryService(user)
+			cache := newTestUserQueryService(source)
+
+			_, err := cache.GetUser(ctx, user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = test.run(ctx, cache, source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			view, err := cache.GetUser(ctx, user.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if view.Role != test.expectedRole {
+				t.Errorf("expected role %v, got %v", test.expectedRole, view.Role)
+			}
+			if source.loadCount() != test.expectedLoads {
+				t.Errorf("expected %d loads, got %d", test.expectedLoads, source.loadCount())
+			}
+		})
+	}
+}
+
+func TestUserQueryServiceDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	source := newMockUserQueryService()
+	source.err = errLoadFailed
+	cache := newTestUserQueryService(source)
+
+	id := uuid.New()
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetUser(ctx, id)
+		if errors.Cause(err) != errLoadFailed {
+			t.Fatalf("expected %v, got %v", errLoadFailed, err)
+		}
+	}
+	if source.loadCount() != 2 {
+		t.Errorf("expected 2 loads, got %d", source.loadCount())
+	}
+}
+
+func TestUserQueryServiceSkipsViewLoadedBeforeInvalidation(t *testing.T) {
+	ctx := context.Background()
+	user := query.UserView{ID: uuid.New(), Email: "user@example.com", Role: query.Listener}
+	source := newMockUserQueryService(user)
+	cache := newTestUserQueryService(source)
+
+	started, release := source.block()
+	loaded := make(chan query.UserView)
+	go func() {
+		view, _ := cache.GetUser(ctx, user.ID)
+		loaded <- view
+	}()
+	<-started
+
+	changed := user
+	changed.Role = query.Creator
+	source.setUser(changed)
+	cache.Invalidate(user.ID)
+	release()
+
+	view := <-loaded
+	if view.Role != query.Listener {
+		t.Fatalf("expected view of running load, got role %v", view.Role)
+	}
+
+	view, err := cache.GetUser(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Role != query.Creator {
+		t.Errorf("stale view loaded before invalidation was cached, got role %v", view.Role)
+	}
+	if source.loadCount() != 2 {
+		t.Errorf("expected 2 loads, got %d", source.loadCount())
+	}
+}
+
+func TestUserQueryServiceLoadIsDetachedFromCanceledCaller(t *testing.T) {
+	user := query.UserView{ID: uuid.New(), Email: "user@example.com"}
+	source := newMockUserQueryService(user)
+	cache := newTestUserQueryService(source)
+
+	started, release := source.block()
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := cache.GetUser(firstCtx, user.ID)
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan error)
+	go func() {
+		_, err := cache.GetUser(context.Background(), user.ID)
+		second <- err
+	}()
+
+	cancelFirst()
+	if err := <-firstErr; err != context.Canceled {
+		t.Fatalf("expected canceled caller to get %v, got %v", context.Canceled, err)
+	}
+
+	source.lock.Lock()
+	loadErr := source.loadCtx.Err()
+	source.lock.Unlock()
+	if loadErr != nil {
+		t.Errorf("shared load was canceled with first caller: %v", loadErr)
+	}
+
+	release()
+	if err := <-second; err != nil {
+		t.Fatalf("expected waiting caller to get view, got %v", err)
+	}
+	if source.loadCount() != 1 {
+		t.Errorf("expected single shared load, got %d", source.loadCount())
+	}
+}
@@ -1,19 +1,50 @@
 package infrastructure
 
 import (
+	"time"
+
 	commonauth "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/auth"
-	commonmysql "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
 
 	"userservice/pkg/userservice/app/auth"
 	"userservice/pkg/userservice/app/hash"
 	"userservice/pkg/userservice/app/query"
 	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/infrastructure/cache"
+	"userservice/pkg/userservice/infrastructure/metrics"
 	"userservice/pkg/userservice/infrastructure/mysql"
 	mysqlquery "userservice/pkg/userservice/infrastructure/mysql/query"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+	"userservice/pkg/userservice/infrastructure/openid"
+	"userservice/pkg/userservice/infrastructure/storage"
 )
 
 type Parameters interface {
 	HasherSalt() string
+	UserCacheSize() int
+	UserCacheTTL() time.Duration
+	UserCacheLoadTimeout() time.Duration
+	LoginMaxFailedAttempts() int
+	LoginLockoutDuration() time.Duration
+	DataExportStorageDir() string
+	DataExportTTL() time.Duration
+	TermsOfServiceVersion() string
+	PrivacyPolicyVersion() string
+	RequireTermsOnSignUp() bool
+	HouseholdMaxMembers() int
+	HouseholdAddressKey() string
+	ChildAge() int
+	AdultAge() int
+	AdultAgeByCountry() map[string]int
+	ExternalIdentityIssuers() map[string]string
+	ExternalIdentityClientIDs() map[string]string
+	ExternalIdentityClientSecrets() map[string]string
+	ExternalIdentityRedirectURL() string
+	OAuth2Issuer() string
+	OAuth2SigningKey() string
+	OAuth2AccessTokenTTL() time.Duration
+	OAuth2RefreshTokenTTL() time.Duration
+	OAuth2DeviceVerificationURL() string
+	SessionTTL() time.Duration
 }
 
 type DependencyContainer interface {
@@ -21,24 +52,175 @@ type DependencyContainer interface {
 	AuthenticationService() auth.AuthenticationService
 	UserDescriptorSerializer() commonauth.UserDescriptorSerializer
 	UserQueryService() query.UserQueryService
+	DataExportService() service.DataExportService
+	OutboxService() service.OutboxService
+	ErasureService() service.ErasureService
+	ConsentService() service.ConsentService
+	AuditService() service.AuditService
+	AuditLogQueryService() query.AuditLogQueryService
+	ProfileService() service.ProfileService
+	CreatorApplicationService() service.CreatorApplicationService
+	CreatorApplicationQueryService() query.CreatorApplicationQueryService
+	ArtistService() service.ArtistService
+	ArtistQueryService() query.ArtistQueryService
+	SubscriptionService() service.SubscriptionService
+	SubscriptionQueryService() query.SubscriptionQueryService
+	HouseholdService() service.HouseholdService
+	ParentalControlsService() service.ParentalControlsService
+	FollowService() service.FollowService
+	BlockService() service.BlockService
+	PrivacyService() service.PrivacyService
+	ExternalIdentityService() service.ExternalIdentityService
+	OAuth2Service() service.OAuth2Service
+	SessionService() service.SessionService
 }
 
-func NewDependencyContainer(client commonmysql.TransactionalClient, parameters Parameters) DependencyContainer {
+func NewDependencyContainer(client mysql.TransactionalClient, parameters Parameters, extraEventHandlers ...service.EventHandler) DependencyContainer {
 	userQueryService := userQueryService(client)
+	credentialsQueryService := userQueryService
 	hasher := hasher(parameters)
 
+	eventHandlers := []service.EventHandler{metrics.NewBusinessEventHandler()}
+	if parameters.UserCacheSize() > 0 {
+		cachedUserQueryService := cachedUserQueryService(userQueryService, parameters)
+		eventHandlers = append(eventHandlers, cache.NewUserQueryServiceInvalidator(cachedUserQueryService))
+		userQueryService = cachedUserQueryService
+	}
+
+	eventHandlers = append(eventHandlers, extraEventHandlers...)
+
+	eventHandler := service.NewCompositeEventHandler(eventHandlers...)
+	archiveStorage := storage.NewFileSystemStorage(parameters.DataExportStorageDir())
+	personalDataErasers := []service.PersonalDataEraser{
+		service.NewDataExportEraser(archiveStorage),
+		service.NewConsentEraser(),
+		service.NewProfileEraser(),
+		service.NewCreatorApplicationEraser(),
+		service.NewArtistMembershipEraser(),
+		service.NewHouseholdEraser(),
+		service.NewParentalControlsEraser(),
+		service.NewFollowEraser(),
+		service.NewBlockEraser(),
+		service.NewPrivacySettingsEraser(),
+		service.NewExternalIdentityEraser(),
+		service.NewOAuth2TokenEraser(),
+		service.NewAuditClientDataEraser(),
+		service.NewSessionEraser(),
+	}
+	userService := userService(unitOfWorkFactory(client), passwordHasher(), eventHandler, personalDataErasers, parameters)
+	consentService := service.NewConsentService(unitOfWorkFactory(client), eventHandler, consentPolicy(parameters))
+	auditService := service.NewAuditService(unitOfWorkFactory(client), eventHandler)
+	auditLogQueryService := mysqlquery.NewAuditLogQueryService(sqlclient.NewClient(client))
+	pinHasher := hash.NewPINHasher()
+	agePolicy := agePolicy(parameters)
+	profileService := service.NewProfileService(unitOfWorkFactory(client), eventHandler, pinHasher, agePolicy)
+	creatorApplicationService := service.NewCreatorApplicationService(unitOfWorkFactory(client), eventHandler)
+	creatorApplicationQueryService := mysqlquery.NewCreatorApplicationQueryService(sqlclient.NewClient(client))
+	artistService := service.NewArtistService(unitOfWorkFactory(client), eventHandler)
+	artistQueryService := mysqlquery.NewArtistQueryService(sqlclient.NewClient(client))
+	subscriptionService := service.NewSubscriptionService(unitOfWorkFactory(client), eventHandler)
+	subscriptionQueryService := mysqlquery.NewSubscriptionQueryService(sqlclient.NewClient(client))
+	householdService := service.NewHouseholdService(
+		unitOfWorkFactory(client),
+		eventHandler,
+		hash.NewHMACHasher(parameters.HouseholdAddressKey()),
+		parameters.HouseholdMaxMembers(),
+	)
+	parentalControlsService := service.NewParentalControlsService(unitOfWorkFactory(client), eventHandler, pinHasher, agePolicy)
+	followService := service.NewFollowService(unitOfWorkFactory(client), eventHandler)
+	blockService := service.NewBlockService(unitOfWorkFactory(client), eventHandler)
+	privacyService := service.NewPrivacyService(unitOfWorkFactory(client), eventHandler)
+	externalIdentityService := service.NewExternalIdentityService(
+		unitOfWorkFactory(client),
+		eventHandler,
+		consentPolicy(parameters),
+		externalIdentityProviders(parameters),
+	)
+	oauth2Service := service.NewOAuth2Service(unitOfWorkFactory(client), eventHandler, mysqlquery.NewOAuth2QueryService(sqlclient.NewClient(client)), subscriptionQueryService, openid.NewSigner(parameters.OAuth2SigningKey()), service.OAuth2Policy{
+		Issuer:                parameters.OAuth2Issuer(),
+		AccessTokenTTL:        parameters.OAuth2AccessTokenTTL(),
+		RefreshTokenTTL:       parameters.OAuth2RefreshTokenTTL(),
+		DeviceVerificationURI: parameters.OAuth2DeviceVerificationURL(),
+	})
+	sessionService := service.NewSessionService(
+		unitOfWorkFactory(client),
+		eventHandler,
+		mysqlquery.NewSessionQueryService(sqlclient.NewClient(client)),
+		parameters.SessionTTL(),
+	)
+	dataExportSections := []service.DataExportSection{
+		service.NewProfileDataExportSection(userQueryService, profileService),
+		service.NewConsentDataExportSection(consentService),
+		service.NewAuditDataExportSection(auditLogQueryService),
+		service.NewRoleHistoryDataExportSection(auditLogQueryService),
+		service.NewSessionsDataExportSection(auditLogQueryService),
+		service.NewCreatorApplicationDataExportSection(creatorApplicationQueryService),
+		service.NewArtistDataExportSection(artistQueryService),
+		service.NewSubscriptionDataExportSection(subscriptionService),
+		service.NewHouseholdDataExportSection(householdService),
+		service.NewParentalControlsDataExportSection(parentalControlsService),
+		service.NewFollowsDataExportSection(followService),
+		service.NewBlocksDataExportSection(blockService),
+		service.NewPrivacyDataExportSection(privacyService),
+		service.NewExternalIdentitiesDataExportSection(externalIdentityService),
+		service.NewOAuth2DataExportSection(oauth2Service, auditLogQueryService),
+	}
+
 	return &dependencyContainer{
-		userService:              userService(unitOfWorkFactory(client), hasher),
-		authenticationService:    authenticationService(userQueryService, hasher),
-		userDescriptorSerializer: userDescriptorSerializer(),
+		userService:                    userService,
+		userQueryService:               userQueryService,
+		authenticationService:          metrics.NewAuthenticationService(authenticationService(userQueryService, credentialsQueryService, userService, consentService, auditService, artistQueryService, subscriptionQueryService, externalIdentityService, oauth2Service, hasher)),
+		userDescriptorSerializer:       userDescriptorSerializer(),
+		dataExportService:              dataExportService(unitOfWorkFactory(client), eventHandler, archiveStorage, dataExportSections, parameters),
+		erasureService:                 service.NewErasureService(unitOfWorkFactory(client), eventHandler, personalDataErasers),
+		outboxService:                  service.NewOutboxService(unitOfWorkFactory(client)),
+		consentService:                 consentService,
+		auditService:                   auditService,
+		auditLogQueryService:           auditLogQueryService,
+		profileService:                 profileService,
+		creatorApplicationService:      creatorApplicationService,
+		creatorApplicationQueryService: creatorApplicationQueryService,
+		artistService:                  artistService,
+		artistQueryService:             artistQueryService,
+		subscriptionService:            subscriptionService,
+		subscriptionQueryService:       subscriptionQueryService,
+		householdService:               householdService,
+		parentalControlsService:        parentalControlsService,
+		followService:                  followService,
+		blockService:                   blockService,
+		privacyService:                 privacyService,
+		externalIdentityService:        externalIdentityService,
+		oauth2Service:                  oauth2Service,
+		sessionService:                 sessionService,
 	}
 }
 
 type dependencyContainer struct {
-	userService              service.UserService
-	userQueryService         query.UserQueryService
-	authenticationService    auth.AuthenticationService
-	userDescriptorSerializer commonauth.UserDescriptorSerializer
+	userService                    service.UserService
+	userQueryService               query.UserQueryService
+	authenticationService          auth.AuthenticationService
+	userDescriptorSerializer       commonauth.UserDescriptorSerializer
+	dataExportService              service.DataExportService
+	erasureService                 service.ErasureService
+	outboxService                  service.OutboxService
+	consentService                 service.ConsentService
+	auditService                   service.AuditService
+	auditLogQueryService           query.AuditLogQueryService
+	profileService                 service.ProfileService
+	creatorApplicationService      service.CreatorApplicationService
+	creatorApplicationQueryService query.CreatorApplicationQueryService
+	artistService                  service.ArtistService
+	artistQueryService             query.ArtistQueryService
+	subscriptionService            service.SubscriptionService
+	subscriptionQueryService       query.SubscriptionQueryService
+	householdService               service.HouseholdService
+	parentalControlsService        service.ParentalControlsService
+	followService                  service.FollowService
+	blockService                   service.BlockService
+	privacyService                 service.PrivacyService
+	externalIdentityService        service.ExternalIdentityService
+	oauth2Service                  service.OAuth2Service
+	sessionService                 service.SessionService
 }
 
 func (container *dependencyContainer) UserService() service.UserService {
@@ -57,15 +239,183 @@ func (container *dependencyContainer) UserQueryService() query.UserQueryService
 	return container.userQueryService
 }
 
-func userService(unitOfWorkFactory service.UnitOfWorkFactory, hasher hash.Hasher) service.UserService {
+func (container *dependencyContainer) OutboxService() service.OutboxService {
+	return container.outboxService
+}
+
+func (container *dependencyContainer) DataExportService() service.DataExportService {
+	return container.dataExportService
+}
+
+func (container *dependencyContainer) ErasureService() service.ErasureService {
+	return container.erasureService
+}
+
+func (container *dependencyContainer) ConsentService() service.ConsentService {
+	return container.consentService
+}
+
+func (container *dependencyContainer) AuditService() service.AuditService {
+	return container.auditService
+}
+
+func (container *dependencyContainer) AuditLogQueryService() query.AuditLogQueryService {
+	return container.auditLogQueryService
+}
+
+func (container *dependencyContainer) ProfileService() service.ProfileService {
+	return container.profileService
+}
+
+func (container *dependencyContainer) CreatorApplicationService() service.CreatorApplicationService {
+	return container.creatorApplicationService
+}
+
+func (container *dependencyContainer) CreatorApplicationQueryService() query.CreatorApplicationQueryService {
+	return container.creatorApplicationQueryService
+}
+
+func (container *dependencyContainer) ArtistService() service.ArtistService {
+	return container.artistService
+}
+
+func (container *dependencyContainer) ArtistQueryService() query.ArtistQueryService {
+	return container.artistQueryService
+}
+
+func (container *dependencyContainer) SubscriptionService() service.SubscriptionService {
+	return container.subscriptionService
+}
+
+func (container *dependencyContainer) SubscriptionQueryService() query.SubscriptionQueryService {
+	return container.subscriptionQueryService
+}
+
+func (container *dependencyContainer) HouseholdService() service.HouseholdService {
+	return container.householdService
+}
+
+func (container *dependencyContainer) ParentalControlsService() service.ParentalControlsService {
+	return container.parentalControlsService
+}
+
+func (container *dependencyContainer) FollowService() service.FollowService {
+	return container.followService
+}
+
+func (container *dependencyContainer) BlockService() service.BlockService {
+	return container.blockService
+}
+
+func (container *dependencyContainer) PrivacyService() service.PrivacyService {
+	return container.privacyService
+}
+
+func (container *dependencyContainer) ExternalIdentityService() service.ExternalIdentityService {
+	return container.externalIdentityService
+}
+
+func (container *dependencyContainer) OAuth2Service() service.OAuth2Service {
+	return container.oauth2Service
+}
+
+func (container *dependencyContainer) SessionService() service.SessionService {
+	return container.sessionService
+}
+
+func userService(
+	unitOfWorkFactory service.UnitOfWorkFactory,
+	hasher hash.PasswordHasher,
+	eventHandler service.EventHandler,
+	erasers []service.PersonalDataEraser,
+	parameters Parameters,
+) service.UserService {
 	return service.NewUserService(
 		unitOfWorkFactory,
 		hasher,
+		eventHandler,
+		erasers,
+		service.LockoutPolicy{
+			MaxFailedAttempts: parameters.LoginMaxFailedAttempts(),
+			Duration:          parameters.LoginLockoutDuration(),
+		},
+		consentPolicy(parameters),
 	)
 }
 
-func authenticationService(queryService query.UserQueryService, hasher hash.Hasher) auth.AuthenticationService {
-	return auth.NewAuthenticationService(queryService, hasher)
+func consentPolicy(parameters Parameters) service.ConsentPolicy {
+	return service.ConsentPolicy{
+		TermsOfServiceVersion: parameters.TermsOfServiceVersion(),
+		PrivacyPolicyVersion:  parameters.PrivacyPolicyVersion(),
+		RequireTermsOnSignUp:  parameters.RequireTermsOnSignUp(),
+	}
+}
+
+func agePolicy(parameters Parameters) service.AgePolicy {
+	return service.AgePolicy{
+		ChildAge:          parameters.ChildAge(),
+		AdultAge:          parameters.AdultAge(),
+		AdultAgeByCountry: parameters.AdultAgeByCountry(),
+	}
+}
+
+func dataExportService(
+	unitOfWorkFactory service.UnitOfWorkFactory,
+	eventHandler service.EventHandler,
+	archiveStorage service.ArchiveStorage,
+	sections []service.DataExportSection,
+	parameters Parameters,
+) service.DataExportService {
+	return service.NewDataExportService(
+		unitOfWorkFactory,
+		eventHandler,
+		archiveStorage,
+		sections,
+		parameters.DataExportTTL(),
+	)
+}
+
+func authenticationService(
+	queryService query.UserQueryService,
+	credentialsQueryService query.UserQueryService,
+	userService service.UserService,
+	consentService service.ConsentService,
+	auditService service.AuditService,
+	artistQueryService query.ArtistQueryService,
+	subscriptionQueryService query.SubscriptionQueryService,
+	externalIdentityService service.ExternalIdentityService,
+	oauth2Service service.OAuth2Service,
+	hasher hash.Hasher,
+) auth.AuthenticationService {
+	return auth.NewAuthenticationService(
+		queryService,
+		credentialsQueryService,
+		userService,
+		consentService,
+		auditService,
+		artistQueryService,
+		subscriptionQueryService,
+		externalIdentityService,
+		oauth2Service,
+		hash.NewVerifier(hasher),
+	)
+}
+
+func externalIdentityProviders(parameters Parameters) map[string]service.ExternalIdentityProvider {
+	providers := map[string]service.ExternalIdentityProvider{}
+	for name, issuer := range parameters.ExternalIdentityIssuers() {
+		clientID := parameters.ExternalIdentityClientIDs()[name]
+		if clientID == "" {
+			continue
+		}
+		providers[name] = openid.NewProvider(openid.ProviderConfig{
+			IssuerURL:    issuer,
+			ClientID:     clientID,
+			ClientSecret: parameters.ExternalIdentityClientSecrets()[name],
+			RedirectURL:  parameters.ExternalIdentityRedirectURL(),
+		})
+	}
+	return providers
 }
 
 func userDescriptorSerializer() commonauth.UserDescriptorSerializer {
@@ -76,10 +426,26 @@ func hasher(parameters Parameters) hash.Hasher {
 	return hash.NewSHA1Hasher(parameters.HasherSalt())
 }
 
-func userQueryService(client commonmysql.TransactionalClient) query.UserQueryService {
-	return mysqlquery.NewUserQueryService(client)
+func passwordHasher() hash.PasswordHasher {
+	return hash.NewBCryptHasher()
+}
+
+func userQueryService(client mysql.TransactionalClient) query.UserQueryService {
+	return mysqlquery.NewUserQueryService(sqlclient.NewClient(client))
+}
+
+func cachedUserQueryService(queryService query.UserQueryService, parameters Parameters) cache.UserQueryService {
+	return cache.NewUserQueryService(
+		queryService,
+		cache.Config{
+			Size:        parameters.UserCacheSize(),
+			TTL:         parameters.UserCacheTTL(),
+			LoadTimeout: parameters.UserCacheLoadTimeout(),
+		},
+		metrics.NewCacheMetrics("user"),
+	)
 }
 
-func unitOfWorkFactory(client commonmysql.TransactionalClient) service.UnitOfWorkFactory {
-	return mysql.NewUnitOfFactory(client)
+func unitOfWorkFactory(client mysql.TransactionalClient) service.UnitOfWorkFactory {
+	return mysql.NewUnitOfFactory(client, metrics.NewUnitOfWorkMetrics())
 }
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type CheckResult struct {
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type Checker interface {
+	AddCheck(name string, check CheckFunc)
+	Check(ctx context.Context) Report
+}
+
+func NewChecker(timeout time.Duration) Checker {
+	return &checker{
+		timeout: timeout,
+		checks:  map[string]CheckFunc{},
+	}
+}
+
+type checker struct {
+	timeout time.Duration
+	lock    sync.RWMutex
+	checks  map[string]CheckFunc
+}
+
+func (c *checker) AddCheck(name string, check CheckFunc) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.checks[name] = check
+}
+
+func (c *checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	c.lock.RLock()
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+	c.lock.RUnlock()
+	sort.Strings(names)
+
+	results := make([]CheckResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, check CheckFunc) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, c.getCheck(name))
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]CheckResult, len(names)),
+	}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (c *checker) getCheck(name string) CheckFunc {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.checks[name]
+}
+
+func runCheck(ctx context.Context, check CheckFunc) CheckResult {
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Status:   StatusUp,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/streadway/amqp"
+)
+
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+func NewDatabaseCheck(pinger Pinger) CheckFunc {
+	return func(ctx context.Context) error {
+		return errors.Wrap(pinger.PingContext(ctx), "failed to ping database")
+	}
+}
+
+type AMQPConfig struct {
+	User     string
+	Password string
+	Host     string
+}
+
+func NewAMQPCheck(config AMQPConfig) CheckFunc {
+	url := fmt.Sprintf("amqp://%s:%s@%s/", config.User, config.Password, config.Host)
+	return func(ctx context.Context) error {
+		timeout := time.Second
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+
+		conn, err := amqp.DialConfig(url, amqp.Config{Dial: amqp.DefaultDial(timeout)})
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to amqp")
+		}
+		return errors.Wrap(conn.Close(), "failed to close amqp connection")
+	}
+}
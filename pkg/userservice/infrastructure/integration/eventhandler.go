@@ -0,0 +1,172 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+// message is contract with downstream services, Type is also used as routing key
+type message struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Payload    interface{} `json:"payload"`
+}
+
+type userPayload struct {
+	UserID string `json:"user_id"`
+}
+
+type creatorApplicationPayload struct {
+	UserID        string `json:"user_id"`
+	ApplicationID string `json:"application_id"`
+}
+
+type artistInvitationPayload struct {
+	InvitationID string `json:"invitation_id"`
+	ArtistID     string `json:"artist_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+}
+
+type subscriptionPayload struct {
+	UserID string `json:"user_id"`
+	Plan   string `json:"plan"`
+	Status string `json:"status"`
+	Trial  bool   `json:"trial"`
+}
+
+type householdInvitationPayload struct {
+	InvitationID string `json:"invitation_id"`
+	HouseholdID  string `json:"household_id"`
+	Email        string `json:"email"`
+}
+
+type householdMemberPayload struct {
+	HouseholdID string `json:"household_id"`
+	UserID      string `json:"user_id"`
+}
+
+type parentalControlsPayload struct {
+	UserID                string `json:"user_id"`
+	ExplicitContentFilter *bool  `json:"explicit_content_filter,omitempty"`
+}
+
+type followPayload struct {
+	FollowerID string `json:"follower_id"`
+	TargetKind string `json:"target_kind"`
+	TargetID   string `json:"target_id"`
+}
+
+type blockPayload struct {
+	BlockerID string `json:"blocker_id"`
+	BlockedID string `json:"blocked_id"`
+}
+
+type privacySettingsPayload struct {
+	UserID                  string `json:"user_id"`
+	ProfileVisibility       string `json:"profile_visibility"`
+	ListeningActivityPublic bool   `json:"listening_activity_public"`
+}
+
+// NewOutboxEventHandler writes domain events other services depend on to outbox in transaction of change,
+// they are published by outbox relay after commit, so no committed event is lost
+func NewOutboxEventHandler() service.TransactionalEventHandler {
+	return &outboxEventHandler{}
+}
+
+type outboxEventHandler struct{}
+
+func (handler *outboxEventHandler) Handle(domain.Event) {}
+
+func (handler *outboxEventHandler) HandleInUnitOfWork(ctx context.Context, provider service.RepositoryProvider, event domain.Event) error {
+	msg, ok := makeMessage(event)
+	if !ok {
+		return nil
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return provider.OutboxRepository().Store(ctx, domain.OutboxMessage{
+		RoutingKey: msg.Type,
+		Body:       body,
+		CreatedAt:  msg.OccurredAt,
+	})
+}
+
+func makeMessage(event domain.Event) (message, bool) {
+	var payload interface{}
+	switch e := event.(type) {
+	case domain.UserErased:
+		payload = userPayload{UserID: uuid.UUID(e.UserID).String()}
+	case domain.CreatorApplicationApproved:
+		payload = creatorApplicationPayload{UserID: uuid.UUID(e.UserID).String(), ApplicationID: uuid.UUID(e.ApplicationID).String()}
+	case domain.CreatorApplicationRejected:
+		payload = creatorApplicationPayload{UserID: uuid.UUID(e.UserID).String(), ApplicationID: uuid.UUID(e.ApplicationID).String()}
+	case domain.ArtistMemberInvited:
+		payload = artistInvitationPayload{
+			InvitationID: uuid.UUID(e.InvitationID).String(),
+			ArtistID:     uuid.UUID(e.ArtistID).String(),
+			Email:        e.Email,
+			Role:         e.Role.Name(),
+		}
+	case domain.SubscriptionChanged:
+		payload = subscriptionPayload{
+			UserID: uuid.UUID(e.UserID).String(),
+			Plan:   e.Plan.Name(),
+			Status: e.Status.Name(),
+			Trial:  e.Trial,
+		}
+	case domain.HouseholdMemberInvited:
+		payload = householdInvitationPayload{
+			InvitationID: uuid.UUID(e.InvitationID).String(),
+			HouseholdID:  uuid.UUID(e.HouseholdID).String(),
+			Email:        e.Email,
+		}
+	case domain.HouseholdMemberJoined:
+		payload = householdMemberPayload{HouseholdID: uuid.UUID(e.HouseholdID).String(), UserID: uuid.UUID(e.UserID).String()}
+	case domain.HouseholdMemberRemoved:
+		payload = householdMemberPayload{HouseholdID: uuid.UUID(e.HouseholdID).String(), UserID: uuid.UUID(e.UserID).String()}
+	case domain.ParentalControlsChanged:
+		payload = parentalControlsPayload{UserID: uuid.UUID(e.UserID).String(), ExplicitContentFilter: e.ExplicitContentFilter}
+	case domain.UserFollowed:
+		payload = makeFollowPayload(e.FollowerID, e.Target)
+	case domain.UserUnfollowed:
+		payload = makeFollowPayload(e.FollowerID, e.Target)
+	case domain.UserBlocked:
+		payload = blockPayload{BlockerID: uuid.UUID(e.BlockerID).String(), BlockedID: uuid.UUID(e.BlockedID).String()}
+	case domain.UserUnblocked:
+		payload = blockPayload{BlockerID: uuid.UUID(e.BlockerID).String(), BlockedID: uuid.UUID(e.BlockedID).String()}
+	case domain.PrivacySettingsChanged:
+		payload = privacySettingsPayload{
+			UserID:                  uuid.UUID(e.UserID).String(),
+			ProfileVisibility:       e.ProfileVisibility.Name(),
+			ListeningActivityPublic: e.ListeningActivityPublic,
+		}
+	default:
+		return message{}, false
+	}
+
+	return message{
+		ID:         uuid.New().String(),
+		Type:       event.ID(),
+		OccurredAt: time.Now(),
+		Payload:    payload,
+	}, true
+}
+
+func makeFollowPayload(followerID domain.UserID, target domain.FollowTarget) followPayload {
+	return followPayload{
+		FollowerID: uuid.UUID(followerID).String(),
+		TargetKind: target.Kind.Name(),
+		TargetID:   target.ID.String(),
+	}
+}
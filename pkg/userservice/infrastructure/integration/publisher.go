@@ -0,0 +1,67 @@
+package integration
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/streadway/amqp"
+)
+
+const exchangeKind = "topic"
+
+var ErrNotConnected = errors.New("amqp publisher is not connected")
+
+type Publisher interface {
+	Publish(routingKey string, body []byte) error
+}
+
+type AMQPPublisher interface {
+	Publisher
+	Connect(conn *amqp.Connection) error
+}
+
+func NewAMQPPublisher(exchange string) AMQPPublisher {
+	return &amqpPublisher{exchange: exchange}
+}
+
+type amqpPublisher struct {
+	exchange string
+
+	lock    sync.Mutex
+	channel *amqp.Channel
+}
+
+func (publisher *amqpPublisher) Connect(conn *amqp.Connection) error {
+	channel, err := conn.Channel()
+	if err != nil {
+		return errors.Wrap(err, "failed to open amqp channel")
+	}
+
+	err = channel.ExchangeDeclare(publisher.exchange, exchangeKind, true, false, false, false, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to declare amqp exchange")
+	}
+
+	publisher.lock.Lock()
+	defer publisher.lock.Unlock()
+	publisher.channel = channel
+	return nil
+}
+
+func (publisher *amqpPublisher) Publish(routingKey string, body []byte) error {
+	publisher.lock.Lock()
+	defer publisher.lock.Unlock()
+
+	if publisher.channel == nil {
+		return ErrNotConnected
+	}
+
+	err := publisher.channel.Publish(publisher.exchange, routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Body:         body,
+	})
+	return errors.Wrap(err, "failed to publish amqp message")
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"context"
+
+	commonauth "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/auth"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"userservice/pkg/userservice/app/auth"
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+var (
+	registrations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Number of registered users by role",
+	}, []string{"role"})
+	logins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Number of authentication attempts by result and failure reason",
+	}, []string{"result", "reason"})
+	authorizationDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "authorization_decisions_total",
+		Help:      "Number of authorization checks by action and decision",
+	}, []string{"action", "decision"})
+)
+
+func NewBusinessEventHandler() service.EventHandler {
+	return &businessEventHandler{}
+}
+
+type businessEventHandler struct{}
+
+func (handler *businessEventHandler) Handle(event domain.Event) {
+	if e, ok := event.(domain.UserCreated); ok {
+		registrations.WithLabelValues(e.Role.Name()).Inc()
+	}
+}
+
+func NewAuthenticationService(authenticationService auth.AuthenticationService) auth.AuthenticationService {
+	return &authenticationServiceDecorator{authenticationService: authenticationService}
+}
+
+type authenticationServiceDecorator struct {
+	authenticationService auth.AuthenticationService
+}
+
+func (decorator *authenticationServiceDecorator) AuthenticateUser(ctx context.Context, email, password string) (auth.AuthenticatedUser, error) {
+	user, err := decorator.authenticationService.AuthenticateUser(ctx, email, password)
+	if err != nil {
+		logins.WithLabelValues("failure", loginFailureReason(err)).Inc()
+	} else {
+		logins.WithLabelValues("success", "").Inc()
+	}
+	return user, err
+}
+
+func (decorator *authenticationServiceDecorator) AuthenticateExternalUser(
+	ctx context.Context,
+	state, code string,
+	signUp service.ExternalSignUp,
+) (auth.AuthenticatedUser, error) {
+	user, err := decorator.authenticationService.AuthenticateExternalUser(ctx, state, code, signUp)
+	if err != nil {
+		logins.WithLabelValues("failure", loginFailureReason(err)).Inc()
+	} else {
+		logins.WithLabelValues("success", "").Inc()
+	}
+	return user, err
+}
+
+func (decorator *authenticationServiceDecorator) AuthenticateDevice(ctx context.Context, clientID, deviceCode string) (auth.AuthenticatedUser, error) {
+	user, err := decorator.authenticationService.AuthenticateDevice(ctx, clientID, deviceCode)
+	switch errors.Cause(err) {
+	case nil:
+		logins.WithLabelValues("success", "").Inc()
+	case service.ErrOAuth2AuthorizationPending, service.ErrOAuth2SlowDown:
+	default:
+		logins.WithLabelValues("failure", loginFailureReason(err)).Inc()
+	}
+	return user, err
+}
+
+func (decorator *authenticationServiceDecorator) CanAddContent(ctx context.Context, descriptor commonauth.UserDescriptor) (bool, error) {
+	canAdd, err := decorator.authenticationService.CanAddContent(ctx, descriptor)
+	authorizationDecisions.WithLabelValues("add_content", authorizationDecision(canAdd, err)).Inc()
+	return canAdd, err
+}
+
+func (decorator *authenticationServiceDecorator) CanAddContentForArtist(ctx context.Context, descriptor commonauth.UserDescriptor, artistID uuid.UUID) (bool, error) {
+	canAdd, err := decorator.authenticationService.CanAddContentForArtist(ctx, descriptor, artistID)
+	authorizationDecisions.WithLabelValues("add_artist_content", authorizationDecision(canAdd, err)).Inc()
+	return canAdd, err
+}
+
+func (decorator *authenticationServiceDecorator) AssertAdmin(ctx context.Context, descriptor commonauth.UserDescriptor) error {
+	err := decorator.authenticationService.AssertAdmin(ctx, descriptor)
+	authorizationDecisions.WithLabelValues("admin", authorizationDecision(err == nil, err)).Inc()
+	return err
+}
+
+func loginFailureReason(err error) string {
+	switch errors.Cause(err) {
+	case domain.ErrUserNotFound, query.ErrUserNotFound:
+		return "user_not_found"
+	case auth.ErrIncorrectAuthData:
+		return "incorrect_password"
+	case auth.ErrUserLocked:
+		return "user_locked"
+	case service.ErrExternalAuthenticationFailed:
+		return "external_provider_rejected"
+	case service.ErrOAuth2AccessDenied,
+		service.ErrOAuth2DeviceCodeExpired,
+		service.ErrInvalidOAuth2Grant,
+		service.ErrInvalidOAuth2Client,
+		domain.ErrOAuth2GrantNotAllowed:
+		return "device_rejected"
+	default:
+		return "internal_error"
+	}
+}
+
+func authorizationDecision(allowed bool, err error) string {
+	switch {
+	case allowed:
+		return "allowed"
+	case err == nil, errors.Cause(err) == auth.ErrOnlyCreatorsCanAddContent, errors.Cause(err) == auth.ErrAdminRequired, errors.Cause(err) == auth.ErrNotArtistUploader:
+		return "denied"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "userservice"
+
+var (
+	cacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "hits_total",
+		Help:      "Number of lookups served from cache",
+	}, []string{"cache", "method"})
+	cacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "misses_total",
+		Help:      "Number of lookups that went to the underlying storage",
+	}, []string{"cache", "method"})
+)
+
+type CacheMetrics interface {
+	Hit(method string)
+	Miss(method string)
+}
+
+func NewCacheMetrics(cache string) CacheMetrics {
+	return &cacheMetrics{cache: cache}
+}
+
+type cacheMetrics struct {
+	cache string
+}
+
+func (m *cacheMetrics) Hit(method string) {
+	cacheHits.WithLabelValues(m.cache, method).Inc()
+}
+
+func (m *cacheMetrics) Miss(method string) {
+	cacheMisses.WithLabelValues(m.cache, method).Inc()
+}
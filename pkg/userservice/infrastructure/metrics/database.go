@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var unitOfWorkCompleted = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Subsystem: "db",
+	Name:      "unit_of_work_completed_total",
+	Help:      "Number of completed units of work by result",
+}, []string{"result"})
+
+type UnitOfWorkMetrics interface {
+	Committed()
+	RolledBack()
+}
+
+func NewUnitOfWorkMetrics() UnitOfWorkMetrics {
+	return &unitOfWorkMetrics{}
+}
+
+type unitOfWorkMetrics struct{}
+
+func (m *unitOfWorkMetrics) Committed() {
+	unitOfWorkCompleted.WithLabelValues("commit").Inc()
+}
+
+func (m *unitOfWorkMetrics) RolledBack() {
+	unitOfWorkCompleted.WithLabelValues("rollback").Inc()
+}
+
+type DBStatsProvider interface {
+	Stats() sql.DBStats
+}
+
+func RegisterDBStats(provider DBStatsProvider) error {
+	return prometheus.Register(newDBStatsCollector(provider))
+}
+
+func newDBStatsCollector(provider DBStatsProvider) prometheus.Collector {
+	newDesc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", name), help, nil, nil)
+	}
+
+	return &dbStatsCollector{
+		provider:          provider,
+		maxOpen:           newDesc("max_open_connections", "Maximum number of open connections to the database"),
+		open:              newDesc("open_connections", "The number of established connections both in use and idle"),
+		inUse:             newDesc("in_use_connections", "The number of connections currently in use"),
+		idle:              newDesc("idle_connections", "The number of idle connections"),
+		waitCount:         newDesc("wait_count_total", "The total number of connections waited for"),
+		waitDuration:      newDesc("wait_duration_seconds_total", "The total time blocked waiting for a new connection"),
+		maxIdleClosed:     newDesc("max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns"),
+		maxLifetimeClosed: newDesc("max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime"),
+	}
+}
+
+type dbStatsCollector struct {
+	provider DBStatsProvider
+
+	maxOpen           *prometheus.Desc
+	open              *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxLifetimeClosed
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.provider.Stats()
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	grpcHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "server_handled_total",
+		Help:      "Number of RPCs completed on the server",
+	}, []string{"method", "code"})
+	grpcHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "server_handling_seconds",
+		Help:      "Latency of RPCs handled by the server",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	httpHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests completed by the REST server",
+	}, []string{"method", "code"})
+	httpHandlingSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests handled by the REST server",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+const Path = "/metrics"
+
+func NewHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+type GRPCMetrics interface {
+	ObserveCall(method string, code codes.Code, duration time.Duration)
+}
+
+func NewGRPCMetrics() GRPCMetrics {
+	return &grpcMetrics{}
+}
+
+type grpcMetrics struct{}
+
+func (m *grpcMetrics) ObserveCall(method string, code codes.Code, duration time.Duration) {
+	grpcHandled.WithLabelValues(method, code.String()).Inc()
+	grpcHandlingSeconds.WithLabelValues(method, code.String()).Observe(duration.Seconds())
+}
+
+type HTTPMetrics interface {
+	ObserveRequest(method string, code int, duration time.Duration)
+}
+
+func NewHTTPMetrics() HTTPMetrics {
+	return &httpMetrics{}
+}
+
+type httpMetrics struct{}
+
+func (m *httpMetrics) ObserveRequest(method string, code int, duration time.Duration) {
+	httpHandled.WithLabelValues(method, strconv.Itoa(code)).Inc()
+	httpHandlingSeconds.WithLabelValues(method, strconv.Itoa(code)).Observe(duration.Seconds())
+}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	commonmysql "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+var ErrContextUnsupported = errors.New("mysql client does not support context-aware calls")
+
+type TransactionalClient interface {
+	sqlclient.Executor
+	PingContext(ctx context.Context) error
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+func NewTransactionalClient(client commonmysql.TransactionalClient) (TransactionalClient, error) {
+	transactionalClient, ok := client.(TransactionalClient)
+	if !ok {
+		return nil, errors.WithStack(ErrContextUnsupported)
+	}
+	return transactionalClient, nil
+}
@@ -0,0 +1,160 @@
+package mysql
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	commonmysql "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+const migrationsDialect = "mysql"
+
+var (
+	ErrMigrationsUnsupported  = errors.New("mysql client does not expose database handle for migrations")
+	ErrSchemaVersionMismatch  = errors.New("database schema version does not match expected one")
+	ErrNoMigrationsToRollback = errors.New("no applied migrations to rollback")
+	ErrPendingMigrations      = errors.New("pending migrations must be applied before redo")
+)
+
+type MigrationStatus struct {
+	ID        string
+	AppliedAt *time.Time
+	Unknown   bool
+}
+
+type Migrator interface {
+	Up() (int, error)
+	Down(max int) (int, error)
+	// Redo fails with ErrPendingMigrations, otherwise it would apply pending migration instead of rolled back one
+	Redo() (string, error)
+	Status() ([]MigrationStatus, error)
+	// CheckSchemaVersion returns ErrSchemaVersionMismatch when applied migrations differ from embedded ones.
+	// Unknown migrations newer than all embedded ones are returned instead, so binary can be rolled back after forward migration
+	CheckSchemaVersion() ([]string, error)
+}
+
+func NewMigrator(client commonmysql.Client, provider commonmysql.MigrationProvider) (Migrator, error) {
+	db, ok := client.(*sqlx.DB)
+	if !ok {
+		return nil, errors.WithStack(ErrMigrationsUnsupported)
+	}
+	return &migrator{
+		db:     db.DB,
+		source: migrate.HttpFileSystemMigrationSource{FileSystem: provider.GetDir()},
+	}, nil
+}
+
+type migrator struct {
+	db     *sql.DB
+	source migrate.MigrationSource
+}
+
+func (m *migrator) Up() (int, error) {
+	n, err := migrate.Exec(m.db, migrationsDialect, m.source, migrate.Up)
+	return n, errors.Wrap(err, "failed to apply migrations")
+}
+
+func (m *migrator) Down(max int) (int, error) {
+	n, err := migrate.ExecMax(m.db, migrationsDialect, m.source, migrate.Down, max)
+	return n, errors.Wrap(err, "failed to rollback migrations")
+}
+
+func (m *migrator) Redo() (string, error) {
+	pending, _, err := migrate.PlanMigration(m.db, migrationsDialect, m.source, migrate.Up, 0)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to plan migration")
+	}
+	if len(pending) > 0 {
+		return "", errors.WithStack(ErrPendingMigrations)
+	}
+
+	planned, _, err := migrate.PlanMigration(m.db, migrationsDialect, m.source, migrate.Down, 1)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to plan migration")
+	}
+	if len(planned) == 0 {
+		return "", errors.WithStack(ErrNoMigrationsToRollback)
+	}
+
+	_, err = migrate.ExecMax(m.db, migrationsDialect, m.source, migrate.Down, 1)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to rollback migration")
+	}
+	_, err = migrate.ExecMax(m.db, migrationsDialect, m.source, migrate.Up, 1)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to reapply migration")
+	}
+
+	return planned[0].Id, nil
+}
+
+func (m *migrator) Status() ([]MigrationStatus, error) {
+	migrations, err := m.source.FindMigrations()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find migrations")
+	}
+	records, err := migrate.GetMigrationRecords(m.db, migrationsDialect)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get applied migrations")
+	}
+
+	appliedAt := make(map[string]time.Time, len(records))
+	for _, record := range records {
+		appliedAt[record.Id] = record.AppliedAt
+	}
+
+	result := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{ID: migration.Id}
+		if at, ok := appliedAt[migration.Id]; ok {
+			status.AppliedAt = &at
+			delete(appliedAt, migration.Id)
+		}
+		result = append(result, status)
+	}
+	for _, record := range records {
+		if _, ok := appliedAt[record.Id]; ok {
+			at := record.AppliedAt
+			result = append(result, MigrationStatus{ID: record.Id, AppliedAt: &at, Unknown: true})
+		}
+	}
+
+	return result, nil
+}
+
+func (m *migrator) CheckSchemaVersion() ([]string, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *migrate.Migration
+	var pending, unknown, newer []string
+	for _, status := range statuses {
+		switch {
+		case status.Unknown:
+			unknown = append(unknown, status.ID)
+		case status.AppliedAt == nil:
+			pending = append(pending, status.ID)
+		default:
+			latest = &migrate.Migration{Id: status.ID}
+		}
+	}
+	for _, id := range unknown {
+		if latest == nil || latest.Less(&migrate.Migration{Id: id}) {
+			newer = append(newer, id)
+		}
+	}
+
+	if len(pending) != 0 {
+		return nil, errors.Wrapf(ErrSchemaVersionMismatch, "pending migrations: %s", strings.Join(pending, ", "))
+	}
+	if len(unknown) != len(newer) {
+		return nil, errors.Wrapf(ErrSchemaVersionMismatch, "unknown applied migrations: %s", strings.Join(unknown, ", "))
+	}
+	return newer, nil
+}
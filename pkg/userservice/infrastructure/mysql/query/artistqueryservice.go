@@ -0,0 +1,182 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+func NewArtistQueryService(client sqlclient.Client) query.ArtistQueryService {
+	return &artistQueryService{client: client}
+}
+
+type artistQueryService struct {
+	client sqlclient.Client
+}
+
+func (service *artistQueryService) GetArtist(ctx context.Context, artistID uuid.UUID) (query.ArtistView, error) {
+	const selectArtistSQL = `SELECT artist_id, name, created_at FROM artist WHERE artist_id = ?`
+	const selectMembersSQL = `SELECT user_id, role, joined_at FROM artist_member WHERE artist_id = ? ORDER BY joined_at`
+
+	binaryUUID, err := artistID.MarshalBinary()
+	if err != nil {
+		return query.ArtistView{}, errors.WithStack(err)
+	}
+
+	var artist sqlxArtistView
+	err = service.client.Get(ctx, &artist, selectArtistSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return query.ArtistView{}, domain.ErrArtistNotFound
+		}
+		return query.ArtistView{}, errors.WithStack(err)
+	}
+
+	var members []sqlxArtistMemberView
+	err = service.client.Select(ctx, &members, selectMembersSQL, binaryUUID)
+	if err != nil {
+		return query.ArtistView{}, errors.WithStack(err)
+	}
+
+	view := query.ArtistView{ID: artist.ArtistID, Name: artist.Name, CreatedAt: artist.CreatedAt}
+	for _, member := range members {
+		view.Members = append(view.Members, query.ArtistMemberView{
+			UserID:   member.UserID,
+			Role:     query.ArtistRole(member.Role),
+			JoinedAt: member.JoinedAt,
+		})
+	}
+	return view, nil
+}
+
+func (service *artistQueryService) ListUserArtists(ctx context.Context, userID uuid.UUID) ([]query.ArtistMembershipView, error) {
+	const selectSQL = `
+		SELECT a.artist_id, a.name, m.role, m.joined_at FROM artist_member m
+		INNER JOIN artist a ON a.artist_id = m.artist_id
+		WHERE m.user_id = ?
+		ORDER BY m.joined_at
+	`
+
+	binaryUUID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var memberships []sqlxArtistMembershipView
+	err = service.client.Select(ctx, &memberships, selectSQL, binaryUUID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	views := make([]query.ArtistMembershipView, 0, len(memberships))
+	for _, membership := range memberships {
+		views = append(views, query.ArtistMembershipView{
+			ArtistID:   membership.ArtistID,
+			ArtistName: membership.Name,
+			Role:       query.ArtistRole(membership.Role),
+			JoinedAt:   membership.JoinedAt,
+		})
+	}
+	return views, nil
+}
+
+func (service *artistQueryService) ListInvitations(ctx context.Context, userID uuid.UUID) ([]query.ArtistInvitationView, error) {
+	const selectSQL = `
+		SELECT i.artist_invitation_id, i.artist_id, a.name, i.role, i.invited_by, i.created_at, i.expires_at FROM artist_invitation i
+		INNER JOIN user u ON u.email = i.email
+		INNER JOIN artist a ON a.artist_id = i.artist_id
+		WHERE u.user_id = ? AND u.deleted_at IS NULL AND i.status = ? AND i.expires_at > ?
+		ORDER BY i.created_at
+	`
+
+	binaryUUID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var invitations []sqlxArtistInvitationView
+	err = service.client.Select(ctx, &invitations, selectSQL, binaryUUID, int(domain.ArtistInvitationStatusPending), time.Now())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	views := make([]query.ArtistInvitationView, 0, len(invitations))
+	for _, invitation := range invitations {
+		views = append(views, query.ArtistInvitationView{
+			ID:         invitation.ArtistInvitationID,
+			ArtistID:   invitation.ArtistID,
+			ArtistName: invitation.Name,
+			Role:       query.ArtistRole(invitation.Role),
+			InvitedBy:  invitation.InvitedBy,
+			CreatedAt:  invitation.CreatedAt,
+			ExpiresAt:  invitation.ExpiresAt,
+		})
+	}
+	return views, nil
+}
+
+func (service *artistQueryService) GetMemberRole(ctx context.Context, artistID, userID uuid.UUID) (query.ArtistRole, error) {
+	const selectSQL = `
+		SELECT m.role FROM artist a
+		LEFT JOIN artist_member m ON m.artist_id = a.artist_id AND m.user_id = ?
+		WHERE a.artist_id = ?
+	`
+
+	binaryArtistID, err := artistID.MarshalBinary()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	binaryUserID, err := userID.MarshalBinary()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	var role *int
+	err = service.client.Get(ctx, &role, selectSQL, binaryUserID, binaryArtistID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, domain.ErrArtistNotFound
+		}
+		return 0, errors.WithStack(err)
+	}
+	if role == nil {
+		return 0, domain.ErrArtistMemberNotFound
+	}
+	return query.ArtistRole(*role), nil
+}
+
+type sqlxArtistView struct {
+	ArtistID  uuid.UUID `db:"artist_id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type sqlxArtistMemberView struct {
+	UserID   uuid.UUID `db:"user_id"`
+	Role     int       `db:"role"`
+	JoinedAt time.Time `db:"joined_at"`
+}
+
+type sqlxArtistMembershipView struct {
+	ArtistID uuid.UUID `db:"artist_id"`
+	Name     string    `db:"name"`
+	Role     int       `db:"role"`
+	JoinedAt time.Time `db:"joined_at"`
+}
+
+type sqlxArtistInvitationView struct {
+	ArtistInvitationID uuid.UUID `db:"artist_invitation_id"`
+	ArtistID           uuid.UUID `db:"artist_id"`
+	Name               string    `db:"name"`
+	Role               int       `db:"role"`
+	InvitedBy          uuid.UUID `db:"invited_by"`
+	CreatedAt          time.Time `db:"created_at"`
+	ExpiresAt          time.Time `db:"expires_at"`
+}
@@ -0,0 +1,106 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const auditLogColumns = `l.audit_log_id, l.action, l.actor_id, l.target_id, COALESCE(c.ip, '') AS ip, COALESCE(c.user_agent, '') AS user_agent,
+	l.request_id, l.details, l.occurred_at, COALESCE(s.hash, '') AS hash`
+
+func NewAuditLogQueryService(client sqlclient.Client) query.AuditLogQueryService {
+	return &auditLogQueryService{client: client}
+}
+
+type auditLogQueryService struct {
+	client sqlclient.Client
+}
+
+func (service *auditLogQueryService) ListEntries(ctx context.Context, spec query.AuditLogSpec) ([]query.AuditEntryView, error) {
+	var conditions []string
+	var args []interface{}
+
+	if spec.UserID != nil {
+		binaryUUID, err := spec.UserID.MarshalBinary()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		conditions = append(conditions, `(l.actor_id = ? OR l.target_id = ?)`)
+		args = append(args, binaryUUID, binaryUUID)
+	}
+	if spec.Action != "" {
+		conditions = append(conditions, `l.action = ?`)
+		args = append(args, spec.Action)
+	}
+	if spec.From != nil {
+		conditions = append(conditions, `l.occurred_at >= ?`)
+		args = append(args, *spec.From)
+	}
+	if spec.To != nil {
+		conditions = append(conditions, `l.occurred_at < ?`)
+		args = append(args, *spec.To)
+	}
+	if spec.BeforeSeq > 0 {
+		conditions = append(conditions, `l.audit_log_id < ?`)
+		args = append(args, spec.BeforeSeq)
+	}
+
+	selectSQL := `SELECT ` + auditLogColumns + ` FROM audit_log l
+		LEFT JOIN audit_log_client c ON c.audit_log_id = l.audit_log_id
+		LEFT JOIN audit_log_seal s ON s.audit_log_id = l.audit_log_id`
+	if len(conditions) != 0 {
+		selectSQL += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	selectSQL += ` ORDER BY l.audit_log_id DESC LIMIT ?`
+	args = append(args, spec.Limit)
+
+	var entries []sqlxAuditEntryView
+	err := service.client.Select(ctx, &entries, selectSQL, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	views := make([]query.AuditEntryView, 0, len(entries))
+	for _, entry := range entries {
+		var details map[string]string
+		err = json.Unmarshal([]byte(entry.Details), &details)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		views = append(views, query.AuditEntryView{
+			Seq:        entry.AuditLogID,
+			Action:     entry.Action,
+			ActorID:    entry.ActorID,
+			TargetID:   entry.TargetID,
+			IP:         entry.IP,
+			UserAgent:  entry.UserAgent,
+			RequestID:  entry.RequestID,
+			Details:    details,
+			OccurredAt: entry.OccurredAt,
+			Hash:       entry.Hash,
+		})
+	}
+	return views, nil
+}
+
+type sqlxAuditEntryView struct {
+	AuditLogID int64      `db:"audit_log_id"`
+	Action     string     `db:"action"`
+	ActorID    *uuid.UUID `db:"actor_id"`
+	TargetID   *uuid.UUID `db:"target_id"`
+	IP         string     `db:"ip"`
+	UserAgent  string     `db:"user_agent"`
+	RequestID  string     `db:"request_id"`
+	Details    string     `db:"details"`
+	OccurredAt time.Time  `db:"occurred_at"`
+	Hash       string     `db:"hash"`
+}
@@ -0,0 +1,80 @@
+package query
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const creatorApplicationColumns = `creator_application_id, user_id, status, statement, reviewer_id, reviewer_notes, applied_at, review_started_at, decided_at`
+
+func NewCreatorApplicationQueryService(client sqlclient.Client) query.CreatorApplicationQueryService {
+	return &creatorApplicationQueryService{client: client}
+}
+
+type creatorApplicationQueryService struct {
+	client sqlclient.Client
+}
+
+func (service *creatorApplicationQueryService) ListPendingApplications(ctx context.Context, offset, limit int) ([]query.CreatorApplicationView, error) {
+	const selectSQL = `SELECT ` + creatorApplicationColumns + ` FROM creator_application WHERE status IN (?, ?) ORDER BY applied_at LIMIT ? OFFSET ?`
+
+	return service.selectAll(ctx, selectSQL,
+		int(query.CreatorApplicationApplied),
+		int(query.CreatorApplicationUnderReview),
+		limit,
+		offset,
+	)
+}
+
+func (service *creatorApplicationQueryService) ListUserApplications(ctx context.Context, userID uuid.UUID) ([]query.CreatorApplicationView, error) {
+	const selectSQL = `SELECT ` + creatorApplicationColumns + ` FROM creator_application WHERE user_id = ? ORDER BY applied_at`
+
+	binaryUUID, err := userID.MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return service.selectAll(ctx, selectSQL, binaryUUID)
+}
+
+func (service *creatorApplicationQueryService) selectAll(ctx context.Context, selectSQL string, args ...interface{}) ([]query.CreatorApplicationView, error) {
+	var applications []sqlxCreatorApplicationView
+	err := service.client.Select(ctx, &applications, selectSQL, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	views := make([]query.CreatorApplicationView, 0, len(applications))
+	for _, application := range applications {
+		views = append(views, query.CreatorApplicationView{
+			ID:              application.CreatorApplicationID,
+			UserID:          application.UserID,
+			Status:          query.CreatorApplicationStatus(application.Status),
+			Statement:       application.Statement,
+			ReviewerID:      application.ReviewerID,
+			ReviewerNotes:   application.ReviewerNotes,
+			AppliedAt:       application.AppliedAt,
+			ReviewStartedAt: application.ReviewStartedAt,
+			DecidedAt:       application.DecidedAt,
+		})
+	}
+	return views, nil
+}
+
+type sqlxCreatorApplicationView struct {
+	CreatorApplicationID uuid.UUID  `db:"creator_application_id"`
+	UserID               uuid.UUID  `db:"user_id"`
+	Status               int        `db:"status"`
+	Statement            string     `db:"statement"`
+	ReviewerID           *uuid.UUID `db:"reviewer_id"`
+	ReviewerNotes        string     `db:"reviewer_notes"`
+	AppliedAt            time.Time  `db:"applied_at"`
+	ReviewStartedAt      *time.Time `db:"review_started_at"`
+	DecidedAt            *time.Time `db:"decided_at"`
+}
@@ -0,0 +1,94 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+func NewOAuth2QueryService(client sqlclient.Client) query.OAuth2QueryService {
+	return &oauth2QueryService{client: client}
+}
+
+type oauth2QueryService struct {
+	client sqlclient.Client
+}
+
+func (service *oauth2QueryService) GetClient(ctx context.Context, clientID string) (query.OAuth2ClientView, error) {
+	const selectSQL = `
+		SELECT client_id, name, secret_hash, redirect_uris, scopes, grant_types, first_party, created_at
+		FROM oauth2_client WHERE client_id = ?
+	`
+
+	var client sqlxOAuth2ClientView
+	err := service.client.Get(ctx, &client, selectSQL, clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return query.OAuth2ClientView{}, domain.ErrOAuth2ClientNotFound
+		}
+		return query.OAuth2ClientView{}, errors.WithStack(err)
+	}
+
+	return query.OAuth2ClientView{
+		ID:           client.ID,
+		Name:         client.Name,
+		RedirectURIs: strings.Fields(client.RedirectURIs),
+		Scopes:       strings.Fields(client.Scopes),
+		GrantTypes:   strings.Fields(client.GrantTypes),
+		Public:       client.SecretHash == "",
+		FirstParty:   client.FirstParty,
+		CreatedAt:    client.CreatedAt,
+	}, nil
+}
+
+func (service *oauth2QueryService) GetToken(ctx context.Context, tokenHash string) (query.OAuth2TokenView, error) {
+	const selectSQL = `
+		SELECT t.kind, t.client_id, t.user_id, t.scopes, t.expires_at FROM oauth2_token t
+		LEFT JOIN user u ON u.user_id = t.user_id
+		WHERE t.token_hash = ? AND (t.user_id IS NULL OR (u.user_id IS NOT NULL AND u.deleted_at IS NULL AND u.erased_at IS NULL))
+	`
+
+	var token sqlxOAuth2TokenView
+	err := service.client.Get(ctx, &token, selectSQL, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return query.OAuth2TokenView{}, domain.ErrOAuth2TokenNotFound
+		}
+		return query.OAuth2TokenView{}, errors.WithStack(err)
+	}
+
+	return query.OAuth2TokenView{
+		Kind:      query.OAuth2TokenKind(token.Kind),
+		ClientID:  token.ClientID,
+		UserID:    token.UserID,
+		Scopes:    strings.Fields(token.Scopes),
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+type sqlxOAuth2ClientView struct {
+	ID           string    `db:"client_id"`
+	Name         string    `db:"name"`
+	SecretHash   string    `db:"secret_hash"`
+	RedirectURIs string    `db:"redirect_uris"`
+	Scopes       string    `db:"scopes"`
+	GrantTypes   string    `db:"grant_types"`
+	FirstParty   bool      `db:"first_party"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type sqlxOAuth2TokenView struct {
+	Kind      int        `db:"kind"`
+	ClientID  string     `db:"client_id"`
+	UserID    *uuid.UUID `db:"user_id"`
+	Scopes    string     `db:"scopes"`
+	ExpiresAt time.Time  `db:"expires_at"`
+}
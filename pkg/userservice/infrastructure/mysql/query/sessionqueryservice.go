@@ -0,0 +1,49 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+func NewSessionQueryService(client sqlclient.Client) query.SessionQueryService {
+	return &sessionQueryService{client: client}
+}
+
+type sessionQueryService struct {
+	client sqlclient.Client
+}
+
+func (service *sessionQueryService) GetSession(ctx context.Context, tokenHash string) (query.SessionView, error) {
+	const selectSQL = `
+		SELECT s.user_id, s.expires_at FROM session s
+		INNER JOIN user u ON u.user_id = s.user_id
+		WHERE s.token_hash = ? AND u.deleted_at IS NULL AND u.erased_at IS NULL
+	`
+
+	var session sqlxSessionView
+	err := service.client.Get(ctx, &session, selectSQL, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return query.SessionView{}, domain.ErrSessionNotFound
+		}
+		return query.SessionView{}, errors.WithStack(err)
+	}
+
+	return query.SessionView{
+		UserID:    session.UserID,
+		ExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+type sqlxSessionView struct {
+	UserID    uuid.UUID `db:"user_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
@@ -0,0 +1,94 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const subscriptionColumns = `s.plan, s.status, s.period_start, s.period_end, s.trial`
+
+func NewSubscriptionQueryService(client sqlclient.Client) query.SubscriptionQueryService {
+	return &subscriptionQueryService{client: client}
+}
+
+type subscriptionQueryService struct {
+	client sqlclient.Client
+}
+
+func (service *subscriptionQueryService) GetEntitlements(ctx context.Context, userID uuid.UUID) (query.EntitlementsView, error) {
+	const selectOwnSQL = `SELECT ` + subscriptionColumns + ` FROM subscription s WHERE s.user_id = ?`
+	const selectManagerSQL = `
+		SELECT ` + subscriptionColumns + ` FROM household_member m
+		INNER JOIN household h ON h.household_id = m.household_id
+		INNER JOIN subscription s ON s.user_id = h.manager_id
+		WHERE m.user_id = ? AND h.manager_id <> m.user_id
+	`
+
+	binaryUUID, err := userID.MarshalBinary()
+	if err != nil {
+		return query.EntitlementsView{}, errors.WithStack(err)
+	}
+
+	now := time.Now()
+	own, err := service.findSubscription(ctx, selectOwnSQL, binaryUUID)
+	if err != nil {
+		return query.EntitlementsView{}, err
+	}
+	var manager *domain.Subscription
+	if own == nil || own.EffectivePlan(now) == domain.FreePlan {
+		manager, err = service.findSubscription(ctx, selectManagerSQL, binaryUUID)
+		if err != nil {
+			return query.EntitlementsView{}, err
+		}
+	}
+	if own == nil {
+		own = &domain.Subscription{UserID: domain.UserID(userID), Plan: domain.FreePlan}
+	}
+
+	effective := domain.ResolveEffectiveSubscription(*own, manager, now)
+	view := query.EntitlementsView{
+		Plan:              query.SubscriptionPlan(effective.Plan),
+		Entitlements:      make([]string, 0, len(effective.Entitlements)),
+		ValidUntil:        effective.ValidUntil,
+		Trial:             effective.Trial,
+		SharedByHousehold: effective.SharedByHousehold,
+	}
+	for _, entitlement := range effective.Entitlements {
+		view.Entitlements = append(view.Entitlements, string(entitlement))
+	}
+	return view, nil
+}
+
+func (service *subscriptionQueryService) findSubscription(ctx context.Context, selectSQL string, args ...interface{}) (*domain.Subscription, error) {
+	var subscription sqlxSubscriptionView
+	err := service.client.Get(ctx, &subscription, selectSQL, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+	return &domain.Subscription{
+		Plan:        domain.SubscriptionPlan(subscription.Plan),
+		Status:      domain.SubscriptionStatus(subscription.Status),
+		PeriodStart: subscription.PeriodStart,
+		PeriodEnd:   subscription.PeriodEnd,
+		Trial:       subscription.Trial,
+	}, nil
+}
+
+type sqlxSubscriptionView struct {
+	Plan        int        `db:"plan"`
+	Status      int        `db:"status"`
+	PeriodStart *time.Time `db:"period_start"`
+	PeriodEnd   *time.Time `db:"period_end"`
+	Trial       bool       `db:"trial"`
+}
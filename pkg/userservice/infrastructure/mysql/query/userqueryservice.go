@@ -1,28 +1,33 @@
 package query
 
 import (
+	"context"
 	"database/sql"
+	"strings"
+	"time"
 
-	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"userservice/pkg/userservice/app/query"
 	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
 )
 
-func NewUserQueryService(client mysql.Client) query.UserQueryService {
+const userColumns = `user_id, email, password, password_algorithm, role, failed_login_attempts, locked_until`
+
+func NewUserQueryService(client sqlclient.Client) query.UserQueryService {
 	return &userQueryService{
 		client: client,
 	}
 }
 
 type userQueryService struct {
-	client mysql.Client
+	client sqlclient.Client
 }
 
-func (service *userQueryService) GetUser(id uuid.UUID) (query.UserView, error) {
-	const selectSQL = `SELECT * from user WHERE user_id = ?`
+func (service *userQueryService) GetUser(ctx context.Context, id uuid.UUID) (query.UserView, error) {
+	const selectSQL = `SELECT ` + userColumns + ` FROM user WHERE user_id = ? AND deleted_at IS NULL`
 
 	binaryUUID, err := id.MarshalBinary()
 	if err != nil {
@@ -31,7 +36,7 @@ func (service *userQueryService) GetUser(id uuid.UUID) (query.UserView, error) {
 
 	var user sqlxUserView
 
-	err = service.client.Get(&user, selectSQL, binaryUUID)
+	err = service.client.Get(ctx, &user, selectSQL, binaryUUID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return query.UserView{}, query.ErrUserNotFound
@@ -39,20 +44,15 @@ func (service *userQueryService) GetUser(id uuid.UUID) (query.UserView, error) {
 		return query.UserView{}, errors.WithStack(err)
 	}
 
-	return query.UserView{
-		ID:       user.UserID,
-		Email:    user.Email,
-		Password: user.Password,
-		Role:     query.Role(user.Role),
-	}, nil
+	return makeUserView(user), nil
 }
 
-func (service *userQueryService) GetByEmail(email string) (query.UserView, error) {
-	const selectSQL = `SELECT * from user WHERE email = ?`
+func (service *userQueryService) GetByEmail(ctx context.Context, email string) (query.UserView, error) {
+	const selectSQL = `SELECT ` + userColumns + ` FROM user WHERE email = ? AND deleted_at IS NULL AND erased_at IS NULL`
 
 	var user sqlxUserView
 
-	err := service.client.Get(&user, selectSQL, email)
+	err := service.client.Get(ctx, &user, selectSQL, domain.NormalizeEmail(email))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return query.UserView{}, domain.ErrUserNotFound
@@ -60,17 +60,53 @@ func (service *userQueryService) GetByEmail(email string) (query.UserView, error
 		return query.UserView{}, errors.WithStack(err)
 	}
 
+	return makeUserView(user), nil
+}
+
+func (service *userQueryService) ListUsers(ctx context.Context, spec query.ListUsersSpec) ([]query.UserView, error) {
+	conditions := []string{`deleted_at IS NULL`}
+	var args []interface{}
+
+	if spec.Role != nil {
+		conditions = append(conditions, `role = ?`)
+		args = append(args, int(*spec.Role))
+	}
+
+	selectSQL := `SELECT ` + userColumns + ` FROM user WHERE ` + strings.Join(conditions, ` AND `) + ` ORDER BY email LIMIT ? OFFSET ?`
+	args = append(args, spec.Limit, spec.Offset)
+
+	var users []sqlxUserView
+
+	err := service.client.Select(ctx, &users, selectSQL, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	views := make([]query.UserView, 0, len(users))
+	for _, user := range users {
+		views = append(views, makeUserView(user))
+	}
+	return views, nil
+}
+
+func makeUserView(user sqlxUserView) query.UserView {
 	return query.UserView{
-		ID:       user.UserID,
-		Email:    user.Email,
-		Password: user.Password,
-		Role:     query.Role(user.Role),
-	}, nil
+		ID:                  user.UserID,
+		Email:               user.Email,
+		Password:            user.Password,
+		Role:                query.Role(user.Role),
+		PasswordAlgorithm:   user.PasswordAlgorithm,
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LockedUntil:         user.LockedUntil,
+	}
 }
 
 type sqlxUserView struct {
-	UserID   uuid.UUID `db:"user_id"`
-	Email    string    `db:"email"`
-	Password string    `db:"password"`
-	Role     int       `db:"role"`
+	UserID              uuid.UUID  `db:"user_id"`
+	Email               string     `db:"email"`
+	Password            string     `db:"password"`
+	PasswordAlgorithm   string     `db:"password_algorithm"`
+	Role                int        `db:"role"`
+	FailedLoginAttempts int        `db:"failed_login_attempts"`
+	LockedUntil         *time.Time `db:"locked_until"`
 }
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const artistInvitationColumns = `artist_invitation_id, artist_id, email, role, status, invited_by, invitee_id, created_at, expires_at, responded_at`
+
+func NewArtistInvitationRepository(client sqlclient.Client) domain.ArtistInvitationRepository {
+	return &artistInvitationRepository{client: client}
+}
+
+type artistInvitationRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *artistInvitationRepository) NewID() domain.ArtistInvitationID {
+	return domain.ArtistInvitationID(uuid.New())
+}
+
+func (repo *artistInvitationRepository) Find(ctx context.Context, id domain.ArtistInvitationID) (domain.ArtistInvitation, error) {
+	const selectSQL = `SELECT ` + artistInvitationColumns + ` FROM artist_invitation WHERE artist_invitation_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return domain.ArtistInvitation{}, errors.WithStack(err)
+	}
+
+	var invitation sqlxArtistInvitation
+	err = repo.client.Get(ctx, &invitation, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ArtistInvitation{}, domain.ErrArtistInvitationNotFound
+		}
+		return domain.ArtistInvitation{}, errors.WithStack(err)
+	}
+	return makeArtistInvitation(invitation), nil
+}
+
+func (repo *artistInvitationRepository) FindPendingByEmail(ctx context.Context, email string) ([]domain.ArtistInvitation, error) {
+	const selectSQL = `SELECT ` + artistInvitationColumns + ` FROM artist_invitation WHERE email = ? AND status = ? ORDER BY created_at`
+
+	return repo.selectAll(ctx, selectSQL, email, int(domain.ArtistInvitationStatusPending))
+}
+
+func (repo *artistInvitationRepository) FindPendingByArtist(ctx context.Context, artistID domain.ArtistID) ([]domain.ArtistInvitation, error) {
+	const selectSQL = `SELECT ` + artistInvitationColumns + ` FROM artist_invitation WHERE artist_id = ? AND status = ? ORDER BY created_at FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(artistID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectAll(ctx, selectSQL, binaryUUID, int(domain.ArtistInvitationStatusPending))
+}
+
+func (repo *artistInvitationRepository) Store(ctx context.Context, invitation domain.ArtistInvitation) error {
+	const insertSQL = `
+		INSERT INTO artist_invitation (` + artistInvitationColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			invitee_id = VALUES(invitee_id),
+			responded_at = VALUES(responded_at)
+	`
+
+	invitationID, err := uuid.UUID(invitation.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	artistID, err := uuid.UUID(invitation.ArtistID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	invitedBy, err := uuid.UUID(invitation.InvitedBy).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	inviteeID, err := optionalBinaryUUID(invitation.InviteeID)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		invitationID,
+		artistID,
+		invitation.Email,
+		int(invitation.Role),
+		int(invitation.Status),
+		invitedBy,
+		inviteeID,
+		invitation.CreatedAt,
+		invitation.ExpiresAt,
+		invitation.RespondedAt,
+	)
+	return err
+}
+
+func (repo *artistInvitationRepository) RemoveByInvitee(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM artist_invitation WHERE invitee_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *artistInvitationRepository) selectAll(ctx context.Context, query string, args ...interface{}) ([]domain.ArtistInvitation, error) {
+	var invitations []sqlxArtistInvitation
+	err := repo.client.Select(ctx, &invitations, query, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.ArtistInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, makeArtistInvitation(invitation))
+	}
+	return result, nil
+}
+
+func makeArtistInvitation(invitation sqlxArtistInvitation) domain.ArtistInvitation {
+	return domain.ArtistInvitation{
+		ID:          domain.ArtistInvitationID(invitation.ArtistInvitationID),
+		ArtistID:    domain.ArtistID(invitation.ArtistID),
+		Email:       invitation.Email,
+		Role:        domain.ArtistRole(invitation.Role),
+		Status:      domain.ArtistInvitationStatus(invitation.Status),
+		InvitedBy:   domain.UserID(invitation.InvitedBy),
+		InviteeID:   optionalUserID(invitation.InviteeID),
+		CreatedAt:   invitation.CreatedAt,
+		ExpiresAt:   invitation.ExpiresAt,
+		RespondedAt: invitation.RespondedAt,
+	}
+}
+
+type sqlxArtistInvitation struct {
+	ArtistInvitationID uuid.UUID  `db:"artist_invitation_id"`
+	ArtistID           uuid.UUID  `db:"artist_id"`
+	Email              string     `db:"email"`
+	Role               int        `db:"role"`
+	Status             int        `db:"status"`
+	InvitedBy          uuid.UUID  `db:"invited_by"`
+	InviteeID          *uuid.UUID `db:"invitee_id"`
+	CreatedAt          time.Time  `db:"created_at"`
+	ExpiresAt          time.Time  `db:"expires_at"`
+	RespondedAt        *time.Time `db:"responded_at"`
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const (
+	artistColumns       = `artist_id, name, created_at`
+	artistMemberColumns = `artist_id, user_id, role, joined_at`
+)
+
+func NewArtistRepository(client sqlclient.Client) domain.ArtistRepository {
+	return &artistRepository{client: client}
+}
+
+type artistRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *artistRepository) NewID() domain.ArtistID {
+	return domain.ArtistID(uuid.New())
+}
+
+func (repo *artistRepository) Find(ctx context.Context, id domain.ArtistID) (domain.Artist, error) {
+	const selectSQL = `SELECT ` + artistColumns + ` FROM artist WHERE artist_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return domain.Artist{}, errors.WithStack(err)
+	}
+
+	var artist sqlxArtist
+	err = repo.client.Get(ctx, &artist, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Artist{}, domain.ErrArtistNotFound
+		}
+		return domain.Artist{}, errors.WithStack(err)
+	}
+
+	return domain.Artist{
+		ID:        domain.ArtistID(artist.ArtistID),
+		Name:      artist.Name,
+		CreatedAt: artist.CreatedAt,
+	}, nil
+}
+
+func (repo *artistRepository) Store(ctx context.Context, artist domain.Artist) error {
+	const insertSQL = `
+		INSERT INTO artist (` + artistColumns + `) VALUES(?, ?, ?)
+		ON DUPLICATE KEY UPDATE name = VALUES(name)
+	`
+
+	binaryUUID, err := uuid.UUID(artist.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL, binaryUUID, artist.Name, artist.CreatedAt)
+	return err
+}
+
+func (repo *artistRepository) FindMember(ctx context.Context, id domain.ArtistID, userID domain.UserID) (domain.ArtistMember, error) {
+	const selectSQL = `SELECT ` + artistMemberColumns + ` FROM artist_member WHERE artist_id = ? AND user_id = ? FOR UPDATE`
+
+	artistID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return domain.ArtistMember{}, errors.WithStack(err)
+	}
+	binaryUserID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.ArtistMember{}, errors.WithStack(err)
+	}
+
+	var member sqlxArtistMember
+	err = repo.client.Get(ctx, &member, selectSQL, artistID, binaryUserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ArtistMember{}, domain.ErrArtistMemberNotFound
+		}
+		return domain.ArtistMember{}, errors.WithStack(err)
+	}
+	return makeArtistMember(member), nil
+}
+
+func (repo *artistRepository) FindMembers(ctx context.Context, id domain.ArtistID) ([]domain.ArtistMember, error) {
+	const selectSQL = `SELECT ` + artistMemberColumns + ` FROM artist_member WHERE artist_id = ? ORDER BY joined_at FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectMembers(ctx, selectSQL, binaryUUID)
+}
+
+func (repo *artistRepository) FindMembershipsByUser(ctx context.Context, userID domain.UserID) ([]domain.ArtistMember, error) {
+	const selectSQL = `SELECT ` + artistMemberColumns + ` FROM artist_member WHERE user_id = ? ORDER BY joined_at`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectMembers(ctx, selectSQL, binaryUUID)
+}
+
+func (repo *artistRepository) StoreMember(ctx context.Context, member domain.ArtistMember) error {
+	const insertSQL = `
+		INSERT INTO artist_member (` + artistMemberColumns + `) VALUES(?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE role = VALUES(role)
+	`
+
+	artistID, err := uuid.UUID(member.ArtistID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	userID, err := uuid.UUID(member.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL, artistID, userID, int(member.Role), member.JoinedAt)
+	return err
+}
+
+func (repo *artistRepository) RemoveMember(ctx context.Context, id domain.ArtistID, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM artist_member WHERE artist_id = ? AND user_id = ?`
+
+	artistID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	binaryUserID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, artistID, binaryUserID)
+	return err
+}
+
+func (repo *artistRepository) selectMembers(ctx context.Context, query string, args ...interface{}) ([]domain.ArtistMember, error) {
+	var members []sqlxArtistMember
+	err := repo.client.Select(ctx, &members, query, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.ArtistMember, 0, len(members))
+	for _, member := range members {
+		result = append(result, makeArtistMember(member))
+	}
+	return result, nil
+}
+
+func makeArtistMember(member sqlxArtistMember) domain.ArtistMember {
+	return domain.ArtistMember{
+		ArtistID: domain.ArtistID(member.ArtistID),
+		UserID:   domain.UserID(member.UserID),
+		Role:     domain.ArtistRole(member.Role),
+		JoinedAt: member.JoinedAt,
+	}
+}
+
+type sqlxArtist struct {
+	ArtistID  uuid.UUID `db:"artist_id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type sqlxArtistMember struct {
+	ArtistID uuid.UUID `db:"artist_id"`
+	UserID   uuid.UUID `db:"user_id"`
+	Role     int       `db:"role"`
+	JoinedAt time.Time `db:"joined_at"`
+}
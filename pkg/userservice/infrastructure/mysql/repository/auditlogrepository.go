@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const (
+	auditLogColumns       = `audit_log_id, action, actor_id, target_id, request_id, details, occurred_at`
+	sealedAuditLogColumns = `l.audit_log_id, l.action, l.actor_id, l.target_id, COALESCE(c.ip, '') AS ip, COALESCE(c.user_agent, '') AS user_agent,
+		l.request_id, l.details, l.occurred_at, s.seal_seq, s.prev_hash, s.hash`
+	auditLogHeadID = 1
+
+	maxAuditIPLength        = 45
+	maxAuditUserAgentLength = 255
+	maxAuditRequestIDLength = 64
+)
+
+func NewAuditLogRepository(client sqlclient.Client) domain.AuditLogRepository {
+	return &auditLogRepository{client: client}
+}
+
+type auditLogRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *auditLogRepository) Append(ctx context.Context, entry domain.AuditEntry) error {
+	const insertSQL = `INSERT INTO audit_log (action, actor_id, target_id, request_id, details, occurred_at) VALUES(?, ?, ?, ?, ?, ?)`
+	const insertClientSQL = `INSERT INTO audit_log_client (audit_log_id, ip, user_agent) VALUES(?, ?, ?)`
+	const insertPendingSQL = `INSERT INTO audit_log_pending (audit_log_id) VALUES(?)`
+
+	actorID, err := optionalBinaryUUID(entry.ActorID)
+	if err != nil {
+		return err
+	}
+	targetID, err := optionalBinaryUUID(entry.TargetID)
+	if err != nil {
+		return err
+	}
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	result, err := repo.client.Exec(ctx, insertSQL,
+		entry.Action,
+		actorID,
+		targetID,
+		truncate(entry.RequestID, maxAuditRequestIDLength),
+		string(details),
+		entry.OccurredAt,
+	)
+	if err != nil {
+		return err
+	}
+	seq, err := result.LastInsertId()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if entry.IP != "" || entry.UserAgent != "" {
+		_, err = repo.client.Exec(ctx, insertClientSQL,
+			seq,
+			truncate(entry.IP, maxAuditIPLength),
+			truncate(entry.UserAgent, maxAuditUserAgentLength),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = repo.client.Exec(ctx, insertPendingSQL, seq)
+	return err
+}
+
+func (repo *auditLogRepository) SealPending(ctx context.Context, limit int) (int, error) {
+	const selectHeadSQL = `SELECT last_seal_seq, last_hash FROM audit_log_head WHERE audit_log_head_id = ? FOR UPDATE`
+	const selectPendingSQL = `
+		SELECT ` + auditLogColumns + ` FROM audit_log
+		WHERE audit_log_id IN (SELECT audit_log_id FROM audit_log_pending)
+		ORDER BY audit_log_id LIMIT ?
+	`
+	const insertSealSQL = `INSERT INTO audit_log_seal (seal_seq, audit_log_id, prev_hash, hash) VALUES(?, ?, ?, ?)`
+	const deletePendingSQL = `DELETE FROM audit_log_pending WHERE audit_log_id = ?`
+	const updateHeadSQL = `UPDATE audit_log_head SET last_seal_seq = ?, last_hash = ? WHERE audit_log_head_id = ?`
+
+	var head sqlxAuditLogHead
+	err := repo.client.Get(ctx, &head, selectHeadSQL, auditLogHeadID)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	var entries []sqlxAuditEntry
+	err = repo.client.Select(ctx, &entries, selectPendingSQL, limit)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	for _, sqlxEntry := range entries {
+		entry, err := makeAuditEntry(sqlxEntry)
+		if err != nil {
+			return 0, err
+		}
+		entry, err = entry.Seal(head.LastSealSeq, head.LastHash)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = repo.client.Exec(ctx, insertSealSQL, entry.ChainSeq, entry.Seq, entry.PrevHash, entry.Hash)
+		if err != nil {
+			return 0, err
+		}
+		_, err = repo.client.Exec(ctx, deletePendingSQL, entry.Seq)
+		if err != nil {
+			return 0, err
+		}
+		head.LastSealSeq, head.LastHash = entry.ChainSeq, entry.Hash
+	}
+
+	_, err = repo.client.Exec(ctx, updateHeadSQL, head.LastSealSeq, head.LastHash, auditLogHeadID)
+	if err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+func (repo *auditLogRepository) FindSealedAfter(ctx context.Context, afterChainSeq int64, limit int) ([]domain.AuditEntry, error) {
+	const selectSQL = `
+		SELECT ` + sealedAuditLogColumns + ` FROM audit_log_seal s
+		INNER JOIN audit_log l ON l.audit_log_id = s.audit_log_id
+		LEFT JOIN audit_log_client c ON c.audit_log_id = s.audit_log_id
+		WHERE s.seal_seq > ? ORDER BY s.seal_seq LIMIT ?
+	`
+
+	var entries []sqlxAuditEntry
+	err := repo.client.Select(ctx, &entries, selectSQL, afterChainSeq, limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		domainEntry, err := makeAuditEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, domainEntry)
+	}
+	return result, nil
+}
+
+func (repo *auditLogRepository) RemoveClientData(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `
+		DELETE c FROM audit_log_client c
+		INNER JOIN audit_log l ON l.audit_log_id = c.audit_log_id
+		WHERE l.actor_id = ? OR l.target_id = ?
+	`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID, binaryUUID)
+	return err
+}
+
+func makeAuditEntry(entry sqlxAuditEntry) (domain.AuditEntry, error) {
+	var details map[string]string
+	err := json.Unmarshal([]byte(entry.Details), &details)
+	if err != nil {
+		return domain.AuditEntry{}, errors.WithStack(err)
+	}
+
+	return domain.AuditEntry{
+		Seq:        entry.AuditLogID,
+		Action:     entry.Action,
+		ActorID:    optionalUserID(entry.ActorID),
+		TargetID:   optionalUserID(entry.TargetID),
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		RequestID:  entry.RequestID,
+		Details:    details,
+		OccurredAt: entry.OccurredAt,
+		ChainSeq:   entry.SealSeq,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}, nil
+}
+
+// truncate cuts by runes, varchar length of mysql is in characters, invalid utf-8 would be rejected by mysql
+func truncate(s string, maxLength int) string {
+	runes := []rune(strings.ToValidUTF8(s, ""))
+	if len(runes) <= maxLength {
+		return string(runes)
+	}
+	return string(runes[:maxLength])
+}
+
+func optionalBinaryUUID(id *domain.UserID) (interface{}, error) {
+	if id == nil {
+		return nil, nil
+	}
+	binaryUUID, err := uuid.UUID(*id).MarshalBinary()
+	return binaryUUID, errors.WithStack(err)
+}
+
+func optionalUserID(id *uuid.UUID) *domain.UserID {
+	if id == nil {
+		return nil
+	}
+	userID := domain.UserID(*id)
+	return &userID
+}
+
+type sqlxAuditLogHead struct {
+	LastSealSeq int64  `db:"last_seal_seq"`
+	LastHash    string `db:"last_hash"`
+}
+
+type sqlxAuditEntry struct {
+	AuditLogID int64      `db:"audit_log_id"`
+	Action     string     `db:"action"`
+	ActorID    *uuid.UUID `db:"actor_id"`
+	TargetID   *uuid.UUID `db:"target_id"`
+	IP         string     `db:"ip"`
+	UserAgent  string     `db:"user_agent"`
+	RequestID  string     `db:"request_id"`
+	Details    string     `db:"details"`
+	OccurredAt time.Time  `db:"occurred_at"`
+	SealSeq    int64      `db:"seal_seq"`
+	PrevHash   string     `db:"prev_hash"`
+	Hash       string     `db:"hash"`
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const blockColumns = `blocker_id, blocked_id, created_at`
+
+func NewBlockRepository(client sqlclient.Client) domain.BlockRepository {
+	return &blockRepository{client: client}
+}
+
+type blockRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *blockRepository) Find(ctx context.Context, blockerID, blockedID domain.UserID) (domain.Block, error) {
+	const selectSQL = `SELECT ` + blockColumns + ` FROM user_block WHERE blocker_id = ? AND blocked_id = ? FOR UPDATE`
+
+	binaryBlockerID, err := uuid.UUID(blockerID).MarshalBinary()
+	if err != nil {
+		return domain.Block{}, errors.WithStack(err)
+	}
+	binaryBlockedID, err := uuid.UUID(blockedID).MarshalBinary()
+	if err != nil {
+		return domain.Block{}, errors.WithStack(err)
+	}
+
+	var block sqlxBlock
+	err = repo.client.Get(ctx, &block, selectSQL, binaryBlockerID, binaryBlockedID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Block{}, domain.ErrBlockNotFound
+		}
+		return domain.Block{}, errors.WithStack(err)
+	}
+	return makeBlock(block), nil
+}
+
+func (repo *blockRepository) FindByBlocker(ctx context.Context, blockerID domain.UserID, offset, limit int) ([]domain.Block, error) {
+	const selectSQL = `
+		SELECT ` + blockColumns + ` FROM user_block
+		WHERE blocker_id = ?
+		ORDER BY created_at DESC, blocked_id
+		LIMIT ? OFFSET ?
+	`
+
+	binaryUUID, err := uuid.UUID(blockerID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectAll(ctx, selectSQL, binaryUUID, limit, offset)
+}
+
+func (repo *blockRepository) FindBetween(ctx context.Context, pairs []domain.UserPair) ([]domain.Block, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	conditions := make([]string, 0, len(pairs))
+	args := make([]interface{}, 0, len(pairs)*4)
+	for _, pair := range pairs {
+		userID, err := uuid.UUID(pair.UserID).MarshalBinary()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		otherID, err := uuid.UUID(pair.OtherID).MarshalBinary()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		conditions = append(conditions, `(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)`)
+		args = append(args, userID, otherID, otherID, userID)
+	}
+
+	selectSQL := `SELECT ` + blockColumns + ` FROM user_block WHERE ` + strings.Join(conditions, " OR ")
+	return repo.selectAll(ctx, selectSQL, args...)
+}
+
+func (repo *blockRepository) IsBlockedEitherWay(ctx context.Context, userID, otherID domain.UserID) (bool, error) {
+	const selectSQL = `
+		SELECT COUNT(*) FROM user_block
+		WHERE (blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)
+	`
+
+	binaryUserID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	binaryOtherID, err := uuid.UUID(otherID).MarshalBinary()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	var count int
+	err = repo.client.Get(ctx, &count, selectSQL, binaryUserID, binaryOtherID, binaryOtherID, binaryUserID)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return count > 0, nil
+}
+
+func (repo *blockRepository) Store(ctx context.Context, block domain.Block) error {
+	const insertSQL = `
+		INSERT INTO user_block (` + blockColumns + `) VALUES(?, ?, ?)
+		ON DUPLICATE KEY UPDATE blocker_id = blocker_id
+	`
+
+	blockerID, err := uuid.UUID(block.BlockerID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	blockedID, err := uuid.UUID(block.BlockedID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL, blockerID, blockedID, block.CreatedAt)
+	return err
+}
+
+func (repo *blockRepository) Remove(ctx context.Context, blockerID, blockedID domain.UserID) error {
+	const deleteSQL = `DELETE FROM user_block WHERE blocker_id = ? AND blocked_id = ?`
+
+	binaryBlockerID, err := uuid.UUID(blockerID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	binaryBlockedID, err := uuid.UUID(blockedID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryBlockerID, binaryBlockedID)
+	return err
+}
+
+func (repo *blockRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM user_block WHERE blocker_id = ? OR blocked_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID, binaryUUID)
+	return err
+}
+
+func (repo *blockRepository) selectAll(ctx context.Context, query string, args ...interface{}) ([]domain.Block, error) {
+	var blocks []sqlxBlock
+	err := repo.client.Select(ctx, &blocks, query, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.Block, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, makeBlock(block))
+	}
+	return result, nil
+}
+
+func makeBlock(block sqlxBlock) domain.Block {
+	return domain.Block{
+		BlockerID: domain.UserID(block.BlockerID),
+		BlockedID: domain.UserID(block.BlockedID),
+		CreatedAt: block.CreatedAt,
+	}
+}
+
+type sqlxBlock struct {
+	BlockerID uuid.UUID `db:"blocker_id"`
+	BlockedID uuid.UUID `db:"blocked_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const consentColumns = `consent_id, user_id, kind, version, channel, granted, source_ip, recorded_at`
+
+func NewConsentRepository(client sqlclient.Client) domain.ConsentRepository {
+	return &consentRepository{client: client}
+}
+
+type consentRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *consentRepository) NewID() domain.ConsentID {
+	return domain.ConsentID(uuid.New())
+}
+
+func (repo *consentRepository) Store(ctx context.Context, consent domain.Consent) error {
+	const insertSQL = `INSERT INTO consent (` + consentColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?)`
+
+	consentID, err := uuid.UUID(consent.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	userID, err := uuid.UUID(consent.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		consentID,
+		userID,
+		int(consent.Kind),
+		consent.Version,
+		consent.Channel,
+		consent.Granted,
+		consent.SourceIP,
+		consent.RecordedAt,
+	)
+	return err
+}
+
+func (repo *consentRepository) FindByUser(ctx context.Context, userID domain.UserID) ([]domain.Consent, error) {
+	const selectSQL = `SELECT ` + consentColumns + ` FROM consent WHERE user_id = ? ORDER BY recorded_at`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var consents []sqlxConsent
+	err = repo.client.Select(ctx, &consents, selectSQL, binaryUUID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.Consent, 0, len(consents))
+	for _, consent := range consents {
+		result = append(result, domain.Consent{
+			ID:         domain.ConsentID(consent.ConsentID),
+			UserID:     domain.UserID(consent.UserID),
+			Kind:       domain.ConsentKind(consent.Kind),
+			Version:    consent.Version,
+			Channel:    consent.Channel,
+			Granted:    consent.Granted,
+			SourceIP:   consent.SourceIP,
+			RecordedAt: consent.RecordedAt,
+		})
+	}
+	return result, nil
+}
+
+func (repo *consentRepository) AnonymizeByUser(ctx context.Context, userID domain.UserID) error {
+	const updateSQL = `UPDATE consent SET source_ip = '' WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, updateSQL, binaryUUID)
+	return err
+}
+
+type sqlxConsent struct {
+	ConsentID  uuid.UUID `db:"consent_id"`
+	UserID     uuid.UUID `db:"user_id"`
+	Kind       int       `db:"kind"`
+	Version    string    `db:"version"`
+	Channel    string    `db:"channel"`
+	Granted    bool      `db:"granted"`
+	SourceIP   string    `db:"source_ip"`
+	RecordedAt time.Time `db:"recorded_at"`
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const creatorApplicationColumns = `creator_application_id, user_id, status, statement, reviewer_id, reviewer_notes, applied_at, review_started_at, decided_at`
+
+func NewCreatorApplicationRepository(client sqlclient.Client) domain.CreatorApplicationRepository {
+	return &creatorApplicationRepository{client: client}
+}
+
+type creatorApplicationRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *creatorApplicationRepository) NewID() domain.CreatorApplicationID {
+	return domain.CreatorApplicationID(uuid.New())
+}
+
+func (repo *creatorApplicationRepository) Find(ctx context.Context, id domain.CreatorApplicationID) (domain.CreatorApplication, error) {
+	const selectSQL = `SELECT ` + creatorApplicationColumns + ` FROM creator_application WHERE creator_application_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return domain.CreatorApplication{}, errors.WithStack(err)
+	}
+
+	return repo.get(ctx, selectSQL, binaryUUID)
+}
+
+func (repo *creatorApplicationRepository) FindOpenByUser(ctx context.Context, userID domain.UserID) (domain.CreatorApplication, error) {
+	const selectSQL = `SELECT ` + creatorApplicationColumns + ` FROM creator_application WHERE user_id = ? AND status IN (?, ?) LIMIT 1 FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.CreatorApplication{}, errors.WithStack(err)
+	}
+
+	return repo.get(ctx, selectSQL,
+		binaryUUID,
+		int(domain.CreatorApplicationStatusApplied),
+		int(domain.CreatorApplicationStatusUnderReview),
+	)
+}
+
+// Add relies on unique index of open applications, so concurrent applications of user can not both be inserted
+func (repo *creatorApplicationRepository) Add(ctx context.Context, application domain.CreatorApplication) error {
+	const insertSQL = `INSERT IGNORE INTO creator_application (` + creatorApplicationColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	args, err := creatorApplicationArgs(application)
+	if err != nil {
+		return err
+	}
+	result, err := repo.client.Exec(ctx, insertSQL, args...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if inserted == 0 {
+		return domain.ErrCreatorApplicationAlreadyOpen
+	}
+	return nil
+}
+
+func (repo *creatorApplicationRepository) Store(ctx context.Context, application domain.CreatorApplication) error {
+	const insertSQL = `
+		INSERT INTO creator_application (` + creatorApplicationColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			statement = VALUES(statement),
+			reviewer_id = VALUES(reviewer_id),
+			reviewer_notes = VALUES(reviewer_notes),
+			review_started_at = VALUES(review_started_at),
+			decided_at = VALUES(decided_at)
+	`
+
+	args, err := creatorApplicationArgs(application)
+	if err != nil {
+		return err
+	}
+	_, err = repo.client.Exec(ctx, insertSQL, args...)
+	return err
+}
+
+func (repo *creatorApplicationRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM creator_application WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *creatorApplicationRepository) get(ctx context.Context, query string, args ...interface{}) (domain.CreatorApplication, error) {
+	var application sqlxCreatorApplication
+
+	err := repo.client.Get(ctx, &application, query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.CreatorApplication{}, domain.ErrCreatorApplicationNotFound
+		}
+		return domain.CreatorApplication{}, errors.WithStack(err)
+	}
+
+	return makeCreatorApplication(application), nil
+}
+
+func creatorApplicationArgs(application domain.CreatorApplication) ([]interface{}, error) {
+	applicationID, err := uuid.UUID(application.ID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	userID, err := uuid.UUID(application.UserID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	reviewerID, err := optionalBinaryUUID(application.ReviewerID)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		applicationID,
+		userID,
+		int(application.Status),
+		application.Statement,
+		reviewerID,
+		application.ReviewerNotes,
+		application.AppliedAt,
+		application.ReviewStartedAt,
+		application.DecidedAt,
+	}, nil
+}
+
+func makeCreatorApplication(application sqlxCreatorApplication) domain.CreatorApplication {
+	return domain.CreatorApplication{
+		ID:              domain.CreatorApplicationID(application.CreatorApplicationID),
+		UserID:          domain.UserID(application.UserID),
+		Status:          domain.CreatorApplicationStatus(application.Status),
+		Statement:       application.Statement,
+		ReviewerID:      optionalUserID(application.ReviewerID),
+		ReviewerNotes:   application.ReviewerNotes,
+		AppliedAt:       application.AppliedAt,
+		ReviewStartedAt: application.ReviewStartedAt,
+		DecidedAt:       application.DecidedAt,
+	}
+}
+
+type sqlxCreatorApplication struct {
+	CreatorApplicationID uuid.UUID  `db:"creator_application_id"`
+	UserID               uuid.UUID  `db:"user_id"`
+	Status               int        `db:"status"`
+	Statement            string     `db:"statement"`
+	ReviewerID           *uuid.UUID `db:"reviewer_id"`
+	ReviewerNotes        string     `db:"reviewer_notes"`
+	AppliedAt            time.Time  `db:"applied_at"`
+	ReviewStartedAt      *time.Time `db:"review_started_at"`
+	DecidedAt            *time.Time `db:"decided_at"`
+}
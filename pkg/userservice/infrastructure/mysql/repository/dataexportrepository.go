@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const dataExportColumns = `data_export_id, user_id, status, download_token_hash, requested_at, started_at, completed_at, expires_at, error`
+
+func NewDataExportRepository(client sqlclient.Client) domain.DataExportRepository {
+	return &dataExportRepository{client: client}
+}
+
+type dataExportRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *dataExportRepository) NewID() domain.DataExportID {
+	return domain.DataExportID(uuid.New())
+}
+
+func (repo *dataExportRepository) Find(ctx context.Context, id domain.DataExportID) (domain.DataExport, error) {
+	const selectSQL = `SELECT ` + dataExportColumns + ` FROM data_export WHERE data_export_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return domain.DataExport{}, errors.WithStack(err)
+	}
+
+	return repo.get(ctx, domain.ErrDataExportNotFound, selectSQL, binaryUUID)
+}
+
+func (repo *dataExportRepository) FindByDownloadTokenHash(ctx context.Context, tokenHash string) (domain.DataExport, error) {
+	const selectSQL = `SELECT ` + dataExportColumns + ` FROM data_export WHERE download_token_hash = ?`
+
+	return repo.get(ctx, domain.ErrDataExportNotFound, selectSQL, tokenHash)
+}
+
+func (repo *dataExportRepository) ClaimPending(ctx context.Context, staleBefore time.Time) (domain.DataExport, error) {
+	// SKIP LOCKED lets several instances take different exports at the same time
+	const selectSQL = `
+		SELECT ` + dataExportColumns + ` FROM data_export
+		WHERE status = ? OR (status = ? AND started_at < ?)
+		ORDER BY requested_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	return repo.get(ctx, domain.ErrNoPendingDataExport, selectSQL,
+		int(domain.DataExportPending),
+		int(domain.DataExportProcessing),
+		staleBefore,
+	)
+}
+
+func (repo *dataExportRepository) FindExpired(ctx context.Context, at time.Time, limit int) ([]domain.DataExport, error) {
+	const selectSQL = `SELECT ` + dataExportColumns + ` FROM data_export WHERE status = ? AND expires_at < ? LIMIT ? FOR UPDATE`
+
+	return repo.selectAll(ctx, selectSQL, int(domain.DataExportReady), at, limit)
+}
+
+func (repo *dataExportRepository) FindByUser(ctx context.Context, userID domain.UserID) ([]domain.DataExport, error) {
+	const selectSQL = `SELECT ` + dataExportColumns + ` FROM data_export WHERE user_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectAll(ctx, selectSQL, binaryUUID)
+}
+
+func (repo *dataExportRepository) Store(ctx context.Context, export domain.DataExport) error {
+	const insertSQL = `
+		INSERT INTO data_export (` + dataExportColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			started_at = VALUES(started_at),
+			completed_at = VALUES(completed_at),
+			expires_at = VALUES(expires_at),
+			error = VALUES(error)
+	`
+
+	exportID, err := uuid.UUID(export.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	userID, err := uuid.UUID(export.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		exportID,
+		userID,
+		int(export.Status),
+		export.DownloadTokenHash,
+		export.RequestedAt,
+		export.StartedAt,
+		export.CompletedAt,
+		export.ExpiresAt,
+		export.Error,
+	)
+	return err
+}
+
+func (repo *dataExportRepository) get(ctx context.Context, notFoundErr error, query string, args ...interface{}) (domain.DataExport, error) {
+	var export sqlxDataExport
+
+	err := repo.client.Get(ctx, &export, query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.DataExport{}, notFoundErr
+		}
+		return domain.DataExport{}, errors.WithStack(err)
+	}
+
+	return makeDataExport(export), nil
+}
+
+func (repo *dataExportRepository) selectAll(ctx context.Context, query string, args ...interface{}) ([]domain.DataExport, error) {
+	var exports []sqlxDataExport
+	err := repo.client.Select(ctx, &exports, query, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.DataExport, 0, len(exports))
+	for _, export := range exports {
+		result = append(result, makeDataExport(export))
+	}
+	return result, nil
+}
+
+func makeDataExport(export sqlxDataExport) domain.DataExport {
+	return domain.DataExport{
+		ID:                domain.DataExportID(export.DataExportID),
+		UserID:            domain.UserID(export.UserID),
+		Status:            domain.DataExportStatus(export.Status),
+		DownloadTokenHash: export.DownloadTokenHash,
+		RequestedAt:       export.RequestedAt,
+		StartedAt:         export.StartedAt,
+		CompletedAt:       export.CompletedAt,
+		ExpiresAt:         export.ExpiresAt,
+		Error:             export.Error,
+	}
+}
+
+type sqlxDataExport struct {
+	DataExportID      uuid.UUID  `db:"data_export_id"`
+	UserID            uuid.UUID  `db:"user_id"`
+	Status            int        `db:"status"`
+	DownloadTokenHash string     `db:"download_token_hash"`
+	RequestedAt       time.Time  `db:"requested_at"`
+	StartedAt         *time.Time `db:"started_at"`
+	CompletedAt       *time.Time `db:"completed_at"`
+	ExpiresAt         *time.Time `db:"expires_at"`
+	Error             string     `db:"error"`
+}
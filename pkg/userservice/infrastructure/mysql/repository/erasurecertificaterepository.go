@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const scopesSeparator = ","
+
+func NewErasureCertificateRepository(client sqlclient.Client) domain.ErasureCertificateRepository {
+	return &erasureCertificateRepository{client: client}
+}
+
+type erasureCertificateRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *erasureCertificateRepository) NewID() domain.ErasureCertificateID {
+	return domain.ErasureCertificateID(uuid.New())
+}
+
+func (repo *erasureCertificateRepository) Store(ctx context.Context, certificate domain.ErasureCertificate) error {
+	const insertSQL = `
+		INSERT INTO erasure_certificate (erasure_certificate_id, user_id, requested_by, erased_at, scopes)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	certificateID, err := uuid.UUID(certificate.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	userID, err := uuid.UUID(certificate.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var requestedBy interface{}
+	if certificate.RequestedBy != nil {
+		requestedBy, err = uuid.UUID(*certificate.RequestedBy).MarshalBinary()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		certificateID,
+		userID,
+		requestedBy,
+		certificate.ErasedAt,
+		strings.Join(certificate.Scopes, scopesSeparator),
+	)
+	return err
+}
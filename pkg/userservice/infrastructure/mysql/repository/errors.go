@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+)
+
+const duplicateEntryErrorNumber = 1062
+
+func isDuplicateEntry(err error) bool {
+	mysqlErr, ok := errors.Cause(err).(*mysql.MySQLError)
+	return ok && mysqlErr.Number == duplicateEntryErrorNumber
+}
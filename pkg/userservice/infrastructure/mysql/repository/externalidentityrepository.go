@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const externalIdentityColumns = `issuer, subject, user_id, provider, email, linked_at`
+
+func NewExternalIdentityRepository(client sqlclient.Client) domain.ExternalIdentityRepository {
+	return &externalIdentityRepository{client: client}
+}
+
+type externalIdentityRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *externalIdentityRepository) FindBySubject(ctx context.Context, issuer, subject string) (domain.ExternalIdentity, error) {
+	const selectSQL = `SELECT ` + externalIdentityColumns + ` FROM external_identity WHERE issuer = ? AND subject = ? FOR UPDATE`
+
+	var identity sqlxExternalIdentity
+	err := repo.client.Get(ctx, &identity, selectSQL, issuer, subject)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ExternalIdentity{}, domain.ErrExternalIdentityNotFound
+		}
+		return domain.ExternalIdentity{}, errors.WithStack(err)
+	}
+	return makeExternalIdentity(identity), nil
+}
+
+func (repo *externalIdentityRepository) FindByUser(ctx context.Context, userID domain.UserID) ([]domain.ExternalIdentity, error) {
+	const selectSQL = `SELECT ` + externalIdentityColumns + ` FROM external_identity WHERE user_id = ? ORDER BY provider FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var identities []sqlxExternalIdentity
+	err = repo.client.Select(ctx, &identities, selectSQL, binaryUUID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.ExternalIdentity, 0, len(identities))
+	for _, identity := range identities {
+		result = append(result, makeExternalIdentity(identity))
+	}
+	return result, nil
+}
+
+func (repo *externalIdentityRepository) Store(ctx context.Context, identity domain.ExternalIdentity) error {
+	const insertSQL = `
+		INSERT INTO external_identity (` + externalIdentityColumns + `) VALUES(?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			email = VALUES(email)
+	`
+
+	binaryUUID, err := uuid.UUID(identity.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		identity.Issuer,
+		identity.Subject,
+		binaryUUID,
+		identity.Provider,
+		identity.Email,
+		identity.LinkedAt,
+	)
+	return err
+}
+
+func (repo *externalIdentityRepository) Remove(ctx context.Context, userID domain.UserID, provider string) error {
+	const deleteSQL = `DELETE FROM external_identity WHERE user_id = ? AND provider = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID, provider)
+	return err
+}
+
+func (repo *externalIdentityRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM external_identity WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func makeExternalIdentity(identity sqlxExternalIdentity) domain.ExternalIdentity {
+	return domain.ExternalIdentity{
+		UserID:   domain.UserID(identity.UserID),
+		Provider: identity.Provider,
+		Issuer:   identity.Issuer,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+		LinkedAt: identity.LinkedAt,
+	}
+}
+
+type sqlxExternalIdentity struct {
+	Issuer   string    `db:"issuer"`
+	Subject  string    `db:"subject"`
+	UserID   uuid.UUID `db:"user_id"`
+	Provider string    `db:"provider"`
+	Email    string    `db:"email"`
+	LinkedAt time.Time `db:"linked_at"`
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const externalLoginRequestColumns = `state, provider, code_verifier, nonce, link_user_id, expires_at`
+
+func NewExternalLoginRequestRepository(client sqlclient.Client) domain.ExternalLoginRequestRepository {
+	return &externalLoginRequestRepository{client: client}
+}
+
+type externalLoginRequestRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *externalLoginRequestRepository) Find(ctx context.Context, state string) (domain.ExternalLoginRequest, error) {
+	const selectSQL = `SELECT ` + externalLoginRequestColumns + ` FROM external_login_request WHERE state = ? FOR UPDATE`
+
+	var request sqlxExternalLoginRequest
+	err := repo.client.Get(ctx, &request, selectSQL, state)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ExternalLoginRequest{}, domain.ErrExternalLoginRequestNotFound
+		}
+		return domain.ExternalLoginRequest{}, errors.WithStack(err)
+	}
+
+	return domain.ExternalLoginRequest{
+		State:        request.State,
+		Provider:     request.Provider,
+		CodeVerifier: request.CodeVerifier,
+		Nonce:        request.Nonce,
+		LinkUserID:   optionalUserID(request.LinkUserID),
+		ExpiresAt:    request.ExpiresAt,
+	}, nil
+}
+
+func (repo *externalLoginRequestRepository) Store(ctx context.Context, request domain.ExternalLoginRequest) error {
+	const insertSQL = `INSERT INTO external_login_request (` + externalLoginRequestColumns + `) VALUES(?, ?, ?, ?, ?, ?)`
+
+	linkUserID, err := optionalBinaryUUID(request.LinkUserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		request.State,
+		request.Provider,
+		request.CodeVerifier,
+		request.Nonce,
+		linkUserID,
+		request.ExpiresAt,
+	)
+	return err
+}
+
+func (repo *externalLoginRequestRepository) Remove(ctx context.Context, state string) error {
+	const deleteSQL = `DELETE FROM external_login_request WHERE state = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, state)
+	return err
+}
+
+func (repo *externalLoginRequestRepository) RemoveExpired(ctx context.Context, before time.Time) error {
+	const deleteSQL = `DELETE FROM external_login_request WHERE expires_at < ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, before)
+	return err
+}
+
+func (repo *externalLoginRequestRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM external_login_request WHERE link_user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+type sqlxExternalLoginRequest struct {
+	State        string     `db:"state"`
+	Provider     string     `db:"provider"`
+	CodeVerifier string     `db:"code_verifier"`
+	Nonce        string     `db:"nonce"`
+	LinkUserID   *uuid.UUID `db:"link_user_id"`
+	ExpiresAt    time.Time  `db:"expires_at"`
+}
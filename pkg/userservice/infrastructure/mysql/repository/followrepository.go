@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const followColumns = `follower_id, target_kind, target_id, created_at`
+
+func NewFollowRepository(client sqlclient.Client) domain.FollowRepository {
+	return &followRepository{client: client}
+}
+
+type followRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *followRepository) Find(ctx context.Context, followerID domain.UserID, target domain.FollowTarget) (domain.Follow, error) {
+	const selectSQL = `SELECT ` + followColumns + ` FROM follow WHERE follower_id = ? AND target_kind = ? AND target_id = ? FOR UPDATE`
+
+	binaryFollowerID, err := uuid.UUID(followerID).MarshalBinary()
+	if err != nil {
+		return domain.Follow{}, errors.WithStack(err)
+	}
+	binaryTargetID, err := target.ID.MarshalBinary()
+	if err != nil {
+		return domain.Follow{}, errors.WithStack(err)
+	}
+
+	var follow sqlxFollow
+	err = repo.client.Get(ctx, &follow, selectSQL, binaryFollowerID, int(target.Kind), binaryTargetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Follow{}, domain.ErrFollowNotFound
+		}
+		return domain.Follow{}, errors.WithStack(err)
+	}
+	return makeFollow(follow), nil
+}
+
+func (repo *followRepository) Add(ctx context.Context, follow domain.Follow) error {
+	const insertSQL = `INSERT IGNORE INTO follow (` + followColumns + `) VALUES(?, ?, ?, ?)`
+
+	followerID, err := uuid.UUID(follow.FollowerID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	targetID, err := follow.Target.ID.MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	result, err := repo.client.Exec(ctx, insertSQL, followerID, int(follow.Target.Kind), targetID, follow.CreatedAt)
+	if err != nil {
+		return err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if inserted != 1 {
+		return domain.ErrAlreadyFollowing
+	}
+	return repo.changeCounts(ctx, followerID, follow.Target.Kind, targetID, 1)
+}
+
+func (repo *followRepository) Remove(ctx context.Context, followerID domain.UserID, target domain.FollowTarget) error {
+	const deleteSQL = `DELETE FROM follow WHERE follower_id = ? AND target_kind = ? AND target_id = ?`
+
+	binaryFollowerID, err := uuid.UUID(followerID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	binaryTargetID, err := target.ID.MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	result, err := repo.client.Exec(ctx, deleteSQL, binaryFollowerID, int(target.Kind), binaryTargetID)
+	if err != nil {
+		return err
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if removed == 0 {
+		return nil
+	}
+	return repo.changeCounts(ctx, binaryFollowerID, target.Kind, binaryTargetID, -1)
+}
+
+func (repo *followRepository) FindFollowers(ctx context.Context, target domain.FollowTarget, offset, limit int) ([]domain.Follow, error) {
+	const selectSQL = `
+		SELECT ` + followColumns + ` FROM follow
+		WHERE target_kind = ? AND target_id = ?
+		ORDER BY created_at DESC, follower_id
+		LIMIT ? OFFSET ?
+	`
+
+	binaryTargetID, err := target.ID.MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectAll(ctx, selectSQL, int(target.Kind), binaryTargetID, limit, offset)
+}
+
+func (repo *followRepository) FindFollowing(ctx context.Context, followerID domain.UserID, offset, limit int) ([]domain.Follow, error) {
+	const selectSQL = `
+		SELECT ` + followColumns + ` FROM follow
+		WHERE follower_id = ?
+		ORDER BY created_at DESC, target_kind, target_id
+		LIMIT ? OFFSET ?
+	`
+
+	binaryUUID, err := uuid.UUID(followerID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectAll(ctx, selectSQL, binaryUUID, limit, offset)
+}
+
+func (repo *followRepository) Counts(ctx context.Context, target domain.FollowTarget) (domain.FollowCounts, error) {
+	const selectSQL = `SELECT followers, following FROM follow_count WHERE target_kind = ? AND target_id = ?`
+
+	binaryTargetID, err := target.ID.MarshalBinary()
+	if err != nil {
+		return domain.FollowCounts{}, errors.WithStack(err)
+	}
+
+	var counts sqlxFollowCounts
+	err = repo.client.Get(ctx, &counts, selectSQL, int(target.Kind), binaryTargetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.FollowCounts{}, nil
+		}
+		return domain.FollowCounts{}, errors.WithStack(err)
+	}
+	return domain.FollowCounts{Followers: counts.Followers, Following: counts.Following}, nil
+}
+
+func (repo *followRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	// each follow of user points to distinct target, so multi-table update changes every count once per follow
+	const decrementFollowersSQL = `
+		UPDATE follow_count c
+		INNER JOIN follow f ON f.target_kind = c.target_kind AND f.target_id = c.target_id
+		SET c.followers = c.followers - 1
+		WHERE f.follower_id = ?
+	`
+	const decrementFollowingSQL = `
+		UPDATE follow_count c
+		INNER JOIN follow f ON c.target_kind = ? AND c.target_id = f.follower_id
+		SET c.following = c.following - 1
+		WHERE f.target_kind = ? AND f.target_id = ?
+	`
+	const deleteFollowsSQL = `DELETE FROM follow WHERE follower_id = ? OR (target_kind = ? AND target_id = ?)`
+	const deleteCountsSQL = `DELETE FROM follow_count WHERE target_kind = ? AND target_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	userKind := int(domain.FollowTargetUser)
+
+	_, err = repo.client.Exec(ctx, decrementFollowersSQL, binaryUUID)
+	if err != nil {
+		return err
+	}
+	_, err = repo.client.Exec(ctx, decrementFollowingSQL, userKind, userKind, binaryUUID)
+	if err != nil {
+		return err
+	}
+	_, err = repo.client.Exec(ctx, deleteFollowsSQL, binaryUUID, userKind, binaryUUID)
+	if err != nil {
+		return err
+	}
+	_, err = repo.client.Exec(ctx, deleteCountsSQL, userKind, binaryUUID)
+	return err
+}
+
+func (repo *followRepository) changeCounts(ctx context.Context, followerID []byte, targetKind domain.FollowTargetKind, targetID []byte, delta int) error {
+	const followersSQL = `
+		INSERT INTO follow_count (target_kind, target_id, followers, following) VALUES(?, ?, ?, 0)
+		ON DUPLICATE KEY UPDATE followers = followers + VALUES(followers)
+	`
+	const followingSQL = `
+		INSERT INTO follow_count (target_kind, target_id, followers, following) VALUES(?, ?, 0, ?)
+		ON DUPLICATE KEY UPDATE following = following + VALUES(following)
+	`
+
+	_, err := repo.client.Exec(ctx, followersSQL, int(targetKind), targetID, delta)
+	if err != nil {
+		return err
+	}
+	_, err = repo.client.Exec(ctx, followingSQL, int(domain.FollowTargetUser), followerID, delta)
+	return err
+}
+
+func (repo *followRepository) selectAll(ctx context.Context, query string, args ...interface{}) ([]domain.Follow, error) {
+	var follows []sqlxFollow
+	err := repo.client.Select(ctx, &follows, query, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.Follow, 0, len(follows))
+	for _, follow := range follows {
+		result = append(result, makeFollow(follow))
+	}
+	return result, nil
+}
+
+func makeFollow(follow sqlxFollow) domain.Follow {
+	return domain.Follow{
+		FollowerID: domain.UserID(follow.FollowerID),
+		Target:     domain.FollowTarget{Kind: domain.FollowTargetKind(follow.TargetKind), ID: follow.TargetID},
+		CreatedAt:  follow.CreatedAt,
+	}
+}
+
+type sqlxFollow struct {
+	FollowerID uuid.UUID `db:"follower_id"`
+	TargetKind int       `db:"target_kind"`
+	TargetID   uuid.UUID `db:"target_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type sqlxFollowCounts struct {
+	Followers int `db:"followers"`
+	Following int `db:"following"`
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const householdInvitationColumns = `household_invitation_id, household_id, email, status, invitee_id, created_at, expires_at, responded_at`
+
+func NewHouseholdInvitationRepository(client sqlclient.Client) domain.HouseholdInvitationRepository {
+	return &householdInvitationRepository{client: client}
+}
+
+type householdInvitationRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *householdInvitationRepository) NewID() domain.HouseholdInvitationID {
+	return domain.HouseholdInvitationID(uuid.New())
+}
+
+func (repo *householdInvitationRepository) Find(ctx context.Context, id domain.HouseholdInvitationID) (domain.HouseholdInvitation, error) {
+	const selectSQL = `SELECT ` + householdInvitationColumns + ` FROM household_invitation WHERE household_invitation_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return domain.HouseholdInvitation{}, errors.WithStack(err)
+	}
+
+	var invitation sqlxHouseholdInvitation
+	err = repo.client.Get(ctx, &invitation, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.HouseholdInvitation{}, domain.ErrHouseholdInvitationNotFound
+		}
+		return domain.HouseholdInvitation{}, errors.WithStack(err)
+	}
+	return makeHouseholdInvitation(invitation), nil
+}
+
+func (repo *householdInvitationRepository) FindPendingByHousehold(ctx context.Context, id domain.HouseholdID) ([]domain.HouseholdInvitation, error) {
+	const selectSQL = `SELECT ` + householdInvitationColumns + ` FROM household_invitation WHERE household_id = ? AND status = ? ORDER BY created_at FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return repo.selectAll(ctx, selectSQL, binaryUUID, int(domain.HouseholdInvitationStatusPending))
+}
+
+func (repo *householdInvitationRepository) FindPendingByEmail(ctx context.Context, email string) ([]domain.HouseholdInvitation, error) {
+	const selectSQL = `SELECT ` + householdInvitationColumns + ` FROM household_invitation WHERE email = ? AND status = ? ORDER BY created_at`
+
+	return repo.selectAll(ctx, selectSQL, email, int(domain.HouseholdInvitationStatusPending))
+}
+
+func (repo *householdInvitationRepository) Store(ctx context.Context, invitation domain.HouseholdInvitation) error {
+	const insertSQL = `
+		INSERT INTO household_invitation (` + householdInvitationColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			invitee_id = VALUES(invitee_id),
+			responded_at = VALUES(responded_at)
+	`
+
+	invitationID, err := uuid.UUID(invitation.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	householdID, err := uuid.UUID(invitation.HouseholdID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	inviteeID, err := optionalBinaryUUID(invitation.InviteeID)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		invitationID,
+		householdID,
+		invitation.Email,
+		int(invitation.Status),
+		inviteeID,
+		invitation.CreatedAt,
+		invitation.ExpiresAt,
+		invitation.RespondedAt,
+	)
+	return err
+}
+
+func (repo *householdInvitationRepository) RemoveByHousehold(ctx context.Context, id domain.HouseholdID) error {
+	const deleteSQL = `DELETE FROM household_invitation WHERE household_id = ?`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *householdInvitationRepository) RemoveByInvitee(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM household_invitation WHERE invitee_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *householdInvitationRepository) selectAll(ctx context.Context, query string, args ...interface{}) ([]domain.HouseholdInvitation, error) {
+	var invitations []sqlxHouseholdInvitation
+	err := repo.client.Select(ctx, &invitations, query, args...)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.HouseholdInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, makeHouseholdInvitation(invitation))
+	}
+	return result, nil
+}
+
+func makeHouseholdInvitation(invitation sqlxHouseholdInvitation) domain.HouseholdInvitation {
+	return domain.HouseholdInvitation{
+		ID:          domain.HouseholdInvitationID(invitation.HouseholdInvitationID),
+		HouseholdID: domain.HouseholdID(invitation.HouseholdID),
+		Email:       invitation.Email,
+		Status:      domain.HouseholdInvitationStatus(invitation.Status),
+		InviteeID:   optionalUserID(invitation.InviteeID),
+		CreatedAt:   invitation.CreatedAt,
+		ExpiresAt:   invitation.ExpiresAt,
+		RespondedAt: invitation.RespondedAt,
+	}
+}
+
+type sqlxHouseholdInvitation struct {
+	HouseholdInvitationID uuid.UUID  `db:"household_invitation_id"`
+	HouseholdID           uuid.UUID  `db:"household_id"`
+	Email                 string     `db:"email"`
+	Status                int        `db:"status"`
+	InviteeID             *uuid.UUID `db:"invitee_id"`
+	CreatedAt             time.Time  `db:"created_at"`
+	ExpiresAt             time.Time  `db:"expires_at"`
+	RespondedAt           *time.Time `db:"responded_at"`
+}
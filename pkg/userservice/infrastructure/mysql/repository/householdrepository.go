@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const (
+	householdColumns       = `household_id, manager_id, address_hash, created_at`
+	householdMemberColumns = `household_id, user_id, joined_at`
+)
+
+func NewHouseholdRepository(client sqlclient.Client) domain.HouseholdRepository {
+	return &householdRepository{client: client}
+}
+
+type householdRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *householdRepository) NewID() domain.HouseholdID {
+	return domain.HouseholdID(uuid.New())
+}
+
+func (repo *householdRepository) Find(ctx context.Context, id domain.HouseholdID) (domain.Household, error) {
+	const selectSQL = `SELECT ` + householdColumns + ` FROM household WHERE household_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return domain.Household{}, errors.WithStack(err)
+	}
+
+	return repo.get(ctx, selectSQL, binaryUUID)
+}
+
+func (repo *householdRepository) FindByMember(ctx context.Context, userID domain.UserID) (domain.Household, error) {
+	const selectSQL = `
+		SELECT h.household_id, h.manager_id, h.address_hash, h.created_at FROM household h
+		INNER JOIN household_member m ON m.household_id = h.household_id
+		WHERE m.user_id = ?
+		FOR UPDATE
+	`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.Household{}, errors.WithStack(err)
+	}
+
+	return repo.get(ctx, selectSQL, binaryUUID)
+}
+
+func (repo *householdRepository) Store(ctx context.Context, household domain.Household) error {
+	const insertSQL = `
+		INSERT INTO household (` + householdColumns + `) VALUES(?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			manager_id = VALUES(manager_id),
+			address_hash = VALUES(address_hash)
+	`
+
+	householdID, err := uuid.UUID(household.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	managerID, err := uuid.UUID(household.ManagerID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL, householdID, managerID, household.AddressHash, household.CreatedAt)
+	return err
+}
+
+func (repo *householdRepository) Remove(ctx context.Context, id domain.HouseholdID) error {
+	const deleteSQL = `DELETE FROM household WHERE household_id = ?`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *householdRepository) FindMembers(ctx context.Context, id domain.HouseholdID) ([]domain.HouseholdMember, error) {
+	const selectSQL = `SELECT ` + householdMemberColumns + ` FROM household_member WHERE household_id = ? ORDER BY joined_at FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var members []sqlxHouseholdMember
+	err = repo.client.Select(ctx, &members, selectSQL, binaryUUID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.HouseholdMember, 0, len(members))
+	for _, member := range members {
+		result = append(result, domain.HouseholdMember{
+			HouseholdID: domain.HouseholdID(member.HouseholdID),
+			UserID:      domain.UserID(member.UserID),
+			JoinedAt:    member.JoinedAt,
+		})
+	}
+	return result, nil
+}
+
+func (repo *householdRepository) StoreMember(ctx context.Context, member domain.HouseholdMember) error {
+	const insertSQL = `INSERT INTO household_member (` + householdMemberColumns + `) VALUES(?, ?, ?)`
+
+	householdID, err := uuid.UUID(member.HouseholdID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	userID, err := uuid.UUID(member.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL, householdID, userID, member.JoinedAt)
+	return err
+}
+
+func (repo *householdRepository) RemoveMember(ctx context.Context, id domain.HouseholdID, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM household_member WHERE household_id = ? AND user_id = ?`
+
+	householdID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	binaryUserID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, householdID, binaryUserID)
+	return err
+}
+
+func (repo *householdRepository) get(ctx context.Context, query string, args ...interface{}) (domain.Household, error) {
+	var household sqlxHousehold
+	err := repo.client.Get(ctx, &household, query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Household{}, domain.ErrHouseholdNotFound
+		}
+		return domain.Household{}, errors.WithStack(err)
+	}
+
+	return domain.Household{
+		ID:          domain.HouseholdID(household.HouseholdID),
+		ManagerID:   domain.UserID(household.ManagerID),
+		AddressHash: household.AddressHash,
+		CreatedAt:   household.CreatedAt,
+	}, nil
+}
+
+type sqlxHousehold struct {
+	HouseholdID uuid.UUID `db:"household_id"`
+	ManagerID   uuid.UUID `db:"manager_id"`
+	AddressHash string    `db:"address_hash"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type sqlxHouseholdMember struct {
+	HouseholdID uuid.UUID `db:"household_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	JoinedAt    time.Time `db:"joined_at"`
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const oauth2AuthorizationCodeColumns = `code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, nonce, expires_at, used_at`
+
+func NewOAuth2AuthorizationCodeRepository(client sqlclient.Client) domain.OAuth2AuthorizationCodeRepository {
+	return &oauth2AuthorizationCodeRepository{client: client}
+}
+
+type oauth2AuthorizationCodeRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *oauth2AuthorizationCodeRepository) Find(ctx context.Context, codeHash string) (domain.OAuth2AuthorizationCode, error) {
+	const selectSQL = `SELECT ` + oauth2AuthorizationCodeColumns + ` FROM oauth2_authorization_code WHERE code_hash = ? FOR UPDATE`
+
+	var code sqlxOAuth2AuthorizationCode
+	err := repo.client.Get(ctx, &code, selectSQL, codeHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.OAuth2AuthorizationCode{}, domain.ErrOAuth2AuthorizationCodeNotFound
+		}
+		return domain.OAuth2AuthorizationCode{}, errors.WithStack(err)
+	}
+
+	return domain.OAuth2AuthorizationCode{
+		CodeHash:      code.CodeHash,
+		ClientID:      code.ClientID,
+		UserID:        domain.UserID(code.UserID),
+		RedirectURI:   code.RedirectURI,
+		Scopes:        strings.Fields(code.Scopes),
+		CodeChallenge: code.CodeChallenge,
+		Nonce:         code.Nonce,
+		ExpiresAt:     code.ExpiresAt,
+		UsedAt:        code.UsedAt,
+	}, nil
+}
+
+func (repo *oauth2AuthorizationCodeRepository) Store(ctx context.Context, code domain.OAuth2AuthorizationCode) error {
+	const insertSQL = `
+		INSERT INTO oauth2_authorization_code (` + oauth2AuthorizationCodeColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			used_at = VALUES(used_at)
+	`
+
+	binaryUUID, err := uuid.UUID(code.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		code.CodeHash,
+		code.ClientID,
+		binaryUUID,
+		code.RedirectURI,
+		joinList(code.Scopes),
+		code.CodeChallenge,
+		code.Nonce,
+		code.ExpiresAt,
+		code.UsedAt,
+	)
+	return err
+}
+
+func (repo *oauth2AuthorizationCodeRepository) Remove(ctx context.Context, codeHash string) error {
+	const deleteSQL = `DELETE FROM oauth2_authorization_code WHERE code_hash = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, codeHash)
+	return err
+}
+
+func (repo *oauth2AuthorizationCodeRepository) RemoveExpired(ctx context.Context, before time.Time) error {
+	const deleteSQL = `DELETE FROM oauth2_authorization_code WHERE expires_at < ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, before)
+	return err
+}
+
+func (repo *oauth2AuthorizationCodeRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM oauth2_authorization_code WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *oauth2AuthorizationCodeRepository) RemoveByClient(ctx context.Context, clientID string) error {
+	const deleteSQL = `DELETE FROM oauth2_authorization_code WHERE client_id = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, clientID)
+	return err
+}
+
+type sqlxOAuth2AuthorizationCode struct {
+	CodeHash      string     `db:"code_hash"`
+	ClientID      string     `db:"client_id"`
+	UserID        uuid.UUID  `db:"user_id"`
+	RedirectURI   string     `db:"redirect_uri"`
+	Scopes        string     `db:"scopes"`
+	CodeChallenge string     `db:"code_challenge"`
+	Nonce         string     `db:"nonce"`
+	ExpiresAt     time.Time  `db:"expires_at"`
+	UsedAt        *time.Time `db:"used_at"`
+}
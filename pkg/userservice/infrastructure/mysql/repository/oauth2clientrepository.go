@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const oauth2ClientColumns = `client_id, name, secret_hash, redirect_uris, scopes, grant_types, first_party, created_at`
+
+func NewOAuth2ClientRepository(client sqlclient.Client) domain.OAuth2ClientRepository {
+	return &oauth2ClientRepository{client: client}
+}
+
+type oauth2ClientRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *oauth2ClientRepository) Find(ctx context.Context, clientID string) (domain.OAuth2Client, error) {
+	const selectSQL = `SELECT ` + oauth2ClientColumns + ` FROM oauth2_client WHERE client_id = ?`
+
+	var client sqlxOAuth2Client
+	err := repo.client.Get(ctx, &client, selectSQL, clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.OAuth2Client{}, domain.ErrOAuth2ClientNotFound
+		}
+		return domain.OAuth2Client{}, errors.WithStack(err)
+	}
+	return makeOAuth2Client(client), nil
+}
+
+func (repo *oauth2ClientRepository) FindAll(ctx context.Context) ([]domain.OAuth2Client, error) {
+	const selectSQL = `SELECT ` + oauth2ClientColumns + ` FROM oauth2_client ORDER BY created_at`
+
+	var clients []sqlxOAuth2Client
+	err := repo.client.Select(ctx, &clients, selectSQL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.OAuth2Client, 0, len(clients))
+	for _, client := range clients {
+		result = append(result, makeOAuth2Client(client))
+	}
+	return result, nil
+}
+
+func (repo *oauth2ClientRepository) Store(ctx context.Context, client domain.OAuth2Client) error {
+	const insertSQL = `
+		INSERT INTO oauth2_client (` + oauth2ClientColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			name = VALUES(name),
+			secret_hash = VALUES(secret_hash),
+			redirect_uris = VALUES(redirect_uris),
+			scopes = VALUES(scopes),
+			grant_types = VALUES(grant_types),
+			first_party = VALUES(first_party)
+	`
+
+	grantTypes := make([]string, 0, len(client.GrantTypes))
+	for _, grantType := range client.GrantTypes {
+		grantTypes = append(grantTypes, string(grantType))
+	}
+
+	_, err := repo.client.Exec(ctx, insertSQL,
+		client.ID,
+		client.Name,
+		client.SecretHash,
+		joinList(client.RedirectURIs),
+		joinList(client.Scopes),
+		joinList(grantTypes),
+		client.FirstParty,
+		client.CreatedAt,
+	)
+	return err
+}
+
+func (repo *oauth2ClientRepository) Remove(ctx context.Context, clientID string) error {
+	const deleteSQL = `DELETE FROM oauth2_client WHERE client_id = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, clientID)
+	return err
+}
+
+func makeOAuth2Client(client sqlxOAuth2Client) domain.OAuth2Client {
+	var grantTypes []domain.OAuth2GrantType
+	for _, grantType := range strings.Fields(client.GrantTypes) {
+		grantTypes = append(grantTypes, domain.OAuth2GrantType(grantType))
+	}
+
+	return domain.OAuth2Client{
+		ID:           client.ID,
+		Name:         client.Name,
+		SecretHash:   client.SecretHash,
+		RedirectURIs: strings.Fields(client.RedirectURIs),
+		Scopes:       strings.Fields(client.Scopes),
+		GrantTypes:   grantTypes,
+		FirstParty:   client.FirstParty,
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+// joinList stores list separated by spaces, scopes and uris never contain spaces
+func joinList(values []string) string {
+	return strings.Join(values, " ")
+}
+
+type sqlxOAuth2Client struct {
+	ID           string    `db:"client_id"`
+	Name         string    `db:"name"`
+	SecretHash   string    `db:"secret_hash"`
+	RedirectURIs string    `db:"redirect_uris"`
+	Scopes       string    `db:"scopes"`
+	GrantTypes   string    `db:"grant_types"`
+	FirstParty   bool      `db:"first_party"`
+	CreatedAt    time.Time `db:"created_at"`
+}
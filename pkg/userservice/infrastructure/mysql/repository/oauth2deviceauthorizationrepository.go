@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const oauth2DeviceAuthorizationColumns = `device_code_hash, user_code, client_id, scopes, status, user_id, poll_interval, last_polled_at, expires_at`
+
+func NewOAuth2DeviceAuthorizationRepository(client sqlclient.Client) domain.OAuth2DeviceAuthorizationRepository {
+	return &oauth2DeviceAuthorizationRepository{client: client}
+}
+
+type oauth2DeviceAuthorizationRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) FindByDeviceCode(ctx context.Context, deviceCodeHash string) (domain.OAuth2DeviceAuthorization, error) {
+	const selectSQL = `SELECT ` + oauth2DeviceAuthorizationColumns + ` FROM oauth2_device_authorization WHERE device_code_hash = ? FOR UPDATE`
+
+	return repo.find(ctx, selectSQL, deviceCodeHash)
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) FindByUserCode(ctx context.Context, userCode string) (domain.OAuth2DeviceAuthorization, error) {
+	const selectSQL = `SELECT ` + oauth2DeviceAuthorizationColumns + ` FROM oauth2_device_authorization WHERE user_code = ? FOR UPDATE`
+
+	return repo.find(ctx, selectSQL, userCode)
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) Store(ctx context.Context, authorization domain.OAuth2DeviceAuthorization) error {
+	const insertSQL = `
+		INSERT INTO oauth2_device_authorization (` + oauth2DeviceAuthorizationColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			user_id = VALUES(user_id),
+			poll_interval = VALUES(poll_interval),
+			last_polled_at = VALUES(last_polled_at)
+	`
+
+	userID, err := optionalBinaryUUID(authorization.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		authorization.DeviceCodeHash,
+		authorization.UserCode,
+		authorization.ClientID,
+		joinList(authorization.Scopes),
+		int(authorization.Status),
+		userID,
+		int(authorization.PollInterval/time.Second),
+		authorization.LastPolledAt,
+		authorization.ExpiresAt,
+	)
+	return err
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) Remove(ctx context.Context, deviceCodeHash string) error {
+	const deleteSQL = `DELETE FROM oauth2_device_authorization WHERE device_code_hash = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, deviceCodeHash)
+	return err
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) RemoveExpired(ctx context.Context, before time.Time) error {
+	const deleteSQL = `DELETE FROM oauth2_device_authorization WHERE expires_at < ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, before)
+	return err
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM oauth2_device_authorization WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) RemoveByClient(ctx context.Context, clientID string) error {
+	const deleteSQL = `DELETE FROM oauth2_device_authorization WHERE client_id = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, clientID)
+	return err
+}
+
+func (repo *oauth2DeviceAuthorizationRepository) find(ctx context.Context, selectSQL string, args ...interface{}) (domain.OAuth2DeviceAuthorization, error) {
+	var authorization sqlxOAuth2DeviceAuthorization
+	err := repo.client.Get(ctx, &authorization, selectSQL, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.OAuth2DeviceAuthorization{}, domain.ErrOAuth2DeviceAuthorizationNotFound
+		}
+		return domain.OAuth2DeviceAuthorization{}, errors.WithStack(err)
+	}
+
+	return domain.OAuth2DeviceAuthorization{
+		DeviceCodeHash: authorization.DeviceCodeHash,
+		UserCode:       authorization.UserCode,
+		ClientID:       authorization.ClientID,
+		Scopes:         strings.Fields(authorization.Scopes),
+		Status:         domain.OAuth2DeviceAuthorizationStatus(authorization.Status),
+		UserID:         optionalUserID(authorization.UserID),
+		PollInterval:   time.Duration(authorization.PollInterval) * time.Second,
+		LastPolledAt:   authorization.LastPolledAt,
+		ExpiresAt:      authorization.ExpiresAt,
+	}, nil
+}
+
+type sqlxOAuth2DeviceAuthorization struct {
+	DeviceCodeHash string     `db:"device_code_hash"`
+	UserCode       string     `db:"user_code"`
+	ClientID       string     `db:"client_id"`
+	Scopes         string     `db:"scopes"`
+	Status         int        `db:"status"`
+	UserID         *uuid.UUID `db:"user_id"`
+	PollInterval   int        `db:"poll_interval"`
+	LastPolledAt   *time.Time `db:"last_polled_at"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+}
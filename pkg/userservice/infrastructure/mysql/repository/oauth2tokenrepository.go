@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const oauth2TokenColumns = `token_hash, kind, client_id, user_id, scopes, authorization_code_hash, expires_at, created_at`
+
+func NewOAuth2TokenRepository(client sqlclient.Client) domain.OAuth2TokenRepository {
+	return &oauth2TokenRepository{client: client}
+}
+
+type oauth2TokenRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *oauth2TokenRepository) Find(ctx context.Context, tokenHash string) (domain.OAuth2Token, error) {
+	const selectSQL = `SELECT ` + oauth2TokenColumns + ` FROM oauth2_token WHERE token_hash = ? FOR UPDATE`
+
+	var token sqlxOAuth2Token
+	err := repo.client.Get(ctx, &token, selectSQL, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.OAuth2Token{}, domain.ErrOAuth2TokenNotFound
+		}
+		return domain.OAuth2Token{}, errors.WithStack(err)
+	}
+
+	return makeOAuth2Token(token), nil
+}
+
+func (repo *oauth2TokenRepository) FindByUser(ctx context.Context, userID domain.UserID) ([]domain.OAuth2Token, error) {
+	const selectSQL = `SELECT ` + oauth2TokenColumns + ` FROM oauth2_token WHERE user_id = ? ORDER BY created_at`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var tokens []sqlxOAuth2Token
+	err = repo.client.Select(ctx, &tokens, selectSQL, binaryUUID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.OAuth2Token, 0, len(tokens))
+	for _, token := range tokens {
+		result = append(result, makeOAuth2Token(token))
+	}
+	return result, nil
+}
+
+func (repo *oauth2TokenRepository) Store(ctx context.Context, token domain.OAuth2Token) error {
+	const insertSQL = `INSERT INTO oauth2_token (` + oauth2TokenColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?)`
+
+	userID, err := optionalBinaryUUID(token.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		token.TokenHash,
+		int(token.Kind),
+		token.ClientID,
+		userID,
+		joinList(token.Scopes),
+		token.AuthorizationCodeHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+func (repo *oauth2TokenRepository) Remove(ctx context.Context, tokenHash string) error {
+	const deleteSQL = `DELETE FROM oauth2_token WHERE token_hash = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, tokenHash)
+	return err
+}
+
+func (repo *oauth2TokenRepository) RemoveExpired(ctx context.Context, before time.Time) error {
+	const deleteSQL = `DELETE FROM oauth2_token WHERE expires_at < ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, before)
+	return err
+}
+
+func (repo *oauth2TokenRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM oauth2_token WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *oauth2TokenRepository) RemoveByClient(ctx context.Context, clientID string) error {
+	const deleteSQL = `DELETE FROM oauth2_token WHERE client_id = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, clientID)
+	return err
+}
+
+func (repo *oauth2TokenRepository) RemoveByAuthorizationCode(ctx context.Context, codeHash string) error {
+	const deleteSQL = `DELETE FROM oauth2_token WHERE authorization_code_hash = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, codeHash)
+	return err
+}
+
+func makeOAuth2Token(token sqlxOAuth2Token) domain.OAuth2Token {
+	return domain.OAuth2Token{
+		TokenHash:             token.TokenHash,
+		Kind:                  domain.OAuth2TokenKind(token.Kind),
+		ClientID:              token.ClientID,
+		UserID:                optionalUserID(token.UserID),
+		Scopes:                strings.Fields(token.Scopes),
+		AuthorizationCodeHash: token.AuthorizationCodeHash,
+		ExpiresAt:             token.ExpiresAt,
+		CreatedAt:             token.CreatedAt,
+	}
+}
+
+type sqlxOAuth2Token struct {
+	TokenHash             string     `db:"token_hash"`
+	Kind                  int        `db:"kind"`
+	ClientID              string     `db:"client_id"`
+	UserID                *uuid.UUID `db:"user_id"`
+	Scopes                string     `db:"scopes"`
+	AuthorizationCodeHash string     `db:"authorization_code_hash"`
+	ExpiresAt             time.Time  `db:"expires_at"`
+	CreatedAt             time.Time  `db:"created_at"`
+}
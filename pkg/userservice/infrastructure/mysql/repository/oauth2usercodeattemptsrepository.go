@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+func NewOAuth2UserCodeAttemptsRepository(client sqlclient.Client) domain.OAuth2UserCodeAttemptsRepository {
+	return &oauth2UserCodeAttemptsRepository{client: client}
+}
+
+type oauth2UserCodeAttemptsRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *oauth2UserCodeAttemptsRepository) Find(ctx context.Context, userID domain.UserID) (domain.OAuth2UserCodeAttempts, error) {
+	const selectSQL = `SELECT failed_attempts, window_started_at, locked_until FROM oauth2_user_code_attempt WHERE user_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.OAuth2UserCodeAttempts{}, errors.WithStack(err)
+	}
+
+	var attempts sqlxOAuth2UserCodeAttempts
+	err = repo.client.Get(ctx, &attempts, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.OAuth2UserCodeAttempts{UserID: userID}, nil
+		}
+		return domain.OAuth2UserCodeAttempts{}, errors.WithStack(err)
+	}
+
+	return domain.OAuth2UserCodeAttempts{
+		UserID:          userID,
+		FailedAttempts:  attempts.FailedAttempts,
+		WindowStartedAt: attempts.WindowStartedAt,
+		LockedUntil:     attempts.LockedUntil,
+	}, nil
+}
+
+func (repo *oauth2UserCodeAttemptsRepository) Store(ctx context.Context, attempts domain.OAuth2UserCodeAttempts) error {
+	const insertSQL = `
+		INSERT INTO oauth2_user_code_attempt (user_id, failed_attempts, window_started_at, locked_until) VALUES(?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			failed_attempts = VALUES(failed_attempts),
+			window_started_at = VALUES(window_started_at),
+			locked_until = VALUES(locked_until)
+	`
+
+	binaryUUID, err := uuid.UUID(attempts.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL, binaryUUID, attempts.FailedAttempts, attempts.WindowStartedAt, attempts.LockedUntil)
+	return err
+}
+
+func (repo *oauth2UserCodeAttemptsRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM oauth2_user_code_attempt WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+type sqlxOAuth2UserCodeAttempts struct {
+	FailedAttempts  int        `db:"failed_attempts"`
+	WindowStartedAt time.Time  `db:"window_started_at"`
+	LockedUntil     *time.Time `db:"locked_until"`
+}
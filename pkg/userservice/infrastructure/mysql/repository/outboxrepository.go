@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const outboxMessageColumns = `outbox_message_id, routing_key, body, created_at`
+
+func NewOutboxRepository(client sqlclient.Client) domain.OutboxRepository {
+	return &outboxRepository{client: client}
+}
+
+type outboxRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *outboxRepository) Store(ctx context.Context, message domain.OutboxMessage) error {
+	const insertSQL = `INSERT INTO outbox_message (routing_key, body, created_at) VALUES(?, ?, ?)`
+
+	_, err := repo.client.Exec(ctx, insertSQL, message.RoutingKey, message.Body, message.CreatedAt)
+	return err
+}
+
+func (repo *outboxRepository) FindOldest(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	const selectSQL = `SELECT ` + outboxMessageColumns + ` FROM outbox_message ORDER BY outbox_message_id LIMIT ? FOR UPDATE`
+
+	var messages []sqlxOutboxMessage
+	err := repo.client.Select(ctx, &messages, selectSQL, limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	result := make([]domain.OutboxMessage, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, domain.OutboxMessage{
+			Seq:        message.OutboxMessageID,
+			RoutingKey: message.RoutingKey,
+			Body:       message.Body,
+			CreatedAt:  message.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func (repo *outboxRepository) Remove(ctx context.Context, seq int64) error {
+	const deleteSQL = `DELETE FROM outbox_message WHERE outbox_message_id = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, seq)
+	return err
+}
+
+type sqlxOutboxMessage struct {
+	OutboxMessageID int64     `db:"outbox_message_id"`
+	RoutingKey      string    `db:"routing_key"`
+	Body            []byte    `db:"body"`
+	CreatedAt       time.Time `db:"created_at"`
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const parentalControlsColumns = `user_id, guardian_id, explicit_content_filter, pin_hash, failed_pin_attempts, pin_locked_until, updated_at`
+
+func NewParentalControlsRepository(client sqlclient.Client) domain.ParentalControlsRepository {
+	return &parentalControlsRepository{client: client}
+}
+
+type parentalControlsRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *parentalControlsRepository) Find(ctx context.Context, userID domain.UserID) (domain.ParentalControls, error) {
+	const selectSQL = `SELECT ` + parentalControlsColumns + ` FROM parental_controls WHERE user_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.ParentalControls{}, errors.WithStack(err)
+	}
+
+	var controls sqlxParentalControls
+	err = repo.client.Get(ctx, &controls, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ParentalControls{}, domain.ErrParentalControlsNotFound
+		}
+		return domain.ParentalControls{}, errors.WithStack(err)
+	}
+
+	return domain.ParentalControls{
+		UserID:                domain.UserID(controls.UserID),
+		GuardianID:            optionalUserID(controls.GuardianID),
+		ExplicitContentFilter: controls.ExplicitContentFilter,
+		PINHash:               controls.PINHash,
+		FailedPINAttempts:     controls.FailedPINAttempts,
+		PINLockedUntil:        controls.PINLockedUntil,
+		UpdatedAt:             controls.UpdatedAt,
+	}, nil
+}
+
+func (repo *parentalControlsRepository) Store(ctx context.Context, controls domain.ParentalControls) error {
+	const insertSQL = `
+		INSERT INTO parental_controls (` + parentalControlsColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			guardian_id = VALUES(guardian_id),
+			explicit_content_filter = VALUES(explicit_content_filter),
+			pin_hash = VALUES(pin_hash),
+			failed_pin_attempts = VALUES(failed_pin_attempts),
+			pin_locked_until = VALUES(pin_locked_until),
+			updated_at = VALUES(updated_at)
+	`
+
+	binaryUUID, err := uuid.UUID(controls.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	guardianID, err := optionalBinaryUUID(controls.GuardianID)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		binaryUUID,
+		guardianID,
+		controls.ExplicitContentFilter,
+		controls.PINHash,
+		controls.FailedPINAttempts,
+		controls.PINLockedUntil,
+		controls.UpdatedAt,
+	)
+	return err
+}
+
+func (repo *parentalControlsRepository) Remove(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM parental_controls WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+func (repo *parentalControlsRepository) RemoveGuardian(ctx context.Context, guardianID domain.UserID) error {
+	const updateSQL = `UPDATE parental_controls SET guardian_id = NULL, updated_at = ? WHERE guardian_id = ?`
+
+	binaryUUID, err := uuid.UUID(guardianID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, updateSQL, time.Now(), binaryUUID)
+	return err
+}
+
+type sqlxParentalControls struct {
+	UserID                uuid.UUID  `db:"user_id"`
+	GuardianID            *uuid.UUID `db:"guardian_id"`
+	ExplicitContentFilter *bool      `db:"explicit_content_filter"`
+	PINHash               string     `db:"pin_hash"`
+	FailedPINAttempts     int        `db:"failed_pin_attempts"`
+	PINLockedUntil        *time.Time `db:"pin_locked_until"`
+	UpdatedAt             time.Time  `db:"updated_at"`
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const privacySettingsColumns = `user_id, profile_visibility, who_can_follow, listening_activity_public, updated_at`
+
+func NewPrivacySettingsRepository(client sqlclient.Client) domain.PrivacySettingsRepository {
+	return &privacySettingsRepository{client: client}
+}
+
+type privacySettingsRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *privacySettingsRepository) Find(ctx context.Context, userID domain.UserID) (domain.PrivacySettings, error) {
+	const selectSQL = `SELECT ` + privacySettingsColumns + ` FROM privacy_settings WHERE user_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.PrivacySettings{}, errors.WithStack(err)
+	}
+
+	var settings sqlxPrivacySettings
+	err = repo.client.Get(ctx, &settings, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.PrivacySettings{}, domain.ErrPrivacySettingsNotFound
+		}
+		return domain.PrivacySettings{}, errors.WithStack(err)
+	}
+
+	return domain.PrivacySettings{
+		UserID:                  domain.UserID(settings.UserID),
+		ProfileVisibility:       domain.ProfileVisibility(settings.ProfileVisibility),
+		WhoCanFollow:            domain.FollowPolicy(settings.WhoCanFollow),
+		ListeningActivityPublic: settings.ListeningActivityPublic,
+		UpdatedAt:               settings.UpdatedAt,
+	}, nil
+}
+
+func (repo *privacySettingsRepository) Store(ctx context.Context, settings domain.PrivacySettings) error {
+	const insertSQL = `
+		INSERT INTO privacy_settings (` + privacySettingsColumns + `) VALUES(?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			profile_visibility = VALUES(profile_visibility),
+			who_can_follow = VALUES(who_can_follow),
+			listening_activity_public = VALUES(listening_activity_public),
+			updated_at = VALUES(updated_at)
+	`
+
+	binaryUUID, err := uuid.UUID(settings.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		binaryUUID,
+		int(settings.ProfileVisibility),
+		int(settings.WhoCanFollow),
+		settings.ListeningActivityPublic,
+		settings.UpdatedAt,
+	)
+	return err
+}
+
+func (repo *privacySettingsRepository) Remove(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM privacy_settings WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+type sqlxPrivacySettings struct {
+	UserID                  uuid.UUID `db:"user_id"`
+	ProfileVisibility       int       `db:"profile_visibility"`
+	WhoCanFollow            int       `db:"who_can_follow"`
+	ListeningActivityPublic bool      `db:"listening_activity_public"`
+	UpdatedAt               time.Time `db:"updated_at"`
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const profileColumns = `user_id, display_name, avatar_url, country, language, date_of_birth, bio, updated_at`
+
+func NewProfileRepository(client sqlclient.Client) domain.ProfileRepository {
+	return &profileRepository{client: client}
+}
+
+type profileRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *profileRepository) Find(ctx context.Context, userID domain.UserID) (domain.Profile, error) {
+	const selectSQL = `SELECT ` + profileColumns + ` FROM profile WHERE user_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.Profile{}, errors.WithStack(err)
+	}
+
+	var profile sqlxProfile
+	err = repo.client.Get(ctx, &profile, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Profile{}, domain.ErrProfileNotFound
+		}
+		return domain.Profile{}, errors.WithStack(err)
+	}
+
+	return domain.Profile{
+		UserID:      domain.UserID(profile.UserID),
+		DisplayName: profile.DisplayName,
+		AvatarURL:   profile.AvatarURL,
+		Country:     profile.Country,
+		Language:    profile.Language,
+		DateOfBirth: profile.DateOfBirth,
+		Bio:         profile.Bio,
+		UpdatedAt:   profile.UpdatedAt,
+	}, nil
+}
+
+func (repo *profileRepository) Store(ctx context.Context, profile domain.Profile) error {
+	const insertSQL = `
+		INSERT INTO profile (` + profileColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			display_name = VALUES(display_name),
+			avatar_url = VALUES(avatar_url),
+			country = VALUES(country),
+			language = VALUES(language),
+			date_of_birth = VALUES(date_of_birth),
+			bio = VALUES(bio),
+			updated_at = VALUES(updated_at)
+	`
+
+	binaryUUID, err := uuid.UUID(profile.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		binaryUUID,
+		profile.DisplayName,
+		profile.AvatarURL,
+		profile.Country,
+		profile.Language,
+		profile.DateOfBirth,
+		profile.Bio,
+		profile.UpdatedAt,
+	)
+	return err
+}
+
+func (repo *profileRepository) Remove(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM profile WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+type sqlxProfile struct {
+	UserID      uuid.UUID  `db:"user_id"`
+	DisplayName string     `db:"display_name"`
+	AvatarURL   string     `db:"avatar_url"`
+	Country     string     `db:"country"`
+	Language    string     `db:"language"`
+	DateOfBirth *time.Time `db:"date_of_birth"`
+	Bio         string     `db:"bio"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
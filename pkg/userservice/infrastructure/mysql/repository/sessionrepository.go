@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+func NewSessionRepository(client sqlclient.Client) domain.SessionRepository {
+	return &sessionRepository{client: client}
+}
+
+type sessionRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *sessionRepository) Store(ctx context.Context, session domain.Session) error {
+	const insertSQL = `INSERT INTO session (token_hash, user_id, expires_at, created_at) VALUES(?, ?, ?, ?)`
+
+	binaryUUID, err := uuid.UUID(session.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL, session.TokenHash, binaryUUID, session.ExpiresAt, session.CreatedAt)
+	return err
+}
+
+func (repo *sessionRepository) Remove(ctx context.Context, tokenHash string) error {
+	const deleteSQL = `DELETE FROM session WHERE token_hash = ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, tokenHash)
+	return err
+}
+
+func (repo *sessionRepository) RemoveExpired(ctx context.Context, before time.Time) error {
+	const deleteSQL = `DELETE FROM session WHERE expires_at < ?`
+
+	_, err := repo.client.Exec(ctx, deleteSQL, before)
+	return err
+}
+
+func (repo *sessionRepository) RemoveByUser(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM session WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
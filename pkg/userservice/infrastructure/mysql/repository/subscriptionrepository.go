@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
+)
+
+const subscriptionColumns = `user_id, plan, status, period_start, period_end, trial, trial_used, updated_at`
+
+func NewSubscriptionRepository(client sqlclient.Client) domain.SubscriptionRepository {
+	return &subscriptionRepository{client: client}
+}
+
+type subscriptionRepository struct {
+	client sqlclient.Client
+}
+
+func (repo *subscriptionRepository) Find(ctx context.Context, userID domain.UserID) (domain.Subscription, error) {
+	const selectSQL = `SELECT ` + subscriptionColumns + ` FROM subscription WHERE user_id = ? FOR UPDATE`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return domain.Subscription{}, errors.WithStack(err)
+	}
+
+	var subscription sqlxSubscription
+	err = repo.client.Get(ctx, &subscription, selectSQL, binaryUUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Subscription{}, domain.ErrSubscriptionNotFound
+		}
+		return domain.Subscription{}, errors.WithStack(err)
+	}
+
+	return domain.Subscription{
+		UserID:      domain.UserID(subscription.UserID),
+		Plan:        domain.SubscriptionPlan(subscription.Plan),
+		Status:      domain.SubscriptionStatus(subscription.Status),
+		PeriodStart: subscription.PeriodStart,
+		PeriodEnd:   subscription.PeriodEnd,
+		Trial:       subscription.Trial,
+		TrialUsed:   subscription.TrialUsed,
+		UpdatedAt:   subscription.UpdatedAt,
+	}, nil
+}
+
+func (repo *subscriptionRepository) Store(ctx context.Context, subscription domain.Subscription) error {
+	const insertSQL = `
+		INSERT INTO subscription (` + subscriptionColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			plan = VALUES(plan),
+			status = VALUES(status),
+			period_start = VALUES(period_start),
+			period_end = VALUES(period_end),
+			trial = VALUES(trial),
+			trial_used = VALUES(trial_used),
+			updated_at = VALUES(updated_at)
+	`
+
+	binaryUUID, err := uuid.UUID(subscription.UserID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, insertSQL,
+		binaryUUID,
+		int(subscription.Plan),
+		int(subscription.Status),
+		subscription.PeriodStart,
+		subscription.PeriodEnd,
+		subscription.Trial,
+		subscription.TrialUsed,
+		subscription.UpdatedAt,
+	)
+	return err
+}
+
+func (repo *subscriptionRepository) Remove(ctx context.Context, userID domain.UserID) error {
+	const deleteSQL = `DELETE FROM subscription WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(userID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
+	return err
+}
+
+type sqlxSubscription struct {
+	UserID      uuid.UUID  `db:"user_id"`
+	Plan        int        `db:"plan"`
+	Status      int        `db:"status"`
+	PeriodStart *time.Time `db:"period_start"`
+	PeriodEnd   *time.Time `db:"period_end"`
+	Trial       bool       `db:"trial"`
+	TrialUsed   bool       `db:"trial_used"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
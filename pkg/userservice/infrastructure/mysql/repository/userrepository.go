@@ -1,101 +1,196 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
-	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 
 	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
 )
 
-func NewUserRepository(client mysql.Client) domain.UserRepository {
+const userColumns = `user_id, email, password, password_algorithm, role, failed_login_attempts, locked_until, deleted_at, erased_at`
+
+func NewUserRepository(client sqlclient.Client) domain.UserRepository {
 	return &userRepository{client: client}
 }
 
 type userRepository struct {
-	client mysql.Client
+	client sqlclient.Client
 }
 
 func (repo *userRepository) NewID() domain.UserID {
 	return domain.UserID(uuid.New())
 }
 
-func (repo *userRepository) Find(id domain.UserID) (domain.User, error) {
-	const selectSQL = `SELECT * from user WHERE user_id = ?`
+func (repo *userRepository) Find(ctx context.Context, id domain.UserID) (domain.User, error) {
+	const selectSQL = `SELECT ` + userColumns + ` FROM user WHERE user_id = ? FOR UPDATE`
 
 	binaryUUID, err := uuid.UUID(id).MarshalBinary()
 	if err != nil {
 		return domain.User{}, err
 	}
 
-	var user sqlxUser
+	return repo.get(ctx, selectSQL, binaryUUID)
+}
+
+func (repo *userRepository) FindByEmail(ctx context.Context, email string) (domain.User, error) {
+	const selectSQL = `SELECT ` + userColumns + ` FROM user WHERE email = ?`
 
-	err = repo.client.Get(&user, selectSQL, binaryUUID)
+	return repo.get(ctx, selectSQL, email)
+}
+
+func (repo *userRepository) Add(ctx context.Context, user domain.User) error {
+	const insertSQL = `INSERT INTO user (` + userColumns + `) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	binaryUUID, err := uuid.UUID(user.ID).MarshalBinary()
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return domain.User{}, domain.ErrUserNotFound
-		}
-		return domain.User{}, errors.WithStack(err)
+		return errors.WithStack(err)
 	}
 
-	return domain.User{
-		ID:       domain.UserID(user.UserID),
-		Email:    user.Email,
-		Password: user.Password,
-		Role:     domain.Role(user.Role),
-	}, nil
+	_, err = repo.client.Exec(ctx, insertSQL,
+		binaryUUID,
+		user.Email,
+		user.Password,
+		user.PasswordAlgorithm,
+		int(user.Role),
+		user.FailedLoginAttempts,
+		user.LockedUntil,
+		user.DeletedAt,
+		user.ErasedAt,
+	)
+	return mapUserError(err)
 }
 
-func (repo *userRepository) FindByEmail(email string) (domain.User, error) {
-	const selectSQL = `SELECT * from user WHERE email = ?`
+func (repo *userRepository) Store(ctx context.Context, user domain.User) error {
+	const updateSQL = `
+		UPDATE user SET
+			email = ?,
+			password = ?,
+			password_algorithm = ?,
+			role = ?,
+			failed_login_attempts = ?,
+			locked_until = ?,
+			deleted_at = ?,
+			erased_at = ?
+		WHERE user_id = ?
+	`
 
-	var user sqlxUser
+	binaryUUID, err := uuid.UUID(user.ID).MarshalBinary()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, updateSQL,
+		user.Email,
+		user.Password,
+		user.PasswordAlgorithm,
+		int(user.Role),
+		user.FailedLoginAttempts,
+		user.LockedUntil,
+		user.DeletedAt,
+		user.ErasedAt,
+		binaryUUID,
+	)
+	return mapUserError(err)
+}
+
+func (repo *userRepository) IncrementFailedLoginAttempts(ctx context.Context, id domain.UserID) (int, error) {
+	const updateSQL = `UPDATE user SET failed_login_attempts = failed_login_attempts + 1 WHERE user_id = ?`
+	const selectSQL = `SELECT failed_login_attempts FROM user WHERE user_id = ?`
+
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	_, err = repo.client.Exec(ctx, updateSQL, binaryUUID)
+	if err != nil {
+		return 0, err
+	}
 
-	err := repo.client.Get(&user, selectSQL, email)
+	var attempts int
+	err = repo.client.Get(ctx, &attempts, selectSQL, binaryUUID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return domain.User{}, domain.ErrUserNotFound
+			return 0, domain.ErrUserNotFound
 		}
-		return domain.User{}, errors.WithStack(err)
+		return 0, errors.WithStack(err)
 	}
-
-	return domain.User{
-		ID:       domain.UserID(user.UserID),
-		Email:    user.Email,
-		Password: user.Password,
-		Role:     domain.Role(user.Role),
-	}, nil
+	return attempts, nil
 }
 
-func (repo *userRepository) Store(user domain.User) error {
-	const insertSQL = `INSERT INTO user VALUES(?, ?, ?, ?)`
+func (repo *userRepository) Remove(ctx context.Context, id domain.UserID) error {
+	const deleteSQL = `DELETE FROM user WHERE user_id = ?`
 
-	binaryUUID, err := uuid.UUID(user.ID).MarshalBinary()
+	binaryUUID, err := uuid.UUID(id).MarshalBinary()
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	_, err = repo.client.Exec(insertSQL, binaryUUID, user.Email, user.Password, int(user.Role))
+	_, err = repo.client.Exec(ctx, deleteSQL, binaryUUID)
 	return err
 }
 
-func (repo *userRepository) Remove(id domain.UserID) error {
-	const deleteSQL = `DELETE FROM user WHERE user_id = ?`
+func (repo *userRepository) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]domain.UserID, error) {
+	const selectSQL = `SELECT user_id FROM user WHERE deleted_at < ? ORDER BY deleted_at LIMIT ?`
 
-	binaryUUID, err := uuid.UUID(id).MarshalBinary()
+	var ids []uuid.UUID
+	err := repo.client.Select(ctx, &ids, selectSQL, before, limit)
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
-	_, err = repo.client.Exec(deleteSQL, binaryUUID)
+	result := make([]domain.UserID, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, domain.UserID(id))
+	}
+	return result, nil
+}
+
+func (repo *userRepository) get(ctx context.Context, query string, args ...interface{}) (domain.User, error) {
+	var user sqlxUser
+
+	err := repo.client.Get(ctx, &user, query, args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		return domain.User{}, errors.WithStack(err)
+	}
+
+	return domain.User{
+		ID:                  domain.UserID(user.UserID),
+		Email:               user.Email,
+		Password:            user.Password,
+		PasswordAlgorithm:   user.PasswordAlgorithm,
+		Role:                domain.Role(user.Role),
+		FailedLoginAttempts: user.FailedLoginAttempts,
+		LockedUntil:         user.LockedUntil,
+		DeletedAt:           user.DeletedAt,
+		ErasedAt:            user.ErasedAt,
+	}, nil
+}
+
+// mapUserError reports email taken by concurrent sign up, check of email in domain does not lock
+func mapUserError(err error) error {
+	if isDuplicateEntry(err) {
+		return domain.ErrUserWithEmailAlreadyExists
+	}
 	return err
 }
 
 type sqlxUser struct {
-	UserID   uuid.UUID `db:"user_id"`
-	Email    string    `db:"email"`
-	Password string    `db:"password"`
-	Role     int       `db:"role"`
+	UserID              uuid.UUID  `db:"user_id"`
+	Email               string     `db:"email"`
+	Password            string     `db:"password"`
+	PasswordAlgorithm   string     `db:"password_algorithm"`
+	Role                int        `db:"role"`
+	FailedLoginAttempts int        `db:"failed_login_attempts"`
+	LockedUntil         *time.Time `db:"locked_until"`
+	DeletedAt           *time.Time `db:"deleted_at"`
+	ErasedAt            *time.Time `db:"erased_at"`
 }
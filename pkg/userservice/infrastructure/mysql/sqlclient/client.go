@@ -0,0 +1,92 @@
+package sqlclient
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "userservice/pkg/userservice/infrastructure/mysql"
+
+type Client interface {
+	Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type Executor interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func NewClient(executor Executor) Client {
+	return &tracedClient{executor: executor}
+}
+
+func NewTransactionClient(unitOfWorkCtx context.Context, executor Executor) Client {
+	return &tracedClient{executor: executor, parent: trace.SpanFromContext(unitOfWorkCtx)}
+}
+
+type tracedClient struct {
+	executor Executor
+	parent   trace.Span
+}
+
+func (c *tracedClient) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, span := c.startSpan(ctx, query)
+	err := c.executor.GetContext(ctx, dest, query, args...)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedClient) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, span := c.startSpan(ctx, query)
+	err := c.executor.SelectContext(ctx, dest, query, args...)
+	endSpan(span, err)
+	return err
+}
+
+func (c *tracedClient) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := c.startSpan(ctx, query)
+	result, err := c.executor.ExecContext(ctx, query, args...)
+	endSpan(span, err)
+	return result, err
+}
+
+func (c *tracedClient) startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	if c.parent != nil && c.parent.SpanContext().IsValid() {
+		ctx = trace.ContextWithSpan(ctx, c.parent)
+	}
+
+	query = strings.TrimSpace(query)
+	return otel.Tracer(instrumentationName).Start(
+		ctx,
+		"mysql."+operationName(query),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			attribute.String("db.statement", query),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func operationName(query string) string {
+	if i := strings.IndexAny(query, " \n\t"); i > 0 {
+		return strings.ToUpper(query[:i])
+	}
+	return strings.ToUpper(query)
+}
@@ -1,46 +1,190 @@
 package mysql
 
 import (
-	"github.com/CuriosityMusicStreaming/ComponentsPool/pkg/infrastructure/mysql"
+	"context"
+
+	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"userservice/pkg/userservice/app/service"
 	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/metrics"
 	"userservice/pkg/userservice/infrastructure/mysql/repository"
+	"userservice/pkg/userservice/infrastructure/mysql/sqlclient"
 )
 
-func NewUnitOfFactory(client mysql.TransactionalClient) service.UnitOfWorkFactory {
-	return &unitOfWorkFactory{client: client}
+const instrumentationName = "userservice/pkg/userservice/infrastructure/mysql"
+
+func NewUnitOfFactory(client TransactionalClient, unitOfWorkMetrics metrics.UnitOfWorkMetrics) service.UnitOfWorkFactory {
+	return &unitOfWorkFactory{client: client, metrics: unitOfWorkMetrics}
 }
 
 type unitOfWorkFactory struct {
-	client mysql.TransactionalClient
+	client  TransactionalClient
+	metrics metrics.UnitOfWorkMetrics
 }
 
-func (factory *unitOfWorkFactory) NewUnitOfWork(_ string) (service.UnitOfWork, error) {
-	transaction, err := factory.client.BeginTransaction()
+func (factory *unitOfWorkFactory) NewUnitOfWork(ctx context.Context, _ string) (service.UnitOfWork, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "UnitOfWork")
+
+	// transaction is rolled back by database/sql as soon as ctx is cancelled
+	transaction, err := factory.client.BeginTxx(ctx, nil)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
 		return nil, errors.WithStack(err)
 	}
 
-	return &unitOfWork{transaction: transaction}, nil
+	return &unitOfWork{
+		ctx:         ctx,
+		span:        span,
+		transaction: transaction,
+		client:      sqlclient.NewTransactionClient(ctx, transaction),
+		metrics:     factory.metrics,
+	}, nil
 }
 
 type unitOfWork struct {
-	transaction mysql.Transaction
+	ctx         context.Context
+	span        trace.Span
+	transaction *sqlx.Tx
+	client      sqlclient.Client
+	metrics     metrics.UnitOfWorkMetrics
 }
 
 func (u *unitOfWork) UserRepository() domain.UserRepository {
-	return repository.NewUserRepository(u.transaction)
+	return repository.NewUserRepository(u.client)
+}
+
+func (u *unitOfWork) DataExportRepository() domain.DataExportRepository {
+	return repository.NewDataExportRepository(u.client)
+}
+
+func (u *unitOfWork) ErasureCertificateRepository() domain.ErasureCertificateRepository {
+	return repository.NewErasureCertificateRepository(u.client)
+}
+
+func (u *unitOfWork) ConsentRepository() domain.ConsentRepository {
+	return repository.NewConsentRepository(u.client)
+}
+
+func (u *unitOfWork) AuditLogRepository() domain.AuditLogRepository {
+	return repository.NewAuditLogRepository(u.client)
+}
+
+func (u *unitOfWork) ProfileRepository() domain.ProfileRepository {
+	return repository.NewProfileRepository(u.client)
+}
+
+func (u *unitOfWork) CreatorApplicationRepository() domain.CreatorApplicationRepository {
+	return repository.NewCreatorApplicationRepository(u.client)
+}
+
+func (u *unitOfWork) ArtistRepository() domain.ArtistRepository {
+	return repository.NewArtistRepository(u.client)
+}
+
+func (u *unitOfWork) ArtistInvitationRepository() domain.ArtistInvitationRepository {
+	return repository.NewArtistInvitationRepository(u.client)
+}
+
+func (u *unitOfWork) SubscriptionRepository() domain.SubscriptionRepository {
+	return repository.NewSubscriptionRepository(u.client)
+}
+
+func (u *unitOfWork) HouseholdRepository() domain.HouseholdRepository {
+	return repository.NewHouseholdRepository(u.client)
+}
+
+func (u *unitOfWork) HouseholdInvitationRepository() domain.HouseholdInvitationRepository {
+	return repository.NewHouseholdInvitationRepository(u.client)
+}
+
+func (u *unitOfWork) ParentalControlsRepository() domain.ParentalControlsRepository {
+	return repository.NewParentalControlsRepository(u.client)
+}
+
+func (u *unitOfWork) FollowRepository() domain.FollowRepository {
+	return repository.NewFollowRepository(u.client)
+}
+
+func (u *unitOfWork) BlockRepository() domain.BlockRepository {
+	return repository.NewBlockRepository(u.client)
+}
+
+func (u *unitOfWork) PrivacySettingsRepository() domain.PrivacySettingsRepository {
+	return repository.NewPrivacySettingsRepository(u.client)
+}
+
+func (u *unitOfWork) ExternalIdentityRepository() domain.ExternalIdentityRepository {
+	return repository.NewExternalIdentityRepository(u.client)
+}
+
+func (u *unitOfWork) ExternalLoginRequestRepository() domain.ExternalLoginRequestRepository {
+	return repository.NewExternalLoginRequestRepository(u.client)
+}
+
+func (u *unitOfWork) OAuth2ClientRepository() domain.OAuth2ClientRepository {
+	return repository.NewOAuth2ClientRepository(u.client)
+}
+
+func (u *unitOfWork) OAuth2AuthorizationCodeRepository() domain.OAuth2AuthorizationCodeRepository {
+	return repository.NewOAuth2AuthorizationCodeRepository(u.client)
+}
+
+func (u *unitOfWork) OAuth2TokenRepository() domain.OAuth2TokenRepository {
+	return repository.NewOAuth2TokenRepository(u.client)
+}
+
+func (u *unitOfWork) OAuth2DeviceAuthorizationRepository() domain.OAuth2DeviceAuthorizationRepository {
+	return repository.NewOAuth2DeviceAuthorizationRepository(u.client)
+}
+
+func (u *unitOfWork) OAuth2UserCodeAttemptsRepository() domain.OAuth2UserCodeAttemptsRepository {
+	return repository.NewOAuth2UserCodeAttemptsRepository(u.client)
+}
+
+func (u *unitOfWork) SessionRepository() domain.SessionRepository {
+	return repository.NewSessionRepository(u.client)
+}
+
+func (u *unitOfWork) OutboxRepository() domain.OutboxRepository {
+	return repository.NewOutboxRepository(u.client)
 }
 
 func (u *unitOfWork) Complete(err error) error {
+	err = u.complete(err)
+	if err != nil {
+		u.span.RecordError(err)
+		u.span.SetStatus(codes.Error, err.Error())
+	}
+	u.span.End()
+	return err
+}
+
+func (u *unitOfWork) complete(err error) error {
 	if err != nil {
+		u.metrics.RolledBack()
 		err2 := u.transaction.Rollback()
 		if err2 != nil {
 			return errors.Wrap(err, err2.Error())
 		}
+		return err
+	}
+
+	err = u.transaction.Commit()
+	if err != nil {
+		u.metrics.RolledBack()
+		if ctxErr := u.ctx.Err(); ctxErr != nil {
+			return errors.WithStack(ctxErr)
+		}
+		return errors.WithStack(err)
 	}
 
-	return errors.WithStack(u.transaction.Commit())
+	u.metrics.Committed()
+	return nil
 }
@@ -0,0 +1,129 @@
+package openid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/coreos/go-oidc"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+type ProviderConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	HTTPClient   *http.Client
+}
+
+// NewProvider discovers provider on first use, so service starts even while provider is unreachable
+func NewProvider(config ProviderConfig) service.ExternalIdentityProvider {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &provider{config: config}
+}
+
+type provider struct {
+	config ProviderConfig
+
+	mu       sync.Mutex
+	oidc     *oidc.Provider
+	oauth2   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func (p *provider) AuthCodeURL(_ context.Context, state, nonce, codeChallenge string) (string, error) {
+	err := p.discover()
+	if err != nil {
+		return "", err
+	}
+	return p.oauth2.AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (service.ExternalClaims, error) {
+	err := p.discover()
+	if err != nil {
+		return service.ExternalClaims{}, err
+	}
+
+	ctx = oidc.ClientContext(ctx, p.config.HTTPClient)
+	token, err := p.oauth2.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	if err != nil {
+		return service.ExternalClaims{}, errors.Wrap(service.ErrExternalAuthenticationFailed, err.Error())
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return service.ExternalClaims{}, errors.Wrap(service.ErrExternalAuthenticationFailed, "no id token in token response")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return service.ExternalClaims{}, errors.Wrap(service.ErrExternalAuthenticationFailed, err.Error())
+	}
+	if idToken.Nonce != nonce {
+		return service.ExternalClaims{}, errors.Wrap(service.ErrExternalAuthenticationFailed, "id token nonce mismatch")
+	}
+
+	var claims struct {
+		Email         string          `json:"email"`
+		EmailVerified json.RawMessage `json:"email_verified"`
+	}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return service.ExternalClaims{}, errors.Wrap(service.ErrExternalAuthenticationFailed, err.Error())
+	}
+
+	return service.ExternalClaims{
+		Issuer:        idToken.Issuer,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+	}, nil
+}
+
+func (p *provider) discover() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oidc != nil {
+		return nil
+	}
+
+	// provider keeps context for fetching keys later, so it must outlive request
+	ctx := oidc.ClientContext(context.Background(), p.config.HTTPClient)
+	discovered, err := oidc.NewProvider(ctx, p.config.IssuerURL)
+	if err != nil {
+		return errors.Wrap(service.ErrExternalAuthenticationFailed, err.Error())
+	}
+
+	p.oidc = discovered
+	p.oauth2 = oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     discovered.Endpoint(),
+		Scopes:       []string{oidc.ScopeOpenID, "email"},
+	}
+	p.verifier = discovered.Verifier(&oidc.Config{ClientID: p.config.ClientID})
+	return nil
+}
+
+// isTrue accepts email_verified as boolean and as string, Apple sends the latter
+func isTrue(raw json.RawMessage) bool {
+	var b bool
+	if json.Unmarshal(raw, &b) == nil {
+		return b
+	}
+	var s string
+	return json.Unmarshal(raw, &s) == nil && s == "true"
+}
@@ -0,0 +1,204 @@
+package openid
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/app/service"
+)
+
+const (
+	testClientID     = "userservice"
+	testClientSecret = "secret"
+	testRedirectURL  = "https://userservice.test/callback"
+	testCode         = "code"
+	testSubject      = "subject"
+	testEmail        = "user@example.com"
+)
+
+// mockProvider is OpenID Connect provider issuing id token made by claims for code redeemed with PKCE verifier of last authorization
+type mockProvider struct {
+	server        *httptest.Server
+	key           *rsa.PrivateKey
+	codeChallenge string
+	claims        func(issuer, nonce string) map[string]interface{}
+	nonce         string
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &mockProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                p.server.URL,
+			"authorization_endpoint":                p.server.URL + "/authorize",
+			"token_endpoint":                        p.server.URL + "/token",
+			"jwks_uri":                              p.server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: &p.key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		err2 := r.ParseForm()
+		if err2 != nil || r.PostForm.Get("code") != testCode || hash.PKCEChallenge(r.PostForm.Get("code_verifier")) != p.codeChallenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     p.sign(t, p.claims(p.server.URL, p.nonce)),
+		})
+	})
+	p.server = httptest.NewServer(mux)
+	return p
+}
+
+// authorize plays user at provider, it remembers PKCE challenge and nonce of authorization url
+func (p *mockProvider) authorize(t *testing.T, authCodeURL string) {
+	u, err := url.Parse(authCodeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization url has no S256 code challenge: %s", authCodeURL)
+	}
+	if query.Get("client_id") != testClientID || query.Get("redirect_uri") != testRedirectURL {
+		t.Fatalf("authorization url has unexpected client: %s", authCodeURL)
+	}
+	p.codeChallenge = query.Get("code_challenge")
+	p.nonce = query.Get("nonce")
+}
+
+func (p *mockProvider) sign(t *testing.T, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: p.key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", "test"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func validClaims(issuer, nonce string) map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":            issuer,
+		"sub":            testSubject,
+		"aud":            testClientID,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          nonce,
+		"email":          testEmail,
+		"email_verified": "true",
+	}
+}
+
+func withClaim(name string, value interface{}) func(issuer, nonce string) map[string]interface{} {
+	return func(issuer, nonce string) map[string]interface{} {
+		claims := validClaims(issuer, nonce)
+		claims[name] = value
+		return claims
+	}
+}
+
+func TestProviderExchange(t *testing.T) {
+	testCases := []struct {
+		name     string
+		verifier string
+		claims   func(issuer, nonce string) map[string]interface{}
+		valid    bool
+	}{
+		{name: "valid", claims: validClaims, valid: true},
+		{name: "pkce verifier mismatch", verifier: "other-verifier", claims: validClaims},
+		{name: "nonce mismatch", claims: withClaim("nonce", "other-nonce")},
+		{name: "foreign audience", claims: withClaim("aud", "other-client")},
+		{name: "foreign issuer", claims: withClaim("iss", "https://issuer.test")},
+		{name: "expired", claims: withClaim("exp", time.Now().Add(-time.Hour).Unix())},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock := newMockProvider(t)
+			defer mock.server.Close()
+			mock.claims = testCase.claims
+
+			provider := NewProvider(ProviderConfig{
+				IssuerURL:    mock.server.URL,
+				ClientID:     testClientID,
+				ClientSecret: testClientSecret,
+				RedirectURL:  testRedirectURL,
+				HTTPClient:   mock.server.Client(),
+			})
+
+			codeVerifier, err := hash.RandomToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			nonce, err := hash.RandomToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			authCodeURL, err := provider.AuthCodeURL(context.Background(), "state", nonce, hash.PKCEChallenge(codeVerifier))
+			if err != nil {
+				t.Fatal(err)
+			}
+			mock.authorize(t, authCodeURL)
+			if mock.nonce != nonce {
+				t.Fatalf("authorization url carries nonce %q, want %q", mock.nonce, nonce)
+			}
+
+			if testCase.verifier != "" {
+				codeVerifier = testCase.verifier
+			}
+			claims, err := provider.Exchange(context.Background(), testCode, codeVerifier, nonce)
+			if !testCase.valid {
+				if errors.Cause(err) != service.ErrExternalAuthenticationFailed {
+					t.Fatalf("got error %v, want %v", err, service.ErrExternalAuthenticationFailed)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := service.ExternalClaims{Issuer: mock.server.URL, Subject: testSubject, Email: testEmail, EmailVerified: true}
+			if claims != expected {
+				t.Fatalf("got claims %+v, want %+v", claims, expected)
+			}
+		})
+	}
+}
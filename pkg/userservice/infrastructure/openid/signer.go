@@ -0,0 +1,142 @@
+package openid
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/square/go-jose.v2"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+var (
+	ErrSigningKeyRequired = errors.New("signing key is required")
+	ErrInvalidSigningKey  = errors.New("signing key is not PEM encoded RSA private key")
+	ErrInvalidSignature   = errors.New("token is not signed by signing key")
+)
+
+func NewSigner(keyPEM string) service.IDTokenSigner {
+	return &signer{keyPEM: keyPEM}
+}
+
+type signer struct {
+	keyPEM string
+
+	mu     sync.Mutex
+	key    *jose.JSONWebKey
+	signer jose.Signer
+}
+
+func (s *signer) Sign(claims map[string]interface{}) (string, error) {
+	err := s.init()
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	signature, err := s.signer.Sign(payload)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	token, err := signature.CompactSerialize()
+	return token, errors.WithStack(err)
+}
+
+func (s *signer) Verify(token string) (map[string]interface{}, error) {
+	err := s.init()
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := jose.ParseSigned(token)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	payload, err := signature.Verify(s.key.Public())
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	var claims map[string]interface{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return claims, nil
+}
+
+func (s *signer) KeySet() ([]byte, error) {
+	err := s.init()
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := jose.JSONWebKeySet{Keys: []jose.JSONWebKey{s.key.Public()}}
+	b, err := json.Marshal(keySet)
+	return b, errors.WithStack(err)
+}
+
+func (s *signer) init() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.signer != nil {
+		return nil
+	}
+
+	privateKey, err := s.privateKey()
+	if err != nil {
+		return err
+	}
+
+	key := jose.JSONWebKey{Key: privateKey, Algorithm: string(jose.RS256), Use: "sig"}
+	// key id is derived from key, so all instances sharing key publish same id
+	publicKey := key.Public()
+	thumbprint, err := publicKey.Thumbprint(crypto.SHA256)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	key.KeyID = base64.RawURLEncoding.EncodeToString(thumbprint)
+
+	joseSigner, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	s.key = &key
+	s.signer = joseSigner
+	return nil
+}
+
+func (s *signer) privateKey() (*rsa.PrivateKey, error) {
+	if s.keyPEM == "" {
+		return nil, ErrSigningKeyRequired
+	}
+
+	block, _ := pem.Decode([]byte(s.keyPEM))
+	if block == nil {
+		return nil, ErrInvalidSigningKey
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidSigningKey
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidSigningKey
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+var (
+	ErrInvalidKey     = errors.New("invalid storage key")
+	ErrObjectNotFound = errors.New("stored object not found")
+)
+
+func NewFileSystemStorage(dir string) service.ArchiveStorage {
+	return &fileSystemStorage{dir: dir}
+}
+
+type fileSystemStorage struct {
+	dir string
+}
+
+func (storage *fileSystemStorage) Save(_ context.Context, key string, r io.Reader) error {
+	path, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(storage.dir, 0o700)
+	if err != nil {
+		return errors.Wrap(err, "failed to create storage directory")
+	}
+
+	// file is written under temporary name, so readers never see partially written archive
+	tmp, err := os.CreateTemp(storage.dir, ".tmp-*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, r)
+	if err != nil {
+		_ = tmp.Close()
+		return errors.WithStack(err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(os.Rename(tmp.Name(), path))
+}
+
+func (storage *fileSystemStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := storage.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, errors.Wrap(ErrObjectNotFound, key)
+	}
+	return file, errors.WithStack(err)
+}
+
+func (storage *fileSystemStorage) Delete(_ context.Context, key string) error {
+	path, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return errors.WithStack(err)
+}
+
+func (storage *fileSystemStorage) path(key string) (string, error) {
+	if key == "" || filepath.Base(key) != key || key[0] == '.' {
+		return "", errors.Wrap(ErrInvalidKey, key)
+	}
+	return filepath.Join(storage.dir, key), nil
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func NewWriterExporter(writer io.Writer) sdktrace.SpanExporter {
+	return &writerExporter{encoder: json.NewEncoder(writer)}
+}
+
+type writerExporter struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+}
+
+func (exporter *writerExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	exporter.lock.Lock()
+	defer exporter.lock.Unlock()
+
+	for _, span := range spans {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := exporter.encoder.Encode(newJSONSpan(span))
+		if err != nil {
+			return errors.Wrap(err, "failed to write span")
+		}
+	}
+	return nil
+}
+
+func (exporter *writerExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+type jsonSpan struct {
+	TraceID      string                 `json:"trace_id"`
+	SpanID       string                 `json:"span_id"`
+	ParentSpanID string                 `json:"parent_span_id,omitempty"`
+	Name         string                 `json:"name"`
+	Kind         string                 `json:"kind"`
+	StartTime    time.Time              `json:"start_time"`
+	EndTime      time.Time              `json:"end_time"`
+	Status       string                 `json:"status"`
+	Description  string                 `json:"description,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+}
+
+func newJSONSpan(span sdktrace.ReadOnlySpan) jsonSpan {
+	result := jsonSpan{
+		TraceID:     span.SpanContext().TraceID().String(),
+		SpanID:      span.SpanContext().SpanID().String(),
+		Name:        span.Name(),
+		Kind:        span.SpanKind().String(),
+		StartTime:   span.StartTime(),
+		EndTime:     span.EndTime(),
+		Status:      span.Status().Code.String(),
+		Description: span.Status().Description,
+	}
+
+	if span.Parent().IsValid() {
+		result.ParentSpanID = span.Parent().SpanID().String()
+	}
+
+	if attributes := span.Attributes(); len(attributes) > 0 {
+		result.Attributes = make(map[string]interface{}, len(attributes))
+		for _, attr := range attributes {
+			result.Attributes[string(attr.Key)] = attr.Value.AsInterface()
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,260 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/auth"
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+var (
+	ErrInvalidArtistID           = errors.New("invalid artist id")
+	ErrInvalidArtistInvitationID = errors.New("invalid artist invitation id")
+	ErrUnknownArtistRole         = errors.New("unknown artist role")
+)
+
+func (server *userServiceServer) CreateArtist(ctx context.Context, req *api.CreateArtistRequest) (*api.Artist, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	artistID, err := server.container.ArtistService().CreateArtist(ctx, userID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	artist, err := server.container.ArtistQueryService().GetArtist(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIArtist(artist), nil
+}
+
+func (server *userServiceServer) GetArtist(ctx context.Context, req *api.GetArtistRequest) (*api.Artist, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	artistID, err := parseArtistID(req.ArtistId)
+	if err != nil {
+		return nil, err
+	}
+
+	artist, err := server.container.ArtistQueryService().GetArtist(ctx, artistID)
+	if err != nil {
+		return nil, err
+	}
+	if !artist.HasMember(userID) {
+		err = server.assertAdmin(ctx, req.UserToken)
+		if err != nil {
+			if errors.Cause(err) == auth.ErrAdminRequired {
+				return nil, domain.ErrArtistPermissionDenied
+			}
+			return nil, err
+		}
+	}
+	return makeAPIArtist(artist), nil
+}
+
+func (server *userServiceServer) ListMyArtists(ctx context.Context, req *api.ListMyArtistsRequest) (*api.ListMyArtistsResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := server.container.ArtistQueryService().ListUserArtists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListMyArtistsResponse{}
+	for _, membership := range memberships {
+		resp.Memberships = append(resp.Memberships, &api.ArtistMembership{
+			ArtistId:   membership.ArtistID.String(),
+			ArtistName: membership.ArtistName,
+			Role:       artistRoleToAPIMap[membership.Role],
+			JoinedAt:   timestamppb.New(membership.JoinedAt),
+		})
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) InviteArtistMember(ctx context.Context, req *api.InviteArtistMemberRequest) (*api.InviteArtistMemberResponse, error) {
+	actorID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	artistID, err := parseArtistID(req.ArtistId)
+	if err != nil {
+		return nil, err
+	}
+	role, ok := apiToArtistRoleMap[req.Role]
+	if !ok {
+		return nil, ErrUnknownArtistRole
+	}
+
+	invitationID, err := server.container.ArtistService().InviteMember(ctx, artistID, actorID, req.Email, role)
+	if err != nil {
+		return nil, err
+	}
+	return &api.InviteArtistMemberResponse{InvitationId: invitationID.String()}, nil
+}
+
+func (server *userServiceServer) ListArtistInvitations(ctx context.Context, req *api.ListArtistInvitationsRequest) (*api.ListArtistInvitationsResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := server.container.ArtistQueryService().ListInvitations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListArtistInvitationsResponse{}
+	for _, invitation := range invitations {
+		resp.Invitations = append(resp.Invitations, &api.ArtistInvitation{
+			InvitationId: invitation.ID.String(),
+			ArtistId:     invitation.ArtistID.String(),
+			ArtistName:   invitation.ArtistName,
+			Role:         artistRoleToAPIMap[invitation.Role],
+			InvitedBy:    invitation.InvitedBy.String(),
+			CreatedAt:    timestamppb.New(invitation.CreatedAt),
+			ExpiresAt:    timestamppb.New(invitation.ExpiresAt),
+		})
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) RespondToArtistInvitation(ctx context.Context, req *api.RespondToArtistInvitationRequest) (*api.RespondToArtistInvitationResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	invitationID, err := uuid.Parse(req.InvitationId)
+	if err != nil {
+		return nil, ErrInvalidArtistInvitationID
+	}
+
+	err = server.container.ArtistService().RespondToInvitation(ctx, invitationID, userID, req.Accept)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RespondToArtistInvitationResponse{}, nil
+}
+
+func (server *userServiceServer) ChangeArtistMemberRole(ctx context.Context, req *api.ChangeArtistMemberRoleRequest) (*api.ChangeArtistMemberRoleResponse, error) {
+	actorID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	artistID, err := parseArtistID(req.ArtistId)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	role, ok := apiToArtistRoleMap[req.Role]
+	if !ok {
+		return nil, ErrUnknownArtistRole
+	}
+
+	err = server.container.ArtistService().ChangeMemberRole(ctx, artistID, actorID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ChangeArtistMemberRoleResponse{}, nil
+}
+
+func (server *userServiceServer) RemoveArtistMember(ctx context.Context, req *api.RemoveArtistMemberRequest) (*api.RemoveArtistMemberResponse, error) {
+	actorID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	artistID, err := parseArtistID(req.ArtistId)
+	if err != nil {
+		return nil, err
+	}
+	userID := actorID
+	if req.UserId != "" {
+		userID, err = uuid.Parse(req.UserId)
+		if err != nil {
+			return nil, ErrInvalidUserID
+		}
+	}
+
+	err = server.container.ArtistService().RemoveMember(ctx, artistID, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RemoveArtistMemberResponse{}, nil
+}
+
+func (server *userServiceServer) TransferArtistOwnership(ctx context.Context, req *api.TransferArtistOwnershipRequest) (*api.TransferArtistOwnershipResponse, error) {
+	actorID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	artistID, err := parseArtistID(req.ArtistId)
+	if err != nil {
+		return nil, err
+	}
+	newOwnerID, err := uuid.Parse(req.NewOwnerId)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	err = server.container.ArtistService().TransferOwnership(ctx, artistID, actorID, newOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TransferArtistOwnershipResponse{}, nil
+}
+
+func parseArtistID(id string) (uuid.UUID, error) {
+	artistID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.UUID{}, ErrInvalidArtistID
+	}
+	return artistID, nil
+}
+
+var apiToArtistRoleMap = map[api.ArtistRole]service.ArtistRole{
+	api.ArtistRole_VIEWER:   service.ArtistViewer,
+	api.ArtistRole_UPLOADER: service.ArtistUploader,
+	api.ArtistRole_MANAGER:  service.ArtistManager,
+	api.ArtistRole_OWNER:    service.ArtistOwner,
+}
+
+var artistRoleToAPIMap = map[query.ArtistRole]api.ArtistRole{
+	query.ArtistViewer:   api.ArtistRole_VIEWER,
+	query.ArtistUploader: api.ArtistRole_UPLOADER,
+	query.ArtistManager:  api.ArtistRole_MANAGER,
+	query.ArtistOwner:    api.ArtistRole_OWNER,
+}
+
+func makeAPIArtist(artist query.ArtistView) *api.Artist {
+	result := &api.Artist{
+		ArtistId:  artist.ID.String(),
+		Name:      artist.Name,
+		CreatedAt: timestamppb.New(artist.CreatedAt),
+	}
+	for _, member := range artist.Members {
+		result.Members = append(result.Members, &api.ArtistMember{
+			UserId:   member.UserID.String(),
+			Role:     artistRoleToAPIMap[member.Role],
+			JoinedAt: timestamppb.New(member.JoinedAt),
+		})
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"context"
+
+	commonauth "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/auth"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"userservice/pkg/userservice/app/service"
+)
+
+const (
+	requestIDMetadataKey        = "x-request-id"
+	userAgentMetadataKey        = "user-agent"
+	gatewayUserAgentMetadataKey = "grpcgateway-user-agent"
+)
+
+type userTokenRequest interface {
+	GetUserToken() string
+}
+
+func NewAuditContextServerInterceptor(serializer commonauth.UserDescriptorSerializer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		auditContext := makeAuditContext(ctx)
+
+		token := ""
+		if r, ok := req.(userTokenRequest); ok {
+			token = r.GetUserToken()
+		}
+		if desc, err := serializer.Deserialize(userTokenFromContext(ctx, token)); err == nil {
+			auditContext.ActorID = &desc.UserID
+		}
+
+		return handler(service.WithAuditContext(ctx, auditContext), req)
+	}
+}
+
+func NewAuditContextStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := service.WithAuditContext(stream.Context(), makeAuditContext(stream.Context()))
+		return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func makeAuditContext(ctx context.Context) service.AuditContext {
+	auditContext := service.AuditContext{IP: clientIPFromContext(ctx)}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		auditContext.RequestID = firstMetadataValue(md, requestIDMetadataKey)
+		auditContext.UserAgent = firstMetadataValue(md, gatewayUserAgentMetadataKey)
+		if auditContext.UserAgent == "" {
+			auditContext.UserAgent = firstMetadataValue(md, userAgentMetadataKey)
+		}
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); auditContext.RequestID == "" && spanContext.HasTraceID() {
+		auditContext.RequestID = spanContext.TraceID().String()
+	}
+	return auditContext
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *contextServerStream) Context() context.Context {
+	return stream.ctx
+}
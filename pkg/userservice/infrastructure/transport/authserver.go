@@ -1,10 +1,14 @@
 package transport
 
 import (
+	"time"
+
+	"github.com/google/uuid"
 	"golang.org/x/net/context"
 
 	authenticationapi "userservice/api/authenticationservice"
 	authorizationapi "userservice/api/authorizationservice"
+	"userservice/pkg/userservice/app/auth"
 	"userservice/pkg/userservice/app/service"
 	"userservice/pkg/userservice/infrastructure"
 )
@@ -22,13 +26,22 @@ type authServer struct {
 	container infrastructure.DependencyContainer
 }
 
-func (server *authServer) CanAddContent(_ context.Context, req *authorizationapi.CanAddContentRequest) (*authorizationapi.CanAddContentResponse, error) {
-	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(req.UserToken)
+func (server *authServer) CanAddContent(ctx context.Context, req *authorizationapi.CanAddContentRequest) (*authorizationapi.CanAddContentResponse, error) {
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, req.UserToken))
 	if err != nil {
 		return nil, err
 	}
 
-	canAddContent, err := server.container.AuthenticationService().CanAddContent(userDesc)
+	var canAddContent bool
+	if req.ArtistId == "" {
+		canAddContent, err = server.container.AuthenticationService().CanAddContent(ctx, userDesc)
+	} else {
+		artistID, err2 := uuid.Parse(req.ArtistId)
+		if err2 != nil {
+			return nil, ErrInvalidArtistID
+		}
+		canAddContent, err = server.container.AuthenticationService().CanAddContentForArtist(ctx, userDesc, artistID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -36,19 +49,95 @@ func (server *authServer) CanAddContent(_ context.Context, req *authorizationapi
 	return &authorizationapi.CanAddContentResponse{CanAdd: canAddContent}, nil
 }
 
-func (server *authServer) AuthenticateUser(_ context.Context, req *authenticationapi.AuthenticateUserRequest) (*authenticationapi.AuthenticateUserResponse, error) {
-	userID, role, err := server.container.AuthenticationService().AuthenticateUser(req.Email, req.Password)
+func (server *authServer) AuthenticateUser(ctx context.Context, req *authenticationapi.AuthenticateUserRequest) (*authenticationapi.AuthenticateUserResponse, error) {
+	user, err := server.container.AuthenticationService().AuthenticateUser(ctx, req.Email, req.Password)
 	if err != nil {
 		return nil, err
 	}
+	return makeAuthenticateUserResponse(user), nil
+}
 
-	return &authenticationapi.AuthenticateUserResponse{
-		UserID: userID,
-		Role:   userRoleToAuthAPIMap[role],
+func (server *authServer) StartExternalSignIn(ctx context.Context, req *authenticationapi.StartExternalSignInRequest) (*authenticationapi.StartExternalSignInResponse, error) {
+	authorization, err := server.container.ExternalIdentityService().StartSignIn(ctx, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return &authenticationapi.StartExternalSignInResponse{AuthorizationUrl: authorization.URL, State: authorization.State}, nil
+}
+
+func (server *authServer) CompleteExternalSignIn(ctx context.Context, req *authenticationapi.CompleteExternalSignInRequest) (*authenticationapi.AuthenticateUserResponse, error) {
+	err := verifyExternalSignInState(ctx, req.State)
+	if err != nil {
+		return nil, err
+	}
+
+	var signUp service.ExternalSignUp
+	if req.AcceptedTermsVersion != "" {
+		signUp.Terms = &service.TermsAcceptance{
+			Version:  req.AcceptedTermsVersion,
+			SourceIP: clientIPFromContext(ctx),
+		}
+	}
+	if req.DateOfBirth != "" {
+		date, err := time.Parse(service.DateLayout, req.DateOfBirth)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		signUp.DateOfBirth = &date
+	}
+
+	user, err := server.container.AuthenticationService().AuthenticateExternalUser(ctx, req.State, req.Code, signUp)
+	if err != nil {
+		return nil, err
+	}
+	return makeAuthenticateUserResponse(user), nil
+}
+
+func (server *authServer) StartDeviceAuthorization(
+	ctx context.Context,
+	req *authenticationapi.StartDeviceAuthorizationRequest,
+) (*authenticationapi.StartDeviceAuthorizationResponse, error) {
+	authorization, err := server.container.OAuth2Service().StartDeviceAuthorization(ctx, service.OAuth2ClientCredentials{ClientID: req.ClientId}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &authenticationapi.StartDeviceAuthorizationResponse{
+		DeviceCode:              authorization.DeviceCode,
+		UserCode:                authorization.UserCode,
+		VerificationUri:         authorization.VerificationURI,
+		VerificationUriComplete: authorization.VerificationURIComplete,
+		ExpiresIn:               int32(authorization.ExpiresIn.Seconds()),
+		Interval:                int32(authorization.Interval.Seconds()),
 	}, nil
 }
 
+func (server *authServer) PollDeviceToken(ctx context.Context, req *authenticationapi.PollDeviceTokenRequest) (*authenticationapi.AuthenticateUserResponse, error) {
+	user, err := server.container.AuthenticationService().AuthenticateDevice(ctx, req.ClientId, req.DeviceCode)
+	if err != nil {
+		return nil, err
+	}
+	return makeAuthenticateUserResponse(user), nil
+}
+
+func makeAuthenticateUserResponse(user auth.AuthenticatedUser) *authenticationapi.AuthenticateUserResponse {
+	return &authenticationapi.AuthenticateUserResponse{
+		UserID:                    user.UserID,
+		Role:                      userRoleToAuthAPIMap[user.Role],
+		TermsReacceptanceRequired: user.TermsReacceptanceRequired,
+		Plan:                      subscriptionPlanToAuthAPIMap[user.Plan],
+		Entitlements:              user.Entitlements,
+	}
+}
+
 var userRoleToAuthAPIMap = map[service.Role]authenticationapi.UserRole{
 	service.Listener: authenticationapi.UserRole_LISTENER,
 	service.Creator:  authenticationapi.UserRole_CREATOR,
+	service.Admin:    authenticationapi.UserRole_ADMIN,
+}
+
+var subscriptionPlanToAuthAPIMap = map[service.SubscriptionPlan]authenticationapi.SubscriptionPlan{
+	service.FreePlan:    authenticationapi.SubscriptionPlan_FREE,
+	service.PremiumPlan: authenticationapi.SubscriptionPlan_PREMIUM,
+	service.FamilyPlan:  authenticationapi.SubscriptionPlan_FAMILY,
+	service.StudentPlan: authenticationapi.SubscriptionPlan_STUDENT,
 }
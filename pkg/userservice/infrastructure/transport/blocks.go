@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/service"
+)
+
+var ErrTooManyUserPairs = errors.New("too many user pairs")
+
+const (
+	defaultBlocksPageSize = 50
+	maxBlocksPageSize     = 500
+	maxIsBlockedPairs     = 100
+)
+
+func (server *userServiceServer) BlockUser(ctx context.Context, req *api.BlockUserRequest) (*api.BlockUserResponse, error) {
+	blockerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	blockedID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	err = server.container.BlockService().BlockUser(ctx, blockerID, blockedID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.BlockUserResponse{}, nil
+}
+
+func (server *userServiceServer) UnblockUser(ctx context.Context, req *api.UnblockUserRequest) (*api.UnblockUserResponse, error) {
+	blockerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	blockedID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	err = server.container.BlockService().UnblockUser(ctx, blockerID, blockedID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.UnblockUserResponse{}, nil
+}
+
+func (server *userServiceServer) ListBlocked(ctx context.Context, req *api.ListBlockedRequest) (*api.ListBlockedResponse, error) {
+	blockerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, err := offsetPage(req.PageSize, req.PageToken, defaultBlocksPageSize, maxBlocksPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := server.container.BlockService().ListBlocked(ctx, blockerID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListBlockedResponse{}
+	for _, block := range blocks {
+		resp.Users = append(resp.Users, &api.BlockedUser{
+			UserId:    block.BlockedID.String(),
+			BlockedAt: timestamppb.New(block.CreatedAt),
+		})
+	}
+	if len(blocks) == limit {
+		resp.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	return resp, nil
+}
+
+// IsBlocked is called by other services, e.g. comments or messaging, with token of user they act for,
+// every pair must include that user, otherwise blocks between other users would leak; admins check any pair
+func (server *userServiceServer) IsBlocked(ctx context.Context, req *api.IsBlockedRequest) (*api.IsBlockedResponse, error) {
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, req.UserToken))
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Pairs) > maxIsBlockedPairs {
+		return nil, ErrTooManyUserPairs
+	}
+
+	pairs := make([]service.UserPair, 0, len(req.Pairs))
+	foreignPairs := false
+	for _, pair := range req.Pairs {
+		userID, err2 := uuid.Parse(pair.UserId)
+		if err2 != nil {
+			return nil, ErrInvalidUserID
+		}
+		otherID, err2 := uuid.Parse(pair.OtherId)
+		if err2 != nil {
+			return nil, ErrInvalidUserID
+		}
+		pairs = append(pairs, service.UserPair{UserID: userID, OtherID: otherID})
+		foreignPairs = foreignPairs || (userID != userDesc.UserID && otherID != userDesc.UserID)
+	}
+	if foreignPairs {
+		err = server.container.AuthenticationService().AssertAdmin(ctx, userDesc)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	blocked, err := server.container.BlockService().IsBlocked(ctx, pairs)
+	if err != nil {
+		return nil, err
+	}
+	return &api.IsBlockedResponse{Blocked: blocked}, nil
+}
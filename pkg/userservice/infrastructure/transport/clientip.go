@@ -0,0 +1,97 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+const forwardedForMetadataKey = "x-forwarded-for"
+
+type TrustedProxies []*net.IPNet
+
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	proxies := make(TrustedProxies, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy network %q", cidr)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (proxies TrustedProxies) contains(address string) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveClientIP walks x-forwarded-for from peer back to first untrusted hop, addresses before it may be forged by client
+func (proxies TrustedProxies) resolveClientIP(ctx context.Context) string {
+	address := peerAddress(ctx)
+	if !proxies.contains(address) {
+		return address
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return address
+	}
+	var hops []string
+	for _, value := range md.Get(forwardedForMetadataKey) {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		address = strings.TrimSpace(hops[i])
+		if !proxies.contains(address) {
+			break
+		}
+	}
+	return address
+}
+
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+type clientIPContextKey struct{}
+
+func NewClientIPServerInterceptor(proxies TrustedProxies) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(context.WithValue(ctx, clientIPContextKey{}, proxies.resolveClientIP(ctx)), req)
+	}
+}
+
+func NewClientIPStreamServerInterceptor(proxies TrustedProxies) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := context.WithValue(stream.Context(), clientIPContextKey{}, proxies.resolveClientIP(stream.Context()))
+		return handler(srv, &contextServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func clientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey{}).(string)
+	return ip
+}
@@ -0,0 +1,44 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+const downloadTokenQueryParam = "token"
+
+func NewDataExportDownloadHandler(dataExportService service.DataExportService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get(downloadTokenQueryParam)
+		if token == "" {
+			http.Error(w, "missing download token", http.StatusBadRequest)
+			return
+		}
+
+		archive, err := dataExportService.OpenArchive(r.Context(), token)
+		if err != nil {
+			switch errors.Cause(err) {
+			case domain.ErrDataExportNotFound:
+				http.Error(w, err.Error(), http.StatusNotFound)
+			case service.ErrDataExportNotReady:
+				http.Error(w, err.Error(), http.StatusConflict)
+			case service.ErrDataExportExpired:
+				http.Error(w, err.Error(), http.StatusGone)
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+		defer archive.Close()
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="user-data.zip"`)
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = io.Copy(w, archive)
+	}
+}
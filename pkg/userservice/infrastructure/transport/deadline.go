@@ -0,0 +1,30 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+type DeadlineConfig struct {
+	DefaultTimeout time.Duration
+	MethodTimeouts map[string]time.Duration
+}
+
+func NewDeadlineServerInterceptor(config DeadlineConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		timeout, ok := config.MethodTimeouts[getGRPCMethodName(info.FullMethod)]
+		if !ok {
+			timeout = config.DefaultTimeout
+		}
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		return handler(ctx, req)
+	}
+}
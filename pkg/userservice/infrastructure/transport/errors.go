@@ -1,25 +1,159 @@
 package transport
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"userservice/pkg/userservice/app/auth"
 	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/app/service"
 	"userservice/pkg/userservice/domain"
+	"userservice/pkg/userservice/infrastructure/userexchange"
 )
 
 func translateError(err error) error {
 	switch errors.Cause(err) {
-	case auth.ErrOnlyCreatorsCanAddContent:
+	case auth.ErrOnlyCreatorsCanAddContent, ErrRoleRequiresAdmin:
 		return status.Error(codes.PermissionDenied, err.Error())
-	case domain.ErrUserNotFound:
-	case query.ErrUserNotFound:
+	case domain.ErrUserNotFound, query.ErrUserNotFound, domain.ErrDataExportNotFound:
 		return status.Error(codes.NotFound, err.Error())
 	case domain.ErrUserWithEmailAlreadyExists:
-	case auth.ErrIncorrectAuthData:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case auth.ErrIncorrectAuthData, ErrUnknownUserRole, ErrUnknownExchangeFormat, userexchange.ErrMissingEmailColumn, ErrInvalidUserID, ErrInvalidDataExportID, ErrInvalidPageToken, ErrInvalidDate:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case service.ErrUnknownConsentKind,
+		service.ErrUnknownMarketingChannel,
+		service.ErrDocumentVersionRequired,
+		service.ErrDocumentConsentNotGranted,
+		service.ErrChannelNotAllowedForKind,
+		service.ErrVersionNotAllowedForKind:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case service.ErrUnknownProfileField,
+		domain.ErrInvalidDisplayName,
+		domain.ErrInvalidAvatarURL,
+		domain.ErrInvalidCountry,
+		domain.ErrInvalidLanguage,
+		domain.ErrInvalidDateOfBirth,
+		domain.ErrBioTooLong:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case ErrInvalidApplicationID, ErrUnknownReviewDecision, domain.ErrCreatorApplicationTextTooLong:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case ErrInvalidArtistID,
+		ErrInvalidArtistInvitationID,
+		ErrUnknownArtistRole,
+		domain.ErrInvalidArtistName,
+		domain.ErrInvalidArtistRole,
+		domain.ErrArtistOwnershipTransferToSelf:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrArtistNotFound, domain.ErrArtistMemberNotFound, domain.ErrArtistInvitationNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.ErrAlreadyArtistMember:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case domain.ErrArtistPermissionDenied, domain.ErrOnlyCreatorsCanCreateArtist, auth.ErrNotArtistUploader:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case domain.ErrArtistInvitationExpired, domain.ErrArtistInvitationNotPending, domain.ErrArtistOwnerCannotLeave:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case ErrUnknownSubscriptionPlan,
+		ErrUnknownSubscriptionStatus,
+		domain.ErrInvalidSubscriptionPlan,
+		domain.ErrInvalidSubscriptionStatus,
+		domain.ErrInvalidBillingPeriod,
+		domain.ErrTrialOnFreePlan:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrTrialAlreadyUsed:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case ErrInvalidHouseholdInvitationID,
+		domain.ErrHouseholdAddressRequired,
+		domain.ErrHouseholdInvitationEmailRequired,
+		domain.ErrHouseholdInvitationForManager:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrHouseholdNotFound, domain.ErrHouseholdMemberNotFound, domain.ErrHouseholdInvitationNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.ErrAlreadyInHousehold:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case domain.ErrOnlyHouseholdManagerCanInvite, domain.ErrHouseholdAddressMismatch:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case domain.ErrHouseholdFull,
+		domain.ErrFamilyPlanRequired,
+		domain.ErrHouseholdInvitationExpired,
+		domain.ErrHouseholdInvitationNotPending,
+		domain.ErrHouseholdManagerCannotLeave:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case domain.ErrInvalidParentalPIN, domain.ErrGuardianIsSelf:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrParentalControlsForbidden, domain.ErrParentalPINMismatch, domain.ErrParentalPINLocked, domain.ErrGuardianRequired,
+		domain.ErrGuardianChangeForbidden, domain.ErrPINSetByGuardian, domain.ErrAgeChangeByGuardian:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case domain.ErrParentalPINRequired, domain.ErrGuardianMustBeAdult, domain.ErrExplicitContentForChild:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case ErrInvalidFollowTargetID, ErrUnknownFollowTargetKind, domain.ErrCannotFollowSelf, domain.ErrInvalidFollowTargetKind:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrFollowNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.ErrAlreadyFollowing:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case domain.ErrFollowBlocked, domain.ErrFollowNotAllowed, domain.ErrProfileNotVisible:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case ErrTooManyUserPairs,
+		ErrUnknownProfileVisibility,
+		ErrUnknownFollowPolicy,
+		domain.ErrCannotBlockSelf,
+		domain.ErrInvalidProfileVisibility,
+		domain.ErrInvalidFollowPolicy:
 		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrBlockNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.ErrAlreadyBlocked:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case service.ErrUnknownExternalProvider:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrExternalIdentityNotFound, domain.ErrExternalLoginRequestNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.ErrExternalIdentityLinkedToOther, domain.ErrExternalProviderAlreadyLinked, domain.ErrExternalEmailBelongsToOtherUser:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case domain.ErrExternalLoginRequestExpired,
+		domain.ErrLastSignInMethod,
+		domain.ErrExternalEmailNotVerified,
+		service.ErrExternalLoginRequestMismatch,
+		ErrExternalSignInStateMismatch:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case service.ErrExternalAuthenticationFailed:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case ErrUnknownOAuth2GrantType, service.ErrInvalidOAuth2ClientRegistration:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrOAuth2ClientNotFound, domain.ErrOAuth2DeviceAuthorizationNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case service.ErrInvalidOAuth2Client:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case service.ErrInvalidOAuth2Grant, domain.ErrInvalidOAuth2Scope:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case domain.ErrOAuth2GrantNotAllowed, service.ErrOAuth2AccessDenied:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case service.ErrOAuth2AuthorizationPending:
+		return status.Error(codes.Unavailable, err.Error())
+	case service.ErrOAuth2SlowDown, domain.ErrOAuth2UserCodeLocked:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case service.ErrOAuth2DeviceCodeExpired:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case domain.ErrCreatorApplicationNotFound:
+		return status.Error(codes.NotFound, err.Error())
+	case domain.ErrCreatorApplicationAlreadyOpen:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case domain.ErrOnlyListenersCanApplyForCreator, domain.ErrInvalidCreatorApplicationDecision:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case service.ErrTermsAcceptanceRequired, service.ErrOutdatedDocumentVersion:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case auth.ErrUserLocked, auth.ErrAdminRequired:
+		return status.Error(codes.PermissionDenied, err.Error())
+	case domain.ErrUserNotDeleted, domain.ErrUserAlreadyErased:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
 	}
 
 	return err
@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "userservice/api/userservice"
+)
+
+func (server *userServiceServer) StartExternalIdentityLink(ctx context.Context, req *api.StartExternalIdentityLinkRequest) (*api.StartExternalIdentityLinkResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	authorization, err := server.container.ExternalIdentityService().StartLink(ctx, userID, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return &api.StartExternalIdentityLinkResponse{AuthorizationUrl: authorization.URL, State: authorization.State}, nil
+}
+
+func (server *userServiceServer) CompleteExternalIdentityLink(ctx context.Context, req *api.CompleteExternalIdentityLinkRequest) (*api.CompleteExternalIdentityLinkResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.container.ExternalIdentityService().CompleteLink(ctx, userID, req.State, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &api.CompleteExternalIdentityLinkResponse{}, nil
+}
+
+func (server *userServiceServer) UnlinkExternalIdentity(ctx context.Context, req *api.UnlinkExternalIdentityRequest) (*api.UnlinkExternalIdentityResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.container.ExternalIdentityService().Unlink(ctx, userID, req.Provider)
+	if err != nil {
+		return nil, err
+	}
+	return &api.UnlinkExternalIdentityResponse{}, nil
+}
+
+func (server *userServiceServer) ListExternalIdentities(ctx context.Context, req *api.ListExternalIdentitiesRequest) (*api.ListExternalIdentitiesResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := server.container.ExternalIdentityService().ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListExternalIdentitiesResponse{}
+	for _, identity := range identities {
+		resp.Identities = append(resp.Identities, &api.ExternalIdentity{
+			Provider: identity.Provider,
+			Email:    identity.Email,
+			LinkedAt: timestamppb.New(identity.LinkedAt),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/service"
+)
+
+var (
+	ErrInvalidFollowTargetID   = errors.New("invalid follow target id")
+	ErrUnknownFollowTargetKind = errors.New("unknown follow target kind")
+)
+
+const (
+	defaultFollowsPageSize = 50
+	maxFollowsPageSize     = 500
+)
+
+func (server *userServiceServer) Follow(ctx context.Context, req *api.FollowRequest) (*api.FollowResponse, error) {
+	followerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	target, err := makeFollowTarget(req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.container.FollowService().Follow(ctx, followerID, target)
+	if err != nil {
+		return nil, err
+	}
+	return &api.FollowResponse{}, nil
+}
+
+func (server *userServiceServer) Unfollow(ctx context.Context, req *api.UnfollowRequest) (*api.UnfollowResponse, error) {
+	followerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	target, err := makeFollowTarget(req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.container.FollowService().Unfollow(ctx, followerID, target)
+	if err != nil {
+		return nil, err
+	}
+	return &api.UnfollowResponse{}, nil
+}
+
+func (server *userServiceServer) ListFollowers(ctx context.Context, req *api.ListFollowersRequest) (*api.ListFollowersResponse, error) {
+	viewerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	target, err := makeFollowTarget(req.Target)
+	if err != nil {
+		return nil, err
+	}
+	offset, limit, err := offsetPage(req.PageSize, req.PageToken, defaultFollowsPageSize, maxFollowsPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	follows, err := server.container.FollowService().ListFollowers(ctx, viewerID, target, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := server.container.FollowService().GetCounts(ctx, viewerID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListFollowersResponse{TotalFollowers: int64(counts.Followers)}
+	for _, follow := range follows {
+		resp.FollowerIds = append(resp.FollowerIds, follow.FollowerID.String())
+	}
+	if len(follows) == limit {
+		resp.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) ListFollowing(ctx context.Context, req *api.ListFollowingRequest) (*api.ListFollowingResponse, error) {
+	viewerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	userID := viewerID
+	if req.UserId != "" {
+		userID, err = uuid.Parse(req.UserId)
+		if err != nil {
+			return nil, ErrInvalidUserID
+		}
+	}
+	offset, limit, err := offsetPage(req.PageSize, req.PageToken, defaultFollowsPageSize, maxFollowsPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	follows, err := server.container.FollowService().ListFollowing(ctx, viewerID, userID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := server.container.FollowService().GetCounts(ctx, viewerID, service.FollowTarget{Kind: service.FollowTargetUser, ID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListFollowingResponse{TotalFollowing: int64(counts.Following)}
+	for _, follow := range follows {
+		resp.Targets = append(resp.Targets, &api.FollowTarget{
+			Kind: followTargetKindToAPIMap[follow.Target.Kind],
+			Id:   follow.Target.ID.String(),
+		})
+	}
+	if len(follows) == limit {
+		resp.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) GetFollowCounts(ctx context.Context, req *api.GetFollowCountsRequest) (*api.FollowCounts, error) {
+	viewerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	target, err := makeFollowTarget(req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := server.container.FollowService().GetCounts(ctx, viewerID, target)
+	if err != nil {
+		return nil, err
+	}
+	return &api.FollowCounts{Followers: int64(counts.Followers), Following: int64(counts.Following)}, nil
+}
+
+func offsetPage(pageSize int32, pageToken string, defaultLimit, maxLimit int) (offset, limit int, err error) {
+	limit = int(pageSize)
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	if pageToken != "" {
+		offset, err = strconv.Atoi(pageToken)
+		if err != nil || offset <= 0 {
+			return 0, 0, ErrInvalidPageToken
+		}
+	}
+	return offset, limit, nil
+}
+
+var apiToFollowTargetKindMap = map[api.FollowTargetKind]service.FollowTargetKind{
+	api.FollowTargetKind_USER:   service.FollowTargetUser,
+	api.FollowTargetKind_ARTIST: service.FollowTargetArtist,
+}
+
+var followTargetKindToAPIMap = map[service.FollowTargetKind]api.FollowTargetKind{
+	service.FollowTargetUser:   api.FollowTargetKind_USER,
+	service.FollowTargetArtist: api.FollowTargetKind_ARTIST,
+}
+
+func makeFollowTarget(target *api.FollowTarget) (service.FollowTarget, error) {
+	if target == nil {
+		return service.FollowTarget{}, ErrInvalidFollowTargetID
+	}
+	kind, ok := apiToFollowTargetKindMap[target.Kind]
+	if !ok {
+		return service.FollowTarget{}, ErrUnknownFollowTargetKind
+	}
+	id, err := uuid.Parse(target.Id)
+	if err != nil {
+		return service.FollowTarget{}, ErrInvalidFollowTargetID
+	}
+	return service.FollowTarget{Kind: kind, ID: id}, nil
+}
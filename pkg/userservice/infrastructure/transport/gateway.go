@@ -0,0 +1,207 @@
+package transport
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	commonauth "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/auth"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	authenticationapi "userservice/api/authenticationservice"
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+const (
+	userTokenMetadataKey            = "user-token"
+	gatewayMetadataKey              = "x-gateway-request"
+	externalSignInStateMetadataKey  = "external-sign-in-state"
+	externalSignInStateCookieSuffix = "_external_state"
+)
+
+var ErrExternalSignInStateMismatch = errors.New("external sign in was started in another browser")
+
+type GatewayConfig struct {
+	SessionCookieName   string
+	SessionCookieMaxAge time.Duration
+	SessionCookieSecure bool
+}
+
+func NewGatewayMux(
+	sessionService service.SessionService,
+	serializer commonauth.UserDescriptorSerializer,
+	config GatewayConfig,
+) *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithProtoErrorHandler(gatewayErrorHandler),
+		runtime.WithMetadata(sessionCookieAnnotator(sessionService, serializer, config)),
+		runtime.WithMetadata(externalSignInStateAnnotator(config)),
+		runtime.WithForwardResponseOption(sessionCookieForwarder(sessionService, config)),
+		runtime.WithForwardResponseOption(externalSignInStateForwarder(config)),
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+	)
+}
+
+// incomingHeaderMatcher additionally passes request id of http client, it ends up in audit log,
+// metadata set by annotators of gateway is never taken from headers
+func incomingHeaderMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, requestIDMetadataKey) {
+		return requestIDMetadataKey, true
+	}
+	metadataKey, ok := runtime.DefaultHeaderMatcher(key)
+	switch strings.ToLower(metadataKey) {
+	case userTokenMetadataKey, gatewayMetadataKey, externalSignInStateMetadataKey:
+		return "", false
+	}
+	return metadataKey, ok
+}
+
+type gatewayError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func gatewayErrorHandler(_ context.Context, _ *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, _ *http.Request, err error) {
+	s := status.Convert(err)
+
+	body, marshalErr := marshaler.Marshal(gatewayError{
+		Code:    s.Code().String(),
+		Message: s.Message(),
+	})
+	if marshalErr != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", marshaler.ContentType())
+	w.WriteHeader(runtime.HTTPStatusFromCode(s.Code()))
+	_, _ = w.Write(body)
+}
+
+func sessionCookieAnnotator(
+	sessionService service.SessionService,
+	serializer commonauth.UserDescriptorSerializer,
+	config GatewayConfig,
+) func(context.Context, *http.Request) metadata.MD {
+	return func(ctx context.Context, r *http.Request) metadata.MD {
+		md := metadata.Pairs(gatewayMetadataKey, "true")
+		userID, err := sessionService.ResolveSession(ctx, sessionToken(r, config.SessionCookieName))
+		if err != nil {
+			return md
+		}
+
+		token, err := serializer.Serialize(commonauth.UserDescriptor{UserID: userID})
+		if err != nil {
+			return md
+		}
+		md.Set(userTokenMetadataKey, token)
+		return md
+	}
+}
+
+func sessionToken(r *http.Request, cookieName string) string {
+	cookie, err := r.Cookie(cookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func sessionCookieForwarder(sessionService service.SessionService, config GatewayConfig) func(context.Context, http.ResponseWriter, proto.Message) error {
+	return func(ctx context.Context, w http.ResponseWriter, message proto.Message) error {
+		resp, ok := message.(*authenticationapi.AuthenticateUserResponse)
+		if !ok {
+			return nil
+		}
+
+		userID, err := uuid.Parse(resp.UserID)
+		if err != nil {
+			return err
+		}
+
+		token, err := sessionService.StartSession(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     config.SessionCookieName,
+			Value:    token,
+			Path:     "/",
+			MaxAge:   int(config.SessionCookieMaxAge.Seconds()),
+			Secure:   config.SessionCookieSecure,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		return nil
+	}
+}
+
+func externalSignInStateAnnotator(config GatewayConfig) func(context.Context, *http.Request) metadata.MD {
+	return func(_ context.Context, r *http.Request) metadata.MD {
+		cookie, err := r.Cookie(config.SessionCookieName + externalSignInStateCookieSuffix)
+		if err != nil {
+			return nil
+		}
+		return metadata.Pairs(externalSignInStateMetadataKey, cookie.Value)
+	}
+}
+
+// externalSignInStateForwarder binds state of started external sign in to browser,
+// so sign in started by other person can not be completed in it
+func externalSignInStateForwarder(config GatewayConfig) func(context.Context, http.ResponseWriter, proto.Message) error {
+	return func(_ context.Context, w http.ResponseWriter, message proto.Message) error {
+		resp, ok := message.(*authenticationapi.StartExternalSignInResponse)
+		if !ok {
+			return nil
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     config.SessionCookieName + externalSignInStateCookieSuffix,
+			Value:    hash.HashToken(resp.State),
+			Path:     "/",
+			MaxAge:   int(domain.ExternalLoginRequestTTL.Seconds()),
+			Secure:   config.SessionCookieSecure,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		return nil
+	}
+}
+
+func verifyExternalSignInState(ctx context.Context, state string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(gatewayMetadataKey)) == 0 {
+		return nil
+	}
+	values := md.Get(externalSignInStateMetadataKey)
+	if len(values) == 0 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(hash.HashToken(state))) != 1 {
+		return ErrExternalSignInStateMismatch
+	}
+	return nil
+}
+
+// userTokenFromContext prefers token of request, except for requests through gateway,
+// token in body of http request is not verified, so only user of session cookie is trusted there
+func userTokenFromContext(ctx context.Context, token string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return token
+	}
+	if len(md.Get(gatewayMetadataKey)) == 0 && token != "" {
+		return token
+	}
+	if values := md.Get(userTokenMetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	infrahealth "userservice/pkg/userservice/infrastructure/health"
+)
+
+func NewLivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, http.StatusText(http.StatusOK))
+	}
+}
+
+func NewReadinessHandler(checker infrahealth.Checker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Check(r.Context())
+
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status == infrahealth.StatusUp {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	}
+}
+
+type HealthStatusUpdater interface {
+	Run() error
+	Stop() error
+}
+
+func NewHealthStatusUpdater(
+	checker infrahealth.Checker,
+	healthServer *health.Server,
+	serviceDependencies map[string][]string,
+	interval time.Duration,
+	logger log.Logger,
+) HealthStatusUpdater {
+	return &healthStatusUpdater{
+		checker:      checker,
+		healthServer: healthServer,
+		services:     serviceDependencies,
+		interval:     interval,
+		logger:       logger,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+type healthStatusUpdater struct {
+	checker      infrahealth.Checker
+	healthServer *health.Server
+	services     map[string][]string
+	interval     time.Duration
+	logger       log.Logger
+	stopChan     chan struct{}
+	lastStatus   healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (updater *healthStatusUpdater) Run() error {
+	ticker := time.NewTicker(updater.interval)
+	defer ticker.Stop()
+
+	for {
+		updater.update()
+
+		select {
+		case <-ticker.C:
+		case <-updater.stopChan:
+			return nil
+		}
+	}
+}
+
+func (updater *healthStatusUpdater) Stop() error {
+	close(updater.stopChan)
+	updater.healthServer.Shutdown()
+	return nil
+}
+
+func (updater *healthStatusUpdater) update() {
+	report := updater.checker.Check(context.Background())
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if report.Status != infrahealth.StatusUp {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	if status != updater.lastStatus {
+		updater.logger.WithField("checks", report.Checks).Info("health status changed to ", status.String())
+		updater.lastStatus = status
+	}
+
+	updater.healthServer.SetServingStatus("", status)
+	for service, dependencies := range updater.services {
+		updater.healthServer.SetServingStatus(service, dependenciesStatus(report, dependencies))
+	}
+}
+
+func dependenciesStatus(report infrahealth.Report, dependencies []string) healthpb.HealthCheckResponse_ServingStatus {
+	for _, dependency := range dependencies {
+		if result, ok := report.Checks[dependency]; ok && result.Status != infrahealth.StatusUp {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
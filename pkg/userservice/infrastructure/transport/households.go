@@ -0,0 +1,136 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/service"
+)
+
+var ErrInvalidHouseholdInvitationID = errors.New("invalid household invitation id")
+
+func (server *userServiceServer) CreateHousehold(ctx context.Context, req *api.CreateHouseholdRequest) (*api.Household, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.container.HouseholdService().CreateHousehold(ctx, userID, req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	household, err := server.container.HouseholdService().GetHousehold(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIHousehold(household), nil
+}
+
+func (server *userServiceServer) GetHousehold(ctx context.Context, req *api.GetHouseholdRequest) (*api.Household, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	household, err := server.container.HouseholdService().GetHousehold(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIHousehold(household), nil
+}
+
+func (server *userServiceServer) InviteHouseholdMember(ctx context.Context, req *api.InviteHouseholdMemberRequest) (*api.InviteHouseholdMemberResponse, error) {
+	managerID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	invitationID, err := server.container.HouseholdService().InviteMember(ctx, managerID, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &api.InviteHouseholdMemberResponse{InvitationId: invitationID.String()}, nil
+}
+
+func (server *userServiceServer) ListHouseholdInvitations(ctx context.Context, req *api.ListHouseholdInvitationsRequest) (*api.ListHouseholdInvitationsResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := server.container.HouseholdService().ListInvitations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListHouseholdInvitationsResponse{}
+	for _, invitation := range invitations {
+		resp.Invitations = append(resp.Invitations, &api.HouseholdInvitation{
+			InvitationId: invitation.ID.String(),
+			HouseholdId:  invitation.HouseholdID.String(),
+			ManagerId:    invitation.ManagerID.String(),
+			CreatedAt:    timestamppb.New(invitation.CreatedAt),
+			ExpiresAt:    timestamppb.New(invitation.ExpiresAt),
+		})
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) RespondToHouseholdInvitation(ctx context.Context, req *api.RespondToHouseholdInvitationRequest) (*api.RespondToHouseholdInvitationResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	invitationID, err := uuid.Parse(req.InvitationId)
+	if err != nil {
+		return nil, ErrInvalidHouseholdInvitationID
+	}
+
+	err = server.container.HouseholdService().RespondToInvitation(ctx, invitationID, userID, req.Accept, req.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RespondToHouseholdInvitationResponse{}, nil
+}
+
+func (server *userServiceServer) RemoveHouseholdMember(ctx context.Context, req *api.RemoveHouseholdMemberRequest) (*api.RemoveHouseholdMemberResponse, error) {
+	actorID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+	userID := actorID
+	if req.UserId != "" {
+		userID, err = uuid.Parse(req.UserId)
+		if err != nil {
+			return nil, ErrInvalidUserID
+		}
+	}
+
+	err = server.container.HouseholdService().RemoveMember(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RemoveHouseholdMemberResponse{}, nil
+}
+
+func makeAPIHousehold(household service.HouseholdView) *api.Household {
+	resp := &api.Household{
+		HouseholdId: household.ID.String(),
+		ManagerId:   household.ManagerID.String(),
+		CreatedAt:   timestamppb.New(household.CreatedAt),
+		MaxMembers:  int32(household.MaxMembers),
+	}
+	for _, member := range household.Members {
+		resp.Members = append(resp.Members, &api.HouseholdMember{
+			UserId:   member.UserID.String(),
+			Manager:  member.Manager,
+			JoinedAt: timestamppb.New(member.JoinedAt),
+		})
+	}
+	return resp
+}
@@ -4,22 +4,49 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 
 	log "github.com/CuriosityMusicStreaming/ComponentsPool/pkg/app/logger"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+
+	"userservice/pkg/userservice/infrastructure/metrics"
 )
 
-func NewLoggingMiddleware(h http.Handler, logger log.Logger) http.Handler {
+const redactedValue = "[redacted]"
+
+var secretRequestFields = map[string]bool{
+	"Password":      true,
+	"Pin":           true,
+	"ParentalPin":   true,
+	"CurrentPin":    true,
+	"NewPin":        true,
+	"UserToken":     true,
+	"ClientSecret":  true,
+	"DeviceCode":    true,
+	"UserCode":      true,
+	"DownloadToken": true,
+	"State":         true,
+	"Code":          true,
+}
+
+func NewLoggingMiddleware(h http.Handler, logger log.Logger, httpMetrics metrics.HTTPMetrics) http.Handler {
 	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 		now := time.Now()
-		h.ServeHTTP(writer, request)
+		recorder := &statusRecorder{ResponseWriter: writer, status: http.StatusOK}
+		h.ServeHTTP(recorder, request)
+
+		duration := time.Since(now)
+		httpMetrics.ObserveRequest(request.Method, recorder.status, duration)
 
+		// only path is logged, query may carry download tokens
 		logger.WithFields(log.Fields{
-			"duration": time.Since(now),
+			"duration": duration,
 			"method":   request.Method,
-			"url":      request.RequestURI,
+			"url":      request.URL.Path,
+			"status":   recorder.status,
 		}).Info("request finished")
 	})
 }
@@ -31,9 +58,12 @@ func NewLoggerServerInterceptor(logger log.Logger) grpc.UnaryServerInterceptor {
 		resp, err = handler(ctx, req)
 
 		fields := log.Fields{
-			"args":     req,
+			"args":     redactRequest(req),
 			"duration": fmt.Sprintf("%v", time.Since(start)),
-			"method":   getGRPCMethodName(info),
+			"method":   getGRPCMethodName(info.FullMethod),
+		}
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+			fields["trace_id"] = spanContext.TraceID().String()
 		}
 
 		entry := logger.WithFields(fields)
@@ -47,7 +77,64 @@ func NewLoggerServerInterceptor(logger log.Logger) grpc.UnaryServerInterceptor {
 	}
 }
 
-func getGRPCMethodName(info *grpc.UnaryServerInfo) string {
-	method := info.FullMethod
-	return method[strings.LastIndex(method, "/")+1:]
+func NewLoggerStreamServerInterceptor(logger log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, stream)
+
+		fields := log.Fields{
+			"duration": fmt.Sprintf("%v", time.Since(start)),
+			"method":   getGRPCMethodName(info.FullMethod),
+		}
+		if spanContext := trace.SpanContextFromContext(stream.Context()); spanContext.HasTraceID() {
+			fields["trace_id"] = spanContext.TraceID().String()
+		}
+
+		entry := logger.WithFields(fields)
+		if err != nil {
+			entry.Error(err, "stream failed")
+		} else {
+			entry.Info("stream finished")
+		}
+
+		return translateError(err)
+	}
+}
+
+func redactRequest(req interface{}) interface{} {
+	value := reflect.ValueOf(req)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return req
+	}
+
+	redacted := reflect.New(value.Elem().Type()).Elem()
+	redacted.Set(value.Elem())
+	for i := 0; i < redacted.NumField(); i++ {
+		field := redacted.Field(i)
+		if secretRequestFields[redacted.Type().Field(i).Name] && field.Kind() == reflect.String && field.CanSet() && field.String() != "" {
+			field.SetString(redactedValue)
+		}
+	}
+	return redacted.Addr().Interface()
+}
+
+func getGRPCMethodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
 }
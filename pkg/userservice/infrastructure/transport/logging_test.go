@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"reflect"
+	"testing"
+
+	authenticationapi "userservice/api/authenticationservice"
+	api "userservice/api/userservice"
+)
+
+func TestRedactRequest(t *testing.T) {
+	addUser := &api.AddUserRequest{UserToken: "token", Email: "user@example.com", Password: "secret"}
+
+	tests := []struct {
+		name     string
+		req      interface{}
+		expected interface{}
+	}{
+		{
+			name:     "secret fields are redacted",
+			req:      addUser,
+			expected: &api.AddUserRequest{UserToken: redactedValue, Email: "user@example.com", Password: redactedValue},
+		},
+		{
+			name:     "empty secret fields stay empty",
+			req:      &authenticationapi.AuthenticateUserRequest{Email: "user@example.com"},
+			expected: &authenticationapi.AuthenticateUserRequest{Email: "user@example.com"},
+		},
+		{
+			name:     "non struct request is kept",
+			req:      "request",
+			expected: "request",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			redacted := redactRequest(test.req)
+			if !reflect.DeepEqual(redacted, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, redacted)
+			}
+		})
+	}
+
+	if addUser.Password != "secret" || addUser.UserToken != "token" {
+		t.Errorf("request passed to handler was modified: %+v", addUser)
+	}
+}
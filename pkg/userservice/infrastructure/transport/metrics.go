@@ -0,0 +1,35 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"userservice/pkg/userservice/infrastructure/metrics"
+)
+
+func NewMetricsServerInterceptor(grpcMetrics metrics.GRPCMetrics) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		start := time.Now()
+
+		resp, err = handler(ctx, req)
+
+		grpcMetrics.ObserveCall(getGRPCMethodName(info.FullMethod), status.Code(err), time.Since(start))
+
+		return resp, err
+	}
+}
+
+func NewMetricsStreamServerInterceptor(grpcMetrics metrics.GRPCMetrics) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		err := handler(srv, stream)
+
+		grpcMetrics.ObserveCall(getGRPCMethodName(info.FullMethod), status.Code(err), time.Since(start))
+
+		return err
+	}
+}
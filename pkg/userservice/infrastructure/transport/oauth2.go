@@ -0,0 +1,383 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+const (
+	OAuth2AuthorizePath           = "/oauth2/authorize"
+	OAuth2TokenPath               = "/oauth2/token"
+	OAuth2JWKSPath                = "/oauth2/jwks"
+	OAuth2UserInfoPath            = "/userinfo"
+	OAuth2DiscoveryPath           = "/.well-known/openid-configuration"
+	OAuth2DeviceAuthorizationPath = "/oauth2/device_authorization"
+	OAuth2MetadataPath            = "/.well-known/oauth-authorization-server"
+)
+
+const (
+	oauth2ConsentParam          = "consent"
+	oauth2ConsentAccept         = "approve"
+	oauth2ConsentChallengeParam = "consent_challenge"
+	oauth2ReturnToParam         = "return_to"
+)
+
+type OAuth2Config struct {
+	SessionCookieName string
+	LoginURL          string
+	ConsentURL        string
+}
+
+type oauth2Error struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func NewOAuth2AuthorizeHandler(
+	oauth2Service service.OAuth2Service,
+	sessionService service.SessionService,
+	config OAuth2Config,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			http.Error(w, "invalid authorization request", http.StatusBadRequest)
+			return
+		}
+
+		request := service.OAuth2AuthorizationRequest{
+			ClientID:            r.Form.Get("client_id"),
+			RedirectURI:         r.Form.Get("redirect_uri"),
+			Scopes:              strings.Fields(r.Form.Get("scope")),
+			Nonce:               r.Form.Get("nonce"),
+			CodeChallenge:       r.Form.Get("code_challenge"),
+			CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+		}
+		state := r.Form.Get("state")
+
+		client, err := oauth2Service.ValidateAuthorizationRequest(r.Context(), request)
+		if err != nil {
+			// only errors found after redirect uri was checked against client are redirected
+			switch errors.Cause(err) {
+			case domain.ErrOAuth2GrantNotAllowed, domain.ErrInvalidOAuth2Scope, service.ErrOAuth2PKCERequired:
+				redirectOAuth2Error(w, r, request.RedirectURI, state, err)
+			case domain.ErrOAuth2ClientNotFound, domain.ErrInvalidOAuth2RedirectURI:
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				http.Error(w, "internal error", http.StatusInternalServerError)
+			}
+			return
+		}
+		if r.Form.Get("response_type") != "code" {
+			redirectWithParams(w, r, request.RedirectURI, url.Values{"error": {"unsupported_response_type"}, "state": {state}})
+			return
+		}
+
+		params := url.Values{}
+		for key, values := range r.Form {
+			if key != oauth2ConsentParam && key != oauth2ConsentChallengeParam {
+				params[key] = values
+			}
+		}
+
+		token := sessionToken(r, config.SessionCookieName)
+		userID, err := sessionService.ResolveSession(r.Context(), token)
+		if errors.Cause(err) == service.ErrInvalidSession {
+			if config.LoginURL == "" {
+				redirectWithParams(w, r, request.RedirectURI, url.Values{"error": {"login_required"}, "state": {state}})
+				return
+			}
+			returnTo := oauth2Service.Issuer() + OAuth2AuthorizePath + "?" + params.Encode()
+			redirectWithParams(w, r, config.LoginURL, url.Values{oauth2ReturnToParam: {returnTo}})
+			return
+		}
+		if err != nil {
+			redirectOAuth2Error(w, r, request.RedirectURI, state, err)
+			return
+		}
+
+		// consent is accepted only by post with challenge issued to consent page for this session and request,
+		// so it can not be given by following link or by form of other site
+		if !client.FirstParty {
+			switch {
+			case r.Method == http.MethodPost && r.PostForm.Get(oauth2ConsentParam) == oauth2ConsentAccept:
+				err = oauth2Service.VerifyConsentChallenge(r.PostForm.Get(oauth2ConsentChallengeParam), userID, token, request)
+				if err != nil {
+					redirectWithParams(w, r, request.RedirectURI, url.Values{"error": {"access_denied"}, "state": {state}})
+					return
+				}
+			case r.Method == http.MethodPost:
+				redirectWithParams(w, r, request.RedirectURI, url.Values{"error": {"access_denied"}, "state": {state}})
+				return
+			case config.ConsentURL == "":
+				redirectWithParams(w, r, request.RedirectURI, url.Values{"error": {"consent_required"}, "state": {state}})
+				return
+			default:
+				challenge, err2 := oauth2Service.IssueConsentChallenge(userID, token, request)
+				if err2 != nil {
+					redirectOAuth2Error(w, r, request.RedirectURI, state, err2)
+					return
+				}
+				params.Set(oauth2ConsentChallengeParam, challenge)
+				redirectWithParams(w, r, config.ConsentURL, params)
+				return
+			}
+		}
+
+		code, err := oauth2Service.Authorize(r.Context(), userID, request)
+		if err != nil {
+			redirectOAuth2Error(w, r, request.RedirectURI, state, err)
+			return
+		}
+		redirectWithParams(w, r, request.RedirectURI, url.Values{"code": {code}, "state": {state}})
+	}
+}
+
+func NewOAuth2TokenHandler(oauth2Service service.OAuth2Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			writeOAuth2Error(w, http.StatusBadRequest, oauth2Error{Error: "invalid_request"})
+			return
+		}
+
+		credentials := oauth2ClientCredentials(r)
+		scopes := strings.Fields(r.PostForm.Get("scope"))
+
+		var tokens service.OAuth2Tokens
+		switch service.OAuth2GrantType(r.PostForm.Get("grant_type")) {
+		case service.OAuth2AuthorizationCodeGrant:
+			tokens, err = oauth2Service.ExchangeAuthorizationCode(
+				r.Context(),
+				credentials,
+				r.PostForm.Get("code"),
+				r.PostForm.Get("redirect_uri"),
+				r.PostForm.Get("code_verifier"),
+			)
+		case service.OAuth2ClientCredentialsGrant:
+			tokens, err = oauth2Service.ExchangeClientCredentials(r.Context(), credentials, scopes)
+		case service.OAuth2RefreshTokenGrant:
+			tokens, err = oauth2Service.ExchangeRefreshToken(r.Context(), credentials, r.PostForm.Get("refresh_token"), scopes)
+		case service.OAuth2DeviceCodeGrant:
+			tokens, err = oauth2Service.PollDeviceToken(r.Context(), credentials, r.PostForm.Get("device_code"))
+		default:
+			writeOAuth2Error(w, http.StatusBadRequest, oauth2Error{Error: "unsupported_grant_type"})
+			return
+		}
+		if err != nil {
+			status, oauthErr := translateOAuth2Error(err)
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Basic realm="oauth2"`)
+			}
+			writeOAuth2Error(w, status, oauthErr)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, http.StatusOK, struct {
+			AccessToken  string `json:"access_token"`
+			TokenType    string `json:"token_type"`
+			ExpiresIn    int64  `json:"expires_in"`
+			RefreshToken string `json:"refresh_token,omitempty"`
+			IDToken      string `json:"id_token,omitempty"`
+			Scope        string `json:"scope,omitempty"`
+		}{
+			AccessToken:  tokens.AccessToken,
+			TokenType:    "Bearer",
+			ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+			RefreshToken: tokens.RefreshToken,
+			IDToken:      tokens.IDToken,
+			Scope:        strings.Join(tokens.Scopes, " "),
+		})
+	}
+}
+
+func NewOAuth2DeviceAuthorizationHandler(oauth2Service service.OAuth2Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseForm()
+		if err != nil {
+			writeOAuth2Error(w, http.StatusBadRequest, oauth2Error{Error: "invalid_request"})
+			return
+		}
+
+		authorization, err := oauth2Service.StartDeviceAuthorization(r.Context(), oauth2ClientCredentials(r), strings.Fields(r.PostForm.Get("scope")))
+		if err != nil {
+			status, oauthErr := translateOAuth2Error(err)
+			if status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", `Basic realm="oauth2"`)
+			}
+			writeOAuth2Error(w, status, oauthErr)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, http.StatusOK, struct {
+			DeviceCode              string `json:"device_code"`
+			UserCode                string `json:"user_code"`
+			VerificationURI         string `json:"verification_uri"`
+			VerificationURIComplete string `json:"verification_uri_complete"`
+			ExpiresIn               int64  `json:"expires_in"`
+			Interval                int64  `json:"interval"`
+		}{
+			DeviceCode:              authorization.DeviceCode,
+			UserCode:                authorization.UserCode,
+			VerificationURI:         authorization.VerificationURI,
+			VerificationURIComplete: authorization.VerificationURIComplete,
+			ExpiresIn:               int64(authorization.ExpiresIn.Seconds()),
+			Interval:                int64(authorization.Interval.Seconds()),
+		})
+	}
+}
+
+func NewOAuth2UserInfoHandler(oauth2Service service.OAuth2Service, userQueryService query.UserQueryService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		info, err := oauth2Service.ResolveAccessToken(r.Context(), accessToken)
+		if err != nil {
+			if errors.Cause(err) == service.ErrInvalidOAuth2AccessToken {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				writeOAuth2Error(w, http.StatusUnauthorized, oauth2Error{Error: "invalid_token"})
+				return
+			}
+			writeOAuth2Error(w, http.StatusInternalServerError, oauth2Error{Error: "server_error"})
+			return
+		}
+		if info.UserID == nil || !domain.ContainsOAuth2Scope(info.Scopes, domain.OAuth2OpenIDScope) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+			writeOAuth2Error(w, http.StatusForbidden, oauth2Error{Error: "insufficient_scope"})
+			return
+		}
+
+		user, err := userQueryService.GetUser(r.Context(), *info.UserID)
+		if err != nil {
+			writeOAuth2Error(w, http.StatusInternalServerError, oauth2Error{Error: "server_error"})
+			return
+		}
+
+		claims := map[string]string{"sub": user.ID.String()}
+		if domain.ContainsOAuth2Scope(info.Scopes, domain.OAuth2EmailScope) {
+			claims["email"] = user.Email
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, http.StatusOK, claims)
+	}
+}
+
+func NewOAuth2DiscoveryHandler(oauth2Service service.OAuth2Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		issuer := oauth2Service.Issuer()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"issuer":                        issuer,
+			"authorization_endpoint":        issuer + OAuth2AuthorizePath,
+			"token_endpoint":                issuer + OAuth2TokenPath,
+			"userinfo_endpoint":             issuer + OAuth2UserInfoPath,
+			"device_authorization_endpoint": issuer + OAuth2DeviceAuthorizationPath,
+			"jwks_uri":                      issuer + OAuth2JWKSPath,
+			"response_types_supported":      []string{"code"},
+			"grant_types_supported": []service.OAuth2GrantType{
+				service.OAuth2AuthorizationCodeGrant,
+				service.OAuth2ClientCredentialsGrant,
+				service.OAuth2RefreshTokenGrant,
+				service.OAuth2DeviceCodeGrant,
+			},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"code_challenge_methods_supported":      []string{"S256"},
+			"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+			"scopes_supported":                      []string{domain.OAuth2OpenIDScope, domain.OAuth2EmailScope},
+			"claims_supported":                      []string{"sub", "email", "plan", "entitlements"},
+		})
+	}
+}
+
+func NewOAuth2JWKSHandler(oauth2Service service.OAuth2Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		keySet, err := oauth2Service.KeySet()
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		_, _ = w.Write(keySet)
+	}
+}
+
+func translateOAuth2Error(err error) (int, oauth2Error) {
+	switch errors.Cause(err) {
+	case service.ErrInvalidOAuth2Client:
+		return http.StatusUnauthorized, oauth2Error{Error: "invalid_client", Description: err.Error()}
+	case service.ErrInvalidOAuth2Grant:
+		return http.StatusBadRequest, oauth2Error{Error: "invalid_grant", Description: err.Error()}
+	case domain.ErrOAuth2GrantNotAllowed:
+		return http.StatusBadRequest, oauth2Error{Error: "unauthorized_client", Description: err.Error()}
+	case domain.ErrInvalidOAuth2Scope:
+		return http.StatusBadRequest, oauth2Error{Error: "invalid_scope", Description: err.Error()}
+	case service.ErrOAuth2PKCERequired:
+		return http.StatusBadRequest, oauth2Error{Error: "invalid_request", Description: err.Error()}
+	case service.ErrOAuth2AuthorizationPending:
+		return http.StatusBadRequest, oauth2Error{Error: "authorization_pending"}
+	case service.ErrOAuth2SlowDown:
+		return http.StatusBadRequest, oauth2Error{Error: "slow_down"}
+	case service.ErrOAuth2DeviceCodeExpired:
+		return http.StatusBadRequest, oauth2Error{Error: "expired_token", Description: err.Error()}
+	case service.ErrOAuth2AccessDenied:
+		return http.StatusBadRequest, oauth2Error{Error: "access_denied", Description: err.Error()}
+	default:
+		return http.StatusInternalServerError, oauth2Error{Error: "server_error"}
+	}
+}
+
+func oauth2ClientCredentials(r *http.Request) service.OAuth2ClientCredentials {
+	credentials := service.OAuth2ClientCredentials{ClientID: r.PostForm.Get("client_id"), ClientSecret: r.PostForm.Get("client_secret")}
+	if id, secret, ok := r.BasicAuth(); ok {
+		// credentials in basic auth are form encoded first, see RFC 6749 section 2.3.1
+		credentials.ClientID, _ = url.QueryUnescape(id)
+		credentials.ClientSecret, _ = url.QueryUnescape(secret)
+	}
+	return credentials
+}
+
+func redirectOAuth2Error(w http.ResponseWriter, r *http.Request, redirectURI, state string, err error) {
+	_, oauthErr := translateOAuth2Error(err)
+	params := url.Values{"error": {oauthErr.Error}, "state": {state}}
+	if oauthErr.Description != "" {
+		params.Set("error_description", oauthErr.Description)
+	}
+	redirectWithParams(w, r, redirectURI, params)
+}
+
+func redirectWithParams(w http.ResponseWriter, r *http.Request, target string, params url.Values) {
+	u, err := url.Parse(target)
+	if err != nil {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+	query := u.Query()
+	for key, values := range params {
+		if len(values) == 1 && values[0] == "" {
+			continue
+		}
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+func writeOAuth2Error(w http.ResponseWriter, status int, err oauth2Error) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, err)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
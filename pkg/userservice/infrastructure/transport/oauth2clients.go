@@ -0,0 +1,132 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/service"
+)
+
+var ErrUnknownOAuth2GrantType = errors.New("unknown oauth2 grant type")
+
+func (server *userServiceServer) RegisterOAuth2Client(ctx context.Context, req *api.RegisterOAuth2ClientRequest) (*api.RegisterOAuth2ClientResponse, error) {
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	registration := service.OAuth2ClientRegistration{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectUris,
+		Scopes:       req.Scopes,
+		Public:       req.Public,
+		FirstParty:   req.FirstParty,
+	}
+	for _, grantType := range req.GrantTypes {
+		domainGrantType, ok := apiToOAuth2GrantTypeMap[grantType]
+		if !ok {
+			return nil, ErrUnknownOAuth2GrantType
+		}
+		registration.GrantTypes = append(registration.GrantTypes, domainGrantType)
+	}
+
+	client, secret, err := server.container.OAuth2Service().RegisterClient(ctx, registration)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RegisterOAuth2ClientResponse{Client: makeAPIOAuth2Client(client), ClientSecret: secret}, nil
+}
+
+func (server *userServiceServer) ListOAuth2Clients(ctx context.Context, req *api.ListOAuth2ClientsRequest) (*api.ListOAuth2ClientsResponse, error) {
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	clients, err := server.container.OAuth2Service().ListClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListOAuth2ClientsResponse{}
+	for _, client := range clients {
+		resp.Clients = append(resp.Clients, makeAPIOAuth2Client(client))
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) RemoveOAuth2Client(ctx context.Context, req *api.RemoveOAuth2ClientRequest) (*api.RemoveOAuth2ClientResponse, error) {
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.container.OAuth2Service().RemoveClient(ctx, req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RemoveOAuth2ClientResponse{}, nil
+}
+
+func (server *userServiceServer) LookupDevice(ctx context.Context, req *api.LookupDeviceRequest) (*api.LookupDeviceResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := server.container.OAuth2Service().LookupDevice(ctx, userID, req.UserCode)
+	if err != nil {
+		return nil, err
+	}
+	return &api.LookupDeviceResponse{
+		Client:    makeAPIOAuth2Client(device.Client),
+		Scopes:    device.Scopes,
+		ExpiresAt: timestamppb.New(device.ExpiresAt),
+	}, nil
+}
+
+func (server *userServiceServer) ApproveDevice(ctx context.Context, req *api.ApproveDeviceRequest) (*api.ApproveDeviceResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := server.container.OAuth2Service().ApproveDevice(ctx, userID, req.UserCode, req.Approve)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ApproveDeviceResponse{Client: makeAPIOAuth2Client(client)}, nil
+}
+
+var apiToOAuth2GrantTypeMap = map[api.OAuth2GrantType]service.OAuth2GrantType{
+	api.OAuth2GrantType_AUTHORIZATION_CODE: service.OAuth2AuthorizationCodeGrant,
+	api.OAuth2GrantType_CLIENT_CREDENTIALS: service.OAuth2ClientCredentialsGrant,
+	api.OAuth2GrantType_REFRESH_TOKEN:      service.OAuth2RefreshTokenGrant,
+	api.OAuth2GrantType_DEVICE_CODE:        service.OAuth2DeviceCodeGrant,
+}
+
+var oauth2GrantTypeToAPIMap = map[service.OAuth2GrantType]api.OAuth2GrantType{
+	service.OAuth2AuthorizationCodeGrant: api.OAuth2GrantType_AUTHORIZATION_CODE,
+	service.OAuth2ClientCredentialsGrant: api.OAuth2GrantType_CLIENT_CREDENTIALS,
+	service.OAuth2RefreshTokenGrant:      api.OAuth2GrantType_REFRESH_TOKEN,
+	service.OAuth2DeviceCodeGrant:        api.OAuth2GrantType_DEVICE_CODE,
+}
+
+func makeAPIOAuth2Client(client service.OAuth2ClientView) *api.OAuth2Client {
+	result := &api.OAuth2Client{
+		ClientId:     client.ID,
+		Name:         client.Name,
+		RedirectUris: client.RedirectURIs,
+		Scopes:       client.Scopes,
+		Public:       client.Public,
+		FirstParty:   client.FirstParty,
+		CreatedAt:    timestamppb.New(client.CreatedAt),
+	}
+	for _, grantType := range client.GrantTypes {
+		result.GrantTypes = append(result.GrantTypes, oauth2GrantTypeToAPIMap[grantType])
+	}
+	return result
+}
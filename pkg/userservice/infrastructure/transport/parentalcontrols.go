@@ -0,0 +1,97 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/service"
+)
+
+func (server *userServiceServer) GetContentRestrictions(ctx context.Context, req *api.GetContentRestrictionsRequest) (*api.ContentRestrictions, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	restrictions, err := server.container.ParentalControlsService().GetContentRestrictions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIContentRestrictions(restrictions), nil
+}
+
+func (server *userServiceServer) SetParentalPin(ctx context.Context, req *api.SetParentalPinRequest) (*api.SetParentalPinResponse, error) {
+	actorID, userID, err := server.resolveParentalControlsUsers(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = server.container.ParentalControlsService().SetPIN(ctx, userID, actorID, req.CurrentPin, req.NewPin)
+	if err != nil {
+		return nil, err
+	}
+	return &api.SetParentalPinResponse{}, nil
+}
+
+func (server *userServiceServer) UpdateParentalControls(ctx context.Context, req *api.UpdateParentalControlsRequest) (*api.ContentRestrictions, error) {
+	actorID, userID, err := server.resolveParentalControlsUsers(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	update := service.ParentalControlsUpdate{RemoveGuardian: req.RemoveGuardian}
+	if req.ExplicitContentFilter != nil {
+		filter := req.ExplicitContentFilter.Value
+		update.ExplicitContentFilter = &filter
+	}
+	if req.GuardianId != "" {
+		guardianID, err2 := uuid.Parse(req.GuardianId)
+		if err2 != nil {
+			return nil, ErrInvalidUserID
+		}
+		update.GuardianID = &guardianID
+	}
+
+	restrictions, err := server.container.ParentalControlsService().UpdateControls(ctx, userID, actorID, req.Pin, update)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIContentRestrictions(restrictions), nil
+}
+
+func (server *userServiceServer) resolveParentalControlsUsers(ctx context.Context, token, userID string) (actorID, targetID uuid.UUID, err error) {
+	actorID, err = server.resolveTargetUser(ctx, token, "")
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, err
+	}
+	if userID == "" {
+		return actorID, actorID, nil
+	}
+	targetID, err = uuid.Parse(userID)
+	if err != nil {
+		return uuid.UUID{}, uuid.UUID{}, ErrInvalidUserID
+	}
+	return actorID, targetID, nil
+}
+
+var ageCategoryToAPIMap = map[service.AgeCategory]api.AgeCategory{
+	service.AgeUnknown: api.AgeCategory_UNKNOWN,
+	service.AgeChild:   api.AgeCategory_CHILD,
+	service.AgeMinor:   api.AgeCategory_MINOR,
+	service.AgeAdult:   api.AgeCategory_ADULT,
+}
+
+func makeAPIContentRestrictions(restrictions service.ContentRestrictionsView) *api.ContentRestrictions {
+	resp := &api.ContentRestrictions{
+		AgeCategory:           ageCategoryToAPIMap[restrictions.Category],
+		Restricted:            restrictions.Restricted,
+		ExplicitContentFilter: restrictions.ExplicitContentFilter,
+		PinSet:                restrictions.PINSet,
+	}
+	if restrictions.GuardianID != nil {
+		resp.GuardianId = restrictions.GuardianID.String()
+	}
+	return resp
+}
@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/service"
+)
+
+var (
+	ErrUnknownProfileVisibility = errors.New("unknown profile visibility")
+	ErrUnknownFollowPolicy      = errors.New("unknown follow policy")
+)
+
+func (server *userServiceServer) GetPrivacySettings(ctx context.Context, req *api.GetPrivacySettingsRequest) (*api.PrivacySettings, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := server.container.PrivacyService().GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIPrivacySettings(settings), nil
+}
+
+func (server *userServiceServer) UpdatePrivacySettings(ctx context.Context, req *api.UpdatePrivacySettingsRequest) (*api.PrivacySettings, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	update := service.PrivacySettingsUpdate{}
+	if req.ProfileVisibility != nil {
+		visibility, ok := apiToProfileVisibilityMap[*req.ProfileVisibility]
+		if !ok {
+			return nil, ErrUnknownProfileVisibility
+		}
+		update.ProfileVisibility = &visibility
+	}
+	if req.WhoCanFollow != nil {
+		policy, ok := apiToFollowPolicyMap[*req.WhoCanFollow]
+		if !ok {
+			return nil, ErrUnknownFollowPolicy
+		}
+		update.WhoCanFollow = &policy
+	}
+	if req.ListeningActivityPublic != nil {
+		public := req.ListeningActivityPublic.Value
+		update.ListeningActivityPublic = &public
+	}
+
+	settings, err := server.container.PrivacyService().UpdateSettings(ctx, userID, update)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIPrivacySettings(settings), nil
+}
+
+var apiToProfileVisibilityMap = map[api.ProfileVisibility]service.ProfileVisibility{
+	api.ProfileVisibility_PUBLIC:    service.ProfileVisibilityPublic,
+	api.ProfileVisibility_FOLLOWERS: service.ProfileVisibilityFollowers,
+	api.ProfileVisibility_PRIVATE:   service.ProfileVisibilityPrivate,
+}
+
+var profileVisibilityToAPIMap = map[service.ProfileVisibility]api.ProfileVisibility{
+	service.ProfileVisibilityPublic:    api.ProfileVisibility_PUBLIC,
+	service.ProfileVisibilityFollowers: api.ProfileVisibility_FOLLOWERS,
+	service.ProfileVisibilityPrivate:   api.ProfileVisibility_PRIVATE,
+}
+
+var apiToFollowPolicyMap = map[api.FollowPolicy]service.FollowPolicy{
+	api.FollowPolicy_EVERYONE: service.FollowPolicyEveryone,
+	api.FollowPolicy_NOBODY:   service.FollowPolicyNobody,
+}
+
+var followPolicyToAPIMap = map[service.FollowPolicy]api.FollowPolicy{
+	service.FollowPolicyEveryone: api.FollowPolicy_EVERYONE,
+	service.FollowPolicyNobody:   api.FollowPolicy_NOBODY,
+}
+
+func makeAPIPrivacySettings(settings service.PrivacySettingsView) *api.PrivacySettings {
+	return &api.PrivacySettings{
+		ProfileVisibility:       profileVisibilityToAPIMap[settings.ProfileVisibility],
+		WhoCanFollow:            followPolicyToAPIMap[settings.WhoCanFollow],
+		ListeningActivityPublic: settings.ListeningActivityPublic,
+	}
+}
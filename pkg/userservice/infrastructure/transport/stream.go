@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"bufio"
+	"io"
+)
+
+const streamChunkSize = 32 * 1024
+
+type chunkReader struct {
+	chunk []byte
+	recv  func() ([]byte, error)
+}
+
+func newChunkReader(first []byte, recv func() ([]byte, error)) io.Reader {
+	return &chunkReader{chunk: first, recv: recv}
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.chunk = chunk
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+type chunkWriter func(chunk []byte) error
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	// stream may keep reference to message until it is sent, so chunk is copied out of reused buffer
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+	err := w(chunk)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func newChunkWriter(send func(chunk []byte) error) *bufio.Writer {
+	return bufio.NewWriterSize(chunkWriter(send), streamChunkSize)
+}
@@ -0,0 +1,122 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/service"
+)
+
+var (
+	ErrUnknownSubscriptionPlan   = errors.New("unknown subscription plan")
+	ErrUnknownSubscriptionStatus = errors.New("unknown subscription status")
+)
+
+func (server *userServiceServer) GetEntitlements(ctx context.Context, req *api.GetEntitlementsRequest) (*api.Entitlements, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	entitlements, err := server.container.SubscriptionQueryService().GetEntitlements(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.Entitlements{
+		Plan:              subscriptionPlanToAPIMap[service.SubscriptionPlan(entitlements.Plan)],
+		Entitlements:      entitlements.Entitlements,
+		Trial:             entitlements.Trial,
+		SharedByHousehold: entitlements.SharedByHousehold,
+	}
+	if entitlements.ValidUntil != nil {
+		resp.ValidUntil = timestamppb.New(*entitlements.ValidUntil)
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) SetSubscription(ctx context.Context, req *api.SetSubscriptionRequest) (*api.Subscription, error) {
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	plan, ok := apiToSubscriptionPlanMap[req.Plan]
+	if !ok {
+		return nil, ErrUnknownSubscriptionPlan
+	}
+	status, ok := apiToSubscriptionStatusMap[req.Status]
+	if !ok {
+		return nil, ErrUnknownSubscriptionStatus
+	}
+
+	update := service.SubscriptionUpdate{
+		Plan:   plan,
+		Status: status,
+		Trial:  req.Trial,
+	}
+	if req.PeriodStart != nil {
+		periodStart := req.PeriodStart.AsTime()
+		update.PeriodStart = &periodStart
+	}
+	if req.PeriodEnd != nil {
+		periodEnd := req.PeriodEnd.AsTime()
+		update.PeriodEnd = &periodEnd
+	}
+
+	subscription, err := server.container.SubscriptionService().SetSubscription(ctx, userID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.Subscription{
+		UserId:    subscription.UserID.String(),
+		Plan:      subscriptionPlanToAPIMap[subscription.Plan],
+		Status:    subscriptionStatusToAPIMap[subscription.Status],
+		Trial:     subscription.Trial,
+		UpdatedAt: timestamppb.New(subscription.UpdatedAt),
+	}
+	if subscription.PeriodStart != nil {
+		resp.PeriodStart = timestamppb.New(*subscription.PeriodStart)
+	}
+	if subscription.PeriodEnd != nil {
+		resp.PeriodEnd = timestamppb.New(*subscription.PeriodEnd)
+	}
+	return resp, nil
+}
+
+var apiToSubscriptionPlanMap = map[api.SubscriptionPlan]service.SubscriptionPlan{
+	api.SubscriptionPlan_FREE:    service.FreePlan,
+	api.SubscriptionPlan_PREMIUM: service.PremiumPlan,
+	api.SubscriptionPlan_FAMILY:  service.FamilyPlan,
+	api.SubscriptionPlan_STUDENT: service.StudentPlan,
+}
+
+var subscriptionPlanToAPIMap = map[service.SubscriptionPlan]api.SubscriptionPlan{
+	service.FreePlan:    api.SubscriptionPlan_FREE,
+	service.PremiumPlan: api.SubscriptionPlan_PREMIUM,
+	service.FamilyPlan:  api.SubscriptionPlan_FAMILY,
+	service.StudentPlan: api.SubscriptionPlan_STUDENT,
+}
+
+var apiToSubscriptionStatusMap = map[api.SubscriptionStatus]service.SubscriptionStatus{
+	api.SubscriptionStatus_ACTIVE:   service.SubscriptionActive,
+	api.SubscriptionStatus_PAST_DUE: service.SubscriptionPastDue,
+	api.SubscriptionStatus_CANCELED: service.SubscriptionCanceled,
+	api.SubscriptionStatus_EXPIRED:  service.SubscriptionExpired,
+}
+
+var subscriptionStatusToAPIMap = map[service.SubscriptionStatus]api.SubscriptionStatus{
+	service.SubscriptionActive:   api.SubscriptionStatus_ACTIVE,
+	service.SubscriptionPastDue:  api.SubscriptionStatus_PAST_DUE,
+	service.SubscriptionCanceled: api.SubscriptionStatus_CANCELED,
+	service.SubscriptionExpired:  api.SubscriptionStatus_EXPIRED,
+}
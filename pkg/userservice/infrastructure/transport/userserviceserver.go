@@ -1,12 +1,21 @@
 package transport
 
 import (
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	api "userservice/api/userservice"
+	"userservice/pkg/userservice/app/auth"
+	"userservice/pkg/userservice/app/query"
 	"userservice/pkg/userservice/app/service"
 	"userservice/pkg/userservice/infrastructure"
+	"userservice/pkg/userservice/infrastructure/userexchange"
 )
 
 func NewUserServiceServer(container infrastructure.DependencyContainer) api.UserServiceServer {
@@ -17,13 +26,35 @@ type userServiceServer struct {
 	container infrastructure.DependencyContainer
 }
 
-func (server *userServiceServer) AddUser(_ context.Context, req *api.AddUserRequest) (*api.AddUserResponse, error) {
+func (server *userServiceServer) AddUser(ctx context.Context, req *api.AddUserRequest) (*api.AddUserResponse, error) {
 	role, ok := apiToUserRoleMap[req.Role]
 	if !ok {
 		return nil, ErrUnknownUserRole
 	}
 
-	userID, err := server.container.UserService().AddUser(req.Email, req.Password, role)
+	// self registration gives only listener role, creators are approved through creator application
+	if role != service.Listener && server.assertAdmin(ctx, req.UserToken) != nil {
+		return nil, ErrRoleRequiresAdmin
+	}
+
+	var terms *service.TermsAcceptance
+	if req.AcceptedTermsVersion != "" {
+		terms = &service.TermsAcceptance{
+			Version:  req.AcceptedTermsVersion,
+			SourceIP: clientIPFromContext(ctx),
+		}
+	}
+
+	var dateOfBirth *time.Time
+	if req.DateOfBirth != "" {
+		date, err := time.Parse(service.DateLayout, req.DateOfBirth)
+		if err != nil {
+			return nil, ErrInvalidDate
+		}
+		dateOfBirth = &date
+	}
+
+	userID, err := server.container.UserService().AddUser(ctx, req.Email, req.Password, role, terms, dateOfBirth)
 	if err != nil {
 		return nil, err
 	}
@@ -31,11 +62,628 @@ func (server *userServiceServer) AddUser(_ context.Context, req *api.AddUserRequ
 	return &api.AddUserResponse{UserId: userID}, nil
 }
 
+func (server *userServiceServer) RestoreUser(ctx context.Context, req *api.RestoreUserRequest) (*api.RestoreUserResponse, error) {
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+
+	err = server.container.UserService().RestoreUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RestoreUserResponse{}, nil
+}
+
+func (server *userServiceServer) RequestDataExport(ctx context.Context, req *api.RequestDataExportRequest) (*api.RequestDataExportResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	exportID, token, err := server.container.DataExportService().RequestDataExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RequestDataExportResponse{
+		ExportId:      exportID.String(),
+		DownloadToken: token,
+	}, nil
+}
+
+func (server *userServiceServer) GetDataExport(ctx context.Context, req *api.GetDataExportRequest) (*api.GetDataExportResponse, error) {
+	exportID, err := uuid.Parse(req.ExportId)
+	if err != nil {
+		return nil, ErrInvalidDataExportID
+	}
+
+	export, err := server.container.DataExportService().GetDataExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = server.resolveTargetUser(ctx, req.UserToken, export.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.GetDataExportResponse{
+		ExportId:    export.ID.String(),
+		UserId:      export.UserID.String(),
+		Status:      dataExportStatusToAPIMap[export.Status],
+		RequestedAt: timestamppb.New(export.RequestedAt),
+	}
+	if export.ExpiresAt != nil {
+		resp.ExpiresAt = timestamppb.New(*export.ExpiresAt)
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) EraseUser(ctx context.Context, req *api.EraseUserRequest) (*api.EraseUserResponse, error) {
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, req.UserToken))
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate, err := server.container.ErasureService().EraseUser(ctx, userID, &userDesc.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.EraseUserResponse{
+		CertificateId: certificate.ID.String(),
+		ErasedAt:      timestamppb.New(certificate.ErasedAt),
+	}, nil
+}
+
+func (server *userServiceServer) RecordConsent(ctx context.Context, req *api.RecordConsentRequest) (*api.RecordConsentResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	kind, ok := apiToConsentKindMap[req.Kind]
+	if !ok {
+		return nil, service.ErrUnknownConsentKind
+	}
+
+	err = server.container.ConsentService().RecordConsent(ctx, userID, service.ConsentRecord{
+		Kind:     kind,
+		Version:  req.Version,
+		Channel:  req.Channel,
+		Granted:  req.Granted,
+		SourceIP: clientIPFromContext(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RecordConsentResponse{}, nil
+}
+
+func (server *userServiceServer) GetConsents(ctx context.Context, req *api.GetConsentsRequest) (*api.GetConsentsResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := server.container.ConsentService().GetConsents(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.GetConsentsResponse{}
+	for _, consent := range consents {
+		resp.Consents = append(resp.Consents, &api.Consent{
+			Kind:       consentKindToAPIMap[consent.Kind],
+			Version:    consent.Version,
+			Channel:    consent.Channel,
+			Granted:    consent.Granted,
+			RecordedAt: timestamppb.New(consent.RecordedAt),
+		})
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) QueryAuditLog(ctx context.Context, req *api.QueryAuditLogRequest) (*api.QueryAuditLogResponse, error) {
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := makeAuditLogSpec(req)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := server.container.AuditLogQueryService().ListEntries(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.QueryAuditLogResponse{}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, &api.AuditEntry{
+			Seq:        entry.Seq,
+			Action:     entry.Action,
+			ActorId:    optionalUUIDString(entry.ActorID),
+			TargetId:   optionalUUIDString(entry.TargetID),
+			Ip:         entry.IP,
+			UserAgent:  entry.UserAgent,
+			RequestId:  entry.RequestID,
+			Details:    entry.Details,
+			OccurredAt: timestamppb.New(entry.OccurredAt),
+			Hash:       entry.Hash,
+		})
+	}
+	if len(entries) == spec.Limit {
+		resp.NextPageToken = strconv.FormatInt(entries[len(entries)-1].Seq, 10)
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) GetProfile(ctx context.Context, req *api.GetProfileRequest) (*api.Profile, error) {
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, req.UserToken))
+	if err != nil {
+		return nil, err
+	}
+	if req.UserId == "" || req.UserId == userDesc.UserID.String() {
+		profile, err2 := server.container.ProfileService().GetProfile(ctx, userDesc.UserID)
+		if err2 != nil {
+			return nil, err2
+		}
+		return makeAPIProfile(profile), nil
+	}
+
+	targetID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, ErrInvalidUserID
+	}
+	err = server.container.AuthenticationService().AssertAdmin(ctx, userDesc)
+	if err == nil {
+		profile, err2 := server.container.ProfileService().GetProfile(ctx, targetID)
+		if err2 != nil {
+			return nil, err2
+		}
+		return makeAPIProfile(profile), nil
+	}
+	if errors.Cause(err) != auth.ErrAdminRequired {
+		return nil, err
+	}
+
+	profile, err := server.container.ProfileService().GetPublicProfile(ctx, userDesc.UserID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIProfile(profile), nil
+}
+
+func (server *userServiceServer) UpdateProfile(ctx context.Context, req *api.UpdateProfileRequest) (*api.Profile, error) {
+	actorID, userID, err := server.resolveParentalControlsUsers(ctx, req.UserToken, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	update, err := makeProfileUpdate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := server.container.ProfileService().UpdateProfile(ctx, userID, actorID, update)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPIProfile(profile), nil
+}
+
+func (server *userServiceServer) ApplyForCreator(ctx context.Context, req *api.ApplyForCreatorRequest) (*api.ApplyForCreatorResponse, error) {
+	userID, err := server.resolveTargetUser(ctx, req.UserToken, "")
+	if err != nil {
+		return nil, err
+	}
+
+	applicationID, err := server.container.CreatorApplicationService().ApplyForCreator(ctx, userID, req.Statement)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ApplyForCreatorResponse{ApplicationId: applicationID.String()}, nil
+}
+
+func (server *userServiceServer) ReviewCreatorApplication(ctx context.Context, req *api.ReviewCreatorApplicationRequest) (*api.CreatorApplication, error) {
+	reviewerID, err := server.authenticateAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	applicationID, err := uuid.Parse(req.ApplicationId)
+	if err != nil {
+		return nil, ErrInvalidApplicationID
+	}
+	decision, ok := apiToReviewDecisionMap[req.Decision]
+	if !ok {
+		return nil, ErrUnknownReviewDecision
+	}
+
+	application, err := server.container.CreatorApplicationService().ReviewCreatorApplication(ctx, applicationID, reviewerID, decision, req.ReviewerNotes)
+	if err != nil {
+		return nil, err
+	}
+	return makeAPICreatorApplication(application), nil
+}
+
+func (server *userServiceServer) ListPendingApplications(ctx context.Context, req *api.ListPendingApplicationsRequest) (*api.ListPendingApplicationsResponse, error) {
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.PageSize)
+	if limit <= 0 {
+		limit = defaultApplicationsPageSize
+	}
+	if limit > maxApplicationsPageSize {
+		limit = maxApplicationsPageSize
+	}
+	offset := 0
+	if req.PageToken != "" {
+		offset, err = strconv.Atoi(req.PageToken)
+		if err != nil || offset <= 0 {
+			return nil, ErrInvalidPageToken
+		}
+	}
+
+	applications, err := server.container.CreatorApplicationQueryService().ListPendingApplications(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &api.ListPendingApplicationsResponse{}
+	for _, application := range applications {
+		resp.Applications = append(resp.Applications, makeAPICreatorApplication(application))
+	}
+	if len(applications) == limit {
+		resp.NextPageToken = strconv.Itoa(offset + limit)
+	}
+	return resp, nil
+}
+
+func (server *userServiceServer) ImportUsers(stream api.UserService_ImportUsersServer) error {
+	ctx := stream.Context()
+
+	req, err := stream.Recv()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return err
+	}
+
+	format, ok := apiToExchangeFormatMap[req.Format]
+	if !ok {
+		return ErrUnknownExchangeFormat
+	}
+
+	reader, err := userexchange.NewReader(format, newChunkReader(req.Chunk, func() ([]byte, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil, err
+			}
+			return nil, errors.WithStack(err)
+		}
+		return req.Chunk, nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, req.UserToken))
+	if err != nil {
+		return err
+	}
+	ctx = service.WithAuditActor(ctx, userDesc.UserID)
+
+	report, err := server.container.UserService().ImportUsers(ctx, reader)
+	if err != nil {
+		return err
+	}
+
+	resp := &api.ImportUsersResponse{Imported: int32(report.Imported)}
+	for _, rowErr := range report.Errors {
+		resp.Errors = append(resp.Errors, &api.ImportUsersResponse_RowError{
+			Row:     int32(rowErr.Row),
+			Email:   rowErr.Email,
+			Message: rowErr.Err.Error(),
+		})
+	}
+	return stream.SendAndClose(resp)
+}
+
+func (server *userServiceServer) ExportUsers(req *api.ExportUsersRequest, stream api.UserService_ExportUsersServer) error {
+	ctx := stream.Context()
+
+	err := server.assertAdmin(ctx, req.UserToken)
+	if err != nil {
+		return err
+	}
+
+	format, ok := apiToExchangeFormatMap[req.Format]
+	if !ok {
+		return ErrUnknownExchangeFormat
+	}
+
+	out := newChunkWriter(func(chunk []byte) error {
+		return stream.Send(&api.ExportUsersResponse{Chunk: chunk})
+	})
+	writer, err := userexchange.NewWriter(format, out, req.IncludePasswordHashes)
+	if err != nil {
+		return err
+	}
+
+	err = userexchange.Export(ctx, server.container.UserQueryService(), query.ListUsersSpec{}, writer)
+	if err != nil {
+		return err
+	}
+	err = out.Flush()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, req.UserToken))
+	if err != nil {
+		return err
+	}
+	return server.container.AuditService().Record(service.WithAuditActor(ctx, userDesc.UserID), service.AuditUsersExported, nil, map[string]string{
+		"format":                  string(format),
+		"include_password_hashes": strconv.FormatBool(req.IncludePasswordHashes),
+	})
+}
+
+func (server *userServiceServer) resolveTargetUser(ctx context.Context, userToken, userID string) (uuid.UUID, error) {
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, userToken))
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if userID == "" || userID == userDesc.UserID.String() {
+		return userDesc.UserID, nil
+	}
+
+	err = server.container.AuthenticationService().AssertAdmin(ctx, userDesc)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	targetID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.UUID{}, ErrInvalidUserID
+	}
+	return targetID, nil
+}
+
+func (server *userServiceServer) assertAdmin(ctx context.Context, userToken string) error {
+	_, err := server.authenticateAdmin(ctx, userToken)
+	return err
+}
+
+func (server *userServiceServer) authenticateAdmin(ctx context.Context, userToken string) (uuid.UUID, error) {
+	userDesc, err := server.container.UserDescriptorSerializer().Deserialize(userTokenFromContext(ctx, userToken))
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	return userDesc.UserID, server.container.AuthenticationService().AssertAdmin(ctx, userDesc)
+}
+
 var apiToUserRoleMap = map[api.UserRole]service.Role{
 	api.UserRole_LISTENER: service.Listener,
 	api.UserRole_CREATOR:  service.Creator,
 }
 
+var apiToExchangeFormatMap = map[api.ExchangeFormat]userexchange.Format{
+	api.ExchangeFormat_CSV:    userexchange.CSV,
+	api.ExchangeFormat_NDJSON: userexchange.NDJSON,
+}
+
+var dataExportStatusToAPIMap = map[service.DataExportStatus]api.DataExportStatus{
+	service.DataExportPending:    api.DataExportStatus_PENDING,
+	service.DataExportProcessing: api.DataExportStatus_PROCESSING,
+	service.DataExportReady:      api.DataExportStatus_READY,
+	service.DataExportFailed:     api.DataExportStatus_FAILED,
+	service.DataExportExpired:    api.DataExportStatus_EXPIRED,
+}
+
+var apiToConsentKindMap = map[api.ConsentKind]service.ConsentKind{
+	api.ConsentKind_TERMS_OF_SERVICE: service.TermsOfService,
+	api.ConsentKind_PRIVACY_POLICY:   service.PrivacyPolicy,
+	api.ConsentKind_MARKETING:        service.Marketing,
+}
+
+var consentKindToAPIMap = map[service.ConsentKind]api.ConsentKind{
+	service.TermsOfService: api.ConsentKind_TERMS_OF_SERVICE,
+	service.PrivacyPolicy:  api.ConsentKind_PRIVACY_POLICY,
+	service.Marketing:      api.ConsentKind_MARKETING,
+}
+
+const (
+	defaultAuditLogPageSize = 100
+	maxAuditLogPageSize     = 1000
+)
+
+func makeAuditLogSpec(req *api.QueryAuditLogRequest) (query.AuditLogSpec, error) {
+	spec := query.AuditLogSpec{
+		Action: req.Action,
+		Limit:  int(req.PageSize),
+	}
+	if spec.Limit <= 0 {
+		spec.Limit = defaultAuditLogPageSize
+	}
+	if spec.Limit > maxAuditLogPageSize {
+		spec.Limit = maxAuditLogPageSize
+	}
+
+	if req.UserId != "" {
+		userID, err := uuid.Parse(req.UserId)
+		if err != nil {
+			return query.AuditLogSpec{}, ErrInvalidUserID
+		}
+		spec.UserID = &userID
+	}
+	if req.From != nil {
+		from := req.From.AsTime()
+		spec.From = &from
+	}
+	if req.To != nil {
+		to := req.To.AsTime()
+		spec.To = &to
+	}
+	if req.PageToken != "" {
+		beforeSeq, err := strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil || beforeSeq <= 0 {
+			return query.AuditLogSpec{}, ErrInvalidPageToken
+		}
+		spec.BeforeSeq = beforeSeq
+	}
+	return spec, nil
+}
+
+func makeProfileUpdate(req *api.UpdateProfileRequest) (service.ProfileUpdate, error) {
+	profile := req.Profile
+	if profile == nil {
+		profile = &api.Profile{}
+	}
+
+	update := service.ProfileUpdate{
+		DisplayName: profile.DisplayName,
+		AvatarURL:   profile.AvatarUrl,
+		Country:     profile.Country,
+		Language:    profile.Language,
+		Bio:         profile.Bio,
+		ParentalPIN: req.ParentalPin,
+	}
+	if profile.DateOfBirth != "" {
+		dateOfBirth, err := time.Parse(service.DateLayout, profile.DateOfBirth)
+		if err != nil {
+			return service.ProfileUpdate{}, ErrInvalidDate
+		}
+		update.DateOfBirth = &dateOfBirth
+	}
+
+	if req.UpdateMask != nil {
+		for _, path := range req.UpdateMask.Paths {
+			update.Fields = append(update.Fields, service.ProfileField(path))
+		}
+		return update, nil
+	}
+
+	populated := map[service.ProfileField]bool{
+		service.ProfileDisplayName: profile.DisplayName != "",
+		service.ProfileAvatarURL:   profile.AvatarUrl != "",
+		service.ProfileCountry:     profile.Country != "",
+		service.ProfileLanguage:    profile.Language != "",
+		service.ProfileDateOfBirth: profile.DateOfBirth != "",
+		service.ProfileBio:         profile.Bio != "",
+	}
+	for _, field := range profileFieldsOrder {
+		if populated[field] {
+			update.Fields = append(update.Fields, field)
+		}
+	}
+	return update, nil
+}
+
+var profileFieldsOrder = []service.ProfileField{
+	service.ProfileDisplayName,
+	service.ProfileAvatarURL,
+	service.ProfileCountry,
+	service.ProfileLanguage,
+	service.ProfileDateOfBirth,
+	service.ProfileBio,
+}
+
+func makeAPIProfile(profile service.ProfileView) *api.Profile {
+	result := &api.Profile{
+		UserId:      profile.UserID.String(),
+		DisplayName: profile.DisplayName,
+		AvatarUrl:   profile.AvatarURL,
+		Country:     profile.Country,
+		Language:    profile.Language,
+		Bio:         profile.Bio,
+	}
+	if profile.DateOfBirth != nil {
+		result.DateOfBirth = profile.DateOfBirth.Format(service.DateLayout)
+	}
+	if !profile.UpdatedAt.IsZero() {
+		result.UpdatedAt = timestamppb.New(profile.UpdatedAt)
+	}
+	return result
+}
+
+const (
+	defaultApplicationsPageSize = 50
+	maxApplicationsPageSize     = 500
+)
+
+var apiToReviewDecisionMap = map[api.ReviewDecision]service.CreatorApplicationDecision{
+	api.ReviewDecision_START_REVIEW: service.StartCreatorApplicationReview,
+	api.ReviewDecision_APPROVE:      service.ApproveCreatorApplication,
+	api.ReviewDecision_REJECT:       service.RejectCreatorApplication,
+}
+
+var creatorApplicationStatusToAPIMap = map[query.CreatorApplicationStatus]api.CreatorApplicationStatus{
+	query.CreatorApplicationApplied:     api.CreatorApplicationStatus_APPLIED,
+	query.CreatorApplicationUnderReview: api.CreatorApplicationStatus_UNDER_REVIEW,
+	query.CreatorApplicationApproved:    api.CreatorApplicationStatus_APPROVED,
+	query.CreatorApplicationRejected:    api.CreatorApplicationStatus_REJECTED,
+}
+
+func makeAPICreatorApplication(application query.CreatorApplicationView) *api.CreatorApplication {
+	result := &api.CreatorApplication{
+		ApplicationId: application.ID.String(),
+		UserId:        application.UserID.String(),
+		Status:        creatorApplicationStatusToAPIMap[application.Status],
+		Statement:     application.Statement,
+		ReviewerId:    optionalUUIDString(application.ReviewerID),
+		ReviewerNotes: application.ReviewerNotes,
+		AppliedAt:     timestamppb.New(application.AppliedAt),
+	}
+	if application.ReviewStartedAt != nil {
+		result.ReviewStartedAt = timestamppb.New(*application.ReviewStartedAt)
+	}
+	if application.DecidedAt != nil {
+		result.DecidedAt = timestamppb.New(*application.DecidedAt)
+	}
+	return result
+}
+
+func optionalUUIDString(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
 var (
-	ErrUnknownUserRole = errors.New("unknown user role")
+	ErrUnknownUserRole       = errors.New("unknown user role")
+	ErrUnknownExchangeFormat = errors.New("unknown exchange format")
+	ErrInvalidUserID         = errors.New("invalid user id")
+	ErrInvalidDataExportID   = errors.New("invalid data export id")
+	ErrInvalidPageToken      = errors.New("invalid page token")
+	ErrInvalidDate           = errors.New("invalid date, expected YYYY-MM-DD")
+	ErrInvalidApplicationID  = errors.New("invalid creator application id")
+	ErrUnknownReviewDecision = errors.New("unknown review decision")
+	ErrRoleRequiresAdmin     = errors.New("only admin can add user with this role")
 )
@@ -0,0 +1,38 @@
+package userexchange
+
+import (
+	"github.com/pkg/errors"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+)
+
+var ErrUnknownFormat = errors.New("unknown exchange format, expected csv or ndjson")
+
+const (
+	columnID                = "id"
+	columnEmail             = "email"
+	columnRole              = "role"
+	columnPassword          = "password"
+	columnPasswordHash      = "password_hash"
+	columnPasswordAlgorithm = "password_algorithm"
+)
+
+var csvHeader = []string{columnID, columnEmail, columnRole, columnPassword, columnPasswordHash, columnPasswordAlgorithm}
+
+type record struct {
+	ID                string `json:"id,omitempty"`
+	Email             string `json:"email"`
+	Role              string `json:"role"`
+	Password          string `json:"password,omitempty"`
+	PasswordHash      string `json:"password_hash,omitempty"`
+	PasswordAlgorithm string `json:"password_algorithm,omitempty"`
+}
+
+func (r record) csvRow() []string {
+	return []string{r.ID, r.Email, r.Role, r.Password, r.PasswordHash, r.PasswordAlgorithm}
+}
@@ -0,0 +1,139 @@
+package userexchange
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/hash"
+	"userservice/pkg/userservice/app/service"
+	"userservice/pkg/userservice/domain"
+)
+
+const maxNDJSONLineSize = 1 << 20
+
+var ErrMissingEmailColumn = errors.New("csv header has no email column")
+
+func NewReader(format Format, r io.Reader) (service.ImportRowReader, error) {
+	switch format {
+	case CSV:
+		return &csvReader{reader: csv.NewReader(r)}, nil
+	case NDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxNDJSONLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, errors.Wrap(ErrUnknownFormat, string(format))
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func (r *csvReader) Read() (service.ImportRow, error) {
+	if r.columns == nil {
+		err := r.readHeader()
+		if err != nil {
+			return service.ImportRow{}, err
+		}
+	}
+
+	fields, err := r.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return service.ImportRow{}, err
+		}
+		return service.ImportRow{}, errors.Wrap(err, "failed to read csv")
+	}
+
+	r.row++
+	value := func(column string) string {
+		i, ok := r.columns[column]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	return makeImportRow(r.row, record{
+		Email:             value(columnEmail),
+		Role:              value(columnRole),
+		Password:          value(columnPassword),
+		PasswordHash:      value(columnPasswordHash),
+		PasswordAlgorithm: value(columnPasswordAlgorithm),
+	}), nil
+}
+
+func (r *csvReader) readHeader() error {
+	r.reader.FieldsPerRecord = -1
+	header, err := r.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return errors.Wrap(err, "failed to read csv header")
+	}
+
+	r.row = 1
+	r.columns = make(map[string]int, len(header))
+	for i, column := range header {
+		r.columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	if _, ok := r.columns[columnEmail]; !ok {
+		return ErrMissingEmailColumn
+	}
+	return nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Read() (service.ImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var rec record
+		err := json.Unmarshal([]byte(line), &rec)
+		if err != nil {
+			return service.ImportRow{Number: r.line, Err: errors.Wrap(err, "invalid json")}, nil
+		}
+		return makeImportRow(r.line, rec), nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return service.ImportRow{}, errors.Wrap(err, "failed to read ndjson")
+	}
+	return service.ImportRow{}, io.EOF
+}
+
+func makeImportRow(number int, rec record) service.ImportRow {
+	row := service.ImportRow{
+		Number: number,
+		User: service.ImportedUser{
+			Email:             rec.Email,
+			Password:          rec.Password,
+			PasswordHash:      rec.PasswordHash,
+			PasswordAlgorithm: hash.Algorithm(strings.ToLower(rec.PasswordAlgorithm)),
+		},
+	}
+
+	role, ok := domain.RoleByName(strings.ToLower(rec.Role))
+	if !ok {
+		row.Err = errors.Wrap(service.ErrUnknownImportedRole, rec.Role)
+	}
+	row.User.Role = service.Role(role)
+	return row
+}
@@ -0,0 +1,113 @@
+package userexchange
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"userservice/pkg/userservice/app/query"
+	"userservice/pkg/userservice/domain"
+)
+
+const exportPageSize = 500
+
+type Writer interface {
+	Write(user query.UserView) error
+	Flush() error
+}
+
+// NewWriter omits password hashes unless includePasswordHashes is set, caller is responsible for checking who asks for them
+func NewWriter(format Format, w io.Writer, includePasswordHashes bool) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{writer: csv.NewWriter(w), includePasswordHashes: includePasswordHashes}, nil
+	case NDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w), includePasswordHashes: includePasswordHashes}, nil
+	default:
+		return nil, errors.Wrap(ErrUnknownFormat, string(format))
+	}
+}
+
+func Export(ctx context.Context, queryService query.UserQueryService, spec query.ListUsersSpec, writer Writer) error {
+	spec.Offset = 0
+	spec.Limit = exportPageSize
+
+	for {
+		users, err := queryService.ListUsers(ctx, spec)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			err = writer.Write(user)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(users) < spec.Limit {
+			return writer.Flush()
+		}
+		spec.Offset += len(users)
+	}
+}
+
+func makeRecord(user query.UserView, includePasswordHashes bool) record {
+	rec := record{
+		ID:    user.ID.String(),
+		Email: user.Email,
+		Role:  domain.Role(user.Role).Name(),
+	}
+	if includePasswordHashes {
+		rec.PasswordHash = user.Password
+		rec.PasswordAlgorithm = user.PasswordAlgorithm
+	}
+	return rec
+}
+
+type csvWriter struct {
+	writer                *csv.Writer
+	includePasswordHashes bool
+	headerWritten         bool
+}
+
+func (w *csvWriter) Write(user query.UserView) error {
+	err := w.writeHeader()
+	if err != nil {
+		return err
+	}
+	return errors.WithStack(w.writer.Write(makeRecord(user, w.includePasswordHashes).csvRow()))
+}
+
+func (w *csvWriter) Flush() error {
+	err := w.writeHeader()
+	if err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return errors.WithStack(w.writer.Error())
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return errors.WithStack(w.writer.Write(csvHeader))
+}
+
+type ndjsonWriter struct {
+	encoder               *json.Encoder
+	includePasswordHashes bool
+}
+
+func (w *ndjsonWriter) Write(user query.UserView) error {
+	return errors.WithStack(w.encoder.Encode(makeRecord(user, w.includePasswordHashes)))
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}